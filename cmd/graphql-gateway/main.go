@@ -0,0 +1,272 @@
+// Command graphql-gateway serves GraphQL endpoint of gRPC services which are declared in descriptor sets,
+// so that simple setups need no Go code nor code generation.
+//
+//	buf build -o image.binpb
+//	graphql-gateway -descriptor_set image.binpb -upstream localhost:50051
+//
+// All services are proxied to the -upstream address unless it's overridden per service
+// by -upstreams flag like "greeter.Greeter=greeter:50051,user.UserService=unix:///var/run/user.sock"
+// or GRAPHQL_UPSTREAM_<SERVICE> environment variable like GRAPHQL_UPSTREAM_GREETER_GREETER.
+// Schema report is served on the path of "<path>/version", and mapping of fields to gRPC methods is served on
+// "<path>/debug/mapping" for the networks of debug.allowed_networks config, which are also allowed to explain queries
+// via X-GraphQL-Explain header.
+//
+// Upstream TLS, limits, CORS, caching and middlewares are configured by YAML or JSON file of runtime.Config,
+// and GRAPHQL_* environment variables like GRAPHQL_LIMITS_MAX_DEPTH, see runtime.ConfigEnvPrefix.
+// The precedence is defaults < config file < environment variables < flags which are given explicitly.
+//
+//	GRAPHQL_LISTEN=:9000 graphql-gateway -config gateway.yaml
+//
+// For Kubernetes, liveness and readiness probes are served on "/healthz" and "/readyz".
+// On SIGTERM, readiness probe starts failing and the gateway keeps serving for the drain delay,
+// then it stops accepting connections and waits for in-flight requests up to the grace period.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"net/http"
+
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	configFile     = flag.String("config", "", "YAML or JSON config file")
+	descriptorSets = flag.String("descriptor_set", "", "comma separated descriptor set files, \".json\" files are read as JSON")
+	upstream       = flag.String("upstream", "", "default upstream gRPC address of all services")
+	upstreams      = flag.String("upstreams", "", "comma separated upstream addresses per service like \"pkg.Service=host:port\"")
+	listen         = flag.String("listen", ":8888", "listen address")
+	path           = flag.String("path", "/graphql", "path of GraphQL endpoint")
+	drainDelay     = flag.Duration("drain_delay", 0, "duration to keep serving after SIGTERM while readiness probe fails")
+	gracePeriod    = flag.Duration("shutdown_grace_period", 30*time.Second, "maximum duration to wait for in-flight requests on shutdown")
+)
+
+func main() {
+	flag.Parse()
+	config, hosts, err := loadConfig()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if len(config.DescriptorSets) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(signals)
+	go func() {
+		<-signals
+		cancel()
+	}()
+	// Upstream flags take precedence over environment variables which are resolved on dial
+	ctx = runtime.ContextWithUpstreamHosts(ctx, hosts)
+	if err := run(ctx, config); err != nil {
+		log.Fatalln(err)
+	}
+}
+
+// loadConfig loads config file and environment variables, and overrides them with flags which are given explicitly.
+// Upstream addresses of -upstreams flag are also returned in order to override environment variables per service.
+func loadConfig() (*runtime.Config, map[string]string, error) {
+	config, err := runtime.LoadConfig(*configFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var hosts map[string]string
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "descriptor_set":
+			config.DescriptorSets = strings.Split(*descriptorSets, ",")
+		case "upstream":
+			config.Upstream.Address = *upstream
+		case "upstreams":
+			if hosts, err = parseUpstreams(*upstreams); err != nil {
+				return
+			}
+			if config.Upstreams == nil {
+				config.Upstreams = map[string]runtime.UpstreamConfig{}
+			}
+			for service, host := range hosts {
+				u := config.Upstreams[service]
+				u.Address = host
+				config.Upstreams[service] = u
+			}
+		case "listen":
+			config.Listen = *listen
+		case "path":
+			config.Path = *path
+		case "drain_delay":
+			config.Shutdown.DrainDelay = *drainDelay
+		case "shutdown_grace_period":
+			config.Shutdown.GracePeriod = *gracePeriod
+		}
+	})
+	return config, hosts, err
+}
+
+// upstreamHandler is DynamicHandler which calls RPCs via the connection of the service upstream
+type upstreamHandler struct {
+	*runtime.DynamicHandler
+	conn *grpc.ClientConn
+}
+
+func (h *upstreamHandler) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	return h.conn, func() {}, nil
+}
+
+func run(ctx context.Context, config *runtime.Config) error {
+	mux, conns, err := newServeMux(ctx, config)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		for _, conn := range conns {
+			conn.Close() // nolint: errcheck
+		}
+	}()
+
+	if err := mux.LogSchemaReport(ctx); err != nil {
+		return err
+	}
+	p := &probes{conns: conns}
+	endpoint := "/" + strings.Trim(config.Path, "/")
+	handler := http.NewServeMux()
+	handler.Handle(endpoint+"/version", mux.VersionHandler())
+	if networks := config.Debug.AllowedNetworks; len(networks) > 0 {
+		allow, err := runtime.IntrospectionFromNetworks(networks...)
+		if err != nil {
+			return err
+		}
+		handler.Handle(endpoint+"/debug/mapping", mux.MappingHandler(allow))
+		mux.Use(runtime.WithExplain(allow))
+	}
+	gateway, err := mux.Build()
+	if err != nil {
+		return err
+	}
+	handler.Handle(endpoint, gateway)
+	handler.HandleFunc("/healthz", p.healthz)
+	handler.HandleFunc("/readyz", p.readyz)
+
+	server := &http.Server{
+		Addr:    config.Listen,
+		Handler: handler,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	log.Printf("GraphQL gateway is listening on %s%s", config.Listen, endpoint)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, draining for %s", config.Shutdown.DrainDelay)
+	p.drain()
+	server.SetKeepAlivesEnabled(false)
+	time.Sleep(config.Shutdown.DrainDelay)
+
+	shutdown, cancel := context.WithTimeout(context.Background(), config.Shutdown.GracePeriod)
+	defer cancel()
+	if err := server.Shutdown(shutdown); err != nil {
+		return fmt.Errorf("failed to shut down gracefully: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// upstreamKey identifies connection which services of the same upstream share
+type upstreamKey struct {
+	address  string
+	protocol string
+	secure   bool
+	tls      runtime.TLSFileConfig
+}
+
+// newServeMux creates ServeMux of the services in descriptor sets, and dials the upstream of each service.
+// Services of the same upstream share the connection, and the caller closes returned connections.
+func newServeMux(ctx context.Context, config *runtime.Config) (*runtime.ServeMux, []*grpc.ClientConn, error) {
+	var services []protoreflect.ServiceDescriptor
+	for _, path := range config.DescriptorSets {
+		s, err := runtime.LoadDescriptorSet(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		services = append(services, s...)
+	}
+	// Build all handlers at once so that types of the shared files are not duplicated
+	handlers, err := runtime.NewDynamicHandlers(nil, services...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conns []*grpc.ClientConn
+	closeAll := func() {
+		for _, conn := range conns {
+			conn.Close() // nolint: errcheck
+		}
+	}
+	shared := map[upstreamKey]*grpc.ClientConn{}
+	mux := runtime.NewServeMux(config.MiddlewareFuncs()...)
+	for _, h := range handlers {
+		service := h.ServiceName()
+		u := config.UpstreamOf(service)
+		key := upstreamKey{
+			address:  runtime.UpstreamHost(ctx, service, u.Address),
+			protocol: u.Protocol,
+		}
+		if u.TLS != nil {
+			key.secure, key.tls = true, *u.TLS
+		}
+		conn, ok := shared[key]
+		if !ok {
+			if conn, err = config.Dial(ctx, service); err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			shared[key] = conn
+			conns = append(conns, conn)
+		}
+		if err := mux.AddHandler(&upstreamHandler{DynamicHandler: h, conn: conn}); err != nil {
+			closeAll()
+			return nil, nil, err
+		}
+	}
+	return mux, conns, nil
+}
+
+// parseUpstreams parses comma separated "service=address" pairs
+func parseUpstreams(s string) (map[string]string, error) {
+	hosts := map[string]string{}
+	if s == "" {
+		return hosts, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		kv := strings.SplitN(pair, "=", 2)
+		if len(kv) != 2 || kv[0] == "" || kv[1] == "" {
+			return nil, fmt.Errorf("invalid upstream %q, must be \"service=address\"", pair)
+		}
+		hosts[kv[0]] = kv[1]
+	}
+	return hosts, nil
+}
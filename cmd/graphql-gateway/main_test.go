@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"testing"
+
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/grpc-graphql-gateway/example/greeter/greeter"
+	gqlproto "github.com/ysugimoto/grpc-graphql-gateway/graphql"
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func writeTestDescriptorSet(t *testing.T) string {
+	t.Helper()
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{
+			protodesc.ToFileDescriptorProto(gqlproto.File_graphql_proto),
+			protodesc.ToFileDescriptorProto(greeter.File_greeter_proto),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "image.binpb")
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseUpstreams(t *testing.T) {
+	hosts, err := parseUpstreams("greeter.Greeter=greeter:50051,user.UserService=unix:///var/run/user.sock")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"greeter.Greeter":  "greeter:50051",
+		"user.UserService": "unix:///var/run/user.sock",
+	}, hosts)
+
+	_, err = parseUpstreams("greeter:50051")
+	assert.Error(t, err)
+}
+
+func TestNewServeMux(t *testing.T) {
+	ctx := context.Background()
+	path := writeTestDescriptorSet(t)
+
+	_, _, err := newServeMux(ctx, &runtime.Config{
+		DescriptorSets: []string{path},
+	})
+	assert.EqualError(t, err, "upstream of Greeter is not specified")
+
+	mux, conns, err := newServeMux(ctx, &runtime.Config{
+		DescriptorSets: []string{path},
+		Upstream:       runtime.UpstreamConfig{Address: "localhost:50051"},
+		Upstreams: map[string]runtime.UpstreamConfig{
+			"Greeter": {Address: "greeter:50051"},
+		},
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, conns, 1)
+	defer conns[0].Close() // nolint: errcheck
+
+	report, err := mux.SchemaReport(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, report.Upstreams, 1)
+	assert.Equal(t, "Greeter", report.Upstreams[0].Service)
+	assert.Equal(t, "greeter:50051", report.Upstreams[0].Target)
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
+)
+
+// probes serves Kubernetes liveness and readiness probes
+type probes struct {
+	conns    []*grpc.ClientConn
+	draining int32
+}
+
+// drain makes readiness probe fail so that load balancers stop routing new requests
+func (p *probes) drain() {
+	atomic.StoreInt32(&p.draining, 1)
+}
+
+// healthz responds OK while the process serves requests
+func (p *probes) healthz(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("ok\n")) // nolint: errcheck
+}
+
+// readyz responds OK unless the gateway is draining or any upstream connection is failing.
+// Idle connections are ready because they connect on the first request.
+func (p *probes) readyz(w http.ResponseWriter, r *http.Request) {
+	if atomic.LoadInt32(&p.draining) == 1 {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	for _, conn := range p.conns {
+		switch state := conn.GetState(); state {
+		case connectivity.TransientFailure, connectivity.Shutdown:
+			http.Error(w, fmt.Sprintf("upstream %s is %s", conn.Target(), state), http.StatusServiceUnavailable)
+			return
+		}
+	}
+	w.Write([]byte("ok\n")) // nolint: errcheck
+}
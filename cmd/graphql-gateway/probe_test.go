@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestProbes(t *testing.T) {
+	conn, err := grpc.Dial("localhost:50051", grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &probes{conns: []*grpc.ClientConn{conn}}
+
+	probe := func(handler http.HandlerFunc) int {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusOK, probe(p.healthz))
+	assert.Equal(t, http.StatusOK, probe(p.readyz))
+
+	conn.Close() // nolint: errcheck
+	assert.Equal(t, http.StatusServiceUnavailable, probe(p.readyz))
+
+	p.conns = nil
+	p.drain()
+	assert.Equal(t, http.StatusServiceUnavailable, probe(p.readyz))
+	assert.Equal(t, http.StatusOK, probe(p.healthz))
+}
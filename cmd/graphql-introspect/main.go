@@ -0,0 +1,66 @@
+// Command graphql-introspect writes introspection JSON of the schema which the gateway serves from descriptor sets,
+// so that tools like graphql-voyager, graphql-codegen and linters consume it without a running server.
+//
+//	buf build -o image.binpb
+//	graphql-introspect -descriptor_set image.binpb -o schema.json
+//
+// The schema is built in the same way as runtime.NewDescriptorSetHandlers.
+// For generated handlers, call runtime.ServeMux.WriteIntrospection after registering them instead.
+package main
+
+import (
+	"flag"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+var (
+	descriptorSets = flag.String("descriptor_set", "", "comma separated descriptor set files, \".json\" files are read as JSON")
+	output         = flag.String("o", "", "output file, default is stdout")
+)
+
+func main() {
+	flag.Parse()
+	if *descriptorSets == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var services []protoreflect.ServiceDescriptor
+	for _, path := range strings.Split(*descriptorSets, ",") {
+		s, err := runtime.LoadDescriptorSet(path)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		services = append(services, s...)
+	}
+	// Build all handlers at once so that types of the shared files are not duplicated
+	handlers, err := runtime.NewDynamicHandlers(nil, services...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	mux := runtime.NewServeMux()
+	for _, h := range handlers {
+		if err := mux.AddHandler(h); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := mux.WriteIntrospection(w); err != nil {
+		log.Fatalln(err)
+	}
+}
@@ -0,0 +1,86 @@
+// Package datadog integrates Datadog APM with the gateway via runtime.APMTracer.
+// This package is a separate module in order to keep the gateway free from dd-trace-go dependencies.
+//
+//	tracer.Start(tracer.WithService("graphql-gateway"))
+//	defer tracer.Stop()
+//
+//	mux := runtime.NewServeMux(runtime.WithAPMTracer(&datadog.Tracer{}))
+package datadog
+
+import (
+	"context"
+
+	"net/http"
+
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/tracer"
+)
+
+// Tracer implements runtime.APMTracer with dd-trace-go global tracer, which should be started via tracer.Start
+type Tracer struct {
+	// Service name of spans, default is the service of global tracer
+	ServiceName string
+}
+
+var _ runtime.APMTracer = (*Tracer)(nil)
+
+// StartRequestSpan implements runtime.APMTracer
+func (t *Tracer) StartRequestSpan(ctx context.Context, r *http.Request) (context.Context, runtime.APMSpan) {
+	opts := t.options(
+		tracer.SpanType(ext.SpanTypeWeb),
+		tracer.ResourceName(r.Method+" "+r.URL.Path),
+		tracer.Tag(ext.HTTPMethod, r.Method),
+		tracer.Tag(ext.HTTPURL, r.URL.Path),
+		tracer.Measured(),
+	)
+	if sctx, err := tracer.Extract(tracer.HTTPHeadersCarrier(r.Header)); err == nil {
+		opts = append(opts, tracer.ChildOf(sctx))
+	}
+	s, ctx := tracer.StartSpanFromContext(ctx, "graphql.request", opts...)
+	return ctx, &span{Span: s, request: true}
+}
+
+// StartResolverSpan implements runtime.APMTracer
+func (t *Tracer) StartResolverSpan(ctx context.Context, name string, md metadata.MD) (context.Context, runtime.APMSpan) {
+	s, ctx := tracer.StartSpanFromContext(ctx, "graphql.resolve", t.options(
+		tracer.SpanType("graphql"),
+		tracer.ResourceName(name),
+	)...)
+	carrier := tracer.TextMapCarrier{}
+	if err := tracer.Inject(s.Context(), carrier); err == nil {
+		for k, v := range carrier {
+			md.Set(k, v)
+		}
+	}
+	return ctx, &span{Span: s}
+}
+
+func (t *Tracer) options(opts ...ddtrace.StartSpanOption) []ddtrace.StartSpanOption {
+	if t.ServiceName != "" {
+		opts = append(opts, tracer.ServiceName(t.ServiceName))
+	}
+	return opts
+}
+
+// span adapts ddtrace.Span to runtime.APMSpan
+type span struct {
+	ddtrace.Span
+	request bool
+}
+
+// SetTag sets the tag, and resource name of request span is renamed to the operation name
+// so that requests are grouped by operation instead of the same endpoint path
+func (s *span) SetTag(key string, value interface{}) {
+	s.Span.SetTag(key, value)
+	if s.request && key == runtime.APMTagOperationName {
+		s.Span.SetTag(ext.ResourceName, value)
+	}
+}
+
+// Finish finishes the span with error
+func (s *span) Finish(err error) {
+	s.Span.Finish(tracer.WithError(err))
+}
@@ -0,0 +1,50 @@
+package datadog
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/ext"
+	"gopkg.in/DataDog/dd-trace-go.v1/ddtrace/mocktracer"
+)
+
+func TestTracer(t *testing.T) {
+	mt := mocktracer.Start()
+	defer mt.Stop()
+
+	tr := &Tracer{ServiceName: "gateway"}
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	r.Header.Set("X-Datadog-Trace-Id", "100")
+	r.Header.Set("X-Datadog-Parent-Id", "200")
+
+	ctx, request := tr.StartRequestSpan(context.Background(), r)
+	md := metadata.MD{}
+	_, resolver := tr.StartResolverSpan(ctx, "Query.user", md)
+	resolver.Finish(errors.New("unavailable"))
+	request.SetTag(runtime.APMTagOperationName, "GetUser")
+	request.Finish(nil)
+
+	spans := mt.FinishedSpans()
+	if !assert.Len(t, spans, 2) {
+		t.FailNow()
+	}
+	rs, qs := spans[0], spans[1]
+
+	assert.Equal(t, "graphql.request", qs.OperationName())
+	assert.Equal(t, "GetUser", qs.Tag(ext.ResourceName))
+	assert.Equal(t, "gateway", qs.Tag(ext.ServiceName))
+	assert.Equal(t, uint64(100), qs.TraceID())
+	assert.Equal(t, uint64(200), qs.ParentID())
+
+	assert.Equal(t, "graphql.resolve", rs.OperationName())
+	assert.Equal(t, "Query.user", rs.Tag(ext.ResourceName))
+	assert.Equal(t, qs.SpanID(), rs.ParentID())
+	assert.NotNil(t, rs.Tag(ext.Error))
+	assert.Equal(t, []string{"100"}, md.Get("x-datadog-trace-id"))
+}
@@ -0,0 +1,15 @@
+module github.com/ysugimoto/grpc-graphql-gateway/contrib/datadog
+
+go 1.15
+
+require (
+	github.com/philhofer/fwd v1.2.0 // indirect
+	github.com/stretchr/testify v1.7.0
+	github.com/ysugimoto/grpc-graphql-gateway v0.0.0-20261015135955-eb577a4d5428
+	golang.org/x/time v0.0.0-20191024005414-555d28b269f0 // indirect
+	google.golang.org/grpc v1.27.0
+	gopkg.in/DataDog/dd-trace-go.v1 v1.34.0
+)
+
+// Local development uses the gateway in this repository, the replace directive is ignored by modules which depend on this module
+replace github.com/ysugimoto/grpc-graphql-gateway => ../../
@@ -0,0 +1,12 @@
+module github.com/ysugimoto/grpc-graphql-gateway/contrib/memcache
+
+go 1.15
+
+require (
+	github.com/bradfitz/gomemcache v0.0.0-20190913173617-a41fca850d0b
+	github.com/stretchr/testify v1.7.0
+	github.com/ysugimoto/grpc-graphql-gateway v0.0.0-20261015135955-eb577a4d5428
+)
+
+// Local development uses the gateway in this repository, the replace directive is ignored by modules which depend on this module
+replace github.com/ysugimoto/grpc-graphql-gateway => ../../
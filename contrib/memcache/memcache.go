@@ -0,0 +1,71 @@
+// Package memcache implements runtime.Cache on memcached, so that multiple gateway replicas share cached responses.
+// This package is a separate module in order to keep the gateway free from memcached client dependencies.
+//
+//	cache := &memcache.Cache{Client: gomemcache.New("localhost:11211"), Prefix: "gateway:"}
+//	mux := runtime.NewServeMux(
+//	    runtime.WithResponseCache(runtime.ResponseCacheConfig{Cache: cache}),
+//	)
+package memcache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	gomemcache "github.com/bradfitz/gomemcache/memcache"
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+)
+
+// maxRelativeExpiration is the longest expiration which memcached treats as relative seconds,
+// longer values are treated as absolute unix time
+const maxRelativeExpiration = 30 * 24 * time.Hour
+
+// Cache implements runtime.Cache on memcached.
+// Note that memcached client doesn't accept context, so cancellation of the request is not propagated.
+type Cache struct {
+	Client *gomemcache.Client
+	// Prefix of all keys
+	Prefix string
+}
+
+var _ runtime.Cache = (*Cache)(nil)
+
+// Get implements runtime.Cache
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	item, err := c.Client.Get(c.Prefix + key)
+	if errors.Is(err, gomemcache.ErrCacheMiss) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return item.Value, true, nil
+}
+
+// Set implements runtime.Cache
+func (c *Cache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return c.Client.Set(&gomemcache.Item{
+		Key:        c.Prefix + key,
+		Value:      value,
+		Expiration: expiration(ttl, time.Now()),
+	})
+}
+
+// Delete implements runtime.Cache
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	if err := c.Client.Delete(c.Prefix + key); err != nil && !errors.Is(err, gomemcache.ErrCacheMiss) {
+		return err
+	}
+	return nil
+}
+
+// expiration converts TTL to memcached expiration, which is seconds rounded up or unix time for long TTL
+func expiration(ttl time.Duration, now time.Time) int32 {
+	switch {
+	case ttl <= 0:
+		return 0
+	case ttl > maxRelativeExpiration:
+		return int32(now.Add(ttl).Unix())
+	default:
+		return int32((ttl + time.Second - 1) / time.Second)
+	}
+}
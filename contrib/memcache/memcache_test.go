@@ -0,0 +1,124 @@
+package memcache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	gomemcache "github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+// testServer is minimal memcached which speaks gets, set and delete commands of the text protocol
+type testServer struct {
+	mu          sync.Mutex
+	items       map[string][]byte
+	expirations map[string]int32
+}
+
+func startTestServer(t *testing.T) (*testServer, string) {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() }) // nolint: errcheck
+
+	s := &testServer{
+		items:       map[string][]byte{},
+		expirations: map[string]int32{},
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+	return s, l.Addr().String()
+}
+
+func (s *testServer) serve(conn net.Conn) {
+	defer conn.Close()
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			return
+		}
+		s.mu.Lock()
+		switch fields[0] {
+		case "gets":
+			for _, key := range fields[1:] {
+				if v, ok := s.items[key]; ok {
+					fmt.Fprintf(rw, "VALUE %s 0 %d 1\r\n%s\r\n", key, len(v), v)
+				}
+			}
+			fmt.Fprint(rw, "END\r\n")
+		case "set":
+			var flags, exp, size int
+			fmt.Sscan(strings.Join(fields[2:5], " "), &flags, &exp, &size) // nolint: errcheck
+			v := make([]byte, size+2)
+			io.ReadFull(rw, v) // nolint: errcheck
+			s.items[fields[1]] = v[:size]
+			s.expirations[fields[1]] = int32(exp)
+			fmt.Fprint(rw, "STORED\r\n")
+		case "delete":
+			if _, ok := s.items[fields[1]]; ok {
+				delete(s.items, fields[1])
+				fmt.Fprint(rw, "DELETED\r\n")
+			} else {
+				fmt.Fprint(rw, "NOT_FOUND\r\n")
+			}
+		}
+		s.mu.Unlock()
+		rw.Flush() // nolint: errcheck
+	}
+}
+
+func TestCache(t *testing.T) {
+	server, addr := startTestServer(t)
+	ctx := context.Background()
+	c := &Cache{
+		Client: gomemcache.New(addr),
+		Prefix: "gateway:",
+	}
+
+	_, ok, err := c.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, c.Set(ctx, "key", []byte("value"), 1500*time.Millisecond))
+	v, ok, err := c.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), v)
+	server.mu.Lock()
+	assert.Equal(t, int32(2), server.expirations["gateway:key"])
+	server.mu.Unlock()
+
+	assert.NoError(t, c.Delete(ctx, "key"))
+	assert.NoError(t, c.Delete(ctx, "key"))
+	_, ok, _ = c.Get(ctx, "key") // nolint: errcheck
+	assert.False(t, ok)
+}
+
+func TestExpiration(t *testing.T) {
+	now := time.Unix(1000, 0)
+	assert.Equal(t, int32(0), expiration(0, now))
+	assert.Equal(t, int32(1), expiration(time.Millisecond, now))
+	assert.Equal(t, int32(60), expiration(time.Minute, now))
+	assert.Equal(t, int32(1000+31*24*60*60), expiration(31*24*time.Hour, now))
+}
@@ -0,0 +1,13 @@
+module github.com/ysugimoto/grpc-graphql-gateway/contrib/redis
+
+go 1.15
+
+require (
+	github.com/alicebob/miniredis/v2 v2.14.1
+	github.com/go-redis/redis/v8 v8.11.4
+	github.com/stretchr/testify v1.7.0
+	github.com/ysugimoto/grpc-graphql-gateway v0.0.0-20261015135955-eb577a4d5428
+)
+
+// Local development uses the gateway in this repository, the replace directive is ignored by modules which depend on this module
+replace github.com/ysugimoto/grpc-graphql-gateway => ../../
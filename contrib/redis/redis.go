@@ -0,0 +1,67 @@
+// Package redis implements runtime.KVStore and runtime.Cache on Redis, so that multiple gateway replicas share the state
+// like rate limit counters, persisted operations and cached responses.
+// This package is a separate module in order to keep the gateway free from Redis client dependencies.
+//
+//	kv := &redis.Store{Client: goredis.NewClient(&goredis.Options{Addr: "localhost:6379"}), Prefix: "gateway:"}
+//	mux := runtime.NewServeMux(
+//	    runtime.RateLimit(runtime.RateLimitConfig{Rate: 10, Burst: 20, Store: runtime.NewKVFixedWindowRateLimitStore(kv, "ratelimit:")}),
+//	    runtime.WithResponseCache(runtime.ResponseCacheConfig{Cache: kv, Prefix: "response:"}),
+//	)
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+)
+
+// incr increments the counter and sets TTL only when the counter is created, atomically
+var incr = goredis.NewScript(`
+local n = redis.call("INCR", KEYS[1])
+if n == 1 and tonumber(ARGV[1]) > 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return n
+`)
+
+// Store implements runtime.KVStore and runtime.Cache on Redis
+type Store struct {
+	// Redis client, cluster client and ring are also accepted
+	Client goredis.UniversalClient
+	// Prefix of all keys
+	Prefix string
+}
+
+var (
+	_ runtime.KVStore = (*Store)(nil)
+	_ runtime.Cache   = (*Store)(nil)
+)
+
+// Get implements runtime.KVStore
+func (s *Store) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	v, err := s.Client.Get(ctx, s.Prefix+key).Bytes()
+	if errors.Is(err, goredis.Nil) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+	return v, true, nil
+}
+
+// Set implements runtime.KVStore
+func (s *Store) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.Client.Set(ctx, s.Prefix+key, value, ttl).Err()
+}
+
+// Incr implements runtime.KVStore
+func (s *Store) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incr.Run(ctx, s.Client, []string{s.Prefix + key}, ttl.Milliseconds()).Int64()
+}
+
+// Delete implements runtime.Cache
+func (s *Store) Delete(ctx context.Context, key string) error {
+	return s.Client.Del(ctx, s.Prefix+key).Err()
+}
@@ -0,0 +1,55 @@
+package redis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mr.Close()
+
+	ctx := context.Background()
+	s := &Store{
+		Client: goredis.NewClient(&goredis.Options{Addr: mr.Addr()}),
+		Prefix: "gateway:",
+	}
+
+	_, ok, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("value"), time.Second))
+	v, ok, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), v)
+	assert.True(t, mr.Exists("gateway:key"))
+	assert.NoError(t, s.Delete(ctx, "key"))
+	assert.False(t, mr.Exists("gateway:key"))
+	assert.NoError(t, s.Delete(ctx, "key"))
+	assert.NoError(t, s.Set(ctx, "key", []byte("value"), time.Second))
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := s.Incr(ctx, "counter", time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, i, n)
+	}
+	assert.Equal(t, time.Second, mr.TTL("gateway:counter"))
+
+	mr.FastForward(time.Second)
+	_, ok, _ = s.Get(ctx, "key") // nolint: errcheck
+	assert.False(t, ok)
+	n, err := s.Incr(ctx, "counter", 0)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+	assert.Equal(t, time.Duration(0), mr.TTL("gateway:counter"))
+}
@@ -0,0 +1,67 @@
+// Package sentry reports GraphQL errors of the gateway to Sentry via runtime.ErrorReporter.
+// This package is a separate module in order to keep the gateway free from sentry-go dependencies.
+//
+//	sentry.Init(sentry.ClientOptions{Dsn: "..."})
+//	defer sentry.Flush(2 * time.Second)
+//
+//	mux := runtime.NewServeMux(runtime.WithErrorReporter(&gatewaysentry.Reporter{}))
+package sentry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+)
+
+// Reporter implements runtime.ErrorReporter which captures single event per request with query context
+type Reporter struct {
+	// Hub to capture events. Default is the hub of the request context which sentry http integration attaches,
+	// or clone of the current hub.
+	Hub *sentry.Hub
+	// Filter returns true if the report should be captured, e.g. only upstream errors.
+	// Default captures all reports.
+	Filter func(report runtime.ErrorReport) bool
+}
+
+var _ runtime.ErrorReporter = (*Reporter)(nil)
+
+// Report implements runtime.ErrorReporter
+func (r *Reporter) Report(ctx context.Context, report runtime.ErrorReport) {
+	if r.Filter != nil && !r.Filter(report) {
+		return
+	}
+	hub := r.Hub
+	if hub == nil {
+		if hub = sentry.GetHubFromContext(ctx); hub == nil {
+			hub = sentry.CurrentHub().Clone()
+		}
+	}
+
+	messages := make([]string, len(report.Errors))
+	for i, e := range report.Errors {
+		messages[i] = e.Message
+	}
+	hub.WithScope(func(scope *sentry.Scope) {
+		if report.Request != nil {
+			scope.SetRequest(report.Request)
+		}
+		if report.OperationName != "" {
+			scope.SetTransaction(report.OperationName)
+			scope.SetTag("graphql.operation", report.OperationName)
+		}
+		scope.SetExtra("graphql.query", report.Query)
+		scope.SetExtra("graphql.variables", report.Variables)
+		scope.SetExtra("graphql.errors", messages)
+
+		// Upstream error is the most likely cause of the failure
+		err := errors.New(report.Errors[0].Message)
+		if len(report.UpstreamErrors) > 0 {
+			st := report.UpstreamErrors[0]
+			scope.SetTag("grpc.code", st.Code().String())
+			err = st.Err()
+		}
+		hub.CaptureException(err)
+	})
+}
@@ -0,0 +1,69 @@
+package sentry
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"net/http/httptest"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testTransport records sent events
+type testTransport struct {
+	mu     sync.Mutex
+	events []*sentry.Event
+}
+
+func (t *testTransport) Configure(sentry.ClientOptions) {}
+func (t *testTransport) Flush(time.Duration) bool       { return true }
+func (t *testTransport) SendEvent(event *sentry.Event) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.events = append(t.events, event)
+}
+
+func TestReporter(t *testing.T) {
+	transport := &testTransport{}
+	client, err := sentry.NewClient(sentry.ClientOptions{Transport: transport})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &Reporter{
+		Hub: sentry.NewHub(client, sentry.NewScope()),
+		Filter: func(report runtime.ErrorReport) bool {
+			return len(report.UpstreamErrors) > 0
+		},
+	}
+
+	report := runtime.ErrorReport{
+		Request:       httptest.NewRequest("POST", "/graphql", nil),
+		OperationName: "GetUser",
+		Query:         "query GetUser { user { id } }",
+		Errors:        []runtime.GraphqlError{{Message: "user service is down"}},
+	}
+	r.Report(context.Background(), report)
+	assert.Len(t, transport.events, 0)
+
+	report.UpstreamErrors = append(report.UpstreamErrors, status.New(codes.Unavailable, "user service is down"))
+	r.Report(context.Background(), report)
+	if !assert.Len(t, transport.events, 1) {
+		t.FailNow()
+	}
+	event := transport.events[0]
+	assert.Equal(t, "GetUser", event.Transaction)
+	assert.Equal(t, "GetUser", event.Tags["graphql.operation"])
+	assert.Equal(t, "Unavailable", event.Tags["grpc.code"])
+	assert.Equal(t, "query GetUser { user { id } }", event.Extra["graphql.query"])
+	assert.Equal(t, []string{"user service is down"}, event.Extra["graphql.errors"])
+	if assert.Len(t, event.Exception, 1) {
+		assert.Equal(t, "rpc error: code = Unavailable desc = user service is down", event.Exception[0].Value)
+	}
+	assert.Equal(t, "/graphql", event.Request.URL[len(event.Request.URL)-len("/graphql"):])
+}
@@ -9,7 +9,9 @@ import (
 )
 
 func main() {
-	mux := runtime.NewServeMux(runtime.Cors())
+	mux := runtime.NewServeMux(runtime.Cors(runtime.CorsConfig{
+		AllowedOrigins: []string{"*"},
+	}))
 
 	if err := starwars.RegisterStartwarsServiceGraphqlHandler(mux, nil); err != nil {
 		log.Fatalln(err)
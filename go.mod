@@ -6,8 +6,13 @@ require (
 	github.com/golang/protobuf v1.4.0-rc.4.0.20200313231945-b860323f09d0
 	github.com/graphql-go/graphql v0.7.8
 	github.com/iancoleman/strcase v0.0.0-20191112232945-16388991a334
-	github.com/stretchr/testify v1.6.1
+	github.com/pkg/errors v0.9.1
+	github.com/stretchr/testify v1.7.0
+	go.opentelemetry.io/otel v1.0.0
+	go.opentelemetry.io/otel/trace v1.0.0
 	golang.org/x/sys v0.0.0-20200223170610-d5e6a3e2c0ae // indirect
+	golang.org/x/text v0.3.0
 	google.golang.org/grpc v1.27.0
 	google.golang.org/protobuf v1.21.0
+	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c
 )
@@ -81,27 +81,96 @@ func (GraphqlType) EnumDescriptor() ([]byte, []int) {
 	return file_graphql_proto_rawDescGZIP(), []int{0}
 }
 
+// Nullability of list type of repeated field.
+type GraphqlListNullability int32
+
+const (
+	// Follow required option of the field, the list is nullable if the field is not required
+	GraphqlListNullability_LIST_DEFAULT GraphqlListNullability = 0
+	// [T]
+	GraphqlListNullability_NULLABLE_LIST GraphqlListNullability = 1
+	// [T]!
+	GraphqlListNullability_NON_NULL_LIST GraphqlListNullability = 2
+	// [T!]
+	GraphqlListNullability_NON_NULL_ITEMS GraphqlListNullability = 3
+	// [T!]!
+	GraphqlListNullability_NON_NULL_LIST_AND_ITEMS GraphqlListNullability = 4
+)
+
+// Enum value maps for GraphqlListNullability.
+var (
+	GraphqlListNullability_name = map[int32]string{
+		0: "LIST_DEFAULT",
+		1: "NULLABLE_LIST",
+		2: "NON_NULL_LIST",
+		3: "NON_NULL_ITEMS",
+		4: "NON_NULL_LIST_AND_ITEMS",
+	}
+	GraphqlListNullability_value = map[string]int32{
+		"LIST_DEFAULT":            0,
+		"NULLABLE_LIST":           1,
+		"NON_NULL_LIST":           2,
+		"NON_NULL_ITEMS":          3,
+		"NON_NULL_LIST_AND_ITEMS": 4,
+	}
+)
+
+func (x GraphqlListNullability) Enum() *GraphqlListNullability {
+	p := new(GraphqlListNullability)
+	*p = x
+	return p
+}
+
+func (x GraphqlListNullability) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GraphqlListNullability) Descriptor() protoreflect.EnumDescriptor {
+	return file_graphql_proto_enumTypes[1].Descriptor()
+}
+
+func (GraphqlListNullability) Type() protoreflect.EnumType {
+	return &file_graphql_proto_enumTypes[1]
+}
+
+func (x GraphqlListNullability) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GraphqlListNullability.Descriptor instead.
+func (GraphqlListNullability) EnumDescriptor() ([]byte, []int) {
+	return file_graphql_proto_rawDescGZIP(), []int{1}
+}
+
 // Extend ServiceOptions in order to define grpc connection setting.
 // User can use this option as following:
 //
-// service Greeter {
-//    option (graphql.service) = {
-//      host: "localhost:50051" // define grpc connection host and port
-//      insecure: true          // set true if connect to insecure grpc server
-//    };
+//	service Greeter {
+//	   option (graphql.service) = {
+//	     host: "localhost:50051" // define grpc connection host and port
+//	     insecure: true          // set true if connect to insecure grpc server
+//	     service_config: "{\"loadBalancingConfig\":[{\"round_robin\":{}}]}"
+//	   };
 //
-//    ... some rpc definitions
-// }
+//	   ... some rpc definitions
+//	}
 type GraphqlService struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
 	// gRPC default connection host.
-	// This value should include host and port, say localhost:50051.
+	// This value should include host and port, say localhost:50051,
+	// or unix domain socket path like unix:///var/run/app.sock.
+	// The host can be overridden at runtime via runtime.WithUpstreamHosts or GRAPHQL_UPSTREAM_* environment variable.
 	Host string `protobuf:"bytes,1,opt,name=host,proto3" json:"host,omitempty"`
 	// If true, automatic connection with insecure option.
 	Insecure bool `protobuf:"varint,2,opt,name=insecure,proto3" json:"insecure,omitempty"`
+	// Default service config in JSON for automatic connection, e.g. retry policy and load balancing policy.
+	// Host could be xds:/// target when the application imports gRPC xDS package to register the resolver.
+	ServiceConfig string `protobuf:"bytes,3,opt,name=service_config,json=serviceConfig,proto3" json:"service_config,omitempty"`
+	// TLS setting for automatic connection, which is ignored if insecure is true.
+	Tls *GraphqlTLS `protobuf:"bytes,4,opt,name=tls,proto3" json:"tls,omitempty"`
 }
 
 func (x *GraphqlService) Reset() {
@@ -150,17 +219,115 @@ func (x *GraphqlService) GetInsecure() bool {
 	return false
 }
 
+func (x *GraphqlService) GetServiceConfig() string {
+	if x != nil {
+		return x.ServiceConfig
+	}
+	return ""
+}
+
+func (x *GraphqlService) GetTls() *GraphqlTLS {
+	if x != nil {
+		return x.Tls
+	}
+	return nil
+}
+
+// TLS setting of gRPC connection. File paths are resolved at runtime, relative to the working directory.
+type GraphqlTLS struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// PEM encoded CA bundle to verify the server certificate, system roots are used if empty.
+	CaFile string `protobuf:"bytes,1,opt,name=ca_file,json=caFile,proto3" json:"ca_file,omitempty"`
+	// PEM encoded client certificate and key for mutual TLS.
+	CertFile string `protobuf:"bytes,2,opt,name=cert_file,json=certFile,proto3" json:"cert_file,omitempty"`
+	KeyFile  string `protobuf:"bytes,3,opt,name=key_file,json=keyFile,proto3" json:"key_file,omitempty"`
+	// Server name to verify the certificate and send as SNI, default is the host.
+	ServerName string `protobuf:"bytes,4,opt,name=server_name,json=serverName,proto3" json:"server_name,omitempty"`
+	// If true, server certificate is not verified. Never use this except for development.
+	InsecureSkipVerify bool `protobuf:"varint,5,opt,name=insecure_skip_verify,json=insecureSkipVerify,proto3" json:"insecure_skip_verify,omitempty"`
+}
+
+func (x *GraphqlTLS) Reset() {
+	*x = GraphqlTLS{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_graphql_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphqlTLS) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphqlTLS) ProtoMessage() {}
+
+func (x *GraphqlTLS) ProtoReflect() protoreflect.Message {
+	mi := &file_graphql_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphqlTLS.ProtoReflect.Descriptor instead.
+func (*GraphqlTLS) Descriptor() ([]byte, []int) {
+	return file_graphql_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GraphqlTLS) GetCaFile() string {
+	if x != nil {
+		return x.CaFile
+	}
+	return ""
+}
+
+func (x *GraphqlTLS) GetCertFile() string {
+	if x != nil {
+		return x.CertFile
+	}
+	return ""
+}
+
+func (x *GraphqlTLS) GetKeyFile() string {
+	if x != nil {
+		return x.KeyFile
+	}
+	return ""
+}
+
+func (x *GraphqlTLS) GetServerName() string {
+	if x != nil {
+		return x.ServerName
+	}
+	return ""
+}
+
+func (x *GraphqlTLS) GetInsecureSkipVerify() bool {
+	if x != nil {
+		return x.InsecureSkipVerify
+	}
+	return false
+}
+
 // Extend MethodOptions in order to define GraphQL Query or Mutation.
 // User can use this option as following:
 //
-// service Greeter {
-//    rpc SayHello(HelloRequest) returns (HelloReply) {
-//      option (graphql.schema) = {
-//        type: QUERY    // declare as Query
-//        name: "hello"  // query name
-//      }
-//    }
-// }
+//	service Greeter {
+//	   rpc SayHello(HelloRequest) returns (HelloReply) {
+//	     option (graphql.schema) = {
+//	       type: QUERY    // declare as Query
+//	       name: "hello"  // query name
+//	     }
+//	   }
+//	}
 //
 // Since gRPC reason, it has limitation that the response could not be repeated.
 // it's dificcurl to respond array response, so that we accept "response.pluck"
@@ -168,62 +335,62 @@ func (x *GraphqlService) GetInsecure() bool {
 //
 // For instance:
 //
-// message Member {
-//   string name = 1;
-// }
+//	message Member {
+//	  string name = 1;
+//	}
 //
-// message ListMembersResponse {
-//   repeated Member members = 1; -- could be array response
-// }
+//	message ListMembersResponse {
+//	  repeated Member members = 1; -- could be array response
+//	}
 //
 // message ListMembersRequest {
 // }
 //
-// service MemberService {
-//    rpc ListMembers(ListMembersRequest) returns (ListMembersResponse) {
-//      option (graphql.schema) = {
-//        type: QUERY
-//        name: "members"
-//        response {
-//          repeated : true
-//          pluck: "members" // Query will respond [Member] instead of ListMembersResponse
-//        }
-//      }
-//    }
-// }
+//	service MemberService {
+//	   rpc ListMembers(ListMembersRequest) returns (ListMembersResponse) {
+//	     option (graphql.schema) = {
+//	       type: QUERY
+//	       name: "members"
+//	       response {
+//	         repeated : true
+//	         pluck: "members" // Query will respond [Member] instead of ListMembersResponse
+//	       }
+//	     }
+//	   }
+//	}
 //
 // In mutation declaration:
 //
-// service MemberService {
-//    rpc CreateMember(CreateMemberRequest) returns (Member) {
-//      option (graphql.schema) = {
-//        type: MUTATION        // declare as Mutation
-//        name: "cretemember"   // mutation name
-//      }
-//    }
-// }
+//	service MemberService {
+//	   rpc CreateMember(CreateMemberRequest) returns (Member) {
+//	     option (graphql.schema) = {
+//	       type: MUTATION        // declare as Mutation
+//	       name: "cretemember"   // mutation name
+//	     }
+//	   }
+//	}
 //
 // The Mutation's input always becomes an input object, so you need to declare argument name.
 //
-// message Member {
-//   string name = 1;
-// }
+//	message Member {
+//	  string name = 1;
+//	}
 //
-// message CreateMemberRequest {
-//   string name = 1;
-// }
+//	message CreateMemberRequest {
+//	  string name = 1;
+//	}
 //
-// service MemberService {
-//    rpc CreateMember(CreateMemberRequest) returns (Member) {
-//      option (graphql.schema) = {
-//        type: MUTATION
-//        name: "createmember"
-//        request {
-//          name: "member" // this is equivalent to createbook(member: Member): Member in GraphQL
-//        }
-//      }
-//    }
-// }
+//	service MemberService {
+//	   rpc CreateMember(CreateMemberRequest) returns (Member) {
+//	     option (graphql.schema) = {
+//	       type: MUTATION
+//	       name: "createmember"
+//	       request {
+//	         name: "member" // this is equivalent to createbook(member: Member): Member in GraphQL
+//	       }
+//	     }
+//	   }
+//	}
 //
 // Finally, user can access this query via /graphql?query={members{name}}
 type GraphqlSchema struct {
@@ -233,18 +400,23 @@ type GraphqlSchema struct {
 
 	// graphql type. Enum of QUERY or MUTATION is valid value
 	Type GraphqlType `protobuf:"varint,1,opt,name=type,proto3,enum=graphql.GraphqlType" json:"type,omitempty"`
-	// query name. this field is required
+	// query name. this field is required for queries,
+	// mutation name is derived from RPC name by the naming rules of the plugin if empty
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	// Query request object configuration
 	Request *GraphqlRequest `protobuf:"bytes,3,opt,name=request,proto3" json:"request,omitempty"`
 	// Query response object configuration
 	Response *GraphqlResponse `protobuf:"bytes,4,opt,name=response,proto3" json:"response,omitempty"`
+	// Group name of the root field.
+	// Fields of the same group are placed under the namespace object of the group
+	// like "query { billing { invoices } }", independent of service boundaries.
+	Group string `protobuf:"bytes,5,opt,name=group,proto3" json:"group,omitempty"`
 }
 
 func (x *GraphqlSchema) Reset() {
 	*x = GraphqlSchema{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_graphql_proto_msgTypes[1]
+		mi := &file_graphql_proto_msgTypes[2]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -257,7 +429,7 @@ func (x *GraphqlSchema) String() string {
 func (*GraphqlSchema) ProtoMessage() {}
 
 func (x *GraphqlSchema) ProtoReflect() protoreflect.Message {
-	mi := &file_graphql_proto_msgTypes[1]
+	mi := &file_graphql_proto_msgTypes[2]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -270,7 +442,7 @@ func (x *GraphqlSchema) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphqlSchema.ProtoReflect.Descriptor instead.
 func (*GraphqlSchema) Descriptor() ([]byte, []int) {
-	return file_graphql_proto_rawDescGZIP(), []int{1}
+	return file_graphql_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *GraphqlSchema) GetType() GraphqlType {
@@ -301,6 +473,13 @@ func (x *GraphqlSchema) GetResponse() *GraphqlResponse {
 	return nil
 }
 
+func (x *GraphqlSchema) GetGroup() string {
+	if x != nil {
+		return x.Group
+	}
+	return ""
+}
+
 // configuration option for request
 type GraphqlRequest struct {
 	state         protoimpl.MessageState
@@ -313,12 +492,15 @@ type GraphqlRequest struct {
 	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
 	// Define pluck message fields
 	Plucks []string `protobuf:"bytes,2,rep,name=plucks,proto3" json:"plucks,omitempty"`
+	// If true and the request message has only one scalar field, the field is taken as the required argument
+	// like "getName(id: String!)" for "GetNameRequest { string id }", even if name is declared.
+	Unwrap bool `protobuf:"varint,3,opt,name=unwrap,proto3" json:"unwrap,omitempty"`
 }
 
 func (x *GraphqlRequest) Reset() {
 	*x = GraphqlRequest{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_graphql_proto_msgTypes[2]
+		mi := &file_graphql_proto_msgTypes[3]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -331,7 +513,7 @@ func (x *GraphqlRequest) String() string {
 func (*GraphqlRequest) ProtoMessage() {}
 
 func (x *GraphqlRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_graphql_proto_msgTypes[2]
+	mi := &file_graphql_proto_msgTypes[3]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -344,7 +526,7 @@ func (x *GraphqlRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphqlRequest.ProtoReflect.Descriptor instead.
 func (*GraphqlRequest) Descriptor() ([]byte, []int) {
-	return file_graphql_proto_rawDescGZIP(), []int{2}
+	return file_graphql_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *GraphqlRequest) GetName() string {
@@ -361,6 +543,13 @@ func (x *GraphqlRequest) GetPlucks() []string {
 	return nil
 }
 
+func (x *GraphqlRequest) GetUnwrap() bool {
+	if x != nil {
+		return x.Unwrap
+	}
+	return false
+}
+
 // configuration option for response
 type GraphqlResponse struct {
 	state         protoimpl.MessageState
@@ -374,12 +563,16 @@ type GraphqlResponse struct {
 	// Note that this field IS NOT repeated, just single string field.
 	// It means the response could only be single.
 	Pluck string `protobuf:"bytes,2,opt,name=pluck,proto3" json:"pluck,omitempty"`
+	// If true and the response message has only one field, the field is exposed directly
+	// like "getName: String!" for "GetNameResponse { string name }" instead of the message.
+	// Unlike "pluck", "required" is applied to the unwrapped field.
+	Unwrap bool `protobuf:"varint,3,opt,name=unwrap,proto3" json:"unwrap,omitempty"`
 }
 
 func (x *GraphqlResponse) Reset() {
 	*x = GraphqlResponse{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_graphql_proto_msgTypes[3]
+		mi := &file_graphql_proto_msgTypes[4]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -392,7 +585,7 @@ func (x *GraphqlResponse) String() string {
 func (*GraphqlResponse) ProtoMessage() {}
 
 func (x *GraphqlResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_graphql_proto_msgTypes[3]
+	mi := &file_graphql_proto_msgTypes[4]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -405,7 +598,7 @@ func (x *GraphqlResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphqlResponse.ProtoReflect.Descriptor instead.
 func (*GraphqlResponse) Descriptor() ([]byte, []int) {
-	return file_graphql_proto_rawDescGZIP(), []int{3}
+	return file_graphql_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GraphqlResponse) GetRequired() bool {
@@ -422,16 +615,27 @@ func (x *GraphqlResponse) GetPluck() string {
 	return ""
 }
 
+func (x *GraphqlResponse) GetUnwrap() bool {
+	if x != nil {
+		return x.Unwrap
+	}
+	return false
+}
+
 // GraphqlField is FieldOptions in protobuf in order to define type field attribute.
 // User can use this option as following:
 //
-// message Member {
-//   string name = 1 [(graphql.field) = {required: true}]; // this field is required in GraphQL, it equivalent to String! on GraphQL
-// }
+//	message Member {
+//	  string name = 1 [(graphql.field) = {required: true}]; // this field is required in GraphQL, it equivalent to String! on GraphQL
+//	}
 //
-// message CreateMemberRequest {
-//   string name = 1; [(grahpql.field) = {default: "anonymous"}]; // use default value on input or query
-// }
+//	message CreateMemberRequest {
+//	  string name = 1; [(grahpql.field) = {default: "anonymous"}]; // use default value on input or query
+//	}
+//
+//	message Book {
+//	  Author author = 1 [(graphql.field) = {cost: 10}]; // this field costs 10 on query complexity analysis
+//	}
 //
 // Note that in protobuf, all fields are dealt with optional
 // so the same as it, all GraphQL fields are optional as default.
@@ -446,17 +650,43 @@ type GraphqlField struct {
 	// Use as other field name (not recommend)
 	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
 	// Define default value on input.
+	// Enum value is declared by the name, and default value of proto2 field is used if this is empty.
 	Default string `protobuf:"bytes,3,opt,name=default,proto3" json:"default,omitempty"`
 	// Omit this field from graphql definition
 	Omit bool `protobuf:"varint,4,opt,name=omit,proto3" json:"omit,omitempty"`
 	// Resolve this field by nested query with additional RPC
 	Resolver string `protobuf:"bytes,5,opt,name=resolver,proto3" json:"resolver,omitempty"`
+	// Cost of resolving this field for query complexity analysis. Default cost is 1.
+	Cost int32 `protobuf:"varint,6,opt,name=cost,proto3" json:"cost,omitempty"`
+	// Nullability of the list of repeated field, which takes precedence over list_nullability file option and required.
+	List GraphqlListNullability `protobuf:"varint,7,opt,name=list,proto3,enum=graphql.GraphqlListNullability" json:"list,omitempty"`
+	// If true, this field stays nullable in strict non-null mode.
+	Nullable bool `protobuf:"varint,8,opt,name=nullable,proto3" json:"nullable,omitempty"`
+	// If true, this string field is exposed as ID scalar in outputs and arguments.
+	IsId bool `protobuf:"varint,9,opt,name=is_id,json=isId,proto3" json:"is_id,omitempty"`
+	// Expose this field as custom scalar of the name like "UUID" or "DateTime", which takes precedence over the field type.
+	// Coercion of the scalar is supplied by the application via ServeMux.RegisterScalar, values pass through as they are by default.
+	Scalar string `protobuf:"bytes,10,opt,name=scalar,proto3" json:"scalar,omitempty"`
+	// Directives which are applied when this field is resolved, written without "@" like "masked(char: \"*\")".
+	// Handlers of the directives are registered by the application via ServeMux.RegisterDirective.
+	Directives []string `protobuf:"bytes,11,rep,name=directives,proto3" json:"directives,omitempty"`
+	// Expose this field as GraphQL interface of the name which is declared by graphql.interface file option.
+	// The field type must be a message of single oneof whose members implement the interface, and the field resolves to the populated member:
+	//
+	//   message Node {
+	//     oneof node {
+	//       User user = 1;
+	//       Order order = 2;
+	//     }
+	//   }
+	//   repeated Node results = 1 [(graphql.field) = {interface: "Node"}]; // results: [Node]
+	Interface string `protobuf:"bytes,12,opt,name=interface,proto3" json:"interface,omitempty"`
 }
 
 func (x *GraphqlField) Reset() {
 	*x = GraphqlField{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_graphql_proto_msgTypes[4]
+		mi := &file_graphql_proto_msgTypes[5]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
@@ -469,7 +699,7 @@ func (x *GraphqlField) String() string {
 func (*GraphqlField) ProtoMessage() {}
 
 func (x *GraphqlField) ProtoReflect() protoreflect.Message {
-	mi := &file_graphql_proto_msgTypes[4]
+	mi := &file_graphql_proto_msgTypes[5]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -482,7 +712,7 @@ func (x *GraphqlField) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GraphqlField.ProtoReflect.Descriptor instead.
 func (*GraphqlField) Descriptor() ([]byte, []int) {
-	return file_graphql_proto_rawDescGZIP(), []int{4}
+	return file_graphql_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GraphqlField) GetRequired() bool {
@@ -520,7 +750,171 @@ func (x *GraphqlField) GetResolver() string {
 	return ""
 }
 
+func (x *GraphqlField) GetCost() int32 {
+	if x != nil {
+		return x.Cost
+	}
+	return 0
+}
+
+func (x *GraphqlField) GetList() GraphqlListNullability {
+	if x != nil {
+		return x.List
+	}
+	return GraphqlListNullability_LIST_DEFAULT
+}
+
+func (x *GraphqlField) GetNullable() bool {
+	if x != nil {
+		return x.Nullable
+	}
+	return false
+}
+
+func (x *GraphqlField) GetIsId() bool {
+	if x != nil {
+		return x.IsId
+	}
+	return false
+}
+
+func (x *GraphqlField) GetScalar() string {
+	if x != nil {
+		return x.Scalar
+	}
+	return ""
+}
+
+func (x *GraphqlField) GetDirectives() []string {
+	if x != nil {
+		return x.Directives
+	}
+	return nil
+}
+
+func (x *GraphqlField) GetInterface() string {
+	if x != nil {
+		return x.Interface
+	}
+	return ""
+}
+
+// GraphqlInterface is FileOptions in protobuf in order to declare GraphQL interface
+// which is implemented automatically by messages that share the field set.
+// User can declare interfaces in a standalone options file and import it from protos of services:
+//
+//	// interfaces.proto
+//	syntax = "proto3";
+//	import "graphql.proto";
+//	option (graphql.interface) = {name: "Node", fields: ["id"]};
+//
+//	// user.proto
+//	import "interfaces.proto";
+//	message User {
+//	  string id = 1 [(graphql.field) = {required: true}]; // User implements Node { id: String! }
+//	}
+//
+// Messages implement the interface if they expose all of the fields with the same types as the first implementing message.
+// Fields are typed as the interface by interface of graphql.field option, which enables polymorphic queries like
+// `{ search(text: "a") { id ... on User { name } } }`.
+type GraphqlInterface struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Interface name
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// GraphQL field names which implementing messages share
+	Fields []string `protobuf:"bytes,2,rep,name=fields,proto3" json:"fields,omitempty"`
+}
+
+func (x *GraphqlInterface) Reset() {
+	*x = GraphqlInterface{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_graphql_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *GraphqlInterface) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GraphqlInterface) ProtoMessage() {}
+
+func (x *GraphqlInterface) ProtoReflect() protoreflect.Message {
+	mi := &file_graphql_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GraphqlInterface.ProtoReflect.Descriptor instead.
+func (*GraphqlInterface) Descriptor() ([]byte, []int) {
+	return file_graphql_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GraphqlInterface) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *GraphqlInterface) GetFields() []string {
+	if x != nil {
+		return x.Fields
+	}
+	return nil
+}
+
 var file_graphql_proto_extTypes = []protoimpl.ExtensionInfo{
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: ([]*GraphqlInterface)(nil),
+		Field:         1079,
+		Name:          "graphql.interface",
+		Tag:           "bytes,1079,rep,name=interface",
+		Filename:      "graphql.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*GraphqlListNullability)(nil),
+		Field:         1080,
+		Name:          "graphql.list_nullability",
+		Tag:           "varint,1080,opt,name=list_nullability,enum=graphql.GraphqlListNullability",
+		Filename:      "graphql.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         1081,
+		Name:          "graphql.strict_non_null",
+		Tag:           "varint,1081,opt,name=strict_non_null",
+		Filename:      "graphql.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         1082,
+		Name:          "graphql.id_fields",
+		Tag:           "varint,1082,opt,name=id_fields",
+		Filename:      "graphql.proto",
+	},
+	{
+		ExtendedType:  (*descriptorpb.FileOptions)(nil),
+		ExtensionType: (*bool)(nil),
+		Field:         1083,
+		Name:          "graphql.bytes_scalar",
+		Tag:           "varint,1083,opt,name=bytes_scalar",
+		Filename:      "graphql.proto",
+	},
 	{
 		ExtendedType:  (*descriptorpb.ServiceOptions)(nil),
 		ExtensionType: (*GraphqlService)(nil),
@@ -547,22 +941,47 @@ var file_graphql_proto_extTypes = []protoimpl.ExtensionInfo{
 	},
 }
 
+// Extension fields to descriptorpb.FileOptions.
+var (
+	// repeated graphql.GraphqlInterface interface = 1079;
+	E_Interface = &file_graphql_proto_extTypes[0]
+	// Nullability of lists of repeated fields in the file, required fields also have non-null list.
+	//
+	// optional graphql.GraphqlListNullability list_nullability = 1080;
+	E_ListNullability = &file_graphql_proto_extTypes[1]
+	// If true, proto3 scalar and enum fields which are not in oneof are non-null in output types,
+	// because protobuf always supplies zero values of them. Use nullable of graphql.field option to opt-out.
+	//
+	// optional bool strict_non_null = 1081;
+	E_StrictNonNull = &file_graphql_proto_extTypes[2]
+	// If true, string fields named "id" or suffixed with "_id" in the file are exposed as ID scalar.
+	// ID is serialized as string, so that fields of other types are not affected.
+	//
+	// optional bool id_fields = 1082;
+	E_IdFields = &file_graphql_proto_extTypes[3]
+	// If true, bytes fields in the file are exposed as Bytes scalar instead of String.
+	// Values of Bytes scalar are base64 encoded into the response in streaming, which saves memory of large bytes.
+	//
+	// optional bool bytes_scalar = 1083;
+	E_BytesScalar = &file_graphql_proto_extTypes[4]
+)
+
 // Extension fields to descriptorpb.ServiceOptions.
 var (
 	// optional graphql.GraphqlService service = 1079;
-	E_Service = &file_graphql_proto_extTypes[0]
+	E_Service = &file_graphql_proto_extTypes[5]
 )
 
 // Extension fields to descriptorpb.FieldOptions.
 var (
 	// optional graphql.GraphqlField field = 1079;
-	E_Field = &file_graphql_proto_extTypes[1]
+	E_Field = &file_graphql_proto_extTypes[6]
 )
 
 // Extension fields to descriptorpb.MethodOptions.
 var (
 	// optional graphql.GraphqlSchema schema = 1079;
-	E_Schema = &file_graphql_proto_extTypes[2]
+	E_Schema = &file_graphql_proto_extTypes[7]
 )
 
 var File_graphql_proto protoreflect.FileDescriptor
@@ -571,62 +990,132 @@ var file_graphql_proto_rawDesc = []byte{
 	0x0a, 0x0d, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12,
 	0x07, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x1a, 0x20, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
 	0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x40, 0x0a, 0x0e, 0x47, 0x72,
-	0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a, 0x04,
-	0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73, 0x74,
-	0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x08, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x22, 0xb6, 0x01, 0x0a,
-	0x0d, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x28,
-	0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x67,
-	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x54, 0x79,
-	0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31, 0x0a, 0x07,
-	0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
-	0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52,
-	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x34, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x0b, 0x32, 0x18, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70,
-	0x68, 0x71, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x22, 0x3c, 0x0a, 0x0e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70,
-	0x6c, 0x75, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6c, 0x75,
-	0x63, 0x6b, 0x73, 0x22, 0x43, 0x0a, 0x0f, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65,
+	0x70, 0x74, 0x6f, 0x72, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8e, 0x01, 0x0a, 0x0e, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x12, 0x0a,
+	0x04, 0x68, 0x6f, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x68, 0x6f, 0x73,
+	0x74, 0x12, 0x1a, 0x0a, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x12, 0x25, 0x0a,
+	0x0e, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x5f, 0x63, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x43, 0x6f,
+	0x6e, 0x66, 0x69, 0x67, 0x12, 0x25, 0x0a, 0x03, 0x74, 0x6c, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x13, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70,
+	0x68, 0x71, 0x6c, 0x54, 0x4c, 0x53, 0x52, 0x03, 0x74, 0x6c, 0x73, 0x22, 0xb0, 0x01, 0x0a, 0x0a,
+	0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x54, 0x4c, 0x53, 0x12, 0x17, 0x0a, 0x07, 0x63, 0x61,
+	0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x63, 0x61, 0x46,
+	0x69, 0x6c, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x65, 0x72, 0x74, 0x5f, 0x66, 0x69, 0x6c, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x65, 0x72, 0x74, 0x46, 0x69, 0x6c, 0x65,
+	0x12, 0x19, 0x0a, 0x08, 0x6b, 0x65, 0x79, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x6b, 0x65, 0x79, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x73,
+	0x65, 0x72, 0x76, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x0a, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x30, 0x0a, 0x14,
+	0x69, 0x6e, 0x73, 0x65, 0x63, 0x75, 0x72, 0x65, 0x5f, 0x73, 0x6b, 0x69, 0x70, 0x5f, 0x76, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x12, 0x69, 0x6e, 0x73, 0x65,
+	0x63, 0x75, 0x72, 0x65, 0x53, 0x6b, 0x69, 0x70, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x22, 0xcc,
+	0x01, 0x0a, 0x0d, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x12, 0x28, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14,
+	0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x31,
+	0x0a, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x17, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71,
+	0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x52, 0x07, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x34, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72,
+	0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x52, 0x08, 0x72,
+	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x22, 0x54, 0x0a,
+	0x0e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x75,
+	0x6e, 0x77, 0x72, 0x61, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x6e, 0x77,
+	0x72, 0x61, 0x70, 0x22, 0x5b, 0x0a, 0x0f, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x52, 0x65,
 	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72,
 	0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72,
 	0x65, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x22, 0x88, 0x01, 0x0a, 0x0c, 0x47, 0x72, 0x61,
-	0x70, 0x68, 0x71, 0x6c, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71,
-	0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71,
-	0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66,
-	0x61, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61,
-	0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6f, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x04, 0x6f, 0x6d, 0x69, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c,
-	0x76, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c,
-	0x76, 0x65, 0x72, 0x2a, 0x34, 0x0a, 0x0b, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x54, 0x79,
-	0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x51, 0x55, 0x45, 0x52, 0x59, 0x10, 0x00, 0x12, 0x0c, 0x0a,
-	0x08, 0x4d, 0x55, 0x54, 0x41, 0x54, 0x49, 0x4f, 0x4e, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x52,
-	0x45, 0x53, 0x4f, 0x4c, 0x56, 0x45, 0x52, 0x10, 0x02, 0x3a, 0x53, 0x0a, 0x07, 0x73, 0x65, 0x72,
-	0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x4f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb7, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x67,
-	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x3a, 0x4b,
-	0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
-	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x4f,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb7, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x15, 0x2e,
-	0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x46,
-	0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a, 0x4f, 0x0a, 0x06, 0x73,
-	0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70,
-	0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68, 0x6f, 0x64, 0x4f, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb7, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67,
-	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x53, 0x63,
-	0x68, 0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x42, 0x33, 0x5a, 0x31,
-	0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x79, 0x73, 0x75, 0x67, 0x69,
-	0x6d, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71,
-	0x6c, 0x2d, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2f, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71,
-	0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+	0x09, 0x52, 0x05, 0x70, 0x6c, 0x75, 0x63, 0x6b, 0x12, 0x16, 0x0a, 0x06, 0x75, 0x6e, 0x77, 0x72,
+	0x61, 0x70, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x75, 0x6e, 0x77, 0x72, 0x61, 0x70,
+	0x22, 0xd8, 0x02, 0x0a, 0x0c, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x46, 0x69, 0x65, 0x6c,
+	0x64, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x69, 0x72, 0x65, 0x64, 0x12, 0x12, 0x0a,
+	0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d,
+	0x65, 0x12, 0x18, 0x0a, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x64, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x6f,
+	0x6d, 0x69, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x04, 0x6f, 0x6d, 0x69, 0x74, 0x12,
+	0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x6f, 0x6c, 0x76, 0x65, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63,
+	0x6f, 0x73, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x63, 0x6f, 0x73, 0x74, 0x12,
+	0x33, 0x0a, 0x04, 0x6c, 0x69, 0x73, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e,
+	0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x4c,
+	0x69, 0x73, 0x74, 0x4e, 0x75, 0x6c, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x04,
+	0x6c, 0x69, 0x73, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x75, 0x6c, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x6e, 0x75, 0x6c, 0x6c, 0x61, 0x62, 0x6c, 0x65,
+	0x12, 0x13, 0x0a, 0x05, 0x69, 0x73, 0x5f, 0x69, 0x64, 0x18, 0x09, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x04, 0x69, 0x73, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x18,
+	0x0a, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x12, 0x1e, 0x0a,
+	0x0a, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x73, 0x18, 0x0b, 0x20, 0x03, 0x28,
+	0x09, 0x52, 0x0a, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x76, 0x65, 0x73, 0x12, 0x1c, 0x0a,
+	0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x22, 0x3e, 0x0a, 0x10, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x2a, 0x34, 0x0a, 0x0b, 0x47,
+	0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x51, 0x55,
+	0x45, 0x52, 0x59, 0x10, 0x00, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x55, 0x54, 0x41, 0x54, 0x49, 0x4f,
+	0x4e, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x52, 0x45, 0x53, 0x4f, 0x4c, 0x56, 0x45, 0x52, 0x10,
+	0x02, 0x2a, 0x81, 0x01, 0x0a, 0x16, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x4c, 0x69, 0x73,
+	0x74, 0x4e, 0x75, 0x6c, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x10, 0x0a, 0x0c,
+	0x4c, 0x49, 0x53, 0x54, 0x5f, 0x44, 0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x00, 0x12, 0x11,
+	0x0a, 0x0d, 0x4e, 0x55, 0x4c, 0x4c, 0x41, 0x42, 0x4c, 0x45, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x10,
+	0x01, 0x12, 0x11, 0x0a, 0x0d, 0x4e, 0x4f, 0x4e, 0x5f, 0x4e, 0x55, 0x4c, 0x4c, 0x5f, 0x4c, 0x49,
+	0x53, 0x54, 0x10, 0x02, 0x12, 0x12, 0x0a, 0x0e, 0x4e, 0x4f, 0x4e, 0x5f, 0x4e, 0x55, 0x4c, 0x4c,
+	0x5f, 0x49, 0x54, 0x45, 0x4d, 0x53, 0x10, 0x03, 0x12, 0x1b, 0x0a, 0x17, 0x4e, 0x4f, 0x4e, 0x5f,
+	0x4e, 0x55, 0x4c, 0x4c, 0x5f, 0x4c, 0x49, 0x53, 0x54, 0x5f, 0x41, 0x4e, 0x44, 0x5f, 0x49, 0x54,
+	0x45, 0x4d, 0x53, 0x10, 0x04, 0x3a, 0x56, 0x0a, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
+	0x63, 0x65, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
+	0x18, 0xb7, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71,
+	0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61,
+	0x63, 0x65, 0x52, 0x09, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x66, 0x61, 0x63, 0x65, 0x3a, 0x69, 0x0a,
+	0x10, 0x6c, 0x69, 0x73, 0x74, 0x5f, 0x6e, 0x75, 0x6c, 0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74,
+	0x79, 0x12, 0x1c, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x62, 0x75, 0x66, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18,
+	0xb8, 0x08, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1f, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c,
+	0x2e, 0x47, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x4c, 0x69, 0x73, 0x74, 0x4e, 0x75, 0x6c, 0x6c,
+	0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x52, 0x0f, 0x6c, 0x69, 0x73, 0x74, 0x4e, 0x75, 0x6c,
+	0x6c, 0x61, 0x62, 0x69, 0x6c, 0x69, 0x74, 0x79, 0x3a, 0x45, 0x0a, 0x0f, 0x73, 0x74, 0x72, 0x69,
+	0x63, 0x74, 0x5f, 0x6e, 0x6f, 0x6e, 0x5f, 0x6e, 0x75, 0x6c, 0x6c, 0x12, 0x1c, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb9, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0d, 0x73, 0x74, 0x72, 0x69, 0x63, 0x74, 0x4e, 0x6f, 0x6e, 0x4e, 0x75, 0x6c, 0x6c, 0x3a,
+	0x3a, 0x0a, 0x09, 0x69, 0x64, 0x5f, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x12, 0x1c, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46,
+	0x69, 0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xba, 0x08, 0x20, 0x01, 0x28,
+	0x08, 0x52, 0x08, 0x69, 0x64, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x73, 0x3a, 0x40, 0x0a, 0x0c, 0x62,
+	0x79, 0x74, 0x65, 0x73, 0x5f, 0x73, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x12, 0x1c, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x6c, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xbb, 0x08, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x0b, 0x62, 0x79, 0x74, 0x65, 0x73, 0x53, 0x63, 0x61, 0x6c, 0x61, 0x72, 0x3a, 0x53, 0x0a,
+	0x07, 0x73, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x1f, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x53, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb7, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x17, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68,
+	0x71, 0x6c, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x52, 0x07, 0x73, 0x65, 0x72, 0x76, 0x69,
+	0x63, 0x65, 0x3a, 0x4b, 0x0a, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x12, 0x1d, 0x2e, 0x67, 0x6f,
+	0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x46, 0x69,
+	0x65, 0x6c, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb7, 0x08, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70,
+	0x68, 0x71, 0x6c, 0x46, 0x69, 0x65, 0x6c, 0x64, 0x52, 0x05, 0x66, 0x69, 0x65, 0x6c, 0x64, 0x3a,
+	0x4f, 0x0a, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x12, 0x1e, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
+	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x4d, 0x65, 0x74, 0x68,
+	0x6f, 0x64, 0x4f, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0xb7, 0x08, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x16, 0x2e, 0x67, 0x72, 0x61, 0x70, 0x68, 0x71, 0x6c, 0x2e, 0x47, 0x72, 0x61, 0x70, 0x68,
+	0x71, 0x6c, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x52, 0x06, 0x73, 0x63, 0x68, 0x65, 0x6d, 0x61,
+	0x42, 0x33, 0x5a, 0x31, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x79,
+	0x73, 0x75, 0x67, 0x69, 0x6d, 0x6f, 0x74, 0x6f, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2d, 0x67, 0x72,
+	0x61, 0x70, 0x68, 0x71, 0x6c, 0x2d, 0x67, 0x61, 0x74, 0x65, 0x77, 0x61, 0x79, 0x2f, 0x67, 0x72,
+	0x61, 0x70, 0x68, 0x71, 0x6c, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -641,34 +1130,47 @@ func file_graphql_proto_rawDescGZIP() []byte {
 	return file_graphql_proto_rawDescData
 }
 
-var file_graphql_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_graphql_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_graphql_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_graphql_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
 var file_graphql_proto_goTypes = []interface{}{
 	(GraphqlType)(0),                    // 0: graphql.GraphqlType
-	(*GraphqlService)(nil),              // 1: graphql.GraphqlService
-	(*GraphqlSchema)(nil),               // 2: graphql.GraphqlSchema
-	(*GraphqlRequest)(nil),              // 3: graphql.GraphqlRequest
-	(*GraphqlResponse)(nil),             // 4: graphql.GraphqlResponse
-	(*GraphqlField)(nil),                // 5: graphql.GraphqlField
-	(*descriptorpb.ServiceOptions)(nil), // 6: google.protobuf.ServiceOptions
-	(*descriptorpb.FieldOptions)(nil),   // 7: google.protobuf.FieldOptions
-	(*descriptorpb.MethodOptions)(nil),  // 8: google.protobuf.MethodOptions
+	(GraphqlListNullability)(0),         // 1: graphql.GraphqlListNullability
+	(*GraphqlService)(nil),              // 2: graphql.GraphqlService
+	(*GraphqlTLS)(nil),                  // 3: graphql.GraphqlTLS
+	(*GraphqlSchema)(nil),               // 4: graphql.GraphqlSchema
+	(*GraphqlRequest)(nil),              // 5: graphql.GraphqlRequest
+	(*GraphqlResponse)(nil),             // 6: graphql.GraphqlResponse
+	(*GraphqlField)(nil),                // 7: graphql.GraphqlField
+	(*GraphqlInterface)(nil),            // 8: graphql.GraphqlInterface
+	(*descriptorpb.FileOptions)(nil),    // 9: google.protobuf.FileOptions
+	(*descriptorpb.ServiceOptions)(nil), // 10: google.protobuf.ServiceOptions
+	(*descriptorpb.FieldOptions)(nil),   // 11: google.protobuf.FieldOptions
+	(*descriptorpb.MethodOptions)(nil),  // 12: google.protobuf.MethodOptions
 }
 var file_graphql_proto_depIdxs = []int32{
-	0, // 0: graphql.GraphqlSchema.type:type_name -> graphql.GraphqlType
-	3, // 1: graphql.GraphqlSchema.request:type_name -> graphql.GraphqlRequest
-	4, // 2: graphql.GraphqlSchema.response:type_name -> graphql.GraphqlResponse
-	6, // 3: graphql.service:extendee -> google.protobuf.ServiceOptions
-	7, // 4: graphql.field:extendee -> google.protobuf.FieldOptions
-	8, // 5: graphql.schema:extendee -> google.protobuf.MethodOptions
-	1, // 6: graphql.service:type_name -> graphql.GraphqlService
-	5, // 7: graphql.field:type_name -> graphql.GraphqlField
-	2, // 8: graphql.schema:type_name -> graphql.GraphqlSchema
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	6, // [6:9] is the sub-list for extension type_name
-	3, // [3:6] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	3,  // 0: graphql.GraphqlService.tls:type_name -> graphql.GraphqlTLS
+	0,  // 1: graphql.GraphqlSchema.type:type_name -> graphql.GraphqlType
+	5,  // 2: graphql.GraphqlSchema.request:type_name -> graphql.GraphqlRequest
+	6,  // 3: graphql.GraphqlSchema.response:type_name -> graphql.GraphqlResponse
+	1,  // 4: graphql.GraphqlField.list:type_name -> graphql.GraphqlListNullability
+	9,  // 5: graphql.interface:extendee -> google.protobuf.FileOptions
+	9,  // 6: graphql.list_nullability:extendee -> google.protobuf.FileOptions
+	9,  // 7: graphql.strict_non_null:extendee -> google.protobuf.FileOptions
+	9,  // 8: graphql.id_fields:extendee -> google.protobuf.FileOptions
+	9,  // 9: graphql.bytes_scalar:extendee -> google.protobuf.FileOptions
+	10, // 10: graphql.service:extendee -> google.protobuf.ServiceOptions
+	11, // 11: graphql.field:extendee -> google.protobuf.FieldOptions
+	12, // 12: graphql.schema:extendee -> google.protobuf.MethodOptions
+	8,  // 13: graphql.interface:type_name -> graphql.GraphqlInterface
+	1,  // 14: graphql.list_nullability:type_name -> graphql.GraphqlListNullability
+	2,  // 15: graphql.service:type_name -> graphql.GraphqlService
+	7,  // 16: graphql.field:type_name -> graphql.GraphqlField
+	4,  // 17: graphql.schema:type_name -> graphql.GraphqlSchema
+	18, // [18:18] is the sub-list for method output_type
+	18, // [18:18] is the sub-list for method input_type
+	13, // [13:18] is the sub-list for extension type_name
+	5,  // [5:13] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_graphql_proto_init() }
@@ -690,7 +1192,7 @@ func file_graphql_proto_init() {
 			}
 		}
 		file_graphql_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GraphqlSchema); i {
+			switch v := v.(*GraphqlTLS); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -702,7 +1204,7 @@ func file_graphql_proto_init() {
 			}
 		}
 		file_graphql_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GraphqlRequest); i {
+			switch v := v.(*GraphqlSchema); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -714,7 +1216,7 @@ func file_graphql_proto_init() {
 			}
 		}
 		file_graphql_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
-			switch v := v.(*GraphqlResponse); i {
+			switch v := v.(*GraphqlRequest); i {
 			case 0:
 				return &v.state
 			case 1:
@@ -726,6 +1228,18 @@ func file_graphql_proto_init() {
 			}
 		}
 		file_graphql_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphqlResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_graphql_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
 			switch v := v.(*GraphqlField); i {
 			case 0:
 				return &v.state
@@ -737,15 +1251,27 @@ func file_graphql_proto_init() {
 				return nil
 			}
 		}
+		file_graphql_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*GraphqlInterface); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_graphql_proto_rawDesc,
-			NumEnums:      1,
-			NumMessages:   5,
-			NumExtensions: 3,
+			NumEnums:      2,
+			NumMessages:   7,
+			NumExtensions: 8,
 			NumServices:   0,
 		},
 		GoTypes:           file_graphql_proto_goTypes,
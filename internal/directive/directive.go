@@ -0,0 +1,60 @@
+// Package directive parses directives which are declared by (graphql.field).directives option.
+// It is shared by runtime and protoc-gen-graphql, so that the plugin validates directives
+// with the same grammar as runtime without depending on runtime.
+package directive
+
+import (
+	"errors"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+)
+
+// Parse parses the directive in GraphQL syntax without "@" like `masked(char: "*")`,
+// variables are not allowed in arguments
+func Parse(directive string) (*ast.Directive, error) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: "{ _ @" + directive + " }",
+	})
+	if err != nil {
+		return nil, errors.New("invalid directive: '" + directive + "'")
+	}
+	// The directive must not break out of the wrapping field like "a } { b"
+	if len(doc.Definitions) != 1 {
+		return nil, errors.New("invalid directive: '" + directive + "'")
+	}
+	op, ok := doc.Definitions[0].(*ast.OperationDefinition)
+	if !ok || op.SelectionSet == nil || len(op.SelectionSet.Selections) != 1 {
+		return nil, errors.New("invalid directive: '" + directive + "'")
+	}
+	field, ok := op.SelectionSet.Selections[0].(*ast.Field)
+	if !ok || len(field.Directives) != 1 || field.SelectionSet != nil {
+		return nil, errors.New("invalid directive: '" + directive + "'")
+	}
+	for _, arg := range field.Directives[0].Arguments {
+		if hasVariable(arg.Value) {
+			return nil, errors.New("variables are not allowed in directive: '" + directive + "'")
+		}
+	}
+	return field.Directives[0], nil
+}
+
+func hasVariable(value ast.Value) bool {
+	switch v := value.(type) {
+	case *ast.Variable:
+		return true
+	case *ast.ListValue:
+		for _, item := range v.Values {
+			if hasVariable(item) {
+				return true
+			}
+		}
+	case *ast.ObjectValue:
+		for _, f := range v.Fields {
+			if hasVariable(f.Value) {
+				return true
+			}
+		}
+	}
+	return false
+}
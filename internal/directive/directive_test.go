@@ -0,0 +1,27 @@
+package directive
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	d, err := Parse(`masked(char: "*", keep: 2)`)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "masked", d.Name.Value)
+		assert.Len(t, d.Arguments, 2)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"@masked",
+		"upper case",
+		"a } { b",
+		"masked(keep: $keep)",
+		"masked(keep: [1, $keep])",
+	} {
+		_, err := Parse(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
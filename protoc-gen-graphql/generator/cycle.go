@@ -0,0 +1,85 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/ysugimoto/grpc-graphql-gateway/protoc-gen-graphql/spec"
+)
+
+// inputEdges returns message fields of the input which refer to other inputs
+func inputEdges(m *spec.Message, inputs map[*spec.Message]struct{}) []*spec.Field {
+	var edges []*spec.Field
+	for _, f := range m.Fields() {
+		if f.Type() != descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+			continue
+		}
+		dep, ok := f.DependType.(*spec.Message)
+		if !ok {
+			continue
+		}
+		if _, ok := inputs[dep]; ok {
+			edges = append(edges, f)
+		}
+	}
+	return edges
+}
+
+// checkInputCycles marks inputs which refer to themselves via input fields, so that their fields are resolved lazily.
+// GraphQL can represent the cycle only if it has nullable or list field,
+// because a value of the input whose fields are all non-null in the cycle never ends.
+// Returns an error which names the cycle of non-null fields if exists.
+func checkInputCycles(inputs []*spec.Message) error {
+	set := make(map[*spec.Message]struct{}, len(inputs))
+	for _, m := range inputs {
+		set[m] = struct{}{}
+	}
+
+	for _, m := range inputs {
+		m.IsCyclicInput = cyclePath(m, m, set, false, map[*spec.Message]struct{}{}, nil) != nil
+	}
+	for _, m := range inputs {
+		if !m.IsCyclicInput {
+			continue
+		}
+		if path := cyclePath(m, m, set, true, map[*spec.Message]struct{}{}, nil); path != nil {
+			return fmt.Errorf(
+				"input %s has a cycle of non-null fields %s -> %s which cannot be represented in GraphQL, "+
+					"make one of the fields optional by removing required option, or repeated",
+				m.FullPath(), strings.Join(path, " -> "), m.FullPath(),
+			)
+		}
+	}
+	return nil
+}
+
+// cyclePath returns fields like "pkg.A.b" on the path from m to the target, or nil if the target is not reachable.
+// If nonNull is true, only fields which must be given in the input are followed.
+func cyclePath(
+	m, target *spec.Message,
+	inputs map[*spec.Message]struct{},
+	nonNull bool,
+	visited map[*spec.Message]struct{},
+	path []string,
+) []string {
+
+	visited[m] = struct{}{}
+	for _, f := range inputEdges(m, inputs) {
+		if nonNull && (!f.IsRequired() || f.IsRepeated()) {
+			continue
+		}
+		dep := f.DependType.(*spec.Message) // nolint: errcheck
+		next := append(path[:len(path):len(path)], m.FullPath()+"."+f.Name())
+		if dep == target {
+			return next
+		}
+		if _, ok := visited[dep]; ok {
+			continue
+		}
+		if found := cyclePath(dep, target, inputs, nonNull, visited, next); found != nil {
+			return found
+		}
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/grpc-graphql-gateway/protoc-gen-graphql/spec"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+const testCycleFile = `
+name: "cycle.proto"
+package: "cycle"
+syntax: "proto3"
+message_type {
+  name: "A"
+  field { name: "b" number: 1 type: TYPE_MESSAGE type_name: ".cycle.B" label: LABEL_OPTIONAL json_name: "b" options { [graphql.field] { required: true } } }
+  field { name: "c" number: 2 type: TYPE_MESSAGE type_name: ".cycle.C" label: LABEL_OPTIONAL json_name: "c" }
+}
+message_type {
+  name: "B"
+  field { name: "a" number: 1 type: TYPE_MESSAGE type_name: ".cycle.A" label: LABEL_OPTIONAL json_name: "a" }
+}
+message_type {
+  name: "C"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+}
+`
+
+// testInputs returns messages of the file as inputs whose message fields refer to the messages
+func testInputs(t *testing.T, file string) map[string]*spec.Message {
+	var fd descriptor.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(file), &fd); err != nil {
+		t.Fatal(err)
+	}
+	f := spec.NewFile(&fd, nil, false)
+	messages := make(map[string]*spec.Message)
+	for _, m := range f.Messages() {
+		messages[m.FullPath()] = m
+	}
+	for _, m := range f.Messages() {
+		for _, field := range m.Fields() {
+			if dep, ok := messages[field.TypeName()]; ok {
+				field.DependType = dep
+			}
+		}
+	}
+	return messages
+}
+
+func inputList(messages map[string]*spec.Message) []*spec.Message {
+	return []*spec.Message{messages["cycle.A"], messages["cycle.B"], messages["cycle.C"]}
+}
+
+func TestCheckInputCycles(t *testing.T) {
+	messages := testInputs(t, testCycleFile)
+	assert.NoError(t, checkInputCycles(inputList(messages)))
+
+	// Inputs on the cycle are resolved lazily
+	assert.True(t, messages["cycle.A"].IsCyclicInput)
+	assert.True(t, messages["cycle.B"].IsCyclicInput)
+	assert.False(t, messages["cycle.C"].IsCyclicInput)
+}
+
+func TestCheckInputCyclesNonNull(t *testing.T) {
+	cases := []struct {
+		name    string
+		replace [2]string
+		err     string
+	}{
+		{
+			name: "required field",
+			replace: [2]string{
+				`label: LABEL_OPTIONAL json_name: "a" }`,
+				`label: LABEL_OPTIONAL json_name: "a" options { [graphql.field] { required: true } } }`,
+			},
+			err: "input cycle.A has a cycle of non-null fields cycle.A.b -> cycle.B.a -> cycle.A",
+		},
+		{
+			// Empty list ends the cycle even if the field is required
+			name: "required repeated field",
+			replace: [2]string{
+				`label: LABEL_OPTIONAL json_name: "a" }`,
+				`label: LABEL_REPEATED json_name: "a" options { [graphql.field] { required: true } } }`,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if !strings.Contains(testCycleFile, c.replace[0]) {
+				t.Fatalf("%s is not found in the file", c.replace[0])
+			}
+			messages := testInputs(t, strings.Replace(testCycleFile, c.replace[0], c.replace[1], 1))
+			err := checkInputCycles(inputList(messages))
+			if c.err == "" {
+				assert.NoError(t, err)
+				assert.True(t, messages["cycle.A"].IsCyclicInput)
+				return
+			}
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
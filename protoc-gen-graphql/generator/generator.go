@@ -6,7 +6,9 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"strings"
 
+	"encoding/json"
 	"go/format"
 	"io/ioutil"
 	"text/template"
@@ -21,6 +23,7 @@ import (
 
 type Template struct {
 	RootPackage *spec.Package
+	Naming      *spec.Naming
 
 	Packages   []*spec.Package
 	Types      []*spec.Message
@@ -28,6 +31,141 @@ type Template struct {
 	Enums      []*spec.Enum
 	Inputs     []*spec.Message
 	Services   []*spec.Service
+	// Interfaces of graphql.interface option which the package uses
+	DeclaredInterfaces []*DeclaredInterface
+	// Client is true if typed clients of services are generated
+	Client bool
+}
+
+// ImplementedInterfaces returns interfaces of graphql.interface option which the message implements
+func (t *Template) ImplementedInterfaces(m *spec.Message) []*DeclaredInterface {
+	var ifaces []*DeclaredInterface
+	for _, d := range t.DeclaredInterfaces {
+		for _, v := range d.Types {
+			if v.Message == m {
+				ifaces = append(ifaces, d)
+			}
+		}
+	}
+	return ifaces
+}
+
+// HasFieldCost returns true if some type fields are annotated with cost option
+func (t *Template) HasFieldCost() bool {
+	for _, m := range t.Types {
+		for _, f := range m.Fields() {
+			if f.Cost() > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasFieldDirectives returns true if some type fields are annotated with directives option
+func (t *Template) HasFieldDirectives() bool {
+	for _, m := range t.Types {
+		for _, f := range m.Fields() {
+			if len(f.Directives()) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasOneofInputs returns true if some inputs are @oneOf inputs which are declared to runtime
+func (t *Template) HasOneofInputs() bool {
+	for _, m := range t.Inputs {
+		if m.IsOneofInput() {
+			return true
+		}
+	}
+	return false
+}
+
+// HasMapFields returns true if some type fields are protobuf maps which are resolved by runtime
+func (t *Template) HasMapFields() bool {
+	for _, m := range t.Types {
+		for _, f := range m.Fields() {
+			if f.IsMap() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// HasRuntimeScalars returns true if some type or input fields are exposed as runtime scalars,
+// which are Bytes of protobuf bytes fields by bytes_scalar option and custom scalars of scalar option
+func (t *Template) HasRuntimeScalars() bool {
+	for _, ms := range [][]*spec.Message{t.Types, t.Inputs} {
+		for _, m := range ms {
+			for _, f := range m.Fields() {
+				if (f.Type() == descriptor.FieldDescriptorProto_TYPE_BYTES && f.BytesScalar()) || f.CustomScalar() != "" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// ClientSelection returns selection set of the response message for typed client,
+// or selection set of the plucked field if the response is plucked.
+// Fields are aliased to the names of message fields, and fields which refer the message on the path are not selected.
+func (t *Template) ClientSelection(m *spec.Message, pluck string) string {
+	if pluck == "" {
+		return t.selection(m.Fields(), map[*spec.Message]struct{}{m: {}})
+	}
+	for _, f := range m.Fields() {
+		if f.Name() != pluck {
+			continue
+		}
+		if dm, ok := f.DependType.(*spec.Message); ok && f.Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+			return t.selection(dm.Fields(), map[*spec.Message]struct{}{dm: {}})
+		}
+	}
+	return ""
+}
+
+func (t *Template) selection(fields []*spec.Field, visited map[*spec.Message]struct{}) string {
+	var selections []string
+	for _, f := range fields {
+		// Resolver fields call another RPC, and polymorphic values of interfaces can't be decoded into messages
+		if f.IsResolve() || f.InterfaceType != "" {
+			continue
+		}
+		name := t.Naming.FieldName(f)
+		if name != f.Name() {
+			name = f.Name() + ": " + name
+		}
+		if f.Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+			// Custom scalar values of messages are application specific, which can't be decoded into the message
+			if f.CustomScalar() != "" {
+				continue
+			}
+			m, ok := f.DependType.(*spec.Message)
+			if !ok {
+				continue
+			}
+			if _, ok := visited[m]; ok {
+				continue
+			}
+			visited[m] = struct{}{}
+			sub := t.selection(m.Fields(), visited)
+			delete(visited, m)
+			if sub == "" {
+				continue
+			}
+			name += " " + sub
+		}
+		selections = append(selections, name)
+	}
+	if len(selections) == 0 {
+		return ""
+	}
+	return "{ " + strings.Join(selections, " ") + " }"
 }
 
 // Generator is struct for analyzing protobuf definition
@@ -38,6 +176,10 @@ type Generator struct {
 	messages map[string]*spec.Message
 	enums    map[string]*spec.Enum
 	logger   *Logger
+
+	// Interfaces of graphql.interface option by the declared name, and the names in declaration order
+	interfaces     map[string]*declaredInterface
+	interfaceNames []string
 }
 
 func New(files []*spec.File, args *spec.Params) *Generator {
@@ -58,13 +200,16 @@ func New(files []*spec.File, args *spec.Params) *Generator {
 		w = os.Stderr
 	}
 
-	return &Generator{
-		files:    files,
-		args:     args,
-		messages: messages,
-		enums:    enums,
-		logger:   NewLogger(w),
+	g := &Generator{
+		files:      files,
+		args:       args,
+		messages:   messages,
+		enums:      enums,
+		logger:     NewLogger(w),
+		interfaces: make(map[string]*declaredInterface),
 	}
+	g.analyzeInterfaces()
+	return g
 }
 
 func (g *Generator) Generate(tmpl string, fs []string) ([]*plugin.CodeGeneratorResponse_File, error) {
@@ -211,6 +356,9 @@ func (g *Generator) generateFile(file *spec.File, tmpl string, services []*spec.
 	sort.Slice(inputs, func(i, j int) bool {
 		return inputs[i].Name() > inputs[j].Name()
 	})
+	if err := checkInputCycles(inputs); err != nil {
+		return nil, err
+	}
 	sort.Slice(interfaces, func(i, j int) bool {
 		return interfaces[i].Name() > interfaces[j].Name()
 	})
@@ -220,13 +368,16 @@ func (g *Generator) generateFile(file *spec.File, tmpl string, services []*spec.
 
 	root := spec.NewPackage(file)
 	t := &Template{
-		RootPackage: root,
-		Packages:    uniquePackages,
-		Types:       types,
-		Enums:       enums,
-		Inputs:      inputs,
-		Interfaces:  interfaces,
-		Services:    services,
+		RootPackage:        root,
+		Naming:             g.args.Naming,
+		Packages:           uniquePackages,
+		Types:              types,
+		Enums:              enums,
+		Inputs:             inputs,
+		Interfaces:         interfaces,
+		Services:           services,
+		DeclaredInterfaces: g.declaredInterfaces(file, types, services),
+		Client:             g.args.Client,
 	}
 
 	buf := new(bytes.Buffer)
@@ -318,6 +469,9 @@ func (g *Generator) analyzeServices() (map[string][]*spec.Service, error) {
 }
 
 func (g *Generator) analyzeService(f *spec.File, s *spec.Service) error {
+	if c := s.ServiceConfig(); c != "" && !json.Valid([]byte(c)) {
+		return errors.New("invalid service config of service " + s.Name() + ": must be JSON")
+	}
 	for _, m := range s.Methods() {
 		if m.Schema == nil {
 			continue
@@ -340,6 +494,7 @@ func (g *Generator) analyzeService(f *spec.File, s *spec.Service) error {
 			s.Queries = append(s.Queries, q)
 		case graphql.GraphqlType_MUTATION:
 			mu := spec.NewMutation(m, input, output, g.args.FieldCamelCase)
+			mu.DefaultName = g.args.Naming.MutationName(s.FullName(), m.Name())
 			if err := g.analyzeMutation(f, mu); err != nil {
 				return err
 			}
@@ -394,7 +549,17 @@ func (g *Generator) analyzeFields(
 				return errors.New("failed to resolve field message type: " + f.TypeName())
 			}
 			f.DependType = m
+			if !asInput && f.Interface() != "" {
+				if err := g.analyzeInterfaceField(rootPkg, f, m); err != nil {
+					return err
+				}
+				continue
+			}
 			if asInput {
+				// Fields have been analyzed already, or are being analyzed in cyclic inputs
+				if m.IsDepended(spec.DependTypeInput, rootPkg) {
+					continue
+				}
 				g.logger.Write("package %s depends on input %s", rootPkg, m.FullPath())
 				m.Depend(spec.DependTypeInput, rootPkg)
 			} else {
@@ -0,0 +1,220 @@
+package generator
+
+import (
+	"errors"
+	"strings"
+
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/ysugimoto/grpc-graphql-gateway/protoc-gen-graphql/spec"
+)
+
+// DeclaredInterface is interface of graphql.interface file option which the generated package uses,
+// the interface is shared with other packages by runtime.DeclaredInterface
+type DeclaredInterface struct {
+	// GraphQL name of the interface
+	Name string
+	// Fields of the first implementing message, which declare types of the interface fields
+	Fields []*spec.Field
+	// Implementing messages whose object types are generated in the package or imported packages
+	Types []*InterfaceType
+}
+
+// InterfaceType is object type of the message which implements the declared interface
+type InterfaceType struct {
+	Message *spec.Message
+	// Go function which returns the object type
+	Func string
+}
+
+// declaredInterface holds messages which implement interface of graphql.interface option in file order
+type declaredInterface struct {
+	*spec.Interface
+	name       string
+	implements []*spec.Message
+}
+
+func (di *declaredInterface) isImplemented(m *spec.Message) bool {
+	for _, v := range di.implements {
+		if v == m {
+			return true
+		}
+	}
+	return false
+}
+
+// analyzeInterfaces finds messages which implement interfaces of graphql.interface option in the files.
+// Messages implement the interface if they expose all of the fields with the same types as the first implementing message.
+// Interfaces are declared once per name, and ones which no message implements are ignored.
+func (g *Generator) analyzeInterfaces() {
+	for _, f := range g.files {
+		for _, i := range f.Interfaces() {
+			if _, ok := g.interfaces[i.Name()]; ok {
+				continue
+			}
+			di := &declaredInterface{
+				Interface: i,
+				name:      g.args.Naming.InterfaceName(spec.NewPackage(i).CamelName, i.Name()),
+			}
+			var types []string
+			for _, file := range g.files {
+				// Messages of options and well-known types are not the ones of services
+				if pkg := file.Package(); strings.HasPrefix(pkg, "google.protobuf") || pkg == "graphql" {
+					continue
+				}
+				for _, m := range file.Messages() {
+					fields := g.interfaceFields(m, i.FieldNames())
+					if fields == nil {
+						continue
+					}
+					keys := make([]string, len(fields))
+					for j, field := range fields {
+						keys[j] = field.FieldType("") + " " + field.TypeName()
+					}
+					if types == nil {
+						types = keys
+					} else if strings.Join(types, ",") != strings.Join(keys, ",") {
+						continue
+					}
+					di.implements = append(di.implements, m)
+				}
+			}
+			if len(di.implements) == 0 {
+				continue
+			}
+			g.logger.Write("interface %s is implemented by %d messages", di.name, len(di.implements))
+			g.interfaces[i.Name()] = di
+			g.interfaceNames = append(g.interfaceNames, i.Name())
+		}
+	}
+}
+
+// interfaceFields returns the fields of the message which are exposed as GraphQL field names in order,
+// nil if the message doesn't expose some of them
+func (g *Generator) interfaceFields(m *spec.Message, names []string) []*spec.Field {
+	if m.IsMapEntry() {
+		return nil
+	}
+	fields := make([]*spec.Field, 0, len(names))
+	for _, name := range names {
+		var field *spec.Field
+		for _, f := range m.Fields() {
+			if !f.IsResolve() && f.Interface() == "" && g.args.Naming.FieldName(f) == name {
+				field = f
+				break
+			}
+		}
+		if field == nil {
+			return nil
+		}
+		// Types of fields are compared before the fields are analyzed
+		switch field.Type() {
+		case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+			if field.DependType = g.getMessage(field.TypeName()); field.DependType == nil {
+				return nil
+			}
+		case descriptor.FieldDescriptorProto_TYPE_ENUM:
+			if field.DependType = g.getEnum(field.TypeName()); field.DependType == nil {
+				return nil
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// analyzeInterfaceField resolves the interface which the field is exposed as by interface of graphql.field option.
+// The field message must consist of a single oneof whose members implement the interface,
+// then the members are depended from the package because they are resolved via the interface.
+func (g *Generator) analyzeInterfaceField(rootPkg string, f *spec.Field, m *spec.Message) error {
+	di, ok := g.interfaces[f.Interface()]
+	if !ok {
+		return errors.New("interface " + f.Interface() + " of field " + f.Name() +
+			" is not declared by graphql.interface option, or no message implements it")
+	}
+	if g.args.FieldCamelCase {
+		return errors.New("interface of field " + f.Name() + " is not supported with field_camel argument")
+	}
+	// Messages of single oneof are the same shape as @oneOf inputs
+	if !m.IsOneofInput() {
+		return errors.New("field " + f.Name() + " of interface " + f.Interface() + " must be a message of single oneof")
+	}
+	for _, member := range m.Fields() {
+		mm := g.getMessage(member.TypeName())
+		if member.Type() != descriptor.FieldDescriptorProto_TYPE_MESSAGE || mm == nil || !di.isImplemented(mm) {
+			return errors.New("member " + member.Name() + " of field " + f.Name() + " doesn't implement interface " + f.Interface())
+		}
+		member.DependType = mm
+		if mm.IsDepended(spec.DependTypeMessage, rootPkg) {
+			continue
+		}
+		g.logger.Write("package %s depends on message %s via interface %s", rootPkg, mm.FullPath(), di.name)
+		mm.Depend(spec.DependTypeMessage, rootPkg)
+		if err := g.analyzeFields(rootPkg, mm, mm.Fields(), false, false); err != nil {
+			return err
+		}
+	}
+	f.InterfaceType = di.name
+	return nil
+}
+
+// declaredInterfaces returns interfaces which the types of the package implement,
+// or which fields of the types and plucked responses of the services are exposed as
+func (g *Generator) declaredInterfaces(file *spec.File, types []*spec.Message, services []*spec.Service) []*DeclaredInterface {
+	var fields []*spec.Field
+	for _, m := range types {
+		fields = append(fields, m.Fields()...)
+	}
+	for _, s := range services {
+		for _, q := range s.Queries {
+			if q.IsPluckResponse() {
+				fields = append(fields, q.PluckResponse()...)
+			}
+		}
+		for _, m := range s.Mutations {
+			if m.IsPluckResponse() {
+				fields = append(fields, m.PluckResponse()...)
+			}
+		}
+	}
+
+	var ifaces []*DeclaredInterface
+	for _, name := range g.interfaceNames {
+		di := g.interfaces[name]
+		used := false
+		for _, m := range types {
+			if di.isImplemented(m) {
+				used = true
+			}
+		}
+		for _, f := range fields {
+			if f.InterfaceType == di.name {
+				used = true
+			}
+		}
+		if !used {
+			continue
+		}
+		d := &DeclaredInterface{
+			Name: di.name,
+		}
+		for _, m := range di.implements {
+			// Object types are not generated for empty messages
+			if !m.IsDepended(spec.DependTypeMessage, file.Package()) || len(m.Fields()) == 0 {
+				continue
+			}
+			if d.Fields == nil {
+				d.Fields = g.interfaceFields(m, di.FieldNames())
+			}
+			fn := strings.TrimSuffix(spec.PrefixType(m.TypeName()), "()")
+			if m.Package() != file.Package() {
+				fn = spec.NewPackage(m).Name + "." + fn
+			}
+			d.Types = append(d.Types, &InterfaceType{
+				Message: m,
+				Func:    fn,
+			})
+		}
+		ifaces = append(ifaces, d)
+	}
+	return ifaces
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/grpc-graphql-gateway/protoc-gen-graphql/generator"
+	"github.com/ysugimoto/grpc-graphql-gateway/protoc-gen-graphql/spec"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+const testSearchFile = `
+name: "search.proto"
+package: "search"
+syntax: "proto3"
+options {
+  go_package: "example.com/search"
+  [graphql.interface] { name: "Node" fields: "id" }
+}
+service {
+  name: "Search"
+  method {
+    name: "Search"
+    input_type: ".search.SearchRequest"
+    output_type: ".search.SearchResponse"
+    options { [graphql.schema] { type: QUERY name: "search" response { pluck: "results" } } }
+  }
+  method {
+    name: "Lookup"
+    input_type: ".search.SearchRequest"
+    output_type: ".search.SearchResponse"
+    options { [graphql.schema] { type: QUERY name: "lookup" } }
+  }
+}
+message_type {
+  name: "SearchRequest"
+  field { name: "text" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "text" }
+}
+message_type {
+  name: "User"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" options { [graphql.field] { required: true } } }
+  field { name: "name" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+}
+message_type {
+  name: "Order"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" options { [graphql.field] { required: true } } }
+  field { name: "total" number: 2 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "total" }
+}
+message_type {
+  name: "Tag"
+  field { name: "id" number: 1 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "id" }
+}
+message_type {
+  name: "Item"
+  field { name: "user" number: 1 type: TYPE_MESSAGE type_name: ".search.User" label: LABEL_OPTIONAL json_name: "user" oneof_index: 0 }
+  field { name: "order" number: 2 type: TYPE_MESSAGE type_name: ".search.Order" label: LABEL_OPTIONAL json_name: "order" oneof_index: 0 }
+  oneof_decl { name: "item" }
+}
+message_type {
+  name: "SearchResponse"
+  field { name: "results" number: 1 type: TYPE_MESSAGE type_name: ".search.Item" label: LABEL_REPEATED json_name: "results" options { [graphql.field] { interface: "Node" } } }
+  field { name: "first" number: 2 type: TYPE_MESSAGE type_name: ".search.Item" label: LABEL_OPTIONAL json_name: "first" options { [graphql.field] { interface: "Node" } } }
+}
+`
+
+// testGenerate generates Go code of the proto file which is written in text format of FileDescriptorProto
+func testGenerate(t *testing.T, params, file string) (string, error) {
+	var fd descriptorpb.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(file), &fd); err != nil {
+		t.Fatal(err)
+	}
+	args, err := spec.NewParams(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []*spec.File{spec.NewFile(&fd, nil, args.FieldCamelCase)}
+	out, err := generator.New(files, args).Generate(goTemplate, []string{fd.GetName()})
+	if err != nil {
+		return "", err
+	}
+	if len(out) != 1 {
+		t.Fatalf("expected one generated file, got %d", len(out))
+	}
+	return out[0].GetContent(), nil
+}
+
+func TestGenerateDeclaredInterface(t *testing.T) {
+	code, err := testGenerate(t, "", testSearchFile)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// Interface is declared with the fields and the implementing messages
+	assert.Contains(t, code, `func Gql__declared_Search_Interface_Node() *graphql.Interface {`)
+	assert.Contains(t, code, `runtime.DeclaredInterface("Search_Interface_Node"`)
+	assert.Regexp(t, `"search.User":\s+Gql__type_User,`, code)
+	assert.Regexp(t, `"search.Order":\s+Gql__type_Order,`, code)
+	// Tag doesn't implement the interface because the type of id differs
+	assert.NotContains(t, code, `"search.Tag"`)
+	assert.Equal(t, 2, strings.Count(code, "Interfaces: []*graphql.Interface{\n\t\t\t\tGql__declared_Search_Interface_Node(),"))
+
+	// Fields and plucked response are typed as the interface, and resolve the populated oneof members
+	assert.Contains(t, code, `Type: graphql.NewList(Gql__declared_Search_Interface_Node()),`)
+	assert.Contains(t, code, `Type: Gql__declared_Search_Interface_Node(),`)
+	assert.Contains(t, code, `return runtime.OneofMembers(v), nil`)
+	assert.Contains(t, code, `return runtime.OneofMembers(resp.GetResults()), nil`)
+}
+
+func TestGenerateDeclaredInterfaceErrors(t *testing.T) {
+	cases := []struct {
+		name    string
+		params  string
+		replace [2]string
+		err     string
+	}{
+		{
+			name:    "undeclared interface",
+			replace: [2]string{`{ interface: "Node" } } }` + "\n" + `  field { name: "first"`, `{ interface: "Entity" } } }` + "\n" + `  field { name: "first"`},
+			err:     "interface Entity of field results is not declared",
+		},
+		{
+			name:   "field_camel",
+			params: "field_camel",
+			err:    "not supported with field_camel argument",
+		},
+		{
+			name:    "member which doesn't implement the interface",
+			replace: [2]string{`type_name: ".search.Order" label: LABEL_OPTIONAL json_name: "order" oneof_index: 0`, `type_name: ".search.Tag" label: LABEL_OPTIONAL json_name: "order" oneof_index: 0`},
+			err:     "member order of field results doesn't implement interface Node",
+		},
+		{
+			name:    "message which is not single oneof",
+			replace: [2]string{`oneof_decl { name: "item" }`, `field { name: "note" number: 3 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" }` + "\n" + `  oneof_decl { name: "item" }`},
+			err:     "field results of interface Node must be a message of single oneof",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			file := testSearchFile
+			if c.replace[0] != "" {
+				if !strings.Contains(file, c.replace[0]) {
+					t.Fatalf("%s is not found in the file", c.replace[0])
+				}
+				file = strings.Replace(file, c.replace[0], c.replace[1], 1)
+			}
+			_, err := testGenerate(t, c.params, file)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+}
@@ -71,7 +71,12 @@ func main() {
 	// in order to access easily plugin options, package name, comment, etc...
 	var files []*spec.File
 	for _, f := range req.GetProtoFile() {
-		files = append(files, spec.NewFile(f, req.GetCompilerVersion(), args.FieldCamelCase))
+		file := spec.NewFile(f, req.GetCompilerVersion(), args.FieldCamelCase)
+		file.DefaultListNullability = args.ListNullability
+		file.DefaultStrictNonNull = args.StrictNonNull
+		file.DefaultIDFields = args.IDFields
+		file.DefaultBytesScalar = args.BytesScalar
+		files = append(files, file)
 	}
 
 	g := generator.New(files, args)
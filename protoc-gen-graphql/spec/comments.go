@@ -3,6 +3,7 @@ package spec
 import (
 	"bytes"
 	"fmt"
+	"strings"
 
 	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
 )
@@ -36,3 +37,31 @@ func makeComments(d *descriptor.FileDescriptorProto) Comments {
 	}
 	return Comments(m)
 }
+
+// formatComment formats proto comment as markdown description.
+// Line breaks, blank lines and relative indentation of code blocks are kept,
+// and the common indentation after comment markers is removed.
+// Note that protoc has already removed leading asterisks of block comments.
+func formatComment(comment string) string {
+	lines := strings.Split(strings.ReplaceAll(comment, "\r\n", "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	// Remove common indentation, which is the space after "//" at least
+	indent := -1
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		if n := len(line) - len(strings.TrimLeft(line, " \t")); indent < 0 || n < indent {
+			indent = n
+		}
+	}
+	for i, line := range lines {
+		if len(line) >= indent && indent > 0 {
+			lines[i] = line[indent:]
+		}
+	}
+	return strings.Trim(strings.Join(lines, "\n"), "\n")
+}
@@ -1,7 +1,9 @@
 package spec
 
 import (
+	"fmt"
 	"log"
+	"strconv"
 	"strings"
 
 	// nolint: staticcheck
@@ -9,6 +11,7 @@ import (
 	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
 	"github.com/iancoleman/strcase"
 	"github.com/ysugimoto/grpc-graphql-gateway/graphql"
+	"github.com/ysugimoto/grpc-graphql-gateway/internal/directive"
 )
 
 // Field spec wraps FieldDescriptorProto with keeping file info
@@ -23,6 +26,9 @@ type Field struct {
 	IsCyclic      bool
 	isCamel       bool
 	forceRequired bool
+
+	// GraphQL name of the declared interface which the field is exposed as by interface option
+	InterfaceType string
 }
 
 func NewField(
@@ -102,40 +108,143 @@ func (f *Field) IsOmit() bool {
 	return f.Option.GetOmit()
 }
 
+// Cost returns field cost for query complexity analysis.
+// Zero means the field is not annotated so runtime uses default cost.
+func (f *Field) Cost() int32 {
+	if f.Option == nil {
+		return 0
+	}
+	return f.Option.GetCost()
+}
+
+// Interface returns the name of interface of graphql.interface file option which the field is exposed as
+func (f *Field) Interface() string {
+	return f.Option.GetInterface()
+}
+
+// IsID returns true if the string field is exposed as ID scalar
+// by is_id option, or by the name when id_fields option is enabled
+func (f *Field) IsID() bool {
+	if f.Type() != descriptor.FieldDescriptorProto_TYPE_STRING {
+		return false
+	}
+	if f.Option.GetIsId() {
+		return true
+	}
+	return f.File.IDFields() && isIDName(f.Name())
+}
+
+// CustomScalar returns the name of custom scalar which the field is exposed as by scalar option.
+// Map fields are exposed as lists of entries, so that the option is ignored.
+func (f *Field) CustomScalar() string {
+	if f.IsMap() {
+		return ""
+	}
+	return f.Option.GetScalar()
+}
+
+// Directives returns directives of the field which are applied by runtime handlers,
+// the build fails if some of them are not valid GraphQL syntax
+func (f *Field) Directives() []string {
+	directives := f.Option.GetDirectives()
+	for _, d := range directives {
+		if _, err := directive.Parse(d); err != nil {
+			log.Fatalf("[PROTOC-GEN-GRAPHQL] Error: %s of field %s\n", err, f.Name())
+		}
+	}
+	return directives
+}
+
+// isIDName returns true if the field name is "id" or suffixed with "_id"
+func isIDName(name string) bool {
+	name = strcase.ToSnake(name)
+	return name == "id" || strings.HasSuffix(name, "_id")
+}
+
 func (f *Field) IsRepeated() bool {
 	return f.Label() == descriptor.FieldDescriptorProto_LABEL_REPEATED
 }
 
+// IsMap returns true if the field is protobuf map which is exposed as a list of key-value entry objects
+func (f *Field) IsMap() bool {
+	m, ok := f.DependType.(*Message)
+	return ok && f.IsRepeated() && m.descriptor.GetOptions().GetMapEntry()
+}
+
 func (f *Field) FieldType(rootPackage string) string {
 	pkg := NewGoPackageFromString(rootPackage)
-	fieldType := f.GraphqlGoType(pkg.Name, false)
-	if f.IsRequired() {
+	fieldType := f.wrapType(f.GraphqlGoType(pkg.Name, false))
+	if f.isStrictNonNull() && !strings.HasPrefix(fieldType, "graphql.NewNonNull(") {
 		fieldType = "graphql.NewNonNull(" + fieldType + ")"
 	}
-	if f.IsRepeated() {
-		fieldType = "graphql.NewList(" + fieldType + ")"
-		if f.IsRequired() {
-			fieldType = "graphql.NewNonNull(" + fieldType + ")"
-		}
-	}
 	return fieldType
 }
 
+// isStrictNonNull returns true if the field is non-null in output types by strict non-null mode.
+// Scalar and enum fields always have zero values in proto3 unless they are in oneof.
+func (f *Field) isStrictNonNull() bool {
+	if !f.File.StrictNonNull() || f.Option.GetNullable() || f.IsRepeated() || f.descriptor.OneofIndex != nil {
+		return false
+	}
+	switch f.Type() {
+	case descriptor.FieldDescriptorProto_TYPE_MESSAGE, descriptor.FieldDescriptorProto_TYPE_GROUP:
+		return false
+	}
+	return true
+}
+
 func (f *Field) FieldTypeInput(rootPackage string) string {
 	pkg := NewGoPackageFromString(rootPackage)
-	fieldType := f.GraphqlGoType(pkg.Name, true)
-	if f.IsRequired() {
-		fieldType = "graphql.NewNonNull(" + fieldType + ")"
-	}
-	if f.IsRepeated() {
-		fieldType = "graphql.NewList(" + fieldType + ")"
+	return f.wrapType(f.GraphqlGoType(pkg.Name, true))
+}
+
+// wrapType wraps the type with list and non-null by required option and list nullability
+func (f *Field) wrapType(fieldType string) string {
+	if !f.IsRepeated() {
 		if f.IsRequired() {
 			fieldType = "graphql.NewNonNull(" + fieldType + ")"
 		}
+		return fieldType
+	}
+	list, items := f.listNullability()
+	if items {
+		fieldType = "graphql.NewNonNull(" + fieldType + ")"
+	}
+	fieldType = "graphql.NewList(" + fieldType + ")"
+	if list {
+		fieldType = "graphql.NewNonNull(" + fieldType + ")"
 	}
 	return fieldType
 }
 
+// listNullability returns whether the list and its items of repeated field are non-null.
+// The list option of the field takes precedence, then required fields have non-null list
+// with the nullability of the file, and both are non-null for required fields by default.
+func (f *Field) listNullability() (list, items bool) {
+	if mode := f.Option.GetList(); mode != graphql.GraphqlListNullability_LIST_DEFAULT {
+		return nonNullList(mode)
+	}
+	mode := f.File.ListNullability()
+	if mode == graphql.GraphqlListNullability_LIST_DEFAULT {
+		return f.IsRequired(), f.IsRequired()
+	}
+	list, items = nonNullList(mode)
+	return list || f.IsRequired(), items
+}
+
+func nonNullList(mode graphql.GraphqlListNullability) (list, items bool) {
+	switch mode {
+	case graphql.GraphqlListNullability_NON_NULL_LIST:
+		return true, false
+	case graphql.GraphqlListNullability_NON_NULL_ITEMS:
+		return false, true
+	case graphql.GraphqlListNullability_NON_NULL_LIST_AND_ITEMS:
+		return true, true
+	default:
+		return false, false
+	}
+}
+
 func (f *Field) SchemaType() string {
 	fieldType := f.GraphqlType()
 	if f.IsRepeated() {
@@ -149,7 +258,7 @@ func (f *Field) SchemaType() string {
 
 func (f *Field) SchemaInputType() string {
 	var prefix string
-	if f.Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+	if f.Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE && f.CustomScalar() == "" {
 		m := f.DependType.(*Message) // nolint: errcheck
 		if f.Package() == m.Package() || IsGooglePackage(f) {
 			prefix = "Input_"
@@ -166,8 +275,10 @@ func (f *Field) SchemaInputType() string {
 	return fieldType
 }
 
+// DefaultValue returns default value as GraphQL literal
 func (f *Field) DefaultValue() string {
-	if f.Option == nil {
+	d := f.defaultOption()
+	if d == "" {
 		return ""
 	}
 	switch f.Type() {
@@ -181,16 +292,58 @@ func (f *Field) DefaultValue() string {
 		descriptor.FieldDescriptorProto_TYPE_UINT32,
 		descriptor.FieldDescriptorProto_TYPE_UINT64,
 		descriptor.FieldDescriptorProto_TYPE_ENUM:
-		return f.Option.GetDefault()
+		return d
 	case descriptor.FieldDescriptorProto_TYPE_STRING:
-		return `"` + f.Option.GetDefault() + `"`
+		return strconv.Quote(d)
 	default:
 		return ""
 	}
 }
 
+// GoDefaultValue returns default value as Go expression of the argument or input field.
+// Enum value is converted to the value of Go enum type, which is the internal value of generated GraphQL enum.
+func (f *Field) GoDefaultValue(rootPackage string) string {
+	if f.Type() != descriptor.FieldDescriptorProto_TYPE_ENUM {
+		return f.DefaultValue()
+	}
+	d := f.defaultOption()
+	if d == "" {
+		return ""
+	}
+	e := f.DependType.(*Enum) // nolint: errcheck
+	for _, v := range e.Values() {
+		if v.Name() != d {
+			continue
+		}
+		var pkgPrefix string
+		if pkg := NewPackage(e); pkg.Name != NewGoPackageFromString(rootPackage).Name && !IsGooglePackage(e) {
+			pkgPrefix = pkg.Name + "."
+		}
+		return fmt.Sprintf("%s%s(%d)", pkgPrefix, e.Name(), v.Number())
+	}
+	log.Fatalf("[PROTOC-GEN-GRAPHQL] Error: default value %s of field %s is not defined in enum %s", d, f.Name(), e.FullPath())
+	return ""
+}
+
+// defaultOption returns default value of graphql.field option, or default value of proto2 field.
+// Infinity and NaN of proto2 float fields are ignored because GraphQL cannot express them.
+func (f *Field) defaultOption() string {
+	if d := f.Option.GetDefault(); d != "" {
+		return d
+	}
+	d := f.descriptor.GetDefaultValue()
+	switch strings.ToLower(d) {
+	case "inf", "-inf", "nan":
+		return ""
+	}
+	return d
+}
+
 // GraphqlType returns appropriate GraphQL type
 func (f *Field) GraphqlType() string {
+	if name := f.CustomScalar(); name != "" {
+		return name
+	}
 	switch f.Type() {
 	case descriptor.FieldDescriptorProto_TYPE_BOOL:
 		return "Boolean"
@@ -205,6 +358,9 @@ func (f *Field) GraphqlType() string {
 		descriptor.FieldDescriptorProto_TYPE_UINT64:
 		return "Int"
 	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		if f.IsID() {
+			return "ID"
+		}
 		return "String"
 	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
 		m := f.DependType.(*Message) // nolint: errcheck
@@ -221,6 +377,9 @@ func (f *Field) GraphqlType() string {
 
 // GraphqlGoType returns appropriate graphql-go type
 func (f *Field) GraphqlGoType(rootPackage string, isInput bool) string {
+	if name := f.CustomScalar(); name != "" {
+		return "runtime.CustomScalar(" + strconv.Quote(name) + ")"
+	}
 	switch f.Type() {
 	case descriptor.FieldDescriptorProto_TYPE_BOOL:
 		return "graphql.Boolean"
@@ -238,13 +397,24 @@ func (f *Field) GraphqlGoType(rootPackage string, isInput bool) string {
 		descriptor.FieldDescriptorProto_TYPE_UINT32,
 		descriptor.FieldDescriptorProto_TYPE_UINT64:
 		return "graphql.Int"
-	case descriptor.FieldDescriptorProto_TYPE_STRING,
-		descriptor.FieldDescriptorProto_TYPE_BYTES:
+	case descriptor.FieldDescriptorProto_TYPE_STRING:
+		if f.IsID() {
+			return "graphql.ID"
+		}
+		return "graphql.String"
+	case descriptor.FieldDescriptorProto_TYPE_BYTES:
+		if f.File.BytesScalar() {
+			return "runtime.Bytes"
+		}
 		return "graphql.String"
 	case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+		if f.InterfaceType != "" && !isInput {
+			return PrefixDeclaredInterface(f.InterfaceType)
+		}
 		m := f.DependType.(*Message) // nolint: errcheck
 		tn := strings.TrimPrefix(f.TypeName(), m.Package()+".")
-		if f.IsCyclic {
+		// Interface is declared for cyclic object types, inputs refer to themselves directly
+		if f.IsCyclic && !isInput {
 			return PrefixInterface(strings.ReplaceAll(tn, ".", "_"))
 		}
 		var pkgPrefix string
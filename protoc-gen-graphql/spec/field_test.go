@@ -0,0 +1,258 @@
+package spec
+
+import (
+	"strings"
+	"testing"
+
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/assert"
+	"github.com/ysugimoto/grpc-graphql-gateway/graphql"
+	"google.golang.org/protobuf/encoding/prototext"
+)
+
+// testFile creates the file spec of the proto file which is written in text format of FileDescriptorProto
+func testFile(t *testing.T, text string) *File {
+	var fd descriptor.FileDescriptorProto
+	if err := prototext.Unmarshal([]byte(text), &fd); err != nil {
+		t.Fatal(err)
+	}
+	return NewFile(&fd, nil, false)
+}
+
+// testMessageField returns the field of the message in the file
+func testMessageField(t *testing.T, f *File, message, field string) *Field {
+	for _, m := range f.Messages() {
+		if m.Name() != message {
+			continue
+		}
+		for _, v := range m.Fields() {
+			if v.Name() == field {
+				return v
+			}
+		}
+	}
+	t.Fatalf("field %s.%s is not found", message, field)
+	return nil
+}
+
+const testListFile = `
+name: "list.proto"
+package: "list"
+syntax: "proto3"
+message_type {
+  name: "Post"
+  field { name: "tags" number: 1 type: TYPE_STRING label: LABEL_REPEATED json_name: "tags" }
+  field { name: "labels" number: 2 type: TYPE_STRING label: LABEL_REPEATED json_name: "labels" options { [graphql.field] { required: true } } }
+  field { name: "ids" number: 3 type: TYPE_INT64 label: LABEL_REPEATED json_name: "ids" options { [graphql.field] { list: NON_NULL_LIST } } }
+  field { name: "title" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+}
+`
+
+func TestFieldListNullability(t *testing.T) {
+	cases := []struct {
+		name     string
+		option   string
+		argument graphql.GraphqlListNullability
+		expects  map[string]string
+	}{
+		{
+			name: "default",
+			expects: map[string]string{
+				"tags":   "graphql.NewList(graphql.String)",
+				"labels": "graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))",
+				"ids":    "graphql.NewNonNull(graphql.NewList(graphql.Int))",
+				"title":  "graphql.String",
+			},
+		},
+		{
+			name:   "file option",
+			option: `options { [graphql.list_nullability]: NON_NULL_ITEMS }`,
+			expects: map[string]string{
+				"tags": "graphql.NewList(graphql.NewNonNull(graphql.String))",
+				// Required fields have non-null list with the nullability of the file
+				"labels": "graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))",
+				"ids":    "graphql.NewNonNull(graphql.NewList(graphql.Int))",
+				"title":  "graphql.String",
+			},
+		},
+		{
+			name:     "plugin argument",
+			argument: graphql.GraphqlListNullability_NON_NULL_LIST,
+			expects: map[string]string{
+				"tags":   "graphql.NewNonNull(graphql.NewList(graphql.String))",
+				"labels": "graphql.NewNonNull(graphql.NewList(graphql.String))",
+				"ids":    "graphql.NewNonNull(graphql.NewList(graphql.Int))",
+				"title":  "graphql.String",
+			},
+		},
+		{
+			name:     "file option takes precedence over plugin argument",
+			option:   `options { [graphql.list_nullability]: NON_NULL_LIST_AND_ITEMS }`,
+			argument: graphql.GraphqlListNullability_NULLABLE_LIST,
+			expects: map[string]string{
+				"tags":   "graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))",
+				"labels": "graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))",
+				"ids":    "graphql.NewNonNull(graphql.NewList(graphql.Int))",
+				"title":  "graphql.String",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := testFile(t, testListFile+c.option)
+			f.DefaultListNullability = c.argument
+			for name, expect := range c.expects {
+				field := testMessageField(t, f, "Post", name)
+				assert.Equal(t, expect, field.FieldType(""), name)
+				// Inputs have the same nullability
+				assert.Equal(t, expect, field.FieldTypeInput(""), name)
+			}
+		})
+	}
+}
+
+const testStrictFile = `
+name: "strict.proto"
+package: "strict"
+syntax: "proto3"
+message_type {
+  name: "Author"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+}
+message_type {
+  name: "Post"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  field { name: "views" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "views" }
+  field { name: "status" number: 3 type: TYPE_ENUM type_name: ".strict.Status" label: LABEL_OPTIONAL json_name: "status" }
+  field { name: "author" number: 4 type: TYPE_MESSAGE type_name: ".strict.Author" label: LABEL_OPTIONAL json_name: "author" }
+  field { name: "summary" number: 5 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "summary" options { [graphql.field] { nullable: true } } }
+  field { name: "slug" number: 6 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "slug" options { [graphql.field] { required: true } } }
+  field { name: "tags" number: 7 type: TYPE_STRING label: LABEL_REPEATED json_name: "tags" }
+  field { name: "url" number: 8 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "url" oneof_index: 0 }
+  oneof_decl { name: "source" }
+}
+enum_type {
+  name: "Status"
+  value { name: "DRAFT" number: 0 }
+}
+`
+
+func TestFieldStrictNonNull(t *testing.T) {
+	strict := map[string]string{
+		"title":  "graphql.NewNonNull(graphql.String)",
+		"views":  "graphql.NewNonNull(graphql.Int)",
+		"status": "graphql.NewNonNull(Gql__enum_Status())",
+		// Messages may be unset, and fields which opt-out or are in oneof are nullable
+		"author":  "Gql__type_Author()",
+		"summary": "graphql.String",
+		"url":     "graphql.String",
+		// Required fields are not wrapped twice
+		"slug": "graphql.NewNonNull(graphql.String)",
+		"tags": "graphql.NewList(graphql.String)",
+	}
+	nullable := map[string]string{
+		"title":   "graphql.String",
+		"views":   "graphql.Int",
+		"status":  "Gql__enum_Status()",
+		"author":  "Gql__type_Author()",
+		"summary": "graphql.String",
+		"url":     "graphql.String",
+		"slug":    "graphql.NewNonNull(graphql.String)",
+		"tags":    "graphql.NewList(graphql.String)",
+	}
+
+	cases := []struct {
+		name     string
+		file     string
+		argument bool
+		expects  map[string]string
+	}{
+		{name: "disabled", file: testStrictFile, expects: nullable},
+		{name: "file option", file: testStrictFile + `options { [graphql.strict_non_null]: true }`, expects: strict},
+		{name: "plugin argument", file: testStrictFile, argument: true, expects: strict},
+		// proto2 fields may be unset
+		{name: "proto2", file: strings.Replace(testStrictFile, "proto3", "proto2", 1), argument: true, expects: nullable},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := testFile(t, c.file)
+			f.DefaultStrictNonNull = c.argument
+			for name, expect := range c.expects {
+				field := testMessageField(t, f, "Post", name)
+				switch field.Type() {
+				case descriptor.FieldDescriptorProto_TYPE_ENUM:
+					field.DependType = f.Enums()[0]
+				case descriptor.FieldDescriptorProto_TYPE_MESSAGE:
+					field.DependType = f.Messages()[0]
+				}
+				assert.Equal(t, expect, field.FieldType("strict"), name)
+				// Inputs are not affected
+				if name != "slug" {
+					assert.NotContains(t, field.FieldTypeInput("strict"), "graphql.NewNonNull", name)
+				}
+			}
+		})
+	}
+}
+
+const testIDFile = `
+name: "id.proto"
+package: "id"
+syntax: "proto3"
+message_type {
+  name: "User"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" }
+  field { name: "group_id" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "groupId" }
+  field { name: "friendId" number: 3 type: TYPE_STRING label: LABEL_REPEATED json_name: "friendId" }
+  field { name: "identity" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "identity" }
+  field { name: "serial_id" number: 5 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "serialId" }
+  field { name: "key" number: 6 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "key" options { [graphql.field] { is_id: true required: true } } }
+}
+`
+
+func TestFieldID(t *testing.T) {
+	ids := map[string]string{
+		"id":       "graphql.ID",
+		"group_id": "graphql.ID",
+		"friendId": "graphql.NewList(graphql.ID)",
+		"identity": "graphql.String",
+		// ID is serialized as string, so that fields of other types are not affected
+		"serial_id": "graphql.Int",
+		"key":       "graphql.NewNonNull(graphql.ID)",
+	}
+	nonIDs := map[string]string{
+		"id":        "graphql.String",
+		"group_id":  "graphql.String",
+		"friendId":  "graphql.NewList(graphql.String)",
+		"identity":  "graphql.String",
+		"serial_id": "graphql.Int",
+		// is_id option declares ID per field
+		"key": "graphql.NewNonNull(graphql.ID)",
+	}
+
+	cases := []struct {
+		name     string
+		option   string
+		argument bool
+		expects  map[string]string
+	}{
+		{name: "disabled", expects: nonIDs},
+		{name: "file option", option: `options { [graphql.id_fields]: true }`, expects: ids},
+		{name: "plugin argument", argument: true, expects: ids},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			f := testFile(t, testIDFile+c.option)
+			f.DefaultIDFields = c.argument
+			for name, expect := range c.expects {
+				field := testMessageField(t, f, "User", name)
+				assert.Equal(t, expect, field.FieldType(""), name)
+				// ID is accepted in arguments and inputs as well
+				assert.Equal(t, expect, field.FieldTypeInput(""), name)
+			}
+		})
+	}
+}
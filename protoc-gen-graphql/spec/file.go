@@ -5,8 +5,11 @@ import (
 	"fmt"
 	"strings"
 
+	// nolint: staticcheck
+	"github.com/golang/protobuf/proto"
 	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
 	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"github.com/ysugimoto/grpc-graphql-gateway/graphql"
 )
 
 // File spec wraps FileDescriptorProto
@@ -23,6 +26,14 @@ type File struct {
 	isCamel bool
 
 	CompilerVersion *plugin.Version
+	// List nullability of list_nullability plugin argument which is used if the file doesn't declare
+	DefaultListNullability graphql.GraphqlListNullability
+	// Strict non-null mode of strict_non_null plugin argument which is enabled for all proto3 files
+	DefaultStrictNonNull bool
+	// ID mapping of id_fields plugin argument which is enabled for all files
+	DefaultIDFields bool
+	// Bytes scalar of bytes_scalar plugin argument which is enabled for all files
+	DefaultBytesScalar bool
 }
 
 func NewFile(
@@ -53,6 +64,106 @@ func NewFile(
 	return f
 }
 
+// ListNullability returns nullability of lists which is declared by list_nullability file option
+func (f *File) ListNullability() graphql.GraphqlListNullability {
+	if opts := f.descriptor.GetOptions(); opts != nil {
+		if ext, err := proto.GetExtension(opts, graphql.E_ListNullability); err == nil {
+			switch v := ext.(type) {
+			case graphql.GraphqlListNullability:
+				if v != graphql.GraphqlListNullability_LIST_DEFAULT {
+					return v
+				}
+			case *graphql.GraphqlListNullability:
+				if v != nil && *v != graphql.GraphqlListNullability_LIST_DEFAULT {
+					return *v
+				}
+			}
+		}
+	}
+	return f.DefaultListNullability
+}
+
+// StrictNonNull returns true if strict non-null mode is enabled for the proto3 file
+// by strict_non_null file option or plugin argument
+func (f *File) StrictNonNull() bool {
+	if f.descriptor.GetSyntax() != "proto3" {
+		return false
+	}
+	if f.DefaultStrictNonNull {
+		return true
+	}
+	if opts := f.descriptor.GetOptions(); opts != nil {
+		if ext, err := proto.GetExtension(opts, graphql.E_StrictNonNull); err == nil {
+			switch v := ext.(type) {
+			case bool:
+				return v
+			case *bool:
+				return v != nil && *v
+			}
+		}
+	}
+	return false
+}
+
+// IDFields returns true if string fields named like identifier are exposed as ID scalar
+// by id_fields file option or plugin argument
+func (f *File) IDFields() bool {
+	if f.DefaultIDFields {
+		return true
+	}
+	if opts := f.descriptor.GetOptions(); opts != nil {
+		if ext, err := proto.GetExtension(opts, graphql.E_IdFields); err == nil {
+			switch v := ext.(type) {
+			case bool:
+				return v
+			case *bool:
+				return v != nil && *v
+			}
+		}
+	}
+	return false
+}
+
+// BytesScalar returns true if bytes fields are exposed as Bytes scalar instead of String
+// by bytes_scalar file option or plugin argument
+func (f *File) BytesScalar() bool {
+	if f.DefaultBytesScalar {
+		return true
+	}
+	if opts := f.descriptor.GetOptions(); opts != nil {
+		if ext, err := proto.GetExtension(opts, graphql.E_BytesScalar); err == nil {
+			switch v := ext.(type) {
+			case bool:
+				return v
+			case *bool:
+				return v != nil && *v
+			}
+		}
+	}
+	return false
+}
+
+// Interfaces returns interfaces which are declared by graphql.interface file option
+func (f *File) Interfaces() []*Interface {
+	opts := f.descriptor.GetOptions()
+	if opts == nil {
+		return nil
+	}
+	ext, err := proto.GetExtension(opts, graphql.E_Interface)
+	if err != nil {
+		return nil
+	}
+	decls, _ := ext.([]*graphql.GraphqlInterface) // nolint: errcheck
+	var ifaces []*Interface
+	for _, decl := range decls {
+		if decl.GetName() == "" || len(decl.GetFields()) == 0 {
+			continue
+		}
+		ifaces = append(ifaces, NewInterface(decl, f))
+	}
+	return ifaces
+}
+
 func (f *File) Services() []*Service {
 	return f.services
 }
@@ -121,7 +232,8 @@ func (f *File) getComment(paths []int) string {
 	}
 
 	if v, ok := f.comments[b.String()]; ok {
-		return strings.ReplaceAll(strings.TrimSpace(v), "`", "`+\"`\"+`")
+		// Comments are placed in raw string literal
+		return strings.ReplaceAll(formatComment(v), "`", "`+\"`\"+`")
 	}
 	return ""
 }
@@ -0,0 +1,29 @@
+package spec
+
+import (
+	"github.com/ysugimoto/grpc-graphql-gateway/graphql"
+)
+
+// Interface spec wraps GraphqlInterface of graphql.interface file option,
+// which messages implement automatically if they expose all of the fields with the same types
+type Interface struct {
+	decl *graphql.GraphqlInterface
+	*File
+}
+
+func NewInterface(decl *graphql.GraphqlInterface, f *File) *Interface {
+	return &Interface{
+		decl: decl,
+		File: f,
+	}
+}
+
+// Name returns the declared name which is referred by interface of graphql.field option
+func (i *Interface) Name() string {
+	return i.decl.GetName()
+}
+
+// FieldNames returns GraphQL field names which implementing messages share
+func (i *Interface) FieldNames() []string {
+	return i.decl.GetFields()
+}
@@ -19,6 +19,8 @@ type Message struct {
 
 	*Dependencies
 	PluckFields []*Field
+	// IsCyclicInput is true if the input refers to itself via input fields
+	IsCyclicInput bool
 }
 
 func NewMessage(
@@ -49,6 +51,28 @@ func NewMessage(
 	return m
 }
 
+// IsOneofInput returns true if all fields of the message are members of a single oneof,
+// then the input is @oneOf input which accepts exactly one of the members
+func (m *Message) IsOneofInput() bool {
+	fields := m.descriptor.GetField()
+	if len(fields) == 0 || fields[0].OneofIndex == nil {
+		return false
+	}
+	index := fields[0].GetOneofIndex()
+	for _, f := range fields {
+		if f.OneofIndex == nil || f.GetOneofIndex() != index {
+			return false
+		}
+	}
+	// proto3 optional field is wrapped in synthetic oneof named "_<field>"
+	return len(fields) > 1 || !strings.HasPrefix(m.descriptor.GetOneofDecl()[index].GetName(), "_")
+}
+
+// IsMapEntry returns true if the message is the entry of protobuf map field
+func (m *Message) IsMapEntry() bool {
+	return m.descriptor.GetOptions().GetMapEntry()
+}
+
 func (m *Message) Fields() []*Field {
 	return m.fields
 }
@@ -1,8 +1,11 @@
 package spec
 
 import (
+	"log"
 	"strings"
 
+	"path/filepath"
+
 	// nolint: staticcheck
 	"github.com/golang/protobuf/proto"
 	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
@@ -64,3 +67,68 @@ func (m *Method) Input() string {
 func (m *Method) Output() string {
 	return strings.TrimPrefix(m.descriptor.GetOutputType(), ".")
 }
+
+// responsePluck returns name of the response field which is declared by pluck,
+// or the only field of the response message if unwrap is declared
+func responsePluck(resp *graphql.GraphqlResponse, output *Message) string {
+	if pluck := resp.GetPluck(); pluck != "" {
+		return pluck
+	}
+	if !resp.GetUnwrap() {
+		return ""
+	}
+	if fields := output.Fields(); len(fields) == 1 {
+		return fields[0].Name()
+	}
+	log.Fatalf("[PROTOC-GEN-GRAPHQL] Error: unwrap requires single field response message but %s has %d fields\n",
+		output.Name(), len(output.Fields()))
+	return ""
+}
+
+// unwrapRequired makes type of the unwrapped field non-null if the response is required
+func unwrapRequired(resp *graphql.GraphqlResponse, fieldType string) string {
+	if resp.GetUnwrap() && resp.GetRequired() && !strings.HasPrefix(fieldType, "graphql.NewNonNull(") {
+		return "graphql.NewNonNull(" + fieldType + ")"
+	}
+	return fieldType
+}
+
+// requestUnwrap returns the only scalar field of the request message if unwrap is declared
+func requestUnwrap(req *graphql.GraphqlRequest, input *Message) *Field {
+	if !req.GetUnwrap() {
+		return nil
+	}
+	fields := input.Fields()
+	if len(fields) != 1 || fields[0].IsRepeated() || fields[0].Type() == descriptor.FieldDescriptorProto_TYPE_MESSAGE {
+		log.Fatalf("[PROTOC-GEN-GRAPHQL] Error: unwrap requires single scalar field request message but %s is not\n", input.Name())
+	}
+	return fields[0]
+}
+
+// argumentType returns type of the argument which is required if the request is unwrapped
+func argumentType(req *graphql.GraphqlRequest, f *Field, rootPackage string) string {
+	fieldType := f.FieldTypeInput(rootPackage)
+	if req.GetUnwrap() && !strings.HasPrefix(fieldType, "graphql.NewNonNull(") {
+		return "graphql.NewNonNull(" + fieldType + ")"
+	}
+	return fieldType
+}
+
+// goMessageType returns Go type of the message which is referred from the package of the method
+func goMessageType(m *Method, msg *Message) string {
+	if m.GoPackage() == msg.GoPackage() {
+		return msg.TypeName()
+	}
+	if IsGooglePackage(msg) {
+		ptypeName, err := getImplementedPtypes(msg)
+		if err != nil {
+			log.Fatalln("[PROTOC-GEN-GRAPHQL] Error:", err)
+		}
+		return "gql_ptypes_" + ptypeName + "." + msg.TypeName()
+	}
+	pkgName := filepath.Base(msg.GoPackage())
+	if index := strings.Index(pkgName, ";"); index > -1 {
+		pkgName = pkgName[index+1:]
+	}
+	return pkgName + "." + msg.TypeName()
+}
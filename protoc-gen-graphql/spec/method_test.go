@@ -0,0 +1,130 @@
+package spec
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testUnwrapFile = `
+name: "unwrap.proto"
+package: "unwrap"
+syntax: "proto3"
+service {
+  name: "Users"
+  method {
+    name: "GetName"
+    input_type: ".unwrap.IDRequest"
+    output_type: ".unwrap.NameResponse"
+    options { [graphql.schema] { type: QUERY name: "name" request { unwrap: true } response { unwrap: true } } }
+  }
+  method {
+    name: "GetRequiredName"
+    input_type: ".unwrap.IDRequest"
+    output_type: ".unwrap.NameResponse"
+    options { [graphql.schema] { type: QUERY name: "requiredName" response { unwrap: true required: true } } }
+  }
+  method {
+    name: "GetTags"
+    input_type: ".unwrap.IDRequest"
+    output_type: ".unwrap.TagsResponse"
+    options { [graphql.schema] { type: QUERY name: "tags" response { unwrap: true required: true } } }
+  }
+  method {
+    name: "Rename"
+    input_type: ".unwrap.IDRequest"
+    output_type: ".unwrap.NameResponse"
+    options { [graphql.schema] { type: MUTATION name: "rename" request { unwrap: true } response { unwrap: true required: true } } }
+  }
+}
+message_type {
+  name: "IDRequest"
+  field { name: "id" number: 1 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "id" }
+}
+message_type {
+  name: "NameResponse"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+}
+message_type {
+  name: "TagsResponse"
+  field { name: "tags" number: 1 type: TYPE_STRING label: LABEL_REPEATED json_name: "tags" options { [graphql.field] { required: true } } }
+}
+`
+
+// testQuery returns the query spec of the method in the file
+func testQuery(t *testing.T, f *File, name string) *Query {
+	m, input, output := testMethod(t, f, name)
+	return NewQuery(m, input, output, false)
+}
+
+// testMutation returns the mutation spec of the method in the file
+func testMutation(t *testing.T, f *File, name string) *Mutation {
+	m, input, output := testMethod(t, f, name)
+	return NewMutation(m, input, output, false)
+}
+
+// testMethod returns the method of the file with its input and output messages
+func testMethod(t *testing.T, f *File, name string) (*Method, *Message, *Message) {
+	messages := make(map[string]*Message)
+	for _, m := range f.Messages() {
+		messages[f.Package()+"."+m.Name()] = m
+	}
+	for _, s := range f.Services() {
+		for _, m := range s.Methods() {
+			if m.Name() == name {
+				return m, messages[m.Input()], messages[m.Output()]
+			}
+		}
+	}
+	t.Fatalf("method %s is not found", name)
+	return nil, nil, nil
+}
+
+func TestMethodUnwrapResponse(t *testing.T) {
+	f := testFile(t, testUnwrapFile)
+	cases := []struct {
+		method string
+		pluck  string
+		expect string
+	}{
+		{method: "GetName", pluck: "name", expect: "graphql.String"},
+		{method: "GetRequiredName", pluck: "name", expect: "graphql.NewNonNull(graphql.String)"},
+		// Required list is non-null already, so that it's not wrapped twice
+		{method: "GetTags", pluck: "tags", expect: "graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))"},
+	}
+
+	for _, c := range cases {
+		t.Run(c.method, func(t *testing.T) {
+			q := testQuery(t, f, c.method)
+			assert.True(t, q.IsPluckResponse())
+			assert.Equal(t, c.pluck, q.PluckResponseName())
+			assert.Equal(t, c.expect, q.QueryType())
+		})
+	}
+
+	m := testMutation(t, f, "Rename")
+	assert.True(t, m.IsPluckResponse())
+	assert.Equal(t, "graphql.NewNonNull(graphql.String)", m.MutationType())
+}
+
+func TestMethodUnwrapRequest(t *testing.T) {
+	f := testFile(t, testUnwrapFile)
+
+	// The only field of the request is the required argument
+	q := testQuery(t, f, "GetName")
+	if args := q.Args(); assert.Len(t, args, 1) {
+		assert.Equal(t, "id", args[0].Name())
+		assert.Equal(t, "graphql.NewNonNull(graphql.Int)", q.ArgumentType(args[0], f.GoPackage()))
+	}
+	m := testMutation(t, f, "Rename")
+	if args := m.Args(); assert.Len(t, args, 1) {
+		assert.Equal(t, "id", args[0].Name())
+		assert.Equal(t, "graphql.NewNonNull(graphql.Int)", m.ArgumentType(args[0], f.GoPackage()))
+	}
+
+	// Arguments of the request which is not unwrapped keep nullability of the fields
+	q = testQuery(t, f, "GetRequiredName")
+	if args := q.Args(); assert.Len(t, args, 1) {
+		assert.Equal(t, "graphql.Int", q.ArgumentType(args[0], f.GoPackage()))
+	}
+}
@@ -14,6 +14,8 @@ type Mutation struct {
 	*Method
 	Input  *Message
 	Output *Message
+	// DefaultName is the name derived from RPC name, which is used if graphql.schema option doesn't have name
+	DefaultName string
 
 	isCamel bool
 }
@@ -32,7 +34,10 @@ func (m *Mutation) IsCamel() bool {
 }
 
 func (m *Mutation) MutationName() string {
-	return m.Schema.GetName()
+	if name := m.Schema.GetName(); name != "" {
+		return name
+	}
+	return m.DefaultName
 }
 
 func (m *Mutation) Request() *graphql.GraphqlRequest {
@@ -52,15 +57,16 @@ func (m *Mutation) Response() *graphql.GraphqlResponse {
 }
 
 func (m *Mutation) IsPluckResponse() bool {
-	resp := m.Response()
-	if resp == nil {
-		return false
-	}
-	return resp.GetPluck() != ""
+	return m.PluckResponseName() != ""
+}
+
+// PluckResponseName returns name of the response field which is exposed instead of the response message
+func (m *Mutation) PluckResponseName() string {
+	return responsePluck(m.Response(), m.Output)
 }
 
 func (m *Mutation) InputName() string {
-	if req := m.Request(); req != nil {
+	if req := m.Request(); req != nil && !req.GetUnwrap() {
 		return req.GetName()
 	}
 	return ""
@@ -89,10 +95,7 @@ func (m *Mutation) PluckRequest() []*Field {
 }
 
 func (m *Mutation) PluckResponse() []*Field {
-	var pluck string
-	if resp := m.Response(); resp != nil {
-		pluck = resp.GetPluck()
-	}
+	pluck := m.PluckResponseName()
 
 	if pluck == "" {
 		return m.Output.Fields()
@@ -109,10 +112,23 @@ func (m *Mutation) PluckResponse() []*Field {
 }
 
 func (m *Mutation) Args() []*Field {
+	if f := requestUnwrap(m.Request(), m.Input); f != nil {
+		return []*Field{f}
+	}
 	return m.PluckRequest()
 }
 
+// ArgumentType returns type of the argument field
+func (m *Mutation) ArgumentType(f *Field, rootPackage string) string {
+	return argumentType(m.Request(), f, rootPackage)
+}
+
 func (m *Mutation) MutationType() string {
+	if m.IsPluckResponse() {
+		field := m.PluckResponse()[0]
+		return unwrapRequired(m.Response(), field.FieldType(m.GoPackage()))
+	}
+
 	var pkgPrefix string
 	if m.GoPackage() != m.Output.GoPackage() {
 		if IsGooglePackage(m.Output) {
@@ -144,7 +160,7 @@ func (m *Mutation) OutputName() string {
 		if field.IsRepeated() {
 			fieldType = "[" + fieldType + "]"
 		}
-		if field.IsRequired() {
+		if field.IsRequired() || m.Response().GetUnwrap() && m.Response().GetRequired() {
 			fieldType += "!"
 		}
 		return fieldType
@@ -167,6 +183,17 @@ func (m *Mutation) InputType() string {
 	return m.Input.Name()
 }
 
+// OutputType returns Go type of the response message, which is used by typed client
+func (m *Mutation) OutputType() string {
+	return goMessageType(m.Method, m.Output)
+}
+
+// IsPluckInterface returns true if the plucked response field is exposed as interface of graphql.interface option,
+// then the response resolves the populated member of the oneof
+func (m *Mutation) IsPluckInterface() bool {
+	return m.IsPluckResponse() && m.PluckResponse()[0].InterfaceType != ""
+}
+
 func (m *Mutation) PluckResponseFieldName() string {
 	fields := m.PluckResponse()
 	return strcase.ToCamel(fields[0].Name())
@@ -0,0 +1,235 @@
+package spec
+
+import (
+	"errors"
+	"io/ioutil"
+	"strings"
+	"unicode"
+
+	"github.com/iancoleman/strcase"
+	"gopkg.in/yaml.v3"
+)
+
+// Default patterns of GraphQL type names, {Package} is camel-cased package name and {Name} is message or enum name
+const (
+	defaultTypePattern      = "{Package}_Type_{Name}"
+	defaultInputPattern     = "{Package}_Input_{Name}"
+	defaultEnumPattern      = "{Package}_Enum_{Name}"
+	defaultInterfacePattern = "{Package}_Interface_{Name}"
+)
+
+// Naming spec transforms generated GraphQL names, which is loaded from YAML or JSON file of naming argument like:
+//
+//	types: "{Name}"
+//	inputs: "{Name}Input"
+//	acronyms: [URL, ID]
+//	pluralize: true
+//	plurals:
+//	  person: people
+//	mutations:
+//	  strip_suffixes: [Mutation]
+//	  services:
+//	    user.UserService:
+//	      strip_prefixes: [User]
+//
+// Field names are transformed only for output types and interfaces,
+// because arguments and input fields are bound to gRPC request messages by their names.
+type Naming struct {
+	// Patterns of type names
+	Types      string `yaml:"types"`
+	Inputs     string `yaml:"inputs"`
+	Enums      string `yaml:"enums"`
+	Interfaces string `yaml:"interfaces"`
+	// Words which are upper-cased in type and field names like "ImageUrl" to "ImageURL"
+	Acronyms []string `yaml:"acronyms"`
+	// If true, names of repeated fields are pluralized like "tag" to "tags"
+	Pluralize bool `yaml:"pluralize"`
+	// Irregular plural forms by lower-cased singular word
+	Plurals map[string]string `yaml:"plurals"`
+	// Rules of mutation names which are derived from RPC names
+	Mutations MutationNaming `yaml:"mutations"`
+
+	acronyms map[string]string
+}
+
+// MutationNaming declares rules to derive mutation name from RPC name whose graphql.schema option doesn't have name.
+// RPC name is lower-camel-cased after the first matched prefix and suffix are stripped,
+// like "UpdateUserEmail" to "updateUserEmail", or "UserServiceCreate" to "create" by stripping "UserService" prefix.
+type MutationNaming struct {
+	StripPrefixes []string `yaml:"strip_prefixes"`
+	StripSuffixes []string `yaml:"strip_suffixes"`
+	// Rules by service full name like "user.UserService" which replace the default rules
+	Services map[string]MutationNaming `yaml:"services"`
+}
+
+// NewNaming returns default naming spec which keeps generated names as they are
+func NewNaming() *Naming {
+	n := &Naming{}
+	n.setDefaults()
+	return n
+}
+
+// LoadNaming reads naming spec from the file
+func LoadNaming(path string) (*Naming, error) {
+	buf, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	n := &Naming{}
+	// YAML is superset of JSON, so that both formats are accepted
+	if err := yaml.Unmarshal(buf, n); err != nil {
+		return nil, errors.New("failed to parse naming spec " + path + ": " + err.Error())
+	}
+	for _, p := range []string{n.Types, n.Inputs, n.Enums, n.Interfaces} {
+		if p != "" && !strings.Contains(p, "{Name}") {
+			return nil, errors.New("naming pattern " + p + " must contain {Name}")
+		}
+	}
+	n.setDefaults()
+	// Types, inputs, enums and interfaces of the same name would collide in the schema
+	patterns := make(map[string]struct{}, 4)
+	for _, p := range []string{n.Types, n.Inputs, n.Enums, n.Interfaces} {
+		if _, ok := patterns[p]; ok {
+			return nil, errors.New("naming pattern " + p + " is used for several kinds of types, names would collide")
+		}
+		patterns[p] = struct{}{}
+	}
+	return n, nil
+}
+
+func (n *Naming) setDefaults() {
+	if n.Types == "" {
+		n.Types = defaultTypePattern
+	}
+	if n.Inputs == "" {
+		n.Inputs = defaultInputPattern
+	}
+	if n.Enums == "" {
+		n.Enums = defaultEnumPattern
+	}
+	if n.Interfaces == "" {
+		n.Interfaces = defaultInterfacePattern
+	}
+	n.acronyms = make(map[string]string, len(n.Acronyms))
+	for _, a := range n.Acronyms {
+		n.acronyms[strings.ToLower(a)] = strings.ToUpper(a)
+	}
+}
+
+// TypeName returns GraphQL name of object type
+func (n *Naming) TypeName(pkg, name string) string {
+	return n.expand(n.Types, pkg, name)
+}
+
+// InputName returns GraphQL name of input object type
+func (n *Naming) InputName(pkg, name string) string {
+	return n.expand(n.Inputs, pkg, name)
+}
+
+// EnumName returns GraphQL name of enum type
+func (n *Naming) EnumName(pkg, name string) string {
+	return n.expand(n.Enums, pkg, name)
+}
+
+// InterfaceName returns GraphQL name of interface type
+func (n *Naming) InterfaceName(pkg, name string) string {
+	return n.expand(n.Interfaces, pkg, name)
+}
+
+// FieldName returns GraphQL name of the field in output types
+func (n *Naming) FieldName(f *Field) string {
+	name := f.FieldName()
+	if n.Pluralize && f.IsRepeated() {
+		name = n.pluralize(name)
+	}
+	return n.casing(name)
+}
+
+// MutationName returns mutation name of the RPC in the service by the rules
+func (n *Naming) MutationName(service, rpc string) string {
+	rules := n.Mutations
+	if r, ok := n.Mutations.Services[service]; ok {
+		rules = r
+	}
+	name := rpc
+	for _, p := range rules.StripPrefixes {
+		// Strip only whole words, e.g. "Do" is not stripped from "Download"
+		if rest := strings.TrimPrefix(name, p); rest != name && rest != "" && (unicode.IsUpper([]rune(rest)[0]) || rest[0] == '_') {
+			// The separator is stripped with the prefix, otherwise the rest is upper-camel-cased
+			if rest = strings.TrimLeft(rest, "_"); rest != "" {
+				name = rest
+				break
+			}
+		}
+	}
+	for _, s := range rules.StripSuffixes {
+		if rest := strings.TrimSuffix(name, s); rest != name && rest != "" {
+			name = rest
+			break
+		}
+	}
+	return n.casing(strcase.ToLowerCamel(name))
+}
+
+func (n *Naming) expand(pattern, pkg, name string) string {
+	return n.casing(strings.NewReplacer("{Package}", pkg, "{Name}", name).Replace(pattern))
+}
+
+// casing upper-cases title-cased words which are declared as acronyms.
+// Lower-cased words are not changed, so that snake-cased names and the head of lower-camel-cased names are kept.
+func (n *Naming) casing(name string) string {
+	if len(n.acronyms) == 0 {
+		return name
+	}
+	var b strings.Builder
+	for _, word := range splitWords(name) {
+		if r := []rune(word); len(r) > 1 && unicode.IsUpper(r[0]) && word[1:] == strings.ToLower(word[1:]) {
+			if a, ok := n.acronyms[strings.ToLower(word)]; ok {
+				word = a
+			}
+		}
+		b.WriteString(word)
+	}
+	return b.String()
+}
+
+// pluralize returns plural form of the last word of the name.
+// Words which end with "s" are regarded as plural already.
+func (n *Naming) pluralize(name string) string {
+	words := splitWords(name)
+	last := words[len(words)-1]
+	lower := strings.ToLower(last)
+	plural, ok := n.Plurals[lower]
+	switch {
+	case ok:
+	case strings.HasSuffix(lower, "s"):
+		return name
+	case strings.HasSuffix(lower, "x"), strings.HasSuffix(lower, "z"),
+		strings.HasSuffix(lower, "ch"), strings.HasSuffix(lower, "sh"):
+		plural = lower + "es"
+	case len(lower) > 1 && strings.HasSuffix(lower, "y") && !strings.ContainsAny(lower[len(lower)-2:len(lower)-1], "aeiou"):
+		plural = lower[:len(lower)-1] + "ies"
+	default:
+		plural = lower + "s"
+	}
+	// Keep casing of the head of the word
+	if last != lower {
+		plural = strings.ToUpper(plural[:1]) + plural[1:]
+	}
+	words[len(words)-1] = plural
+	return strings.Join(words, "")
+}
+
+// splitWords splits the name into words at upper-cased letters and underscores, underscores belong to the next word
+func splitWords(name string) []string {
+	var words []string
+	start := 0
+	runes := []rune(name)
+	for i := 1; i < len(runes); i++ {
+		if unicode.IsUpper(runes[i]) && !unicode.IsUpper(runes[i-1]) || runes[i] == '_' {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
@@ -0,0 +1,221 @@
+package spec
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	descriptor "github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+)
+
+// testNaming loads naming spec from the content in temporary file
+func testNaming(t *testing.T, content string) (*Naming, error) {
+	dir, err := ioutil.TempDir("", "naming")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "naming.yaml")
+	if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return LoadNaming(path)
+}
+
+func testField(name string, repeated bool) *Field {
+	label := descriptor.FieldDescriptorProto_LABEL_OPTIONAL
+	if repeated {
+		label = descriptor.FieldDescriptorProto_LABEL_REPEATED
+	}
+	d := &descriptor.FieldDescriptorProto{
+		Name:  proto.String(name),
+		Type:  descriptor.FieldDescriptorProto_TYPE_STRING.Enum(),
+		Label: label.Enum(),
+	}
+	return NewField(d, nil, false)
+}
+
+func TestNamingTypeNames(t *testing.T) {
+	cases := []struct {
+		name      string
+		spec      string
+		types     string
+		inputs    string
+		enums     string
+		ifaces    string
+		pkg, base string
+	}{
+		{
+			name:   "default patterns",
+			types:  "Greeter_Type_HelloRequest",
+			inputs: "Greeter_Input_HelloRequest",
+			enums:  "Greeter_Enum_HelloRequest",
+			ifaces: "Greeter_Interface_HelloRequest",
+			pkg:    "Greeter",
+			base:   "HelloRequest",
+		},
+		{
+			name:   "custom patterns",
+			spec:   "types: \"{Name}\"\ninputs: \"{Name}Input\"\nenums: \"{Package}{Name}\"\n",
+			types:  "HelloRequest",
+			inputs: "HelloRequestInput",
+			enums:  "GreeterHelloRequest",
+			ifaces: "Greeter_Interface_HelloRequest",
+			pkg:    "Greeter",
+			base:   "HelloRequest",
+		},
+		{
+			name:   "JSON spec",
+			spec:   `{"types": "{Name}Type", "interfaces": "{Name}Node"}`,
+			types:  "UserType",
+			inputs: "Greeter_Input_User",
+			enums:  "Greeter_Enum_User",
+			ifaces: "UserNode",
+			pkg:    "Greeter",
+			base:   "User",
+		},
+		{
+			name:   "acronyms",
+			spec:   "types: \"{Name}\"\nacronyms: [URL, ID]\n",
+			types:  "ImageURL",
+			inputs: "Greeter_Input_ImageURL",
+			enums:  "Greeter_Enum_ImageURL",
+			ifaces: "Greeter_Interface_ImageURL",
+			pkg:    "Greeter",
+			base:   "ImageUrl",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			n := NewNaming()
+			if c.spec != "" {
+				var err error
+				if n, err = testNaming(t, c.spec); !assert.NoError(t, err) {
+					return
+				}
+			}
+			assert.Equal(t, c.types, n.TypeName(c.pkg, c.base))
+			assert.Equal(t, c.inputs, n.InputName(c.pkg, c.base))
+			assert.Equal(t, c.enums, n.EnumName(c.pkg, c.base))
+			assert.Equal(t, c.ifaces, n.InterfaceName(c.pkg, c.base))
+		})
+	}
+}
+
+func TestNamingInvalidSpec(t *testing.T) {
+	cases := []struct {
+		name string
+		spec string
+		err  string
+	}{
+		{
+			name: "pattern without name",
+			spec: "types: \"{Package}_Type\"\n",
+			err:  "must contain {Name}",
+		},
+		{
+			name: "types and inputs collide",
+			spec: "types: \"{Name}\"\ninputs: \"{Name}\"\n",
+			err:  "names would collide",
+		},
+		{
+			name: "pattern collides with default",
+			spec: "enums: \"{Package}_Type_{Name}\"\n",
+			err:  "names would collide",
+		},
+		{
+			name: "malformed spec",
+			spec: "types: [",
+			err:  "failed to parse naming spec",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			_, err := testNaming(t, c.spec)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), c.err)
+			}
+		})
+	}
+
+	_, err := LoadNaming(filepath.Join(os.TempDir(), "not-found-naming.yaml"))
+	assert.Error(t, err)
+}
+
+func TestNamingFieldName(t *testing.T) {
+	n, err := testNaming(t, "pluralize: true\nacronyms: [URL]\nplurals:\n  person: people\n")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cases := []struct {
+		field    string
+		repeated bool
+		expect   string
+	}{
+		{field: "tag", repeated: true, expect: "tags"},
+		{field: "tags", repeated: true, expect: "tags"},
+		{field: "box", repeated: true, expect: "boxes"},
+		{field: "category", repeated: true, expect: "categories"},
+		{field: "day", repeated: true, expect: "days"},
+		{field: "person", repeated: true, expect: "people"},
+		{field: "relatedPerson", repeated: true, expect: "relatedPeople"},
+		{field: "image_url", repeated: true, expect: "image_urls"},
+		{field: "imageUrl", repeated: false, expect: "imageURL"},
+		{field: "url", repeated: false, expect: "url"},
+		{field: "tag", repeated: false, expect: "tag"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expect, n.FieldName(testField(c.field, c.repeated)), c.field)
+	}
+
+	// Default naming keeps field names
+	assert.Equal(t, "tag", NewNaming().FieldName(testField("tag", true)))
+}
+
+func TestNamingMutationName(t *testing.T) {
+	n, err := testNaming(t, `
+acronyms: [URL]
+mutations:
+  strip_prefixes: [Do]
+  strip_suffixes: [Mutation, Request]
+  services:
+    user.UserService:
+      strip_prefixes: [UserService]
+`)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	cases := []struct {
+		service string
+		rpc     string
+		expect  string
+	}{
+		{service: "greeter.Greeter", rpc: "UpdateUserEmail", expect: "updateUserEmail"},
+		{service: "greeter.Greeter", rpc: "DoUpdate", expect: "update"},
+		// Prefixes are stripped only as whole words
+		{service: "greeter.Greeter", rpc: "Download", expect: "download"},
+		{service: "greeter.Greeter", rpc: "Do_update", expect: "update"},
+		{service: "greeter.Greeter", rpc: "Do_", expect: "do"},
+		{service: "greeter.Greeter", rpc: "CreateUserMutation", expect: "createUser"},
+		// The first matched suffix is stripped only
+		{service: "greeter.Greeter", rpc: "CreateRequestMutation", expect: "createRequest"},
+		// Names are never stripped to be empty
+		{service: "greeter.Greeter", rpc: "Mutation", expect: "mutation"},
+		{service: "greeter.Greeter", rpc: "SetImageUrl", expect: "setImageURL"},
+		// Rules of the service replace the default rules
+		{service: "user.UserService", rpc: "UserServiceCreate", expect: "create"},
+		{service: "user.UserService", rpc: "DoUpdateMutation", expect: "doUpdateMutation"},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expect, n.MutationName(c.service, c.rpc), c.service+"/"+c.rpc)
+	}
+
+	assert.Equal(t, "updateUserEmail", NewNaming().MutationName("greeter.Greeter", "UpdateUserEmail"))
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"regexp"
 	"strings"
+
+	"github.com/ysugimoto/grpc-graphql-gateway/graphql"
 )
 
 var acceptablePathsValues = map[string]struct{}{
@@ -18,11 +20,23 @@ type Params struct {
 	Verbose        bool
 	FieldCamelCase bool
 	Paths          string
+	Naming         *Naming
+	// Default nullability of lists for files which don't declare list_nullability option
+	ListNullability graphql.GraphqlListNullability
+	// Enable strict non-null mode for all proto3 files
+	StrictNonNull bool
+	// Expose string fields named like identifier as ID scalar for all files
+	IDFields bool
+	// Expose bytes fields as Bytes scalar for all files
+	BytesScalar bool
+	// Generate typed clients which call queries and mutations through the gateway over HTTP
+	Client bool
 }
 
 func NewParams(p string) (*Params, error) {
 	params := &Params{
 		Excludes: []*regexp.Regexp{},
+		Naming:   NewNaming(),
 	}
 	if p == "" {
 		return params, nil
@@ -49,6 +63,14 @@ func NewParams(p string) (*Params, error) {
 			params.Excludes = append(params.Excludes, regex)
 		case "field_camel":
 			params.FieldCamelCase = true
+		case "strict_non_null":
+			params.StrictNonNull = true
+		case "id_fields":
+			params.IDFields = true
+		case "bytes_scalar":
+			params.BytesScalar = true
+		case "client":
+			params.Client = true
 		case "paths":
 			if len(kv) == 1 {
 				return nil, errors.New("argument " + kv[0] + " must have value")
@@ -56,6 +78,24 @@ func NewParams(p string) (*Params, error) {
 				return nil, errors.New("argument " + kv[0] + " value must either of import and source_relative")
 			}
 			params.Paths = kv[1]
+		case "naming":
+			if len(kv) == 1 {
+				return nil, errors.New("argument " + kv[0] + " must have value")
+			}
+			naming, err := LoadNaming(kv[1])
+			if err != nil {
+				return nil, err
+			}
+			params.Naming = naming
+		case "list_nullability":
+			if len(kv) == 1 {
+				return nil, errors.New("argument " + kv[0] + " must have value")
+			}
+			v, ok := graphql.GraphqlListNullability_value[strings.ToUpper(kv[1])]
+			if !ok {
+				return nil, errors.New("argument " + kv[0] + " value must be one of nullable_list, non_null_list, non_null_items and non_null_list_and_items")
+			}
+			params.ListNullability = graphql.GraphqlListNullability(v)
 		default:
 			return nil, errors.New("Unacceptable argument " + kv[0] + " provided")
 		}
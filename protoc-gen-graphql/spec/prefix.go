@@ -24,6 +24,11 @@ func PrefixInterface(name string) string {
 	return "Gql__interface_" + name + "()"
 }
 
+// PrefixDeclaredInterface adds prefix to avoid conflicting name
+func PrefixDeclaredInterface(name string) string {
+	return "Gql__declared_" + name + "()"
+}
+
 func IsGooglePackage(p PackageGetter) bool {
 	return strings.HasPrefix(p.Package(), "google.protobuf")
 }
@@ -58,11 +58,12 @@ func (q *Query) IsPluckRequest() bool {
 }
 
 func (q *Query) IsPluckResponse() bool {
-	resp := q.Response()
-	if resp == nil {
-		return false
-	}
-	return resp.GetPluck() != ""
+	return q.PluckResponseName() != ""
+}
+
+// PluckResponseName returns name of the response field which is exposed instead of the response message
+func (q *Query) PluckResponseName() string {
+	return responsePluck(q.Response(), q.Output)
 }
 
 func (q *Query) PluckRequest() []*Field {
@@ -87,10 +88,7 @@ func (q *Query) PluckRequest() []*Field {
 }
 
 func (q *Query) PluckResponse() []*Field {
-	var pluck string
-	if resp := q.Response(); resp != nil {
-		pluck = resp.GetPluck()
-	}
+	pluck := q.PluckResponseName()
 
 	if pluck == "" {
 		return q.Output.Fields()
@@ -108,7 +106,7 @@ func (q *Query) PluckResponse() []*Field {
 func (q *Query) QueryType() string {
 	if q.IsPluckResponse() {
 		field := q.PluckResponse()[0]
-		return field.FieldType(q.GoPackage())
+		return unwrapRequired(q.Response(), field.FieldType(q.GoPackage()))
 	}
 
 	var pkgPrefix string
@@ -140,9 +138,17 @@ func (q *Query) QueryType() string {
 }
 
 func (q *Query) Args() []*Field {
+	if f := requestUnwrap(q.Request(), q.Input); f != nil {
+		return []*Field{f}
+	}
 	return q.PluckRequest()
 }
 
+// ArgumentType returns type of the argument field
+func (q *Query) ArgumentType(f *Field, rootPackage string) string {
+	return argumentType(q.Request(), f, rootPackage)
+}
+
 func (q *Query) SchemaArgs() string {
 	args := make([]string, len(q.Input.Fields()))
 	for i, v := range q.Input.Fields() {
@@ -162,7 +168,7 @@ func (q *Query) OutputName() string {
 		if field.IsRepeated() {
 			fieldType = "[" + fieldType + "]"
 		}
-		if field.IsRequired() {
+		if field.IsRequired() || q.Response().GetUnwrap() && q.Response().GetRequired() {
 			fieldType += "!"
 		}
 		return fieldType
@@ -191,6 +197,17 @@ func (q *Query) InputType() string {
 	return q.Input.Name()
 }
 
+// OutputType returns Go type of the response message, which is used by typed client
+func (q *Query) OutputType() string {
+	return goMessageType(q.Method, q.Output)
+}
+
+// IsPluckInterface returns true if the plucked response field is exposed as interface of graphql.interface option,
+// then the response resolves the populated member of the oneof
+func (q *Query) IsPluckInterface() bool {
+	return q.IsPluckResponse() && q.PluckResponse()[0].InterfaceType != ""
+}
+
 func (q *Query) PluckResponseFieldName() string {
 	fields := q.PluckResponse()
 	return strcase.ToCamel(fields[0].Name())
@@ -60,6 +60,14 @@ func (s *Service) Name() string {
 	return s.descriptor.GetName()
 }
 
+// FullName returns package qualified service name like "greeter.Greeter"
+func (s *Service) FullName() string {
+	if pkg := s.Package(); pkg != "" {
+		return pkg + "." + s.Name()
+	}
+	return s.Name()
+}
+
 func (s *Service) Methods() []*Method {
 	return s.methods
 }
@@ -77,3 +85,17 @@ func (s *Service) Insecure() bool {
 	}
 	return s.Option.GetInsecure()
 }
+
+func (s *Service) TLS() *graphql.GraphqlTLS {
+	if s.Option == nil || s.Option.GetInsecure() {
+		return nil
+	}
+	return s.Option.GetTls()
+}
+
+func (s *Service) ServiceConfig() string {
+	if s.Option == nil {
+		return ""
+	}
+	return s.Option.GetServiceConfig()
+}
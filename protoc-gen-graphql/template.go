@@ -11,6 +11,9 @@ import (
 	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
 	"google.golang.org/grpc"
 	"github.com/pkg/errors"
+{{- else if or .HasFieldCost .HasFieldDirectives .HasMapFields .HasRuntimeScalars .HasOneofInputs .DeclaredInterfaces }}
+
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
 {{- end }}
 	"github.com/graphql-go/graphql"
 
@@ -38,7 +41,7 @@ var (
 func Gql__enum_{{ .Name }}() *graphql.Enum {
 	if gql__enum_{{ .Name }} == nil {
 		gql__enum_{{ .Name }} =  graphql.NewEnum(graphql.EnumConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Enum_{{ .Name }}",
+			Name: "{{ $.Naming.EnumName $.RootPackage.CamelName .Name }}",
 			Values: graphql.EnumValueConfigMap{
 {{- range .Values }}
 				"{{ .Name }}": &graphql.EnumValueConfig{
@@ -60,14 +63,14 @@ func Gql__enum_{{ .Name }}() *graphql.Enum {
 func Gql__interface_{{ .TypeName }}() *graphql.Interface {
 	if gql__interface_{{ .TypeName }} == nil {
 		gql__interface_{{ .TypeName }} =  graphql.NewInterface(graphql.InterfaceConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Interface_{{ .TypeName }}",
+			Name: "{{ $.Naming.InterfaceName $.RootPackage.CamelName .TypeName }}",
 			{{- if .Comment }}
 			Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 			{{- end }}
 			Fields: graphql.Fields{
 {{- range .Fields }}
 			{{- if not .IsCyclic }}
-				"{{ .FieldName }}": &graphql.Field{
+				"{{ $.Naming.FieldName . }}": &graphql.Field{
 					Type: {{ .FieldType $.RootPackage.Path }},
 					{{- if .Comment }}
 					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
@@ -86,11 +89,30 @@ func Gql__interface_{{ .TypeName }}() *graphql.Interface {
 
 {{ end }}
 
-{{ range .Types -}}
+{{ range .DeclaredInterfaces -}}
+func Gql__declared_{{ .Name }}() *graphql.Interface {
+	return runtime.DeclaredInterface("{{ .Name }}", func() graphql.Fields {
+		return graphql.Fields{
+{{- range .Fields }}
+			"{{ $.Naming.FieldName . }}": &graphql.Field{
+				Type: {{ .FieldType $.RootPackage.Path }},
+			},
+{{- end }}
+		}
+	}, runtime.MessageTypes{
+{{- range .Types }}
+		"{{ .Message.FullPath }}": {{ .Func }},
+{{- end }}
+	})
+}
+
+{{ end }}
+
+{{ range $type := .Types -}}
 func Gql__type_{{ .TypeName }}() *graphql.Object {
 	if gql__type_{{ .TypeName }} == nil {
 		gql__type_{{ .TypeName }} =  graphql.NewObject(graphql.ObjectConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Type_{{ .TypeName }}",
+			Name: "{{ $.Naming.TypeName $.RootPackage.CamelName .TypeName }}",
 			{{- if .Comment }}
 			Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 			{{- end }}
@@ -98,7 +120,7 @@ func Gql__type_{{ .TypeName }}() *graphql.Object {
 {{- range .Fields }}
 				{{- if .IsResolve }}
 				{{ $query := .ResolveSubField $.Services }}
-				"{{ .FieldName }}": &graphql.Field{
+				"{{ $.Naming.FieldName . }}": &graphql.Field{
 						Type: {{ $query.QueryType }},
 						{{- if $query.Comment }}
 						Description: ` + "`" + `{{ $query.Comment }}` + "`" + `,
@@ -106,12 +128,12 @@ func Gql__type_{{ .TypeName }}() *graphql.Object {
 						Args: graphql.FieldConfigArgument{
 						{{- range $query.Args }}
 							"{{ .FieldName }}": &graphql.ArgumentConfig{
-								Type: {{ .FieldTypeInput $.RootPackage.Path }},
+								Type: {{ $query.ArgumentType . $.RootPackage.Path }},
 								{{- if .Comment }}
 								Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 								{{- end }}
-								{{- if .DefaultValue }}
-								DefaultValue: {{ .DefaultValue }},
+								{{- with .GoDefaultValue $.RootPackage.Path }}
+								DefaultValue: {{ . }},
 								{{- end }}
 							},
 						{{- end }}
@@ -131,12 +153,14 @@ func Gql__type_{{ .TypeName }}() *graphql.Object {
 							}
 							defer closer()
 							client := {{ $query.Package }}New{{ $query.Method.Service.Name }}Client(conn)
-							resp, err := client.{{ $query.Method.Name }}(p.Context, &req)
+							resp, err := client.{{ $query.Method.Name }}(p.Context, &req, runtime.CallOptions(p.Context)...)
 							if err != nil {
 								return nil, errors.Wrap(err, "Failed to call RPC {{ $query.Method.Name }}")
 							}
 							{{- if $query.IsPluckResponse }}
-								{{- if $query.IsCamel }}
+								{{- if $query.IsPluckInterface }}
+								return runtime.OneofMembers(resp.Get{{ $query.PluckResponseFieldName }}()), nil
+								{{- else if $query.IsCamel }}
 								return runtime.MarshalResponse(resp.Get{{ $query.PluckResponseFieldName }}()), nil
 								{{- else }}
 								return resp.Get{{ $query.PluckResponseFieldName }}(), nil
@@ -151,23 +175,59 @@ func Gql__type_{{ .TypeName }}() *graphql.Object {
 						},
 				},
 				{{- else }}
-				"{{ .FieldName }}": &graphql.Field{
+				"{{ $.Naming.FieldName . }}": &graphql.Field{
 					Type: {{ .FieldType $.RootPackage.Path }},
 					{{- if .Comment }}
 					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 					{{- end }}
+					{{- if or (ne ($.Naming.FieldName .) .FieldName) .IsMap .InterfaceType }}
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						{{- if ne ($.Naming.FieldName .) .FieldName }}
+						// Resolve renamed field by the original name
+						p.Info.FieldName = "{{ .FieldName }}"
+						{{- end }}
+						{{- if .IsMap }}
+						v, err := graphql.DefaultResolveFn(p)
+						if err != nil {
+							return nil, err
+						}
+						return runtime.MapEntries(v), nil
+						{{- else if .InterfaceType }}
+						v, err := graphql.DefaultResolveFn(p)
+						if err != nil {
+							return nil, err
+						}
+						// Resolve the populated member of the oneof, whose object type is resolved by the interface
+						return runtime.OneofMembers(v), nil
+						{{- else }}
+						return graphql.DefaultResolveFn(p)
+						{{- end }}
+					},
+					{{- end }}
 				},
 				{{- end }}
 {{- end }}
 			},
-			{{- if .Interfaces }}
+			{{- if or .Interfaces ($.ImplementedInterfaces .) }}
 			Interfaces: []*graphql.Interface{
 {{- range .Interfaces }}
 			Gql__interface_{{ .TypeName }}(),
+{{- end }}
+{{- range ($.ImplementedInterfaces .) }}
+			Gql__declared_{{ .Name }}(),
 {{- end }}
 			},
 			{{- end }}
 		})
+{{- range .Fields }}
+		{{- if .Cost }}
+		runtime.SetFieldCost("{{ $.Naming.TypeName $.RootPackage.CamelName $type.TypeName }}", "{{ $.Naming.FieldName . }}", {{ .Cost }})
+		{{- end }}
+		{{- if .Directives }}
+		runtime.SetFieldDirectives("{{ $.Naming.TypeName $.RootPackage.CamelName $type.TypeName }}", "{{ $.Naming.FieldName . }}"
+			{{- range .Directives }}, {{ printf "%q" . }}{{ end }})
+		{{- end }}
+{{- end }}
 	}
 	return gql__type_{{ .TypeName }}
 }
@@ -178,18 +238,35 @@ func Gql__type_{{ .TypeName }}() *graphql.Object {
 func Gql__input_{{ .TypeName }}() *graphql.InputObject {
 	if gql__input_{{ .TypeName }} == nil {
 		gql__input_{{ .TypeName }} =  graphql.NewInputObject(graphql.InputObjectConfig{
-			Name: "{{ $.RootPackage.CamelName }}_Input_{{ .TypeName }}",
+			Name: "{{ $.Naming.InputName $.RootPackage.CamelName .TypeName }}",
+			{{- if .IsCyclicInput }}
+			// Fields are resolved lazily because the input refers to itself
+			Fields: (graphql.InputObjectConfigFieldMapThunk)(func() graphql.InputObjectConfigFieldMap {
+				return graphql.InputObjectConfigFieldMap{
+			{{- else }}
 			Fields: graphql.InputObjectConfigFieldMap{
+			{{- end }}
 {{- range .Fields }}
 				"{{ .FieldName }}": &graphql.InputObjectFieldConfig{
 					{{- if .Comment }}
 					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 					{{- end }}
 					Type: {{ .FieldTypeInput $.RootPackage.Path }},
+					{{- with .GoDefaultValue $.RootPackage.Path }}
+					DefaultValue: {{ . }},
+					{{- end }}
 				},
 {{- end }}
+			{{- if .IsCyclicInput }}
+				}
+			}),
+			{{- else }}
 			},
+			{{- end }}
 		})
+		{{- if .IsOneofInput }}
+		runtime.SetOneofInput("{{ $.Naming.InputName $.RootPackage.CamelName .TypeName }}")
+		{{- end }}
 	}
 	return gql__input_{{ .TypeName }}
 }
@@ -221,6 +298,9 @@ func new_graphql_resolver_{{ $service.Name }}(conn *grpc.ClientConn) *graphql__r
 		{{- if .Insecure }}
 			grpc.WithInsecure(),
 		{{- end }}
+		{{- if .ServiceConfig }}
+			grpc.WithDefaultServiceConfig({{ printf "%q" .ServiceConfig }}),
+		{{- end }}
 		},
 	}
 }
@@ -233,13 +313,95 @@ func (x *graphql__resolver_{{ $service.Name }}) CreateConnection(ctx context.Con
 	}
 
 	// Otherwise, this handler opens connection with specified host
-	conn, err := grpc.DialContext(ctx, x.host, x.dialOptions...)
+	opts := append([]grpc.DialOption{}, x.dialOptions...)
+	{{- with .TLS }}
+	creds, err := runtime.UpstreamTLS(runtime.UpstreamTLSConfig{
+		CAFile:             {{ printf "%q" .CaFile }},
+		CertFile:           {{ printf "%q" .CertFile }},
+		KeyFile:            {{ printf "%q" .KeyFile }},
+		ServerName:         {{ printf "%q" .ServerName }},
+		InsecureSkipVerify: {{ .InsecureSkipVerify }},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	opts = append(opts, creds)
+	{{- end }}
+	conn, err := runtime.DialUpstream(ctx, x.ServiceName(), x.host, opts...)
 	if err != nil {
 		return nil, nil, err
 	}
 	return conn, func() { conn.Close() }, nil
 }
 
+// ServiceName returns gRPC service name which is used in schema diagnostics.
+func (x *graphql__resolver_{{ $service.Name }}) ServiceName() string {
+	return "{{ $service.FullName }}"
+}
+
+// FieldMappings returns gRPC methods which back queries and mutations, which is used in schema diagnostics.
+func (x *graphql__resolver_{{ $service.Name }}) FieldMappings() []runtime.FieldMapping {
+	return []runtime.FieldMapping{
+{{- range .Queries }}
+	{{- if not .IsResolver }}
+		{
+			Operation:    "query",
+			Field:        "{{ .QueryName }}",
+			Service:      "{{ $service.FullName }}",
+			Method:       "/{{ $service.FullName }}/{{ .Method.Name }}",
+			RequestType:  "{{ .Method.Input }}",
+			ResponseType: "{{ .Method.Output }}",
+			Arguments: []runtime.ArgumentBinding{
+			{{- range .Args }}
+				{Argument: "{{ .FieldName }}", RequestField: "{{ .Name }}"},
+			{{- end }}
+			},
+			{{- if .IsPluckResponse }}
+			ResponseField: "{{ .PluckResponseName }}",
+			{{- end }}
+		},
+	{{- end }}
+{{- end }}
+{{- range .Mutations }}
+		{
+			Operation:    "mutation",
+			Field:        "{{ .MutationName }}",
+			Service:      "{{ $service.FullName }}",
+			Method:       "/{{ $service.FullName }}/{{ .Method.Name }}",
+			RequestType:  "{{ .Method.Input }}",
+			ResponseType: "{{ .Method.Output }}",
+			{{- if .InputName }}
+			Input:        "{{ .InputName }}",
+			{{- end }}
+			Arguments: []runtime.ArgumentBinding{
+			{{- range .Args }}
+				{Argument: "{{ .FieldName }}", RequestField: "{{ .Name }}"},
+			{{- end }}
+			},
+			{{- if .IsPluckResponse }}
+			ResponseField: "{{ .PluckResponseName }}",
+			{{- end }}
+		},
+{{- end }}
+	}
+}
+
+// FieldGroups returns groups of root fields which are declared by group of graphql.schema option.
+func (x *graphql__resolver_{{ $service.Name }}) FieldGroups() map[string]string {
+	return map[string]string{
+{{- range .Queries }}
+	{{- if and (not .IsResolver) .Schema.GetGroup }}
+		"{{ .QueryName }}": "{{ .Schema.GetGroup }}",
+	{{- end }}
+{{- end }}
+{{- range .Mutations }}
+	{{- if .Schema.GetGroup }}
+		"{{ .MutationName }}": "{{ .Schema.GetGroup }}",
+	{{- end }}
+{{- end }}
+	}
+}
+
 // GetQueries returns acceptable graphql.Fields for Query.
 func (x *graphql__resolver_{{ $service.Name }}) GetQueries(conn *grpc.ClientConn) graphql.Fields {
 	return graphql.Fields{
@@ -251,14 +413,15 @@ func (x *graphql__resolver_{{ $service.Name }}) GetQueries(conn *grpc.ClientConn
 			Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 			{{- end }}
 			Args: graphql.FieldConfigArgument{
+			{{- $method := . }}
 			{{- range .Args }}
 				"{{ .FieldName }}": &graphql.ArgumentConfig{
-					Type: {{ .FieldTypeInput $.RootPackage.Path }},
+					Type: {{ $method.ArgumentType . $.RootPackage.Path }},
 					{{- if .Comment }}
 					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 					{{- end }}
-					{{- if .DefaultValue }}
-					DefaultValue: {{ .DefaultValue }},
+					{{- with .GoDefaultValue $.RootPackage.Path }}
+					DefaultValue: {{ . }},
 					{{- end }}
 				},
 			{{- end }}
@@ -269,12 +432,14 @@ func (x *graphql__resolver_{{ $service.Name }}) GetQueries(conn *grpc.ClientConn
 					return nil, errors.Wrap(err, "Failed to marshal request for {{ .QueryName }}")
 				}
 				client := New{{ .Method.Service.Name }}Client(conn)
-				resp, err := client.{{ .Method.Name }}(p.Context, &req)
+				resp, err := client.{{ .Method.Name }}(p.Context, &req, runtime.CallOptions(p.Context)...)
 				if err != nil {
 					return nil, errors.Wrap(err, "Failed to call RPC {{ .Method.Name }}")
 				}
 				{{- if .IsPluckResponse }}
-					{{- if .IsCamel }}
+					{{- if .IsPluckInterface }}
+					return runtime.OneofMembers(resp.Get{{ .PluckResponseFieldName }}()), nil
+					{{- else if .IsCamel }}
 					return runtime.MarshalResponse(resp.Get{{ .PluckResponseFieldName }}()), nil
 					{{- else }}
 					return resp.Get{{ .PluckResponseFieldName }}(), nil
@@ -308,14 +473,15 @@ func (x *graphql__resolver_{{ $service.Name }}) GetMutations(conn *grpc.ClientCo
 					Type: graphql.NewNonNull(Gql__input_{{ .Input.TypeName }}()),
 				},
 			{{- else }}
+			{{- $method := . }}
 			{{- range .Args }}
 				"{{ .FieldName }}": &graphql.ArgumentConfig{
-					Type: {{ .FieldTypeInput $.RootPackage.Path }},
+					Type: {{ $method.ArgumentType . $.RootPackage.Path }},
 					{{- if .Comment }}
 					Description: ` + "`" + `{{ .Comment }}` + "`" + `,
 					{{- end }}
-					{{- if .DefaultValue }}
-					DefaultValue: {{ .DefaultValue }},
+					{{- with .GoDefaultValue $.RootPackage.Path }}
+					DefaultValue: {{ . }},
 					{{- end }}
 				},
 			{{- end }}
@@ -324,19 +490,21 @@ func (x *graphql__resolver_{{ $service.Name }}) GetMutations(conn *grpc.ClientCo
 			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
 				var req {{ .InputType }}
 				{{- if .InputName }}
-				if err := runtime.MarshalRequest(p.Args["{{ .InputName }}"], &req, {{ if .IsCamel }}true{{ else }}false{{ end }}); err != nil {
+				if err := runtime.MarshalInput(p.Args["{{ .InputName }}"], &req, {{ if .IsCamel }}true{{ else }}false{{ end }}); err != nil {
 				{{- else }}
 				if err := runtime.MarshalRequest(p.Args, &req, {{ if .IsCamel }}true{{ else }}false{{ end }}); err != nil {
 				{{- end }}
 					return nil, errors.Wrap(err, "Failed to marshal request for {{ .MutationName }}")
 				}
 				client := New{{ $service.Name }}Client(conn)
-				resp, err := client.{{ .Method.Name }}(p.Context, &req)
+				resp, err := client.{{ .Method.Name }}(p.Context, &req, runtime.CallOptions(p.Context)...)
 				if err != nil {
 					return nil, errors.Wrap(err, "Failed to call RPC {{ .Method.Name }}")
 				}
 				{{- if .IsPluckResponse }}
-					{{- if .IsCamel }}
+					{{- if .IsPluckInterface }}
+					return runtime.OneofMembers(resp.Get{{ .PluckResponseFieldName }}()), nil
+					{{- else if .IsCamel }}
 					return runtime.MarshalResponse(resp.Get{{ .PluckResponseFieldName }}()), nil
 					{{- else }}
 					return resp.Get{{ .PluckResponseFieldName }}(), nil
@@ -371,6 +539,8 @@ func Register{{ .Name }}Graphql(mux *runtime.ServeMux) error {
 //    option (graphql.service) = {
 //        host: "host:port"
 //        insecure: true or false
+//        service_config: "JSON service config"
+//        tls: { ca_file: "ca.pem" }
 //    };
 //
 //    ...with RPC definitions
@@ -380,4 +550,70 @@ func Register{{ .Name }}GraphqlHandler(mux *runtime.ServeMux, conn *grpc.ClientC
 }
 
 {{ end }}
+{{- if .Client }}
+{{ range $_, $service := .Services -}}
+// {{ $service.Name }}GraphqlClient calls queries and mutations of {{ $service.FullName }} through the gateway over HTTP.
+type {{ $service.Name }}GraphqlClient struct {
+	client *runtime.GraphqlClient
+}
+
+// New{{ $service.Name }}GraphqlClient creates typed client which sends requests via the client.
+// Note that fields are expected to be served at the root of the schema, not under the namespace of ServeMux.
+func New{{ $service.Name }}GraphqlClient(client *runtime.GraphqlClient) *{{ $service.Name }}GraphqlClient {
+	return &{{ $service.Name }}GraphqlClient{client: client}
+}
+{{ range .Queries }}
+{{- if not (or .IsResolver .IsPluckInterface) }}
+// {{ .Method.Name }} calls {{ .QueryName }} query.
+func (c *{{ $service.Name }}GraphqlClient) {{ .Method.Name }}(ctx context.Context, req *{{ .InputType }}) (*{{ .OutputType }}, error) {
+	var resp {{ .OutputType }}
+	err := c.client.Call(ctx, runtime.ClientField{
+		Operation: "query",
+		{{- if .Schema.GetGroup }}
+		Group:     "{{ .Schema.GetGroup }}",
+		{{- end }}
+		Name:      "{{ .QueryName }}",
+		Arguments: runtime.GraphqlArguments(req, []string{ {{- range .Args }}"{{ .Name }}", {{ end -}} }, {{ .IsCamel }}),
+		Selection: {{ printf "%q" ($.ClientSelection .Output .PluckResponseName) }},
+		{{- if .IsPluckResponse }}
+		ResponseField: "{{ .PluckResponseName }}",
+		{{- end }}
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+{{ end }}
+{{- end }}
+{{- range .Mutations }}
+{{- if not .IsPluckInterface }}
+// {{ .Method.Name }} calls {{ .MutationName }} mutation.
+func (c *{{ $service.Name }}GraphqlClient) {{ .Method.Name }}(ctx context.Context, req *{{ .InputType }}) (*{{ .OutputType }}, error) {
+	var resp {{ .OutputType }}
+	err := c.client.Call(ctx, runtime.ClientField{
+		Operation: "mutation",
+		{{- if .Schema.GetGroup }}
+		Group:     "{{ .Schema.GetGroup }}",
+		{{- end }}
+		Name:      "{{ .MutationName }}",
+		{{- if .InputName }}
+		Arguments: runtime.GraphqlInputArgument("{{ .InputName }}", req, {{ .IsCamel }}),
+		{{- else }}
+		Arguments: runtime.GraphqlArguments(req, []string{ {{- range .Args }}"{{ .Name }}", {{ end -}} }, {{ .IsCamel }}),
+		{{- end }}
+		Selection: {{ printf "%q" ($.ClientSelection .Output .PluckResponseName) }},
+		{{- if .IsPluckResponse }}
+		ResponseField: "{{ .PluckResponseName }}",
+		{{- end }}
+	}, &resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+{{ end }}
+{{- end }}
+{{ end }}
+{{- end }}
 `
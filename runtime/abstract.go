@@ -0,0 +1,220 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Helpers to resolve object types of unions and interfaces.
+// Types are passed as functions like generated Gql__type_* in order to refer to them lazily,
+// and the resolved object is the one which is in the schema so that __typename is the name of the object.
+// Nil is resolved if the value doesn't match any of types, then graphql-go responds the error of the field.
+
+// ResolveTypeByMessage returns ResolveTypeFn which resolves the type by full name of proto message of the value
+func ResolveTypeByMessage(types map[protoreflect.FullName]func() *graphql.Object) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		msg, ok := p.Value.(proto.Message)
+		if !ok {
+			return nil
+		}
+		if fn, ok := types[msg.ProtoReflect().Descriptor().FullName()]; ok {
+			return fn()
+		}
+		return nil
+	}
+}
+
+// ResolveTypeByOneof returns ResolveTypeFn which resolves the type by the populated member of the oneof in the message.
+// Types are keyed by proto field names of the members.
+func ResolveTypeByOneof(oneof protoreflect.Name, types map[protoreflect.Name]func() *graphql.Object) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		msg, ok := p.Value.(proto.Message)
+		if !ok {
+			return nil
+		}
+		fd := populatedMember(msg.ProtoReflect(), oneof)
+		if fd == nil {
+			return nil
+		}
+		if fn, ok := types[fd.Name()]; ok {
+			return fn()
+		}
+		return nil
+	}
+}
+
+// ResolveTypeByField returns ResolveTypeFn which resolves the type by the value of type field like "kind",
+// which is the convention of messages that don't use oneof. The field is looked up by proto field name of the message,
+// or by the key of the object that dynamic handlers resolve. Types are keyed by string values or enum value names.
+func ResolveTypeByField(field string, types map[string]func() *graphql.Object) graphql.ResolveTypeFn {
+	return func(p graphql.ResolveTypeParams) *graphql.Object {
+		var name string
+		switch v := p.Value.(type) {
+		case proto.Message:
+			m := v.ProtoReflect()
+			fd := m.Descriptor().Fields().ByName(protoreflect.Name(field))
+			if fd == nil {
+				return nil
+			}
+			if fd.Kind() == protoreflect.EnumKind {
+				if ev := fd.Enum().Values().ByNumber(m.Get(fd).Enum()); ev != nil {
+					name = string(ev.Name())
+				}
+			} else {
+				name = m.Get(fd).String()
+			}
+		case map[string]interface{}:
+			if tv, ok := v[field]; ok && tv != nil {
+				name = fmt.Sprint(tv)
+			}
+		}
+		if fn, ok := types[name]; ok {
+			return fn()
+		}
+		return nil
+	}
+}
+
+// ResolveOneofMember returns FieldResolveFn which resolves the populated member of the oneof in the source message,
+// so that the field of union whose members are message fields of the oneof is resolved with ResolveTypeByMessage.
+func ResolveOneofMember(oneof protoreflect.Name) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		msg, ok := p.Source.(proto.Message)
+		if !ok {
+			return nil, fmt.Errorf("oneof %s must be resolved from proto message, got %T", oneof, p.Source)
+		}
+		m := msg.ProtoReflect()
+		fd := populatedMember(m, oneof)
+		if fd == nil {
+			return nil, nil
+		}
+		if fd.Message() != nil {
+			return m.Get(fd).Message().Interface(), nil
+		}
+		return m.Get(fd).Interface(), nil
+	}
+}
+
+// populatedMember returns the member field which is set in the oneof, nil if no member is set or the oneof doesn't exist
+func populatedMember(m protoreflect.Message, oneof protoreflect.Name) protoreflect.FieldDescriptor {
+	od := m.Descriptor().Oneofs().ByName(oneof)
+	if od == nil {
+		return nil
+	}
+	return m.WhichOneof(od)
+}
+
+// OneofMembers resolves the populated member of the message which consists of a single oneof,
+// or the members of the list of such messages. It is used for fields which are exposed as the interface
+// by interface of graphql.field option, then the object type of the member is resolved by DeclaredInterface.
+func OneofMembers(v interface{}) interface{} {
+	if msg, ok := v.(proto.Message); ok {
+		return oneofMember(msg.ProtoReflect())
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return v
+	}
+	members := make([]interface{}, rv.Len())
+	for i := range members {
+		members[i] = OneofMembers(rv.Index(i).Interface())
+	}
+	return members
+}
+
+func oneofMember(m protoreflect.Message) interface{} {
+	if !m.IsValid() || m.Descriptor().Oneofs().Len() == 0 {
+		return nil
+	}
+	fd := m.WhichOneof(m.Descriptor().Oneofs().Get(0))
+	if fd == nil || fd.Message() == nil {
+		return nil
+	}
+	return m.Get(fd).Message().Interface()
+}
+
+// MessageTypes maps full names of proto messages to their object types
+type MessageTypes map[protoreflect.FullName]func() *graphql.Object
+
+// declaredInterface is interface of graphql.interface option which is shared by generated packages
+type declaredInterface struct {
+	iface *graphql.Interface
+	mu    sync.RWMutex
+	types MessageTypes
+}
+
+var (
+	declaredInterfacesMu sync.Mutex
+	declaredInterfaces   = make(map[string]*declaredInterface)
+)
+
+// DeclaredInterface returns the interface of graphql.interface option by the name, which is shared by generated packages
+// because messages of several packages can implement it. Fields are declared by the first caller,
+// and object types of the messages which implement the interface are added by each caller.
+// The object type is resolved by proto message of the value, and the types are added to the schema
+// even if no field refers them, so that they can be queried via fields of the interface.
+func DeclaredInterface(name string, fields graphql.FieldsThunk, types MessageTypes) *graphql.Interface {
+	declaredInterfacesMu.Lock()
+	defer declaredInterfacesMu.Unlock()
+	di, ok := declaredInterfaces[name]
+	if !ok {
+		di = &declaredInterface{
+			types: MessageTypes{},
+		}
+		di.iface = graphql.NewInterface(graphql.InterfaceConfig{
+			Name:   name,
+			Fields: fields,
+			ResolveType: func(p graphql.ResolveTypeParams) *graphql.Object {
+				msg, ok := p.Value.(proto.Message)
+				if !ok {
+					return nil
+				}
+				di.mu.RLock()
+				fn, ok := di.types[msg.ProtoReflect().Descriptor().FullName()]
+				di.mu.RUnlock()
+				if !ok {
+					return nil
+				}
+				return fn()
+			},
+		})
+		declaredInterfaces[name] = di
+	}
+	di.mu.Lock()
+	defer di.mu.Unlock()
+	for k, v := range types {
+		di.types[k] = v
+	}
+	return di.iface
+}
+
+// declaredImplementations returns object types which implement declared interfaces in the schema but the schema doesn't have
+func declaredImplementations(schema graphql.Schema) []graphql.Type {
+	// Object types are built after unlock because they refer the interfaces by DeclaredInterface
+	var fns []func() *graphql.Object
+	declaredInterfacesMu.Lock()
+	for name, di := range declaredInterfaces {
+		if schema.Type(name) != di.iface {
+			continue
+		}
+		di.mu.RLock()
+		for _, fn := range di.types {
+			fns = append(fns, fn)
+		}
+		di.mu.RUnlock()
+	}
+	declaredInterfacesMu.Unlock()
+
+	var types []graphql.Type
+	for _, fn := range fns {
+		if obj := fn(); schema.Type(obj.Name()) == nil {
+			types = append(types, obj)
+		}
+	}
+	return types
+}
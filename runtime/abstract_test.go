@@ -0,0 +1,281 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const testSearchFile = `
+name: "search.proto"
+package: "search.v1"
+syntax: "proto3"
+message_type {
+  name: "Article"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+}
+message_type {
+  name: "Video"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  field { name: "seconds" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "seconds" }
+}
+message_type {
+  name: "Result"
+  field { name: "article" number: 1 type: TYPE_MESSAGE type_name: ".search.v1.Article" label: LABEL_OPTIONAL json_name: "article" oneof_index: 0 }
+  field { name: "video" number: 2 type: TYPE_MESSAGE type_name: ".search.v1.Video" label: LABEL_OPTIONAL json_name: "video" oneof_index: 0 }
+  field { name: "kind" number: 3 type: TYPE_ENUM type_name: ".search.v1.Kind" label: LABEL_OPTIONAL json_name: "kind" }
+  oneof_decl { name: "item" }
+}
+enum_type {
+  name: "Kind"
+  value { name: "ARTICLE" number: 0 }
+  value { name: "VIDEO" number: 1 }
+}
+`
+
+// testMessageField resolves the field of dynamic message by proto field name
+func testMessageField(t graphql.Output, name protoreflect.Name) *graphql.Field {
+	return &graphql.Field{
+		Type: t,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			m := p.Source.(proto.Message).ProtoReflect() // nolint: errcheck
+			return m.Get(m.Descriptor().Fields().ByName(name)).Interface(), nil
+		},
+	}
+}
+
+func TestResolveAbstractTypes(t *testing.T) {
+	file := testDynamicFile(t, "search.proto", testSearchFile)
+	messages := file.Messages()
+
+	article := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Article",
+		Fields: graphql.Fields{
+			"title": testMessageField(graphql.String, "title"),
+		},
+	})
+	video := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Video",
+		Fields: graphql.Fields{
+			"title":   testMessageField(graphql.String, "title"),
+			"seconds": testMessageField(graphql.Int, "seconds"),
+		},
+	})
+	articleType := func() *graphql.Object { return article }
+	videoType := func() *graphql.Object { return video }
+
+	item := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "Item",
+		Types: []*graphql.Object{article, video},
+		ResolveType: ResolveTypeByMessage(map[protoreflect.FullName]func() *graphql.Object{
+			"search.v1.Article": articleType,
+			"search.v1.Video":   videoType,
+		}),
+	})
+	result := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Result",
+		Fields: graphql.Fields{
+			"item": &graphql.Field{
+				Type:    item,
+				Resolve: ResolveOneofMember("item"),
+			},
+		},
+	})
+
+	// Results are resolved by the populated oneof member or kind field as results themselves
+	byOneof := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "ByOneof",
+		Types: []*graphql.Object{result},
+		ResolveType: ResolveTypeByOneof("item", map[protoreflect.Name]func() *graphql.Object{
+			"video": func() *graphql.Object { return result },
+		}),
+	})
+	byKind := graphql.NewUnion(graphql.UnionConfig{
+		Name:  "ByKind",
+		Types: []*graphql.Object{result},
+		ResolveType: ResolveTypeByField("kind", map[string]func() *graphql.Object{
+			"VIDEO": func() *graphql.Object { return result },
+		}),
+	})
+
+	newResult := func(member protoreflect.Name, title string, kind protoreflect.EnumNumber) proto.Message {
+		r := dynamicpb.NewMessage(messages.ByName("Result"))
+		fd := r.Descriptor().Fields().ByName(member)
+		m := dynamicpb.NewMessage(fd.Message())
+		m.Set(fd.Message().Fields().ByName("title"), protoreflect.ValueOfString(title))
+		r.Set(fd, protoreflect.ValueOfMessage(m))
+		r.Set(r.Descriptor().Fields().ByName("kind"), protoreflect.ValueOfEnum(kind))
+		return r
+	}
+	results := []interface{}{
+		newResult("article", "Go", 0),
+		newResult("video", "GraphQL", 1),
+		dynamicpb.NewMessage(messages.ByName("Result")),
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"results": &graphql.Field{
+					Type: graphql.NewList(result),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return results, nil
+					},
+				},
+				"byOneof": &graphql.Field{
+					Type: byOneof,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return results[1], nil
+					},
+				},
+				"byKind": &graphql.Field{
+					Type: byKind,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return map[string]interface{}{"kind": "VIDEO"}, nil
+					},
+				},
+			},
+		}),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	res := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			results { item { __typename ... on Article { title } ... on Video { title seconds } } }
+			byOneof { __typename }
+			byKind { __typename }
+		}`,
+	})
+	assert.Len(t, res.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{"item": map[string]interface{}{"__typename": "Article", "title": "Go"}},
+			map[string]interface{}{"item": map[string]interface{}{"__typename": "Video", "title": "GraphQL", "seconds": 0}},
+			map[string]interface{}{"item": nil},
+		},
+		"byOneof": map[string]interface{}{"__typename": "Result"},
+		"byKind":  map[string]interface{}{"__typename": "Result"},
+	}, res.Data)
+
+	// Value which matches none of types is the error of the field
+	results[1] = newResult("article", "Go", 0)
+	res = graphql.Do(graphql.Params{
+		Schema:        schema,
+		RequestString: `{ byOneof { __typename } }`,
+	})
+	assert.Len(t, res.Errors, 1)
+}
+
+func TestDeclaredInterface(t *testing.T) {
+	file := testDynamicFile(t, "search.proto", testSearchFile)
+	messages := file.Messages()
+
+	fields := func() graphql.Fields {
+		return graphql.Fields{
+			"title": &graphql.Field{Type: graphql.String},
+		}
+	}
+	var article, video *graphql.Object
+	articleType := func() *graphql.Object {
+		if article == nil {
+			article = graphql.NewObject(graphql.ObjectConfig{
+				Name: "DeclaredArticle",
+				Fields: graphql.Fields{
+					"title": testMessageField(graphql.String, "title"),
+				},
+				Interfaces: []*graphql.Interface{DeclaredInterface("Declared", fields, nil)},
+			})
+		}
+		return article
+	}
+	videoType := func() *graphql.Object {
+		if video == nil {
+			video = graphql.NewObject(graphql.ObjectConfig{
+				Name: "DeclaredVideo",
+				Fields: graphql.Fields{
+					"title":   testMessageField(graphql.String, "title"),
+					"seconds": testMessageField(graphql.Int, "seconds"),
+				},
+				Interfaces: []*graphql.Interface{DeclaredInterface("Declared", fields, nil)},
+			})
+		}
+		return video
+	}
+
+	// Interface is shared by the name, and types are merged by each caller
+	iface := DeclaredInterface("Declared", fields, MessageTypes{"search.v1.Article": articleType})
+	assert.Equal(t, iface, DeclaredInterface("Declared", fields, MessageTypes{"search.v1.Video": videoType}))
+
+	newResult := func(member protoreflect.Name, title string) proto.Message {
+		r := dynamicpb.NewMessage(messages.ByName("Result"))
+		fd := r.Descriptor().Fields().ByName(member)
+		m := dynamicpb.NewMessage(fd.Message())
+		m.Set(fd.Message().Fields().ByName("title"), protoreflect.ValueOfString(title))
+		r.Set(fd, protoreflect.ValueOfMessage(m))
+		return r
+	}
+	results := []proto.Message{
+		newResult("article", "Go"),
+		newResult("video", "GraphQL"),
+		dynamicpb.NewMessage(messages.ByName("Result")),
+	}
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"results": &graphql.Field{
+					Type: graphql.NewList(iface),
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return OneofMembers(results), nil
+					},
+				},
+				"first": &graphql.Field{
+					Type: iface,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return OneofMembers(results[1]), nil
+					},
+				},
+			},
+		}),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	// Implementations are not in the schema since no field refers them
+	types := declaredImplementations(schema)
+	assert.Len(t, types, 2)
+	schema, err = graphql.NewSchema(graphql.SchemaConfig{
+		Query: schema.QueryType(),
+		Types: types,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, declaredImplementations(schema), 0)
+
+	res := graphql.Do(graphql.Params{
+		Schema: schema,
+		RequestString: `{
+			results { __typename title ... on DeclaredVideo { seconds } }
+			first { __typename }
+		}`,
+	})
+	assert.Len(t, res.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"results": []interface{}{
+			map[string]interface{}{"__typename": "DeclaredArticle", "title": "Go"},
+			map[string]interface{}{"__typename": "DeclaredVideo", "title": "GraphQL", "seconds": 0},
+			nil,
+		},
+		"first": map[string]interface{}{"__typename": "DeclaredVideo"},
+	}, res.Data)
+}
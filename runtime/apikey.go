@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"net/http"
+	"net/textproto"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// APIKeyHeader is the HTTP header name which APIKey middleware reads a key from
+const APIKeyHeader = "X-Api-Key"
+
+// ErrInvalidAPIKey should be returned from KeyValidator when the key is not acceptable
+var ErrInvalidAPIKey = errors.New("invalid api key")
+
+// KeyValidator validates API key which is sent via X-Api-Key header.
+// Validate returns metadata which is associated with the key,
+// then the metadata is forwarded to upstream gRPC services.
+type KeyValidator interface {
+	Validate(ctx context.Context, key string) (metadata.MD, error)
+}
+
+// KeyValidatorFunc is an adapter to use ordinary function as KeyValidator
+type KeyValidatorFunc func(ctx context.Context, key string) (metadata.MD, error)
+
+// Validate implements KeyValidator
+func (f KeyValidatorFunc) Validate(ctx context.Context, key string) (metadata.MD, error) {
+	return f(ctx, key)
+}
+
+// StaticKeyValidator validates API key from static key set.
+// The value of each key is forwarded as metadata, it could be nil.
+type StaticKeyValidator map[string]metadata.MD
+
+// Validate implements KeyValidator
+func (s StaticKeyValidator) Validate(ctx context.Context, key string) (metadata.MD, error) {
+	md, ok := s[key]
+	if !ok {
+		return nil, ErrInvalidAPIKey
+	}
+	return md, nil
+}
+
+// RemoteKeyValidator validates API key by asking to remote HTTP endpoint.
+// The key is sent as X-Api-Key header, and treats as valid when endpoint responds 200 OK.
+// Headers which have Grpc-Metadata- prefix in the response are forwarded as metadata.
+type RemoteKeyValidator struct {
+	Endpoint string
+	Client   *http.Client
+}
+
+// Validate implements KeyValidator
+func (v *RemoteKeyValidator) Validate(ctx context.Context, key string) (metadata.MD, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, v.Endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set(APIKeyHeader, key)
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check api key: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, ErrInvalidAPIKey
+	}
+	// Only headers which are explicitly declared as metadata are forwarded,
+	// permanent headers of the response like Content-Type or Set-Cookie are not for upstream services
+	md := metadata.MD{}
+	for k, vs := range resp.Header {
+		if key := textproto.CanonicalMIMEHeaderKey(k); strings.HasPrefix(key, MetadataHeaderPrefix) {
+			md.Append(key[len(MetadataHeaderPrefix):], vs...)
+		}
+	}
+	return md, nil
+}
+
+type apiKeyKey struct{}
+
+// APIKeyFromContext returns API key which has been validated by APIKey middleware
+func APIKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(apiKeyKey{}).(string)
+	return key, ok
+}
+
+// APIKey is middleware function to validate X-Api-Key header with KeyValidator.
+// If validation succeeded, associated metadata is appended to outgoing gRPC context.
+// Errors of the validator other than ErrInvalidAPIKey are logged and not exposed to the client.
+func APIKey(validator KeyValidator) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		key := r.Header.Get(APIKeyHeader)
+		if key == "" {
+			return ctx, NewMiddlewareError("UNAUTHENTICATED", "api key is required")
+		}
+		md, err := validator.Validate(ctx, key)
+		if errors.Is(err, ErrInvalidAPIKey) {
+			return ctx, NewMiddlewareError("UNAUTHENTICATED", ErrInvalidAPIKey.Error())
+		} else if err != nil {
+			serveMux.logger().Printf("failed to validate api key: %s", err)
+			return ctx, NewMiddlewareError("UNAUTHENTICATED", "failed to validate api key")
+		}
+		ctx = context.WithValue(ctx, apiKeyKey{}, key)
+		if md.Len() == 0 {
+			return ctx, nil
+		}
+		if outgoing, ok := metadata.FromOutgoingContext(ctx); ok {
+			md = metadata.Join(outgoing, md)
+		}
+		return metadata.NewOutgoingContext(ctx, md), nil
+	}
+}
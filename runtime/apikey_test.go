@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"log"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAPIKeyMiddleware(t *testing.T) {
+	validator := StaticKeyValidator{
+		"valid-key": metadata.Pairs("x-client-id", "client-a"),
+		"plain-key": nil,
+	}
+	m := APIKey(validator)
+
+	t.Run("Reject request without key", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		_, err := m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+		if assert.Error(t, err) {
+			me, ok := err.(*MiddlewareError)
+			assert.True(t, ok)
+			assert.Equal(t, "UNAUTHENTICATED", me.Code)
+		}
+	})
+
+	t.Run("Reject request with unknown key", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r.Header.Set(APIKeyHeader, "unknown")
+		_, err := m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+		assert.Error(t, err)
+	})
+
+	t.Run("Hide errors of validator", func(t *testing.T) {
+		m := APIKey(KeyValidatorFunc(func(ctx context.Context, key string) (metadata.MD, error) {
+			return nil, errors.New("dial tcp 10.0.0.1:443: connection refused")
+		}))
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r.Header.Set(APIKeyHeader, "valid-key")
+		mux := NewServeMux()
+		mux.Logger = log.New(ioutil.Discard, "", 0)
+		_, err := m(r.Context(), mux, httptest.NewRecorder(), r)
+		if assert.Error(t, err) {
+			assert.NotContains(t, err.Error(), "10.0.0.1")
+		}
+	})
+
+	t.Run("Annotate metadata for valid key", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r.Header.Set(APIKeyHeader, "valid-key")
+		ctx, err := m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+		assert.NoError(t, err)
+		key, ok := APIKeyFromContext(ctx)
+		assert.True(t, ok)
+		assert.Equal(t, "valid-key", key)
+		md, ok := metadata.FromOutgoingContext(ctx)
+		if assert.True(t, ok) {
+			assert.Equal(t, []string{"client-a"}, md.Get("x-client-id"))
+		}
+	})
+
+	t.Run("Pass valid key without metadata", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+		r.Header.Set(APIKeyHeader, "plain-key")
+		ctx, err := m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+		assert.NoError(t, err)
+		_, ok := metadata.FromOutgoingContext(ctx)
+		assert.False(t, ok)
+	})
+}
+
+func TestRemoteKeyValidator(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get(APIKeyHeader) != "remote-key" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Grpc-Metadata-Tenant", "tenant-a")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	v := &RemoteKeyValidator{Endpoint: srv.URL}
+	md, err := v.Validate(context.Background(), "remote-key")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"tenant-a"}, md.Get("tenant"))
+	// Permanent headers of the response are not forwarded
+	assert.Len(t, md, 1)
+
+	_, err = v.Validate(context.Background(), "other-key")
+	assert.Equal(t, ErrInvalidAPIKey, err)
+}
@@ -0,0 +1,87 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/grpc/metadata"
+)
+
+// APMSpan is span of APMTracer
+type APMSpan interface {
+	SetTag(key string, value interface{})
+	// Finish ends the span, err is nil if succeeded
+	Finish(err error)
+}
+
+// APMTracer is small interface to integrate APM vendor tracers without adding their dependencies to the gateway,
+// see contrib/datadog package for Datadog APM.
+type APMTracer interface {
+	// StartRequestSpan starts span of the HTTP request which continues the trace of incoming request headers
+	StartRequestSpan(ctx context.Context, r *http.Request) (context.Context, APMSpan)
+	// StartResolverSpan starts child span of the field resolver which calls gRPC,
+	// and injects the trace context to outgoing gRPC metadata md
+	StartResolverSpan(ctx context.Context, name string, md metadata.MD) (context.Context, APMSpan)
+}
+
+// Span tags which are set by the gateway
+const (
+	APMTagOperationName = "graphql.operation.name"
+	APMTagFieldPath     = "graphql.field.path"
+	APMTagUpstream      = "grpc.upstream"
+)
+
+type apmTracerKey struct{}
+
+// WithAPMTracer is middleware function to trace requests and resolvers which call gRPC with APM tracer.
+// Spans are tagged with operation label (see OperationLabelFromContext), and resolver spans are also tagged with field path and upstream target.
+func WithAPMTracer(tracer APMTracer) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		ctx, span := tracer.StartRequestSpan(ctx, r)
+		onFinish(ctx, func(result *graphql.Result) {
+			if label := OperationLabelFromContext(ctx); label != "" {
+				span.SetTag(APMTagOperationName, label)
+			}
+			var err error
+			if len(result.Errors) > 0 {
+				err = result.Errors[0]
+			}
+			span.Finish(err)
+		})
+		return context.WithValue(ctx, apmTracerKey{}, tracer), nil
+	}
+}
+
+// startAPMResolverSpan starts APM span for the field resolver and returns context which should be passed to the resolver,
+// and function to finish the span. If APM tracer is not configured, returns the context as it is.
+func startAPMResolverSpan(ctx context.Context, info graphql.ResolveInfo) (context.Context, func(error)) {
+	tracer, ok := ctx.Value(apmTracerKey{}).(APMTracer)
+	if !ok {
+		return ctx, func(error) {}
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	ctx, span := tracer.StartResolverSpan(ctx, info.ParentType.Name()+"."+info.FieldName, md)
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	path := info.Path.AsArray()
+	fields := make([]string, len(path))
+	for i, p := range path {
+		fields[i] = fmt.Sprint(p)
+	}
+	span.SetTag(APMTagFieldPath, strings.Join(fields, "."))
+	if label := OperationLabelFromContext(ctx); label != "" {
+		span.SetTag(APMTagOperationName, label)
+	}
+	if len(fields) > 0 {
+		if target, ok := upstreamFromContext(ctx, fields[0]); ok {
+			span.SetTag(APMTagUpstream, target)
+		}
+	}
+	return ctx, span.Finish
+}
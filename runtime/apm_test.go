@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+type testAPMSpan struct {
+	name     string
+	tags     map[string]interface{}
+	finished bool
+	err      error
+}
+
+func (s *testAPMSpan) SetTag(key string, value interface{}) { s.tags[key] = value }
+func (s *testAPMSpan) Finish(err error)                     { s.finished, s.err = true, err }
+
+// testAPMTracer records started spans
+type testAPMTracer struct {
+	mu    sync.Mutex
+	spans []*testAPMSpan
+}
+
+func (tr *testAPMTracer) start(name string) *testAPMSpan {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	span := &testAPMSpan{name: name, tags: map[string]interface{}{}}
+	tr.spans = append(tr.spans, span)
+	return span
+}
+
+func (tr *testAPMTracer) StartRequestSpan(ctx context.Context, r *http.Request) (context.Context, APMSpan) {
+	return ctx, tr.start("request " + r.Header.Get("X-Trace-Id"))
+}
+
+func (tr *testAPMTracer) StartResolverSpan(ctx context.Context, name string, md metadata.MD) (context.Context, APMSpan) {
+	md.Set("x-trace-id", "resolver")
+	return ctx, tr.start("resolve " + name)
+}
+
+func TestWithAPMTracer(t *testing.T) {
+	var traceID []string
+	h := newTestHandler()
+	h.queries["traced"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			md, _ := metadata.FromOutgoingContext(p.Context)
+			traceID = md.Get("x-trace-id")
+			return "ok", nil
+		},
+	}
+	tracer := &testAPMTracer{}
+	mux := NewServeMux(WithAPMTracer(tracer))
+	assert.NoError(t, mux.AddHandler(h))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"query Traced { traced }","operationName":"Traced"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-Trace-Id", "incoming")
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	if !assert.Len(t, tracer.spans, 2) {
+		t.FailNow()
+	}
+	request, resolver := tracer.spans[0], tracer.spans[1]
+	assert.Equal(t, "request incoming", request.name)
+	assert.Equal(t, "Traced", request.tags[APMTagOperationName])
+	assert.True(t, request.finished)
+	assert.NoError(t, request.err)
+
+	assert.Equal(t, "resolve Query.traced", resolver.name)
+	assert.Equal(t, map[string]interface{}{
+		APMTagOperationName: "Traced",
+		APMTagFieldPath:     "traced",
+	}, resolver.tags)
+	assert.True(t, resolver.finished)
+	assert.Equal(t, []string{"resolver"}, traceID)
+}
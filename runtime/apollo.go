@@ -0,0 +1,327 @@
+package runtime
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"io/ioutil"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// DefaultApolloReportEndpoint is Apollo Studio usage reporting ingress endpoint
+	DefaultApolloReportEndpoint = "https://usage-reporting.api.apollographql.com/api/ingress/traces"
+
+	apolloAgentVersion = "grpc-graphql-gateway"
+
+	// Apollo duration histogram has 384 buckets which grow by 10% starting from 1 microsecond
+	apolloLatencyBuckets = 384
+)
+
+// ApolloReporterConfig is configuration for ApolloReporter
+type ApolloReporterConfig struct {
+	// Apollo Studio API key, required
+	APIKey string
+	// Graph reference like "my-graph@current", required
+	GraphRef string
+	// Version of this gateway service which is shown in Apollo Studio
+	ServiceVersion string
+	// Report endpoint, default is DefaultApolloReportEndpoint
+	Endpoint string
+	// Interval to send batched reports, default is 20 seconds
+	FlushInterval time.Duration
+	// HTTP client to send reports, default is http.DefaultClient
+	Client *http.Client
+}
+
+// apolloStats is aggregated operation stats per client
+type apolloStats struct {
+	latencies []int64
+	requests  uint64
+	errors    uint64
+}
+
+type apolloClient struct {
+	name    string
+	version string
+}
+
+// ApolloReporter batches operation stats and sends them to Apollo Studio in usage reporting protobuf format
+type ApolloReporter struct {
+	config ApolloReporterConfig
+
+	mu    sync.Mutex
+	stats map[string]map[apolloClient]*apolloStats
+	count uint64
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewApolloReporter creates ApolloReporter pointer and starts to send reports periodically.
+// Call Close on shutdown in order to send remaining stats.
+func NewApolloReporter(config ApolloReporterConfig) *ApolloReporter {
+	if config.Endpoint == "" {
+		config.Endpoint = DefaultApolloReportEndpoint
+	}
+	if config.FlushInterval == 0 {
+		config.FlushInterval = 20 * time.Second
+	}
+	if config.Client == nil {
+		config.Client = http.DefaultClient
+	}
+
+	r := &ApolloReporter{
+		config: config,
+		stats:  make(map[string]map[apolloClient]*apolloStats),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.run()
+	return r
+}
+
+func (r *ApolloReporter) run() {
+	defer close(r.done)
+	ticker := time.NewTicker(r.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := r.Flush(context.Background()); err != nil {
+				grpclog.Errorf("Failed to send Apollo usage report: %s", err)
+			}
+		case <-r.stop:
+			return
+		}
+	}
+}
+
+// Close stops periodic reporting and sends remaining stats
+func (r *ApolloReporter) Close() error {
+	close(r.stop)
+	<-r.done
+	return r.Flush(context.Background())
+}
+
+// WithApolloReporter is middleware function to record operation stats to the reporter
+func WithApolloReporter(reporter *ApolloReporter) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		start := time.Now()
+		client := apolloClient{
+			name:    r.Header.Get("Apollographql-Client-Name"),
+			version: r.Header.Get("Apollographql-Client-Version"),
+		}
+		onFinish(ctx, func(result *graphql.Result) {
+			// Request which could not be parsed doesn't have any operation to report
+			req := requestFromContext(ctx)
+			if req == nil {
+				return
+			}
+			reporter.record(apolloSignature(req), client, time.Since(start), len(result.Errors) > 0)
+		})
+		return ctx, nil
+	}
+}
+
+func (r *ApolloReporter) record(signature string, client apolloClient, d time.Duration, hasErrors bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	clients, ok := r.stats[signature]
+	if !ok {
+		clients = make(map[apolloClient]*apolloStats)
+		r.stats[signature] = clients
+	}
+	s, ok := clients[client]
+	if !ok {
+		s = &apolloStats{
+			latencies: make([]int64, apolloLatencyBuckets),
+		}
+		clients[client] = s
+	}
+	s.latencies[apolloLatencyBucket(d)]++
+	s.requests++
+	if hasErrors {
+		s.errors++
+	}
+	r.count++
+}
+
+// Flush sends batched stats to Apollo Studio immediately
+func (r *ApolloReporter) Flush(ctx context.Context) error {
+	r.mu.Lock()
+	stats, count := r.stats, r.count
+	r.stats = make(map[string]map[apolloClient]*apolloStats)
+	r.count = 0
+	r.mu.Unlock()
+
+	if count == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(r.encodeReport(stats, count, time.Now())); err != nil {
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, r.config.Endpoint, &buf)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/protobuf")
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Set("X-Api-Key", r.config.APIKey)
+	req.Header.Set("User-Agent", apolloAgentVersion)
+
+	resp, err := r.config.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := ioutil.ReadAll(resp.Body) // nolint: errcheck
+		return fmt.Errorf("Apollo usage reporting responds status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
+
+// encodeReport encodes stats as Report message of Apollo usage reporting protocol.
+// We encode only stats related fields by hand instead of depending on generated code of reports.proto.
+func (r *ApolloReporter) encodeReport(stats map[string]map[apolloClient]*apolloStats, count uint64, now time.Time) []byte {
+	hostname, _ := os.Hostname() // nolint: errcheck
+
+	// ReportHeader
+	var header []byte
+	header = appendString(header, 5, hostname)
+	header = appendString(header, 6, apolloAgentVersion)
+	header = appendString(header, 7, r.config.ServiceVersion)
+	header = appendString(header, 12, r.config.GraphRef)
+
+	var report []byte
+	report = appendMessage(report, 1, header)
+
+	// google.protobuf.Timestamp end_time
+	var endTime []byte
+	endTime = protowire.AppendTag(endTime, 1, protowire.VarintType)
+	endTime = protowire.AppendVarint(endTime, uint64(now.Unix()))
+	endTime = protowire.AppendTag(endTime, 2, protowire.VarintType)
+	endTime = protowire.AppendVarint(endTime, uint64(now.Nanosecond()))
+	report = appendMessage(report, 2, endTime)
+
+	// map<string, TracesAndStats> traces_per_query
+	for signature, clients := range stats {
+		var tracesAndStats []byte
+		for client, s := range clients {
+			// StatsContext
+			var statsContext []byte
+			statsContext = appendString(statsContext, 2, client.name)
+			statsContext = appendString(statsContext, 3, client.version)
+
+			// QueryLatencyStats
+			var latency []byte
+			latency = protowire.AppendTag(latency, 2, protowire.VarintType)
+			latency = protowire.AppendVarint(latency, s.requests)
+			if s.errors > 0 {
+				latency = protowire.AppendTag(latency, 8, protowire.VarintType)
+				latency = protowire.AppendVarint(latency, s.errors)
+			}
+			var counts []byte
+			for _, c := range compressLatencies(s.latencies) {
+				counts = protowire.AppendVarint(counts, protowire.EncodeZigZag(c))
+			}
+			latency = protowire.AppendTag(latency, 13, protowire.BytesType)
+			latency = protowire.AppendBytes(latency, counts)
+
+			// ContextualizedStats
+			var contextualized []byte
+			contextualized = appendMessage(contextualized, 1, statsContext)
+			contextualized = appendMessage(contextualized, 2, latency)
+			tracesAndStats = appendMessage(tracesAndStats, 2, contextualized)
+		}
+
+		var entry []byte
+		entry = appendString(entry, 1, signature)
+		entry = appendMessage(entry, 2, tracesAndStats)
+		report = appendMessage(report, 5, entry)
+	}
+
+	report = protowire.AppendTag(report, 6, protowire.VarintType)
+	report = protowire.AppendVarint(report, count)
+	return report
+}
+
+func appendString(b []byte, num protowire.Number, v string) []byte {
+	if v == "" {
+		return b
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendString(b, v)
+}
+
+func appendMessage(b []byte, num protowire.Number, v []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, v)
+}
+
+// apolloLatencyBucket returns bucket index of Apollo duration histogram
+func apolloLatencyBucket(d time.Duration) int {
+	bucket := math.Ceil(math.Log(float64(d.Nanoseconds())/1000) / math.Log(1.1))
+	switch {
+	case math.IsNaN(bucket) || bucket <= 0:
+		return 0
+	case bucket >= apolloLatencyBuckets:
+		return apolloLatencyBuckets - 1
+	default:
+		return int(bucket)
+	}
+}
+
+// compressLatencies encodes histogram counts as Apollo expects,
+// consecutive zero buckets are replaced by negative number of them and trailing zeros are dropped.
+func compressLatencies(counts []int64) []int64 {
+	out := make([]int64, 0)
+	zeros := int64(0)
+	for _, c := range counts {
+		if c == 0 {
+			zeros++
+			continue
+		}
+		switch zeros {
+		case 0:
+		case 1:
+			out = append(out, 0)
+		default:
+			out = append(out, -zeros)
+		}
+		zeros = 0
+		out = append(out, c)
+	}
+	return out
+}
+
+// apolloSignature returns stats report key of the operation, which consists of operation name and whitespace normalized query
+func apolloSignature(req *GraphqlRequest) string {
+	name := req.OperationName
+	if name == "" {
+		name = "-"
+	}
+	return "# " + name + "\n" + strings.Join(strings.Fields(req.Query), " ")
+}
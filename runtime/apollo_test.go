@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"compress/gzip"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApolloLatencyBucket(t *testing.T) {
+	assert.Equal(t, 0, apolloLatencyBucket(0))
+	assert.Equal(t, 0, apolloLatencyBucket(time.Microsecond))
+	assert.Equal(t, 1, apolloLatencyBucket(1100*time.Nanosecond))
+	assert.Equal(t, 231, apolloLatencyBucket(time.Hour))
+}
+
+func TestCompressLatencies(t *testing.T) {
+	assert.Equal(t, []int64{1, 0, 2, -3, 4}, compressLatencies([]int64{1, 0, 2, 0, 0, 0, 4, 0, 0}))
+	assert.Equal(t, []int64{}, compressLatencies([]int64{0, 0}))
+}
+
+func TestApolloReporter(t *testing.T) {
+	var (
+		apiKey string
+		body   []byte
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		apiKey = r.Header.Get("X-Api-Key")
+		gz, err := gzip.NewReader(r.Body)
+		if assert.NoError(t, err) {
+			body, _ = ioutil.ReadAll(gz)
+		}
+	}))
+	defer server.Close()
+
+	reporter := NewApolloReporter(ApolloReporterConfig{
+		APIKey:        "service:test",
+		GraphRef:      "test@current",
+		Endpoint:      server.URL,
+		FlushInterval: time.Hour,
+	})
+	mux := NewServeMux(WithApolloReporter(reporter))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"query Hello {\n  hello\n}","operationName":"Hello"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Apollographql-Client-Name", "web")
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	assert.NoError(t, reporter.Flush(context.Background()))
+	assert.Equal(t, "service:test", apiKey)
+	assert.Contains(t, string(body), "test@current")
+	assert.Contains(t, string(body), "# Hello\nquery Hello { hello }")
+	assert.Contains(t, string(body), "web")
+
+	// Nothing is sent when there are no stats
+	body = nil
+	assert.NoError(t, reporter.Close())
+	assert.Nil(t, body)
+}
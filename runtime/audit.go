@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"context"
+	"time"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// AuditEntry is a record of executed mutation
+type AuditEntry struct {
+	// Caller identity which is extracted by AuditConfig.Identity
+	Identity string
+	// GraphQL operation name, or the document hash for anonymous operation, see OperationLabelFromContext
+	OperationName string
+	// Mutation field name
+	Field string
+	// Mutation arguments which sensitive values are masked
+	Args map[string]interface{}
+	// Error message if the mutation failed, empty on success
+	Error     string
+	StartTime time.Time
+	Duration  time.Duration
+}
+
+// Success reports whether the mutation succeeded
+func (e AuditEntry) Success() bool {
+	return e.Error == ""
+}
+
+// AuditHook is called for every executed mutation.
+// Hook is called synchronously in the resolver, so send entries to the storage asynchronously if it's slow.
+type AuditHook func(ctx context.Context, entry AuditEntry)
+
+// AuditConfig is configuration for WithAuditHook middleware
+type AuditConfig struct {
+	// Hook receives audit entries, required
+	Hook AuditHook
+	// Identity extracts caller identity from the request, default is remote IP address
+	Identity func(ctx context.Context, r *http.Request) string
+}
+
+type auditConfig struct {
+	hook     AuditHook
+	identity string
+}
+
+type auditKey struct{}
+
+// WithAuditHook is middleware function to fire audit hook for every executed mutation
+func WithAuditHook(config AuditConfig) MiddlewareFunc {
+	if config.Identity == nil {
+		config.Identity = defaultAuditIdentity
+	}
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, auditKey{}, auditConfig{
+			hook:     config.Hook,
+			identity: config.Identity(ctx, r),
+		}), nil
+	}
+}
+
+func defaultAuditIdentity(ctx context.Context, r *http.Request) string {
+	return RateLimitByIP(r)
+}
+
+// auditMutation fires audit hook if the field is a mutation, and returns function to complete it with resolver error
+func auditMutation(p graphql.ResolveParams) func(error) {
+	config, ok := p.Context.Value(auditKey{}).(auditConfig)
+	if !ok || !isMutationType(p.Info.ParentType) || isNamespaceMutationField(p.Info.ReturnType) {
+		return func(error) {}
+	}
+	entry := AuditEntry{
+		Identity:      config.identity,
+		OperationName: OperationLabelFromContext(p.Context),
+		Field:         p.Info.FieldName,
+		Args:          redactValue(p.Args, redactPatterns(p.Context)).(map[string]interface{}),
+		StartTime:     time.Now(),
+	}
+	return func(err error) {
+		entry.Duration = time.Since(entry.StartTime)
+		if err != nil {
+			entry.Error = err.Error()
+		}
+		config.hook(p.Context, entry)
+	}
+}
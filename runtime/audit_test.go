@@ -0,0 +1,61 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"net/http"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAuditHook(t *testing.T) {
+	var entries []AuditEntry
+	mux := NewServeMux(WithAuditHook(AuditConfig{
+		Hook: func(ctx context.Context, entry AuditEntry) {
+			entries = append(entries, entry)
+		},
+	}))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	// Queries are not audited
+	serveTestQuery(t, mux, `{ hello }`)
+	assert.Len(t, entries, 0)
+
+	serveTestQuery(t, mux, `mutation Login { login(password: "p@ss") }`)
+	if assert.Len(t, entries, 1) {
+		e := entries[0]
+		assert.Equal(t, "192.0.2.1", e.Identity)
+		assert.Equal(t, "Login", e.OperationName)
+		assert.Equal(t, "login", e.Field)
+		assert.Equal(t, map[string]interface{}{"password": RedactedValue}, e.Args)
+		assert.True(t, e.Success())
+	}
+}
+
+func TestWithAuditHookRecordsDeniedMutation(t *testing.T) {
+	var entries []AuditEntry
+	mux := NewServeMux(
+		WithAuditHook(AuditConfig{
+			Hook: func(ctx context.Context, entry AuditEntry) {
+				entries = append(entries, entry)
+			},
+			Identity: func(ctx context.Context, r *http.Request) string {
+				return "user-1"
+			},
+		}),
+		WithFieldAuthorizer(func(ctx context.Context, p FieldAuthorizeParams) error {
+			return errors.New("denied")
+		}),
+	)
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	serveTestQuery(t, mux, `mutation { login(password: "p@ss") }`)
+	if assert.Len(t, entries, 1) {
+		assert.Equal(t, "user-1", entries[0].Identity)
+		assert.Equal(t, "anonymous:"+documentHash(`mutation { login(password: "p@ss") }`), entries[0].OperationName)
+		assert.False(t, entries[0].Success())
+		assert.Equal(t, "denied", entries[0].Error)
+	}
+}
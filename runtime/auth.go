@@ -0,0 +1,107 @@
+package runtime
+
+import (
+	"context"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// AuthConfig is configuration for WithAuthentication middleware
+type AuthConfig struct {
+	// Middlewares which authenticate the request like APIKey, required
+	Middlewares []MiddlewareFunc
+	// Root fields which are served without credentials like "health".
+	// The operation is exempted only if all of its root fields are exempted.
+	ExemptFields []string
+	// Operation names which are served without credentials like "HealthCheck".
+	// Operation names are chosen by clients, so they are exempted only if the document is registered
+	// in the store of WithPersistedOperations middleware.
+	ExemptOperations []string
+	// ExemptIntrospection reports whether the request can introspect the schema without credentials,
+	// e.g. IntrospectionFromNetworks for internal network. Operations which select other fields are not exempted.
+	ExemptIntrospection IntrospectionPredicate
+}
+
+type authKey struct{}
+
+type authentication struct {
+	config AuthConfig
+	w      http.ResponseWriter
+	r      *http.Request
+}
+
+// WithAuthentication is middleware function to authenticate requests by the middlewares unless the operation is exempted,
+// so that health checks and internal introspection are served without credentials while everything else requires them.
+// The middlewares run after the document is parsed and before it's validated,
+// so that validation errors don't leak the schema to unauthenticated clients.
+// Note that the middlewares run after all other middlewares, so their predicates can't refer identity like APIKeyFromContext.
+func WithAuthentication(config AuthConfig) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, authKey{}, &authentication{
+			config: config,
+			w:      w,
+			r:      r,
+		}), nil
+	}
+}
+
+// authenticate runs authentication middlewares of WithAuthentication unless the operation is exempted
+func authenticate(ctx context.Context, serveMux *ServeMux, doc *ast.Document, req *GraphqlRequest) (context.Context, []GraphqlError) {
+	a, ok := ctx.Value(authKey{}).(*authentication)
+	if !ok {
+		return ctx, nil
+	}
+	exempted, merr := a.isExempted(ctx, serveMux, doc, req)
+	if merr != nil {
+		return ctx, []GraphqlError{middlewareError(merr)}
+	}
+	if exempted {
+		return ctx, nil
+	}
+	for _, m := range a.config.Middlewares {
+		next, err := m(ctx, serveMux, a.w, a.r)
+		if err != nil {
+			return ctx, []GraphqlError{middlewareError(err)}
+		}
+		ctx = next
+	}
+	return ctx, nil
+}
+
+// isExempted reports whether the operation to be executed is served without credentials
+func (a *authentication) isExempted(ctx context.Context, serveMux *ServeMux, doc *ast.Document, req *GraphqlRequest) (bool, *MiddlewareError) {
+	op := selectOperation(doc, req.OperationName)
+	if op == nil {
+		return false, nil
+	}
+	if op.Name != nil && containsString(a.config.ExemptOperations, op.Name.Value) {
+		persisted, merr := serveMux.isPersistedDocument(ctx, req)
+		if merr != nil || persisted {
+			return persisted, merr
+		}
+	}
+
+	fields := selectionFields(op.SelectionSet, collectFragments(doc), map[string]struct{}{})
+	if len(fields) == 0 {
+		return false, nil
+	}
+	exempted, introspection := true, true
+	for _, f := range fields {
+		switch f.Name.Value {
+		case "__typename":
+		case "__schema", "__type":
+			exempted = false
+		default:
+			introspection = false
+			if !containsString(a.config.ExemptFields, f.Name.Value) {
+				exempted = false
+			}
+		}
+	}
+	if exempted {
+		return true, nil
+	}
+	return introspection && a.config.ExemptIntrospection != nil && a.config.ExemptIntrospection(ctx, a.r), nil
+}
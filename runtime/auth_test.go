@@ -0,0 +1,73 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithAuthentication(t *testing.T) {
+	const health = `query HealthCheck { hello }`
+	internal, err := IntrospectionFromNetworks("192.0.2.0/24")
+	assert.NoError(t, err)
+
+	var authenticated int
+	h := newTestHandler()
+	h.queries["health"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return "ok", nil
+		},
+	}
+	mux := NewServeMux(
+		WithPersistedOperations(PersistedOperationsConfig{
+			Store: NewPersistedOperations(health),
+		}),
+		WithAuthentication(AuthConfig{
+			Middlewares: []MiddlewareFunc{
+				func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+					authenticated++
+					return ctx, NewMiddlewareError("UNAUTHENTICATED", "credentials are required")
+				},
+			},
+			ExemptFields:        []string{"health"},
+			ExemptOperations:    []string{"HealthCheck"},
+			ExemptIntrospection: internal,
+		}),
+	)
+	assert.NoError(t, mux.AddHandler(h))
+
+	for _, query := range []string{
+		`{ health }`,
+		`{ health __typename }`,
+		`{ ...Health } fragment Health on Query { health }`,
+		health,
+		`{"id":"` + persistedOperationHash(health) + `"}`,
+		`{ __schema { queryType { name } } }`,
+	} {
+		result := serveTestQuery(t, mux, query)
+		assert.Len(t, result.Errors, 0, query)
+	}
+	assert.Equal(t, 0, authenticated)
+
+	for _, query := range []string{
+		`{ hello }`,
+		`{ health hello }`,
+		// Operation name of free-form query is not trusted
+		`query HealthCheck { user(id: "1") { id } }`,
+		`{ __schema { queryType { name } } hello }`,
+		// Validation errors are not exposed
+		`{ unknown }`,
+	} {
+		result := serveTestQuery(t, mux, query)
+		assert.Nil(t, result.Data)
+		if assert.Len(t, result.Errors, 1, query) {
+			assert.Equal(t, "UNAUTHENTICATED", result.Errors[0].Extensions["code"])
+		}
+	}
+	assert.Equal(t, 5, authenticated)
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"context"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// FieldAuthorizeParams describes the field which is going to be resolved
+type FieldAuthorizeParams struct {
+	// Object type name which field belongs to
+	TypeName string
+	// Field name
+	FieldName string
+	// Resolved field arguments
+	Args map[string]interface{}
+}
+
+// FieldAuthorizer is called for each resolving field.
+// Caller identity could be retrieved from context, e.g. APIKeyFromContext.
+// If returned error is not nil, the field access is denied and the field is resolved as null with PERMISSION_DENIED error.
+type FieldAuthorizer func(ctx context.Context, p FieldAuthorizeParams) error
+
+type fieldAuthorizerKey struct{}
+
+// WithFieldAuthorizer is middleware function to enable field level authorization
+func WithFieldAuthorizer(authorizer FieldAuthorizer) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, fieldAuthorizerKey{}, authorizer), nil
+	}
+}
+
+func authorizeField(p graphql.ResolveParams) error {
+	authorizer, ok := p.Context.Value(fieldAuthorizerKey{}).(FieldAuthorizer)
+	if !ok {
+		return nil
+	}
+	err := authorizer(p.Context, FieldAuthorizeParams{
+		TypeName:  p.Info.ParentType.Name(),
+		FieldName: p.Info.FieldName,
+		Args:      p.Args,
+	})
+	if err != nil {
+		return NewFieldError("PERMISSION_DENIED", err.Error())
+	}
+	return nil
+}
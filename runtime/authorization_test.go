@@ -0,0 +1,41 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldAuthorizer(t *testing.T) {
+	var called []string
+	mux := NewServeMux(WithFieldAuthorizer(func(ctx context.Context, p FieldAuthorizeParams) error {
+		called = append(called, p.TypeName+"."+p.FieldName)
+		if p.TypeName == "Test_Type_User" && p.FieldName == "email" {
+			return errors.New("email is not allowed")
+		}
+		return nil
+	}))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ user(id: "1") { id email } }`)
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{
+			"id":    "1",
+			"email": nil,
+		},
+	}, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		e := result.Errors[0]
+		assert.Equal(t, "email is not allowed", e.Message)
+		assert.Equal(t, "PERMISSION_DENIED", e.Extensions["code"])
+		assert.Equal(t, []interface{}{"user", "email"}, e.Path)
+	}
+	assert.ElementsMatch(t, []string{"Query.user", "Test_Type_User.id", "Test_Type_User.email"}, called)
+
+	// Nested types are instrumented only once even if multiple requests are served
+	called = nil
+	serveTestQuery(t, mux, `{ user(id: "1") { id } }`)
+	assert.Equal(t, []string{"Query.user", "Test_Type_User.id"}, called)
+}
@@ -0,0 +1,127 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+
+	"net/http"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// B3 header names, see https://github.com/openzipkin/b3-propagation
+const (
+	b3TraceIDHeader = "x-b3-traceid"
+	b3SpanIDHeader  = "x-b3-spanid"
+	b3SampledHeader = "x-b3-sampled"
+	b3FlagsHeader   = "x-b3-flags"
+	b3SingleHeader  = "b3"
+)
+
+// B3Propagator is propagation.TextMapPropagator of Zipkin B3 headers.
+// Extract accepts both single b3 header and multiple X-B3-* headers, and Inject writes multiple headers
+// unless SingleHeader is true.
+type B3Propagator struct {
+	SingleHeader bool
+}
+
+var _ propagation.TextMapPropagator = B3Propagator{}
+
+// Inject implements propagation.TextMapPropagator
+func (b B3Propagator) Inject(ctx context.Context, carrier propagation.TextMapCarrier) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return
+	}
+	sampled := "0"
+	if sc.IsSampled() {
+		sampled = "1"
+	}
+	if b.SingleHeader {
+		carrier.Set(b3SingleHeader, sc.TraceID().String()+"-"+sc.SpanID().String()+"-"+sampled)
+		return
+	}
+	carrier.Set(b3TraceIDHeader, sc.TraceID().String())
+	carrier.Set(b3SpanIDHeader, sc.SpanID().String())
+	carrier.Set(b3SampledHeader, sampled)
+}
+
+// Extract implements propagation.TextMapPropagator
+func (b B3Propagator) Extract(ctx context.Context, carrier propagation.TextMapCarrier) context.Context {
+	var traceID, spanID, sampled string
+	if single := carrier.Get(b3SingleHeader); single != "" {
+		// {TraceId}-{SpanId}-{SamplingState}-{ParentSpanId}, where the last two are optional
+		parts := strings.Split(single, "-")
+		if len(parts) < 2 {
+			return ctx
+		}
+		traceID, spanID = parts[0], parts[1]
+		if len(parts) > 2 {
+			sampled = parts[2]
+		}
+	} else {
+		traceID, spanID, sampled = carrier.Get(b3TraceIDHeader), carrier.Get(b3SpanIDHeader), carrier.Get(b3SampledHeader)
+		if carrier.Get(b3FlagsHeader) == "1" {
+			sampled = "d"
+		}
+	}
+
+	// 64 bit trace ID is left-padded to 128 bit
+	if len(traceID) == 16 {
+		traceID = strings.Repeat("0", 16) + traceID
+	}
+	tid, err := trace.TraceIDFromHex(traceID)
+	if err != nil {
+		return ctx
+	}
+	sid, err := trace.SpanIDFromHex(spanID)
+	if err != nil {
+		return ctx
+	}
+	var flags trace.TraceFlags
+	switch sampled {
+	case "1", "d", "true":
+		flags = trace.FlagsSampled
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: flags,
+		Remote:     true,
+	}))
+}
+
+// Fields implements propagation.TextMapPropagator
+func (b B3Propagator) Fields() []string {
+	return []string{b3TraceIDHeader, b3SpanIDHeader, b3SampledHeader, b3FlagsHeader, b3SingleHeader}
+}
+
+type tracePropagatorKey struct{}
+
+// WithTracePropagator is middleware function to change trace context propagation format, default is W3C tracecontext.
+// The propagator extracts trace context of incoming request and injects it to gRPC metadata,
+// so that trace headers are forwarded even if tracer provider is not configured.
+// Use this before WithTracerProvider in order to continue and propagate spans in the format, e.g.
+//
+//	// B3 alongside W3C tracecontext
+//	WithTracePropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, B3Propagator{}))
+//	// B3 instead of W3C tracecontext
+//	WithTracePropagator(B3Propagator{})
+func WithTracePropagator(p propagation.TextMapPropagator) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		ctx = p.Extract(ctx, propagation.HeaderCarrier(r.Header))
+		md := metadata.MD{}
+		p.Inject(ctx, metadataCarrier(md))
+		return appendOutgoingMetadata(context.WithValue(ctx, tracePropagatorKey{}, p), md), nil
+	}
+}
+
+// tracePropagatorFromContext returns propagator of WithTracePropagator middleware, or W3C tracecontext
+func tracePropagatorFromContext(ctx context.Context) propagation.TextMapPropagator {
+	if p, ok := ctx.Value(tracePropagatorKey{}).(propagation.TextMapPropagator); ok {
+		return p
+	}
+	return propagation.TraceContext{}
+}
@@ -0,0 +1,132 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestB3PropagatorExtract(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  http.Header
+		traceID string
+		spanID  string
+		sampled bool
+	}{
+		{
+			name:    "multiple headers",
+			header:  http.Header{"X-B3-Traceid": {"80f198ee56343ba864fe8b2a57d3eff7"}, "X-B3-Spanid": {"e457b5a2e4d86bd1"}, "X-B3-Sampled": {"1"}},
+			traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+			spanID:  "e457b5a2e4d86bd1",
+			sampled: true,
+		},
+		{
+			name:    "64 bit trace ID and debug flag",
+			header:  http.Header{"X-B3-Traceid": {"64fe8b2a57d3eff7"}, "X-B3-Spanid": {"e457b5a2e4d86bd1"}, "X-B3-Flags": {"1"}},
+			traceID: "000000000000000064fe8b2a57d3eff7",
+			spanID:  "e457b5a2e4d86bd1",
+			sampled: true,
+		},
+		{
+			name:    "single header",
+			header:  http.Header{"B3": {"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-0-05e3ac9a4f6e3b90"}},
+			traceID: "80f198ee56343ba864fe8b2a57d3eff7",
+			spanID:  "e457b5a2e4d86bd1",
+		},
+		{name: "sampling state only", header: http.Header{"B3": {"0"}}},
+		{name: "invalid span ID", header: http.Header{"X-B3-Traceid": {"80f198ee56343ba864fe8b2a57d3eff7"}, "X-B3-Spanid": {"invalid"}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sc := trace.SpanContextFromContext(B3Propagator{}.Extract(context.Background(), propagation.HeaderCarrier(tt.header)))
+			if tt.traceID == "" {
+				assert.False(t, sc.IsValid())
+				return
+			}
+			assert.Equal(t, tt.traceID, sc.TraceID().String())
+			assert.Equal(t, tt.spanID, sc.SpanID().String())
+			assert.Equal(t, tt.sampled, sc.IsSampled())
+			assert.True(t, sc.IsRemote())
+		})
+	}
+}
+
+func TestB3PropagatorInject(t *testing.T) {
+	tid, _ := trace.TraceIDFromHex("80f198ee56343ba864fe8b2a57d3eff7") // nolint: errcheck
+	sid, _ := trace.SpanIDFromHex("e457b5a2e4d86bd1")                  // nolint: errcheck
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    tid,
+		SpanID:     sid,
+		TraceFlags: trace.FlagsSampled,
+	}))
+
+	md := metadata.MD{}
+	B3Propagator{}.Inject(ctx, metadataCarrier(md))
+	assert.Equal(t, metadata.MD{
+		"x-b3-traceid": {"80f198ee56343ba864fe8b2a57d3eff7"},
+		"x-b3-spanid":  {"e457b5a2e4d86bd1"},
+		"x-b3-sampled": {"1"},
+	}, md)
+
+	md = metadata.MD{}
+	B3Propagator{SingleHeader: true}.Inject(ctx, metadataCarrier(md))
+	assert.Equal(t, metadata.MD{
+		"b3": {"80f198ee56343ba864fe8b2a57d3eff7-e457b5a2e4d86bd1-1"},
+	}, md)
+}
+
+func TestWithTracePropagator(t *testing.T) {
+	var md metadata.MD
+	h := newTestHandler()
+	h.queries["traced"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			md, _ = metadata.FromOutgoingContext(p.Context)
+			return "ok", nil
+		},
+	}
+	serve := func(mux *ServeMux) {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ traced }`))
+		r.Header.Set("X-B3-Traceid", "80f198ee56343ba864fe8b2a57d3eff7")
+		r.Header.Set("X-B3-Spanid", "e457b5a2e4d86bd1")
+		r.Header.Set("X-B3-Sampled", "1")
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	t.Run("forward without tracer provider", func(t *testing.T) {
+		mux := NewServeMux(WithTracePropagator(B3Propagator{}))
+		assert.NoError(t, mux.AddHandler(h))
+		serve(mux)
+		assert.Equal(t, []string{"80f198ee56343ba864fe8b2a57d3eff7"}, md.Get("x-b3-traceid"))
+		assert.Equal(t, []string{"e457b5a2e4d86bd1"}, md.Get("x-b3-spanid"))
+		assert.Len(t, md.Get("traceparent"), 0)
+	})
+
+	t.Run("translate to tracecontext with tracer provider", func(t *testing.T) {
+		tp := &testTracerProvider{}
+		mux := NewServeMux(
+			WithTracePropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, B3Propagator{})),
+			WithTracerProvider(tp),
+		)
+		assert.NoError(t, mux.AddHandler(h))
+		serve(mux)
+		if !assert.Len(t, tp.spans, 2) {
+			t.FailNow()
+		}
+		server, resolver := tp.spans[0], tp.spans[1]
+		assert.Equal(t, "80f198ee56343ba864fe8b2a57d3eff7", server.parent.TraceID().String())
+		assert.Equal(t, []string{resolver.sc.SpanID().String()}, md.Get("x-b3-spanid"))
+		assert.Equal(t, []string{"00-80f198ee56343ba864fe8b2a57d3eff7-" + resolver.sc.SpanID().String() + "-01"}, md.Get("traceparent"))
+	})
+}
@@ -0,0 +1,35 @@
+package runtime
+
+import (
+	"context"
+
+	"net/http"
+
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/propagation"
+	"google.golang.org/grpc/metadata"
+)
+
+// WithBaggage is middleware function to propagate W3C Baggage of incoming request to gRPC metadata,
+// so that contextual attributes like tenant or user tier survive the GraphQL hop.
+// Baggage is also attached to the context and available via baggage.FromContext of OpenTelemetry.
+// If keys are specified, only members of the keys are propagated, otherwise all members are propagated.
+// Invalid baggage header is ignored.
+func WithBaggage(keys ...string) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		ctx = propagation.Baggage{}.Extract(ctx, propagation.HeaderCarrier(r.Header))
+		if len(keys) > 0 {
+			b := baggage.FromContext(ctx)
+			for _, m := range b.Members() {
+				if !containsString(keys, m.Key()) {
+					b = b.DeleteMember(m.Key())
+				}
+			}
+			ctx = baggage.ContextWithBaggage(ctx, b)
+		}
+
+		md := metadata.MD{}
+		propagation.Baggage{}.Inject(ctx, metadataCarrier(md))
+		return appendOutgoingMetadata(ctx, md), nil
+	}
+}
@@ -0,0 +1,50 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/baggage"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestWithBaggage(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		header   string
+		expected []string
+	}{
+		{name: "all members", header: "tenant=acme,tier=gold;ttl=60", expected: []string{"tenant=acme,tier=gold;ttl=60"}},
+		{name: "filtered members", keys: []string{"tenant"}, header: "tenant=acme,user=secret", expected: []string{"tenant=acme"}},
+		{name: "no header"},
+		{name: "invalid header", header: "=invalid"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+			if tt.header != "" {
+				r.Header.Set("Baggage", tt.header)
+			}
+			ctx, err := WithBaggage(tt.keys...)(context.Background(), nil, nil, r)
+			assert.NoError(t, err)
+
+			md, _ := metadata.FromOutgoingContext(ctx)
+			if tt.expected == nil {
+				assert.Len(t, md.Get("baggage"), 0)
+				return
+			}
+			b, err := baggage.Parse(md.Get("baggage")[0])
+			assert.NoError(t, err)
+			expected, err := baggage.Parse(tt.expected[0])
+			assert.NoError(t, err)
+			assert.ElementsMatch(t, expected.Members(), b.Members())
+			assert.Equal(t, len(expected.Members()), baggage.FromContext(ctx).Len())
+		})
+	}
+}
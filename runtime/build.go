@@ -0,0 +1,163 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// ErrServeMuxBuilt is returned when handlers are registered after ServeMux is built
+var ErrServeMuxBuilt = errors.New("ServeMux is already built")
+
+// builtHandler serves requests with the configuration which is frozen by Build
+type builtHandler struct {
+	mux         *ServeMux
+	middlewares []MiddlewareFunc
+}
+
+func (h *builtHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.serve(w, r, h.middlewares)
+}
+
+// servedSchema is the schema which is built once and served for requests of the tenant.
+// versions are schema versions of the handlers which are reloaded at runtime, and the schema is rebuilt when they change.
+type servedSchema struct {
+	versions string
+	schema   graphql.Schema
+}
+
+// Build finalizes the configuration and returns http.Handler which serves graphql requests.
+// The schema of registered handlers, and of each tenant, is built and validated here
+// so that schema errors are reported at startup instead of on the first request, and is served for all requests.
+// Root fields of the schema resolve the fields which are bound to the connections created on each request.
+// After Build, handlers can't be registered and Use returns ErrServeMuxBuilt, and the exported fields must not be changed.
+func (s *ServeMux) Build() (http.Handler, error) {
+	if s.built {
+		return nil, ErrServeMuxBuilt
+	}
+	s.served = make(map[string]*servedSchema)
+	if _, err := s.servedSchemaOf("", snapshotHandlers(s.handlers)); err != nil {
+		return nil, fmt.Errorf("Schema validation error: %s", err)
+	}
+	for tenant := range s.tenantHandlers {
+		if _, err := s.servedSchemaOf(tenant, snapshotHandlers(s.tenantHandlersOf(tenant))); err != nil {
+			return nil, fmt.Errorf("Schema validation error of tenant %s: %s", tenant, err)
+		}
+	}
+	s.built = true
+	return &builtHandler{
+		mux:         s,
+		middlewares: append([]MiddlewareFunc{}, s.middlewares...),
+	}, nil
+}
+
+// handlerSnapshot is the handler which serves the request, and the snapshot of it if the handler is reloaded at runtime
+type handlerSnapshot struct {
+	handler  GraphqlHandler
+	snapshot GraphqlHandler
+	version  string
+}
+
+// snapshotHandlers takes snapshots of handlers which are reloaded at runtime,
+// so that the schema and the fields of the request are consistent
+func snapshotHandlers(handlers []GraphqlHandler) []handlerSnapshot {
+	snapshots := make([]handlerSnapshot, len(handlers))
+	for i, h := range handlers {
+		snapshots[i] = handlerSnapshot{handler: h, snapshot: h}
+		if v, ok := h.(schemaVersioner); ok {
+			snapshots[i].snapshot, snapshots[i].version = v.snapshot()
+		}
+	}
+	return snapshots
+}
+
+// servedSchemaOf returns schema of the handlers which is cached for the tenant after Build,
+// otherwise builds schema on each request because handlers may be registered later.
+func (s *ServeMux) servedSchemaOf(tenant string, handlers []handlerSnapshot) (graphql.Schema, error) {
+	if s.served == nil {
+		return s.boundSchemaOf(handlers)
+	}
+	versions := make([]string, len(handlers))
+	for i, h := range handlers {
+		versions[i] = h.version
+	}
+	key := strings.Join(versions, ",")
+
+	s.servedMu.Lock()
+	defer s.servedMu.Unlock()
+	if served, ok := s.served[tenant]; ok && served.versions == key {
+		return served.schema, nil
+	}
+	schema, err := s.boundSchemaOf(handlers)
+	if err != nil {
+		return schema, err
+	}
+	s.served[tenant] = &servedSchema{
+		versions: key,
+		schema:   schema,
+	}
+	return schema, nil
+}
+
+// boundSchemaOf builds instrumented schema of the handlers whose root fields resolve the fields bound by bindFields
+func (s *ServeMux) boundSchemaOf(handlers []handlerSnapshot) (graphql.Schema, error) {
+	queries := graphql.Fields{}
+	mutations := graphql.Fields{}
+	groups := map[string]string{}
+	for _, hs := range handlers {
+		h := hs.snapshot
+		for k, v := range h.GetQueries(nil) {
+			queries[k] = boundField("Query", k, v)
+		}
+		for k, v := range h.GetMutations(nil) {
+			mutations[k] = boundField("Mutation", k, v)
+		}
+		for k, v := range fieldGroupsOf(h) {
+			groups[k] = v
+		}
+	}
+	queries = s.groupFields("Query", queries, groups)
+	mutations = s.groupFields("Mutation", mutations, groups)
+	instrumentFields(queries)
+	instrumentFields(mutations)
+
+	schema, err := newSchema(queries, mutations, s.directiveDefinitions()...)
+	if err != nil {
+		return schema, err
+	}
+	instrumentSchema(&schema)
+	return schema, nil
+}
+
+type boundFieldsKey struct{}
+
+// boundFields are resolvers of root fields which are bound to the connections of the request, keyed by "<Operation>.<field>"
+type boundFields map[string]graphql.FieldResolveFn
+
+// bindFields stores resolvers of the root fields in the context, which are resolved by the served schema
+func bindFields(ctx context.Context, bound boundFields) context.Context {
+	return context.WithValue(ctx, boundFieldsKey{}, bound)
+}
+
+// boundField copies the root field whose resolver calls the one which is bound to the request
+func boundField(operation, name string, f *graphql.Field) *graphql.Field {
+	key := operation + "." + name
+	field := *f
+	field.Resolve = func(p graphql.ResolveParams) (interface{}, error) {
+		bound, _ := p.Context.Value(boundFieldsKey{}).(boundFields)
+		resolve, ok := bound[key]
+		if !ok {
+			return nil, errors.New("field " + name + " is not bound to the request")
+		}
+		if resolve == nil {
+			return graphql.DefaultResolveFn(p)
+		}
+		return resolve(p)
+	}
+	return &field
+}
@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMuxBuild(t *testing.T) {
+	var calls int
+	mux := NewServeMux(func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		calls++
+		return ctx, nil
+	})
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	handler, err := mux.Build()
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ hello }`))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	result := &graphql.Result{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(result))
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{"hello": "world"}, result.Data)
+	assert.Equal(t, 1, calls)
+
+	// Schema is built once and served for all requests
+	served := mux.served[""]
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ hello }`)))
+	assert.Same(t, served, mux.served[""])
+
+	// Configuration is frozen
+	assert.Equal(t, ErrServeMuxBuilt, mux.AddHandler(newGroupedTestHandler("invoices", "Invoice", "billing")))
+	assert.Equal(t, ErrServeMuxBuilt, mux.AddTenantHandler("tenant-a.example.com", newTestHandler()))
+	logger := &testLogger{}
+	mux.Logger = logger
+	assert.Same(t, mux, mux.Use(WithTracing()))
+	assert.Len(t, mux.middlewares, 1)
+	if assert.Len(t, logger.lines, 1) {
+		assert.Contains(t, logger.lines[0], ErrServeMuxBuilt.Error())
+	}
+	_, err = mux.Build()
+	assert.Equal(t, ErrServeMuxBuilt, err)
+}
+
+func TestServeMuxBuildValidatesTenantSchema(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	// Root field names don't conflict, but the type name does
+	assert.NoError(t, mux.AddTenantHandler("tenant-a.example.com", &testHandler{
+		queries: graphql.Fields{
+			"tenantUser": &graphql.Field{
+				Type: graphql.NewObject(graphql.ObjectConfig{
+					Name: "Test_Type_User",
+					Fields: graphql.Fields{
+						"name": &graphql.Field{Type: graphql.String},
+					},
+				}),
+			},
+		},
+	}))
+
+	_, err := mux.Build()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "tenant-a.example.com")
+	}
+}
@@ -0,0 +1,264 @@
+package runtime
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Bytes is GraphQL scalar of protobuf bytes fields which is opted in by bytes_scalar option instead of String,
+// and is represented as base64 encoded string. Values are kept as []byte in the result,
+// and encoded into the response writer directly so that large bytes fields don't build intermediate strings.
+var Bytes = graphql.NewScalar(graphql.ScalarConfig{
+	Name:        "Bytes",
+	Description: "The `Bytes` scalar type represents binary data as base64 encoded string.",
+	Serialize: func(value interface{}) interface{} {
+		switch v := value.(type) {
+		case []byte:
+			if len(v) == 0 {
+				return ""
+			}
+			return v
+		case string:
+			return v
+		case *string:
+			if v == nil {
+				return nil
+			}
+			return *v
+		default:
+			return nil
+		}
+	},
+	ParseValue: func(value interface{}) interface{} {
+		s, ok := value.(string)
+		if !ok || !isBase64(s) {
+			return nil
+		}
+		return s
+	},
+	ParseLiteral: func(valueAST ast.Value) interface{} {
+		v, ok := valueAST.(*ast.StringValue)
+		if !ok || !isBase64(v.Value) {
+			return nil
+		}
+		return v.Value
+	},
+})
+
+func isBase64(s string) bool {
+	_, err := base64.StdEncoding.DecodeString(s)
+	return err == nil
+}
+
+type maxBytesFieldSizeKey struct{}
+
+// WithMaxBytesFieldSize is middleware function to limit the size of bytes field values which are responded,
+// values which exceed the size are resolved with BYTES_FIELD_TOO_LARGE error instead of being encoded into the response.
+func WithMaxBytesFieldSize(size int) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, maxBytesFieldSizeKey{}, size), nil
+	}
+}
+
+// resolveBytes checks the size of the value of bytes field, and encodes the value into base64 string
+// if the field is exposed as String. The value is not resolved if the size exceeds the limit,
+// because graphql-go responds the value which is returned with the error.
+func resolveBytes(p graphql.ResolveParams, value interface{}) (interface{}, error) {
+	if err := checkBytesSize(p, value); err != nil {
+		return nil, err
+	}
+	return encodeBytes(p, value), nil
+}
+
+// checkBytesSize returns an error if the value of bytes field, or any item of list of bytes field, exceeds the limit
+func checkBytesSize(p graphql.ResolveParams, value interface{}) error {
+	limit, ok := p.Context.Value(maxBytesFieldSizeKey{}).(int)
+	if !ok || limit <= 0 {
+		return nil
+	}
+	named := graphql.GetNamed(p.Info.ReturnType)
+	if named != Bytes && named != graphql.String {
+		return nil
+	}
+	size := bytesSize(value, named == Bytes)
+	if size <= limit {
+		return nil
+	}
+	return NewFieldError(
+		"BYTES_FIELD_TOO_LARGE",
+		fmt.Sprintf("bytes field %s has %d bytes which exceeds maximum %d bytes", p.Info.FieldName, size, limit),
+	)
+}
+
+// bytesSize returns the size of bytes value, or the maximum size of the items.
+// Strings are base64 encoded bytes only if the field is Bytes scalar.
+func bytesSize(value interface{}, isBytes bool) int {
+	var size int
+	switch v := value.(type) {
+	case []byte:
+		size = len(v)
+	case string:
+		if isBytes {
+			size = base64.StdEncoding.DecodedLen(len(v))
+		}
+	case [][]byte:
+		for _, item := range v {
+			if len(item) > size {
+				size = len(item)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if s := bytesSize(item, isBytes); s > size {
+				size = s
+			}
+		}
+	}
+	return size
+}
+
+// encodeBytes encodes the value of bytes field which is exposed as String into base64 string,
+// because String scalar serializes []byte as Go syntax of the slice
+func encodeBytes(p graphql.ResolveParams, value interface{}) interface{} {
+	if graphql.GetNamed(p.Info.ReturnType) != graphql.String {
+		return value
+	}
+	switch v := value.(type) {
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case [][]byte:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = base64.StdEncoding.EncodeToString(item)
+		}
+		return items
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			if b, ok := item.([]byte); ok {
+				items[i] = base64.StdEncoding.EncodeToString(b)
+			} else {
+				items[i] = item
+			}
+		}
+		return items
+	}
+	return value
+}
+
+// hasBytesValue returns true if the value has []byte of Bytes scalar
+func hasBytesValue(v interface{}) bool {
+	switch t := v.(type) {
+	case []byte:
+		return true
+	case map[string]interface{}:
+		for _, item := range t {
+			if hasBytesValue(item) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			if hasBytesValue(item) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeResult writes the result as JSON like json.Marshal does, except that bytes values of Bytes scalar
+// are base64 encoded into the writer in streaming
+func writeResult(w io.Writer, result *graphql.Result) error {
+	bw := bufio.NewWriter(w)
+	bw.WriteString(`{"data":`) // nolint: errcheck
+	if err := writeJSONValue(bw, result.Data); err != nil {
+		return err
+	}
+	if len(result.Errors) > 0 {
+		bw.WriteString(`,"errors":`) // nolint: errcheck
+		if err := writeJSONValue(bw, result.Errors); err != nil {
+			return err
+		}
+	}
+	if len(result.Extensions) > 0 {
+		bw.WriteString(`,"extensions":`) // nolint: errcheck
+		if err := writeJSONValue(bw, result.Extensions); err != nil {
+			return err
+		}
+	}
+	bw.WriteByte('}') // nolint: errcheck
+	return bw.Flush()
+}
+
+func writeJSONValue(w *bufio.Writer, v interface{}) error {
+	switch t := v.(type) {
+	case []byte:
+		w.WriteByte('"') // nolint: errcheck
+		enc := base64.NewEncoder(base64.StdEncoding, w)
+		if _, err := enc.Write(t); err != nil {
+			return err
+		}
+		if err := enc.Close(); err != nil {
+			return err
+		}
+		return w.WriteByte('"')
+	case map[string]interface{}:
+		if t == nil {
+			_, err := w.WriteString("null")
+			return err
+		}
+		// Keys are sorted like json.Marshal
+		keys := make([]string, 0, len(t))
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		w.WriteByte('{') // nolint: errcheck
+		for i, k := range keys {
+			if i > 0 {
+				w.WriteByte(',') // nolint: errcheck
+			}
+			if err := writeJSONValue(w, k); err != nil {
+				return err
+			}
+			w.WriteByte(':') // nolint: errcheck
+			if err := writeJSONValue(w, t[k]); err != nil {
+				return err
+			}
+		}
+		return w.WriteByte('}')
+	case []interface{}:
+		if t == nil {
+			_, err := w.WriteString("null")
+			return err
+		}
+		w.WriteByte('[') // nolint: errcheck
+		for i, item := range t {
+			if i > 0 {
+				w.WriteByte(',') // nolint: errcheck
+			}
+			if err := writeJSONValue(w, item); err != nil {
+				return err
+			}
+		}
+		return w.WriteByte(']')
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+}
@@ -0,0 +1,116 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBytesTestHandler(data []byte) *testHandler {
+	h := newTestHandler()
+	h.queries["file"] = &graphql.Field{
+		Type: graphql.NewObject(graphql.ObjectConfig{
+			Name: "File",
+			Fields: graphql.Fields{
+				"content": &graphql.Field{Type: graphql.NewNonNull(Bytes)},
+				"chunks":  &graphql.Field{Type: graphql.NewList(Bytes)},
+				"text":    &graphql.Field{Type: graphql.String},
+				"texts":   &graphql.Field{Type: graphql.NewList(graphql.String)},
+			},
+		}),
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{
+				"content": data,
+				"chunks":  [][]byte{data[:1], nil},
+				"text":    data,
+				"texts":   []interface{}{data[:1], nil},
+			}, nil
+		},
+	}
+	return h
+}
+
+func TestBytesScalar(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newBytesTestHandler([]byte("hello"))))
+
+	result := serveTestQuery(t, mux, `{ file { content chunks } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"file": map[string]interface{}{
+			"content": "aGVsbG8=",
+			"chunks":  []interface{}{"aA==", ""},
+		},
+	}, result.Data)
+}
+
+func TestBytesString(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newBytesTestHandler([]byte("hello"))))
+
+	// Bytes fields which are exposed as String are encoded by the resolver
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ file { text texts } }`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Equal(t, `{"data":{"file":{"text":"aGVsbG8=","texts":["aA==",null]}}}`, w.Body.String())
+	assert.Equal(t, fmt.Sprint(w.Body.Len()), w.Header().Get("Content-Length"))
+
+	// Responses which have values of Bytes scalar are written in streaming
+	r = httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ file { content } }`))
+	w = httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Equal(t, `{"data":{"file":{"content":"aGVsbG8="}}}`, w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Length"))
+}
+
+func TestWithMaxBytesFieldSize(t *testing.T) {
+	mux := NewServeMux(WithMaxBytesFieldSize(4))
+	assert.NoError(t, mux.AddHandler(newBytesTestHandler([]byte("hello"))))
+
+	result := serveTestQuery(t, mux, `{ file { chunks } }`)
+	assert.Len(t, result.Errors, 0)
+
+	result = serveTestQuery(t, mux, `{ file { content } }`)
+	assert.Equal(t, map[string]interface{}{"file": nil}, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "BYTES_FIELD_TOO_LARGE", result.Errors[0].Extensions["code"])
+	}
+
+	// Bytes fields which are exposed as String are also limited
+	result = serveTestQuery(t, mux, `{ file { text } }`)
+	assert.Equal(t, map[string]interface{}{"file": map[string]interface{}{"text": nil}}, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "BYTES_FIELD_TOO_LARGE", result.Errors[0].Extensions["code"])
+	}
+}
+
+func TestWriteResult(t *testing.T) {
+	result := &graphql.Result{
+		Data: map[string]interface{}{
+			"file": map[string]interface{}{
+				"name":    "<script>",
+				"content": []byte("hello world"),
+				"size":    11,
+				"tags":    []interface{}{"a", nil, 1.5},
+				"empty":   []interface{}{},
+			},
+			"none": nil,
+		},
+		Errors:     []gqlerrors.FormattedError{{Message: "failed"}},
+		Extensions: map[string]interface{}{"cost": 1},
+	}
+	expected, err := json.Marshal(result)
+	assert.NoError(t, err)
+
+	var b bytes.Buffer
+	assert.NoError(t, writeResult(&b, result))
+	assert.Equal(t, string(expected), b.String())
+}
@@ -0,0 +1,240 @@
+package runtime
+
+import (
+	"container/list"
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"google.golang.org/grpc/grpclog"
+)
+
+// ResponseCacheHeader is response header which tells whether the response is served from the response cache, HIT or MISS
+const ResponseCacheHeader = "X-Cache"
+
+// Cache is storage interface of the response cache. Implement this with shared storage like Redis or memcached
+// in order to share cached responses across multiple gateway replicas, see contrib/redis and contrib/memcache packages.
+type Cache interface {
+	// Get returns the value of the key, ok is false if the key doesn't exist or is expired
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores the value with TTL, zero TTL means the value never expires
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the key, deleting missing key is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// LRUCache is in-memory Cache which evicts least recently used entries over the capacity
+type LRUCache struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+	now      func() time.Time
+}
+
+// NewLRUCache creates LRUCache pointer which holds up to capacity entries
+func NewLRUCache(capacity int) *LRUCache {
+	return &LRUCache{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get implements Cache
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false, nil
+	}
+	e := el.Value.(*lruEntry) // nolint: errcheck
+	if !e.expires.IsZero() && !c.now().Before(e.expires) {
+		c.remove(el)
+		return nil, false, nil
+	}
+	c.order.MoveToFront(el)
+	return e.value, true, nil
+}
+
+// Set implements Cache
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e := &lruEntry{
+		key:   key,
+		value: append([]byte{}, value...),
+	}
+	if ttl > 0 {
+		e.expires = c.now().Add(ttl)
+	}
+	if el, ok := c.entries[key]; ok {
+		el.Value = e
+		c.order.MoveToFront(el)
+		return nil
+	}
+	c.entries[key] = c.order.PushFront(e)
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// Delete implements Cache
+func (c *LRUCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.remove(el)
+	}
+	return nil
+}
+
+// Len returns number of entries in the cache including expired ones which are not evicted yet
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+func (c *LRUCache) remove(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry).key) // nolint: errcheck
+}
+
+// ResponseCacheConfig is configuration for WithResponseCache middleware
+type ResponseCacheConfig struct {
+	// Cache backend, default is LRUCache of 1000 entries
+	Cache Cache
+	// TTL of cached responses, default is one minute
+	TTL time.Duration
+	// Prefix of cache keys, which is useful to share the backend with other applications
+	Prefix string
+	// Vary returns the value which the response varies by, like user identity.
+	// Default is Authorization and Cookie headers, so that responses are never shared between users.
+	// Return constant value in order to share responses between all users if queries don't depend on identity.
+	Vary func(r *http.Request) string
+}
+
+type responseCacheKey struct{}
+
+type responseCache struct {
+	config ResponseCacheConfig
+	vary   string
+	w      http.ResponseWriter
+	bypass bool
+}
+
+// WithResponseCache is middleware function to cache successful query responses.
+// Responses are keyed by the query, variables, operationName and Vary value of the request.
+// Mutations and responses which have errors are never cached.
+// Requests which have Cache-Control: no-cache header skip the lookup and refresh the cached response.
+// Cache backend errors are logged and the request is executed as if there's no cache.
+func WithResponseCache(config ResponseCacheConfig) MiddlewareFunc {
+	if config.Cache == nil {
+		config.Cache = NewLRUCache(1000)
+	}
+	if config.TTL <= 0 {
+		config.TTL = time.Minute
+	}
+	if config.Vary == nil {
+		config.Vary = func(r *http.Request) string {
+			return r.Header.Get("Authorization") + "\x00" + r.Header.Get("Cookie")
+		}
+	}
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, responseCacheKey{}, &responseCache{
+			config: config,
+			vary:   config.Vary(r),
+			w:      w,
+			bypass: strings.Contains(r.Header.Get("Cache-Control"), "no-cache"),
+		}), nil
+	}
+}
+
+// cachedResponse returns cached result of the request, and function to store the result on cache miss.
+// If the request is not cacheable, both of them are nil.
+func cachedResponse(ctx context.Context, doc *ast.Document, req *GraphqlRequest) (*graphql.Result, func(*graphql.Result)) {
+	c, ok := ctx.Value(responseCacheKey{}).(*responseCache)
+	if !ok || !isQueryDocument(doc) {
+		return nil, nil
+	}
+	key, err := c.key(req)
+	if err != nil {
+		return nil, nil
+	}
+
+	if !c.bypass {
+		if b, ok, err := c.config.Cache.Get(ctx, key); err != nil {
+			grpclog.Errorf("Failed to get cached response: %s", err)
+		} else if ok {
+			var result graphql.Result
+			if err := json.Unmarshal(b, &result); err == nil {
+				c.w.Header().Set(ResponseCacheHeader, "HIT")
+				return &result, nil
+			}
+		}
+	}
+
+	c.w.Header().Set(ResponseCacheHeader, "MISS")
+	return nil, func(result *graphql.Result) {
+		if result.HasErrors() {
+			return
+		}
+		b, err := json.Marshal(result)
+		if err != nil {
+			return
+		}
+		if err := c.config.Cache.Set(ctx, key, b, c.config.TTL); err != nil {
+			grpclog.Errorf("Failed to set cached response: %s", err)
+		}
+	}
+}
+
+func (c *responseCache) key(req *GraphqlRequest) (string, error) {
+	variables, err := json.Marshal(req.Variables)
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	for _, v := range []string{req.Query, req.OperationName, string(variables), c.vary} {
+		hash.Write([]byte(v))    // nolint: errcheck
+		hash.Write([]byte{0x00}) // nolint: errcheck
+	}
+	return c.config.Prefix + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// isQueryDocument reports whether all operations in the document are queries
+func isQueryDocument(doc *ast.Document) bool {
+	var found bool
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if op.Operation != ast.OperationTypeQuery {
+			return false
+		}
+		found = true
+	}
+	return found
+}
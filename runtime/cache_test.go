@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	c := NewLRUCache(2)
+	c.now = func() time.Time { return now }
+
+	assert.NoError(t, c.Set(ctx, "a", []byte("1"), 0))
+	assert.NoError(t, c.Set(ctx, "b", []byte("2"), time.Second))
+	// Touch a so that b is the least recently used
+	v, ok, err := c.Get(ctx, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), v)
+
+	assert.NoError(t, c.Set(ctx, "c", []byte("3"), 0))
+	assert.Equal(t, 2, c.Len())
+	_, ok, _ = c.Get(ctx, "b") // nolint: errcheck
+	assert.False(t, ok)
+
+	assert.NoError(t, c.Delete(ctx, "a"))
+	assert.NoError(t, c.Delete(ctx, "missing"))
+	_, ok, _ = c.Get(ctx, "a") // nolint: errcheck
+	assert.False(t, ok)
+
+	// Expired
+	assert.NoError(t, c.Set(ctx, "d", []byte("4"), time.Second))
+	now = now.Add(time.Second)
+	_, ok, _ = c.Get(ctx, "d") // nolint: errcheck
+	assert.False(t, ok)
+	_, ok, _ = c.Get(ctx, "c") // nolint: errcheck
+	assert.True(t, ok)
+}
+
+func TestWithResponseCache(t *testing.T) {
+	var calls int
+	h := newTestHandler()
+	h.queries["counter"] = &graphql.Field{
+		Type: graphql.Int,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			calls++
+			return calls, nil
+		},
+	}
+	mux := NewServeMux(WithResponseCache(ResponseCacheConfig{}))
+	assert.NoError(t, mux.AddHandler(h))
+
+	serve := func(query string, header http.Header) (*httptest.ResponseRecorder, string) {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query))
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w, w.Header().Get(ResponseCacheHeader)
+	}
+
+	w, status := serve(`{ counter }`, nil)
+	assert.Equal(t, "MISS", status)
+	assert.JSONEq(t, `{"data":{"counter":1}}`, w.Body.String())
+
+	w, status = serve(`{ counter }`, nil)
+	assert.Equal(t, "HIT", status)
+	assert.JSONEq(t, `{"data":{"counter":1}}`, w.Body.String())
+	assert.Equal(t, 1, calls)
+
+	t.Run("varies by authorization", func(t *testing.T) {
+		w, status := serve(`{ counter }`, http.Header{"Authorization": {"Bearer other"}})
+		assert.Equal(t, "MISS", status)
+		assert.JSONEq(t, `{"data":{"counter":2}}`, w.Body.String())
+	})
+
+	t.Run("no-cache refreshes", func(t *testing.T) {
+		_, status := serve(`{ counter }`, http.Header{"Cache-Control": {"no-cache"}})
+		assert.Equal(t, "MISS", status)
+		w, status := serve(`{ counter }`, nil)
+		assert.Equal(t, "HIT", status)
+		assert.JSONEq(t, `{"data":{"counter":3}}`, w.Body.String())
+	})
+
+	t.Run("mutations are not cached", func(t *testing.T) {
+		_, status := serve(`mutation { login }`, nil)
+		assert.Equal(t, "", status)
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		_, status := serve(`{ counter unknown }`, nil)
+		assert.Equal(t, "", status)
+		_, status = serve(`{ counter unknown }`, nil)
+		assert.Equal(t, "", status)
+	})
+}
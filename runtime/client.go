@@ -0,0 +1,254 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/iancoleman/strcase"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// GraphqlClient sends operations to the gateway over HTTP, which is used by typed clients
+// that protoc-gen-graphql generates with client option.
+type GraphqlClient struct {
+	// Endpoint is URL of the gateway like "http://localhost:8888/graphql"
+	Endpoint string
+	// HTTPClient sends requests, default is http.DefaultClient
+	HTTPClient *http.Client
+	// Header is sent with all requests like Authorization
+	Header http.Header
+}
+
+// NewGraphqlClient creates client of the gateway endpoint
+func NewGraphqlClient(endpoint string) *GraphqlClient {
+	return &GraphqlClient{
+		Endpoint: endpoint,
+		Header:   http.Header{},
+	}
+}
+
+// ClientField describes the root field which typed client calls
+type ClientField struct {
+	// Operation is "query" or "mutation"
+	Operation string
+	// Group is the namespace field which the field is grouped under, empty if the field is not grouped
+	Group string
+	Name  string
+	// Arguments is literal of the arguments like `(id: "1")`, see GraphqlArguments
+	Arguments string
+	// Selection is selection set of the field like "{ id name }", empty if the field is scalar.
+	// Fields are aliased to the names of response message fields.
+	Selection string
+	// ResponseField is the field of response message which the field responds instead of whole message
+	ResponseField string
+}
+
+// ClientError is returned from GraphqlClient when the gateway responds errors
+type ClientError struct {
+	Errors []GraphqlError
+}
+
+func (e *ClientError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Message
+	}
+	return "graphql: " + strings.Join(messages, ", ")
+}
+
+// Call sends the operation which selects the field, and unmarshals the response of the field to resp
+func (c *GraphqlClient) Call(ctx context.Context, f ClientField, resp proto.Message) error {
+	field := f.Name + f.Arguments + " " + f.Selection
+	if f.Group != "" {
+		field = f.Group + " { " + field + " }"
+	}
+	data, err := c.Do(ctx, f.Operation+" { "+field+" }")
+	if err != nil {
+		return err
+	}
+	v := data[f.Name]
+	if f.Group != "" {
+		group, ok := data[f.Group].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("graphql: group %s is not responded", f.Group)
+		}
+		v = group[f.Name]
+	}
+	if f.ResponseField != "" {
+		v = map[string]interface{}{f.ResponseField: v}
+	}
+	return UnmarshalResponse(v, resp)
+}
+
+// Do sends the query and returns data of the response, ClientError is returned if the response has errors
+func (c *GraphqlClient) Do(ctx context.Context, query string) (map[string]interface{}, error) {
+	body, err := json.Marshal(GraphqlRequest{Query: query})
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range c.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close() // nolint: errcheck
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("graphql: unexpected status %d", res.StatusCode)
+	}
+
+	var result struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []GraphqlError         `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+	if len(result.Errors) > 0 {
+		return nil, &ClientError{Errors: result.Errors}
+	}
+	return result.Data, nil
+}
+
+// UnmarshalResponse sets GraphQL response value to the message.
+// Object keys are names of message fields, enums are value names and maps are lists of key-value entries.
+func UnmarshalResponse(v interface{}, msg proto.Message) error {
+	if v == nil {
+		return nil
+	}
+	values, ok := v.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("graphql: response must be an object, got %T", v)
+	}
+	return setMessageBy(msg.ProtoReflect(), values, func(fd protoreflect.FieldDescriptor) string {
+		return string(fd.Name())
+	})
+}
+
+// GraphqlArguments returns literal of GraphQL arguments of the message fields like `(id: "1", first: 10)`.
+// Fields are argument names, which are proto field names, and argument names are lower camel cased if isCamel is true.
+func GraphqlArguments(msg proto.Message, fields []string, isCamel bool) string {
+	m := msg.ProtoReflect()
+	var args []string
+	for _, name := range fields {
+		fd := m.Descriptor().Fields().ByName(protoreflect.Name(name))
+		if fd == nil || !hasLiteral(m, fd) {
+			continue
+		}
+		args = append(args, literalName(fd, isCamel)+": "+fieldLiteral(m, fd, isCamel))
+	}
+	if len(args) == 0 {
+		return ""
+	}
+	return "(" + strings.Join(args, ", ") + ")"
+}
+
+// GraphqlInputArgument returns literal of the argument which passes whole message as input object like `(input: { title: "" })`
+func GraphqlInputArgument(name string, msg proto.Message, isCamel bool) string {
+	return "(" + name + ": " + messageLiteral(msg.ProtoReflect(), isCamel) + ")"
+}
+
+// hasLiteral reports whether the field is sent, omitted fields, unset message and oneof fields are not sent.
+// Messages of custom scalars are not sent either because their values are application specific.
+func hasLiteral(m protoreflect.Message, fd protoreflect.FieldDescriptor) bool {
+	if fieldOption(fd).GetOmit() || (fd.Message() != nil && isCustomScalarField(fd)) {
+		return false
+	}
+	if fd.ContainingOneof() != nil || (fd.Message() != nil && !fd.IsList() && !fd.IsMap()) {
+		return m.Has(fd)
+	}
+	return true
+}
+
+func literalName(fd protoreflect.FieldDescriptor, isCamel bool) string {
+	if isCamel {
+		return strcase.ToLowerCamel(string(fd.Name()))
+	}
+	return string(fd.Name())
+}
+
+func messageLiteral(m protoreflect.Message, isCamel bool) string {
+	fields := m.Descriptor().Fields()
+	var values []string
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !hasLiteral(m, fd) {
+			continue
+		}
+		values = append(values, literalName(fd, isCamel)+": "+fieldLiteral(m, fd, isCamel))
+	}
+	return "{" + strings.Join(values, ", ") + "}"
+}
+
+func fieldLiteral(m protoreflect.Message, fd protoreflect.FieldDescriptor, isCamel bool) string {
+	v := m.Get(fd)
+	switch {
+	case fd.IsMap():
+		var entries []string
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			entries = append(entries, "{key: "+valueLiteral(fd.MapKey(), k.Value(), isCamel)+
+				", value: "+valueLiteral(fd.MapValue(), mv, isCamel)+"}")
+			return true
+		})
+		// Map iteration order is random, sort entries for the stable literal
+		sort.Strings(entries)
+		return "[" + strings.Join(entries, ", ") + "]"
+	case fd.IsList():
+		list := v.List()
+		items := make([]string, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			items[i] = valueLiteral(fd, list.Get(i), isCamel)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	default:
+		return valueLiteral(fd, v, isCamel)
+	}
+}
+
+func valueLiteral(fd protoreflect.FieldDescriptor, v protoreflect.Value, isCamel bool) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageLiteral(v.Message(), isCamel)
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByNumber(v.Enum()); ev != nil {
+			return string(ev.Name())
+		}
+		return strconv.Itoa(int(v.Enum()))
+	case protoreflect.StringKind:
+		return stringLiteral(v.String())
+	case protoreflect.BytesKind:
+		return stringLiteral(base64.StdEncoding.EncodeToString(v.Bytes()))
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	default:
+		return v.String()
+	}
+}
+
+// stringLiteral quotes the string, JSON string escapes are valid in GraphQL
+func stringLiteral(s string) string {
+	b, _ := json.Marshal(s) // nolint: errcheck
+	return string(b)
+}
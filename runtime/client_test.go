@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+func TestGraphqlArguments(t *testing.T) {
+	file := testBookFiles(t)
+	book := dynamicpb.NewMessage(file.Messages().ByName("Book"))
+	assert.NoError(t, UnmarshalResponse(map[string]interface{}{
+		"title": "Say \"Hi\"",
+		"genre": "NOVEL",
+		"ratings": []interface{}{
+			map[string]interface{}{"key": "bob", "value": float64(4)},
+			map[string]interface{}{"key": "alice", "value": float64(5)},
+		},
+		"cover":      "aGVsbG8=",
+		"page_count": float64(412),
+	}, book))
+
+	fields := book.Descriptor().Fields()
+	assert.Equal(t, "Say \"Hi\"", book.Get(fields.ByName("title")).String())
+	assert.Equal(t, protoreflect.EnumNumber(1), book.Get(fields.ByName("genre")).Enum())
+	assert.Equal(t, 2, book.Get(fields.ByName("ratings")).Map().Len())
+	assert.Equal(t, []byte("hello"), book.Get(fields.ByName("cover")).Bytes())
+	assert.Equal(t, int64(412), book.Get(fields.ByName("page_count")).Int())
+
+	assert.Equal(t,
+		`(title: "Say \"Hi\"", page_count: 412, genre: NOVEL)`,
+		GraphqlArguments(book, []string{"title", "page_count", "genre", "unknown"}, false),
+	)
+	assert.Equal(t,
+		`(book: {title: "Say \"Hi\"", genre: NOVEL, ratings: [{key: "alice", value: 5}, {key: "bob", value: 4}], `+
+			`cover: "aGVsbG8=", related: [], pageCount: 412})`,
+		GraphqlInputArgument("book", book, true),
+	)
+	assert.Equal(t, "", GraphqlArguments(book, nil, false))
+
+	assert.Error(t, UnmarshalResponse(map[string]interface{}{"genre": "UNKNOWN_GENRE"}, book))
+}
+
+func TestGraphqlClientCall(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		var req GraphqlRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		query = req.Query
+		if r.URL.Query().Get("fail") != "" {
+			w.Write([]byte(`{"data":null,"errors":[{"message":"not found"}]}`)) // nolint: errcheck
+			return
+		}
+		w.Write([]byte(`{"data":{"library":{"books":[{"title":"Dune","genre":"NOVEL"}]}}}`)) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	file := testBookFiles(t)
+	client := NewGraphqlClient(server.URL)
+	client.Header.Set("Authorization", "Bearer token")
+	resp := dynamicpb.NewMessage(file.Messages().ByName("ListBooksResponse"))
+	f := ClientField{
+		Operation:     "query",
+		Group:         "library",
+		Name:          "books",
+		Selection:     "{ title genre }",
+		ResponseField: "books",
+	}
+	assert.NoError(t, client.Call(context.Background(), f, resp))
+	assert.Equal(t, "query { library { books { title genre } } }", query)
+	books := resp.Get(resp.Descriptor().Fields().ByName("books")).List()
+	if assert.Equal(t, 1, books.Len()) {
+		assert.Equal(t, "Dune", books.Get(0).Message().Get(file.Messages().ByName("Book").Fields().ByName("title")).String())
+	}
+
+	client.Endpoint = server.URL + "?fail=1"
+	err := client.Call(context.Background(), f, resp)
+	if assert.IsType(t, &ClientError{}, err) {
+		assert.Equal(t, "graphql: not found", err.Error())
+	}
+}
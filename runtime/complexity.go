@@ -0,0 +1,271 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DefaultFieldCost is used for query complexity analysis when the field doesn't have cost option
+var DefaultFieldCost = 1
+
+// ComplexityMultiplierArgs are argument names which multiply cost of child selections,
+// e.g. "{ books(first: 10) { title } }" costs 1 + 10 * 1.
+var ComplexityMultiplierArgs = []string{"first", "last", "limit", "page_size", "pageSize"}
+
+var fieldCosts = struct {
+	sync.RWMutex
+	costs map[string]int
+}{
+	costs: make(map[string]int),
+}
+
+// SetFieldCost registers cost of type field for query complexity analysis.
+// This function is called from generated code for fields which have (graphql.field).cost option.
+func SetFieldCost(typeName, fieldName string, cost int) {
+	fieldCosts.Lock()
+	defer fieldCosts.Unlock()
+	fieldCosts.costs[typeName+"."+fieldName] = cost
+}
+
+func fieldCost(typeName, fieldName string) int {
+	fieldCosts.RLock()
+	defer fieldCosts.RUnlock()
+	if cost, ok := fieldCosts.costs[typeName+"."+fieldName]; ok {
+		return cost
+	}
+	return DefaultFieldCost
+}
+
+type maxQueryComplexityKey struct{}
+
+// WithMaxQueryComplexity is middleware function to limit total cost of the query document.
+// The cost is calculated from registered field costs and list multiplier arguments,
+// and the request is rejected with QUERY_TOO_COMPLEX error before any RPC runs.
+func WithMaxQueryComplexity(complexity int) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, maxQueryComplexityKey{}, complexity), nil
+	}
+}
+
+// checkComplexity checks the validated document against complexity limit which is stored in the context
+func checkComplexity(ctx context.Context, schema *graphql.Schema, doc *ast.Document, variables map[string]interface{}) []GraphqlError {
+	limit, ok := ctx.Value(maxQueryComplexityKey{}).(int)
+	if !ok {
+		return nil
+	}
+	if queryComplexity(schema, doc, variables, limit) > limit {
+		return []GraphqlError{
+			{
+				Message: fmt.Sprintf("query complexity exceeds maximum complexity %d", limit),
+				Extensions: map[string]interface{}{
+					"code": "QUERY_TOO_COMPLEX",
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// queryComplexity returns maximum cost of all operations in the document, which saturates at limit+1
+func queryComplexity(schema *graphql.Schema, doc *ast.Document, variables map[string]interface{}, limit int) int {
+	c := &complexityCalculator{
+		schema:    schema,
+		fragments: collectFragments(doc),
+		variables: variables,
+		costs:     make(map[string]int),
+		visited:   make(map[string]struct{}),
+		limit:     limit,
+	}
+
+	var highest int
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		var root *graphql.Object
+		switch op.Operation {
+		case ast.OperationTypeQuery:
+			root = schema.QueryType()
+		case ast.OperationTypeMutation:
+			root = schema.MutationType()
+		case ast.OperationTypeSubscription:
+			root = schema.SubscriptionType()
+		}
+		if root == nil {
+			continue
+		}
+		if cost := c.selectionCost(op.SelectionSet, root); cost > highest {
+			highest = cost
+		}
+		if highest > limit {
+			break
+		}
+	}
+	return highest
+}
+
+// complexityCalculator memoizes cost of each fragment by the type like depthCounter,
+// and costs saturate at limit+1 so that large multipliers don't overflow
+type complexityCalculator struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	costs     map[string]int
+	visited   map[string]struct{}
+	limit     int
+}
+
+// add returns sum of costs which saturates at limit+1
+func (c *complexityCalculator) add(a, b int) int {
+	if a > c.limit-b {
+		return c.limit + 1
+	}
+	return a + b
+}
+
+// multiply returns product of the multiplier and the cost which saturates at limit+1
+func (c *complexityCalculator) multiply(n, cost int) int {
+	if n > 0 && cost > c.limit/n {
+		return c.limit + 1
+	}
+	return n * cost
+}
+
+func (c *complexityCalculator) selectionCost(set *ast.SelectionSet, parent graphql.Type) int {
+	if set == nil {
+		return 0
+	}
+
+	var cost int
+	for _, s := range set.Selections {
+		switch v := s.(type) {
+		case *ast.Field:
+			cost = c.add(cost, c.fieldCost(v, parent))
+		case *ast.InlineFragment:
+			t := parent
+			if v.TypeCondition != nil && v.TypeCondition.Name != nil {
+				if tc := c.schema.Type(v.TypeCondition.Name.Value); tc != nil {
+					t = tc
+				}
+			}
+			cost = c.add(cost, c.selectionCost(v.SelectionSet, t))
+		case *ast.FragmentSpread:
+			if v.Name != nil {
+				cost = c.add(cost, c.fragmentCost(v.Name.Value, parent))
+			}
+		}
+		if cost > c.limit {
+			return cost
+		}
+	}
+	return cost
+}
+
+func (c *complexityCalculator) fragmentCost(name string, parent graphql.Type) int {
+	f, ok := c.fragments[name]
+	if !ok {
+		return 0
+	}
+	t := parent
+	if f.TypeCondition != nil && f.TypeCondition.Name != nil {
+		if tc := c.schema.Type(f.TypeCondition.Name.Value); tc != nil {
+			t = tc
+		}
+	}
+	key := name + "@" + t.Name()
+	if cost, ok := c.costs[key]; ok {
+		return cost
+	}
+	if _, ok := c.visited[name]; ok {
+		return 0
+	}
+	c.visited[name] = struct{}{}
+	cost := c.selectionCost(f.SelectionSet, t)
+	delete(c.visited, name)
+	c.costs[key] = cost
+	return cost
+}
+
+func (c *complexityCalculator) fieldCost(field *ast.Field, parent graphql.Type) int {
+	if field.Name == nil || strings.HasPrefix(field.Name.Value, "__") {
+		return 0
+	}
+	name := field.Name.Value
+
+	var fields graphql.FieldDefinitionMap
+	switch t := parent.(type) {
+	case *graphql.Object:
+		fields = t.Fields()
+	case *graphql.Interface:
+		fields = t.Fields()
+	}
+	cost := fieldCost(parent.Name(), name)
+	def, ok := fields[name]
+	if !ok {
+		return cost
+	}
+	child, ok := graphql.GetNamed(def.Type).(graphql.Type)
+	if !ok {
+		return cost
+	}
+	return c.add(cost, c.multiply(c.multiplier(field), c.selectionCost(field.SelectionSet, child)))
+}
+
+// multiplier returns list size which is specified via multiplier arguments
+func (c *complexityCalculator) multiplier(field *ast.Field) int {
+	for _, arg := range field.Arguments {
+		if arg.Name == nil || !isMultiplierArg(arg.Name.Value) {
+			continue
+		}
+		var n int
+		switch v := arg.Value.(type) {
+		case *ast.IntValue:
+			n, _ = strconv.Atoi(v.Value) // nolint: errcheck
+		case *ast.Variable:
+			if v.Name != nil {
+				n = intValue(c.variables[v.Name.Value])
+			}
+		}
+		if n > 1 {
+			return n
+		}
+	}
+	return 1
+}
+
+func isMultiplierArg(name string) bool {
+	for _, a := range ComplexityMultiplierArgs {
+		if a == name {
+			return true
+		}
+	}
+	return false
+}
+
+func intValue(v interface{}) int {
+	switch n := v.(type) {
+	case int:
+		return n
+	case int32:
+		return int(n)
+	case int64:
+		return int(n)
+	case float64:
+		return int(n)
+	case json.Number:
+		i, _ := n.Int64() // nolint: errcheck
+		return int(i)
+	default:
+		return 0
+	}
+}
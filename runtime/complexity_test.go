@@ -0,0 +1,89 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryComplexity(t *testing.T) {
+	bookType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Test_Type_Book",
+		Fields: graphql.Fields{
+			"title": &graphql.Field{
+				Type: graphql.String,
+			},
+			"author": &graphql.Field{
+				Type: testUserType,
+			},
+		},
+	})
+	SetFieldCost("Test_Type_Book", "author", 5)
+
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"books": &graphql.Field{
+					Type: graphql.NewList(bookType),
+					Args: graphql.FieldConfigArgument{
+						"first": &graphql.ArgumentConfig{
+							Type: graphql.Int,
+						},
+					},
+				},
+			},
+		}),
+	})
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+
+	tests := []struct {
+		query     string
+		variables map[string]interface{}
+		expect    int
+	}{
+		{query: `{ books { title } }`, expect: 2},
+		{query: `{ books { title author { id } } }`, expect: 8},
+		{query: `{ books(first: 10) { title } }`, expect: 11},
+		{query: `query ($n: Int) { books(first: $n) { title } }`, variables: map[string]interface{}{"n": float64(3)}, expect: 4},
+		{query: `{ books { ...f } } fragment f on Test_Type_Book { title author { id email } }`, expect: 9},
+		{query: `{ __typename books { __typename title } }`, expect: 2},
+	}
+
+	for _, tt := range tests {
+		doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.expect, queryComplexity(&schema, doc, tt.variables, 100), tt.query)
+		}
+	}
+
+	// Costs saturate at the limit without overflow, and fragments are not walked at every spread
+	for _, query := range []string{
+		`{ books(first: 9223372036854775807) { author { id } } }`,
+		`{ books(first: 4294967296) { title } a: books(first: 4294967296) { title } }`,
+		fragmentBomb(64, "books { title }"),
+	} {
+		doc, err := parser.Parse(parser.ParseParams{Source: query})
+		if assert.NoError(t, err) {
+			assert.Equal(t, 101, queryComplexity(&schema, doc, nil, 100), query)
+		}
+	}
+}
+
+func TestMaxQueryComplexity(t *testing.T) {
+	mux := NewServeMux(WithMaxQueryComplexity(2))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ user(id: "1") { id } }`)
+	assert.Len(t, result.Errors, 0)
+
+	result = serveTestQuery(t, mux, `{ user(id: "1") { id email } }`)
+	assert.Nil(t, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "QUERY_TOO_COMPLEX", result.Errors[0].Extensions["code"])
+	}
+}
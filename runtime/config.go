@@ -0,0 +1,598 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"io/ioutil"
+	"net/http"
+	"net/url"
+
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is configuration of the gateway which is loaded from YAML or JSON file via LoadConfig.
+// The standalone gateway command is configured by this, and library users build middlewares and upstream connections from it.
+//
+//	listen: ":8888"
+//	descriptor_sets: [image.binpb]
+//	upstream:
+//	  address: localhost:50051
+//	upstreams:
+//	  greeter.Greeter:
+//	    address: ${GREETER_ADDRESS:-greeter:50051}
+//	    tls:
+//	      ca_file: /etc/certs/ca.pem
+//	  user.UserService:
+//	    address: https://users.internal:8443
+//	    protocol: connect
+//	limits:
+//	  max_depth: 10
+//	  timeout: 5s
+//	cors:
+//	  allowed_origins: ["https://app.example.com"]
+//	cache:
+//	  ttl: 30s
+//	middlewares:
+//	  csrf: true
+//	  introspection: false
+//	shutdown:
+//	  drain_delay: 5s
+//	debug:
+//	  allowed_networks: ["10.0.0.0/8"]
+type Config struct {
+	// Listen address of the standalone gateway, default is ":8888"
+	Listen string `yaml:"listen"`
+	// Path of GraphQL endpoint of the standalone gateway, default is "/graphql"
+	Path string `yaml:"path"`
+	// Descriptor set files which the standalone gateway serves
+	DescriptorSets []string `yaml:"descriptor_sets"`
+	// Default upstream of all services
+	Upstream UpstreamConfig `yaml:"upstream" env:"-"`
+	// Upstreams per service full name like "greeter.Greeter", which override the default upstream
+	Upstreams map[string]UpstreamConfig `yaml:"upstreams" env:"-"`
+
+	Limits      LimitsConfig         `yaml:"limits"`
+	CORS        *CORSFileConfig      `yaml:"cors"`
+	Cache       *CacheFileConfig     `yaml:"cache"`
+	RateLimit   *RateLimitFileConfig `yaml:"rate_limit"`
+	Middlewares MiddlewareToggles    `yaml:"middlewares"`
+	Shutdown    ShutdownConfig       `yaml:"shutdown"`
+	Debug       DebugConfig          `yaml:"debug"`
+}
+
+// UpstreamConfig is upstream gRPC server of services
+type UpstreamConfig struct {
+	// Target address like "host:port", "dns:///host:port" or "unix:///path/to.sock",
+	// or base URL like "https://host:port" for Connect and Twirp protocol.
+	// The path of Twirp base URL is the route prefix, default is "/twirp".
+	Address string `yaml:"address"`
+	// TLS of the connection, plaintext if nil
+	TLS *TLSFileConfig `yaml:"tls"`
+	// Protocol of the upstream, "grpc" (default), "connect" or "twirp"
+	Protocol string `yaml:"protocol"`
+}
+
+// Upstream protocols of UpstreamConfig
+const (
+	ProtocolGRPC    = "grpc"
+	ProtocolConnect = "connect"
+	ProtocolTwirp   = "twirp"
+)
+
+// TLSFileConfig is file representation of UpstreamTLSConfig
+type TLSFileConfig struct {
+	CAFile             string `yaml:"ca_file"`
+	CertFile           string `yaml:"cert_file"`
+	KeyFile            string `yaml:"key_file"`
+	ServerName         string `yaml:"server_name"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+}
+
+// LimitsConfig enables query limit middlewares, zero values are unlimited
+type LimitsConfig struct {
+	MaxDepth      int `yaml:"max_depth"`
+	MaxAliases    int `yaml:"max_aliases"`
+	MaxRootFields int `yaml:"max_root_fields"`
+	MaxComplexity int `yaml:"max_complexity"`
+	MaxJSONDepth  int `yaml:"max_json_depth"`
+	MaxVariables  int `yaml:"max_variables"`
+	// Limits of the query document which are checked before parsing
+	MaxQueryBytes  int `yaml:"max_query_bytes"`
+	MaxQueryTokens int `yaml:"max_query_tokens"`
+	// Timeout of the operation, and overrides per operation name
+	Timeout           time.Duration            `yaml:"timeout"`
+	OperationTimeouts map[string]time.Duration `yaml:"operation_timeouts"`
+	// Limits of collecting server-streaming RPCs into list fields
+	StreamMaxItems int           `yaml:"stream_max_items"`
+	StreamTimeout  time.Duration `yaml:"stream_timeout"`
+	// Maximum size of bytes field values in the response
+	MaxBytesFieldSize int `yaml:"max_bytes_field_size"`
+}
+
+// CORSFileConfig is file representation of CorsConfig
+type CORSFileConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// CacheFileConfig enables response cache on in-memory LRUCache
+type CacheFileConfig struct {
+	TTL time.Duration `yaml:"ttl"`
+	// Maximum number of cached responses, default is 1000
+	Size int `yaml:"size"`
+}
+
+// RateLimitFileConfig enables rate limit per client IP
+type RateLimitFileConfig struct {
+	// Rate must be positive
+	Rate float64 `yaml:"rate"`
+	// Default is 1
+	Burst int `yaml:"burst"`
+}
+
+// MiddlewareToggles enables middlewares which don't need configuration
+type MiddlewareToggles struct {
+	CSRF            bool `yaml:"csrf"`
+	SecurityHeaders bool `yaml:"security_headers"`
+	Tracing         bool `yaml:"tracing"`
+	// Introspection is allowed unless this is false
+	Introspection *bool `yaml:"introspection"`
+	// "Did you mean" hints of validation errors are responded unless this is false
+	Suggestions *bool `yaml:"suggestions"`
+	// Threshold of slow query log, disabled if zero
+	SlowQueryLog time.Duration `yaml:"slow_query_log"`
+	// Patterns of redacted variables and error messages
+	Redact []string `yaml:"redact"`
+	// Names of Sanitizers which are applied to string arguments of all fields like ["trim_space", "nfc"]
+	Sanitize []string `yaml:"sanitize"`
+	// Policy of unknown enum values returned by upstreams, "null" (default), "error" or "pass_through"
+	UnknownEnum string `yaml:"unknown_enum"`
+	// Metadata keys of upstream responses which are forwarded to HTTP response headers, all keys are forwarded if empty
+	ResponseHeaders []string `yaml:"response_headers"`
+}
+
+// ShutdownConfig is graceful shutdown of the standalone gateway on SIGTERM
+type ShutdownConfig struct {
+	// Duration to keep serving after readiness probe starts failing, so that load balancers stop routing new requests
+	DrainDelay time.Duration `yaml:"drain_delay"`
+	// Maximum duration to wait for in-flight requests, default is 30 seconds
+	GracePeriod time.Duration `yaml:"grace_period"`
+}
+
+// ConfigEnvPrefix is prefix of environment variables which override config values.
+// The variable name is the prefix and upper cased YAML keys joined with underscore, e.g. GRAPHQL_LIMITS_MAX_DEPTH for limits.max_depth.
+// Lists are comma separated, and maps are not configurable by environment variables.
+//
+// The default upstream address is GRAPHQL_UPSTREAM, and upstream addresses per service
+// are UpstreamHostEnvPrefix variables which are resolved on Dial.
+const ConfigEnvPrefix = "GRAPHQL_"
+
+// DebugConfig enables debug endpoints of the standalone gateway like "<path>/debug/mapping" and explain mode
+type DebugConfig struct {
+	// CIDR notations of networks which are allowed to access debug endpoints, disabled if empty
+	AllowedNetworks []string `yaml:"allowed_networks"`
+}
+
+// envPattern matches "$$" escape and "${NAME}" or "${NAME:-default}" interpolation
+var envPattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// LoadConfig reads YAML or JSON config file, and overrides it with ConfigEnvPrefix environment variables.
+// Environment variables are interpolated like "${NAME}" or "${NAME:-default}" before parsing, and "$$" is literal "$".
+// Unknown keys are rejected in order to detect typos.
+// If path is empty, the config is loaded only from environment variables.
+func LoadConfig(path string) (*Config, error) {
+	c := &Config{}
+	if path != "" {
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if c, err = parseConfig(b); err != nil {
+			return nil, fmt.Errorf("failed to load config %s: %w", path, err)
+		}
+	}
+	if err := applyConfigEnv(c); err != nil {
+		return nil, err
+	}
+	for _, name := range c.Middlewares.Sanitize {
+		if _, ok := Sanitizers[name]; !ok {
+			return nil, fmt.Errorf("unknown sanitizer %q", name)
+		}
+	}
+	if p := c.Middlewares.UnknownEnum; p != "" {
+		if _, ok := unknownEnumPolicies[p]; !ok {
+			return nil, fmt.Errorf("unknown enum policy %q", p)
+		}
+	}
+	c.setDefaults()
+	if c.RateLimit != nil {
+		if err := (RateLimitConfig{Rate: c.RateLimit.Rate, Burst: c.RateLimit.Burst}).Validate(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// parseConfig parses config file without defaults
+func parseConfig(b []byte) (*Config, error) {
+	var missing []string
+	b = envPattern.ReplaceAllFunc(b, func(m []byte) []byte {
+		if string(m) == "$$" {
+			return []byte("$")
+		}
+		sub := envPattern.FindSubmatch(m)
+		if v, ok := os.LookupEnv(string(sub[1])); ok {
+			return []byte(v)
+		}
+		if bytes.Contains(m, []byte(":-")) {
+			return sub[2]
+		}
+		missing = append(missing, string(sub[1]))
+		return nil
+	})
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("environment variables %v are not set", missing)
+	}
+
+	c := &Config{}
+	dec := yaml.NewDecoder(bytes.NewReader(b))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// applyConfigEnv overrides the config with ConfigEnvPrefix environment variables
+func applyConfigEnv(c *Config) error {
+	if v, ok := os.LookupEnv(strings.TrimSuffix(UpstreamHostEnvPrefix, "_")); ok {
+		c.Upstream.Address = v
+	}
+	_, err := applyEnvFields(reflect.ValueOf(c).Elem(), ConfigEnvPrefix)
+	return err
+}
+
+// applyEnvFields sets fields of the struct value from environment variables whose names are prefix and upper cased YAML keys,
+// and reports whether any of them is set. Nil struct pointers are allocated only if any of their fields is set.
+func applyEnvFields(v reflect.Value, prefix string) (bool, error) {
+	var applied bool
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		key := strings.Split(f.Tag.Get("yaml"), ",")[0]
+		if key == "" || key == "-" || f.Tag.Get("env") == "-" {
+			continue
+		}
+		name := prefix + strings.ToUpper(key)
+		fv := v.Field(i)
+
+		switch {
+		case f.Type.Kind() == reflect.Struct:
+			ok, err := applyEnvFields(fv, name+"_")
+			if err != nil {
+				return false, err
+			}
+			applied = applied || ok
+		case f.Type.Kind() == reflect.Ptr && f.Type.Elem().Kind() == reflect.Struct:
+			nv := reflect.New(f.Type.Elem())
+			if !fv.IsNil() {
+				nv.Elem().Set(fv.Elem())
+			}
+			ok, err := applyEnvFields(nv.Elem(), name+"_")
+			if err != nil {
+				return false, err
+			}
+			if ok {
+				fv.Set(nv)
+				applied = true
+			}
+		case f.Type.Kind() == reflect.Map:
+			continue
+		default:
+			s, ok := os.LookupEnv(name)
+			if !ok {
+				continue
+			}
+			if err := setEnvValue(fv, s); err != nil {
+				return false, fmt.Errorf("invalid environment variable %s: %w", name, err)
+			}
+			applied = true
+		}
+	}
+	return applied, nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setEnvValue sets string value of environment variable to the field
+func setEnvValue(v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		nv := reflect.New(v.Type().Elem())
+		if err := setEnvValue(nv.Elem(), s); err != nil {
+			return err
+		}
+		v.Set(nv)
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", v.Type())
+		}
+		var items []string
+		for _, item := range strings.Split(s, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// setDefaults fills unspecified values with defaults
+func (c *Config) setDefaults() {
+	if c.Listen == "" {
+		c.Listen = ":8888"
+	}
+	if c.Path == "" {
+		c.Path = "/graphql"
+	}
+	if c.Shutdown.GracePeriod == 0 {
+		c.Shutdown.GracePeriod = 30 * time.Second
+	}
+	if c.RateLimit != nil && c.RateLimit.Burst == 0 {
+		c.RateLimit.Burst = 1
+	}
+}
+
+// MiddlewareFuncs builds middlewares which are enabled in the config
+func (c *Config) MiddlewareFuncs() []MiddlewareFunc {
+	var ms []MiddlewareFunc
+	t := c.Middlewares
+	if t.SecurityHeaders {
+		ms = append(ms, SecurityHeaders(SecurityHeadersConfig{}))
+	}
+	if c.CORS != nil {
+		ms = append(ms, Cors(CorsConfig{
+			AllowedOrigins:   c.CORS.AllowedOrigins,
+			AllowedHeaders:   c.CORS.AllowedHeaders,
+			ExposedHeaders:   c.CORS.ExposedHeaders,
+			AllowCredentials: c.CORS.AllowCredentials,
+			MaxAge:           c.CORS.MaxAge,
+		}))
+	}
+	if t.Tracing {
+		ms = append(ms, WithTracing())
+	}
+	if t.SlowQueryLog > 0 {
+		ms = append(ms, WithSlowQueryLog(t.SlowQueryLog))
+	}
+	if len(t.Redact) > 0 {
+		ms = append(ms, WithRedaction(t.Redact...))
+	}
+	if len(t.Sanitize) > 0 {
+		config := SanitizeConfig{}
+		for _, name := range t.Sanitize {
+			config.Global = append(config.Global, Sanitizers[name])
+		}
+		ms = append(ms, WithArgumentSanitizer(config))
+	}
+	if policy := unknownEnumPolicies[t.UnknownEnum]; policy != UnknownEnumNull {
+		ms = append(ms, WithUnknownEnumPolicy(policy))
+	}
+	if len(t.ResponseHeaders) > 0 {
+		ms = append(ms, WithResponseHeaderAllowlist(t.ResponseHeaders...))
+	}
+	if c.RateLimit != nil {
+		ms = append(ms, RateLimit(RateLimitConfig{
+			Rate:  c.RateLimit.Rate,
+			Burst: c.RateLimit.Burst,
+		}))
+	}
+	if t.CSRF {
+		ms = append(ms, CSRFProtection(CSRFConfig{}))
+	}
+	if t.Introspection != nil && !*t.Introspection {
+		ms = append(ms, WithIntrospection(func(ctx context.Context, r *http.Request) bool {
+			return false
+		}))
+	}
+	if t.Suggestions != nil && !*t.Suggestions {
+		ms = append(ms, WithoutSuggestions())
+	}
+
+	l := c.Limits
+	if l.MaxJSONDepth > 0 || l.MaxVariables > 0 {
+		ms = append(ms, WithRequestParseLimits(RequestParseLimits{
+			MaxJSONDepth: l.MaxJSONDepth,
+			MaxVariables: l.MaxVariables,
+		}))
+	}
+	if l.MaxQueryBytes > 0 {
+		ms = append(ms, WithMaxQuerySize(l.MaxQueryBytes))
+	}
+	if l.MaxQueryTokens > 0 {
+		ms = append(ms, WithMaxQueryTokens(l.MaxQueryTokens))
+	}
+	if l.MaxDepth > 0 {
+		ms = append(ms, WithMaxQueryDepth(l.MaxDepth))
+	}
+	if l.MaxAliases > 0 {
+		ms = append(ms, WithMaxAliases(l.MaxAliases))
+	}
+	if l.MaxRootFields > 0 {
+		ms = append(ms, WithMaxRootFields(l.MaxRootFields))
+	}
+	if l.MaxComplexity > 0 {
+		ms = append(ms, WithMaxQueryComplexity(l.MaxComplexity))
+	}
+	if l.Timeout > 0 {
+		ms = append(ms, Timeout(l.Timeout, l.OperationTimeouts))
+	}
+	if l.StreamMaxItems > 0 || l.StreamTimeout > 0 {
+		ms = append(ms, WithStreamLimits(StreamLimits{
+			MaxItems: l.StreamMaxItems,
+			Timeout:  l.StreamTimeout,
+		}))
+	}
+	if l.MaxBytesFieldSize > 0 {
+		ms = append(ms, WithMaxBytesFieldSize(l.MaxBytesFieldSize))
+	}
+
+	if c.Cache != nil {
+		size := c.Cache.Size
+		if size <= 0 {
+			size = 1000
+		}
+		ms = append(ms, WithResponseCache(ResponseCacheConfig{
+			Cache: NewLRUCache(size),
+			TTL:   c.Cache.TTL,
+		}))
+	}
+
+	if hosts := c.upstreamHosts(); len(hosts) > 0 {
+		ms = append(ms, WithUpstreamHosts(hosts))
+	}
+	return ms
+}
+
+// upstreamHosts returns addresses per service for generated handlers
+func (c *Config) upstreamHosts() map[string]string {
+	hosts := map[string]string{}
+	for service, u := range c.Upstreams {
+		if u.Address != "" {
+			hosts[service] = u.Address
+		}
+	}
+	return hosts
+}
+
+// UpstreamOf returns upstream of the service, or the default upstream if it's not configured per service
+func (c *Config) UpstreamOf(service string) UpstreamConfig {
+	if u, ok := c.Upstreams[service]; ok {
+		if u.Address == "" {
+			u.Address = c.Upstream.Address
+			if u.Protocol == "" {
+				u.Protocol = c.Upstream.Protocol
+			}
+		}
+		return u
+	}
+	return c.Upstream
+}
+
+// Dial creates connection to the upstream of the service with TLS of the config.
+// The address is overridden by environment variable of UpstreamHostEnvPrefix.
+func (c *Config) Dial(ctx context.Context, service string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = opts[:len(opts):len(opts)]
+	u := c.UpstreamOf(service)
+	address := UpstreamHost(ctx, service, u.Address)
+	if address == "" {
+		return nil, fmt.Errorf("upstream of %s is not specified", service)
+	}
+	switch u.Protocol {
+	case "", ProtocolGRPC:
+	case ProtocolConnect, ProtocolTwirp:
+		return u.dialHTTP(address)
+	default:
+		return nil, fmt.Errorf("unknown protocol %q of %s upstream", u.Protocol, service)
+	}
+	if u.TLS == nil {
+		opts = append(opts, grpc.WithInsecure())
+	} else {
+		creds, err := UpstreamTLS(u.TLS.upstreamTLSConfig())
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, creds)
+	}
+	return Dial(ctx, address, opts...)
+}
+
+// dialHTTP creates connection to Connect or Twirp server of the base URL
+func (u UpstreamConfig) dialHTTP(baseURL string) (*grpc.ClientConn, error) {
+	client := http.DefaultClient
+	if u.TLS != nil {
+		c, err := u.TLS.upstreamTLSConfig().TLSConfig()
+		if err != nil {
+			return nil, err
+		}
+		client = &http.Client{
+			Transport: &http.Transport{
+				Proxy:             http.ProxyFromEnvironment,
+				TLSClientConfig:   c,
+				ForceAttemptHTTP2: true,
+			},
+		}
+	}
+
+	if u.Protocol == ProtocolTwirp {
+		base, err := url.Parse(baseURL)
+		if err != nil {
+			return nil, err
+		}
+		config := TwirpConfig{
+			Prefix: base.Path,
+			Client: client,
+		}
+		base.Path = ""
+		config.BaseURL = base.String()
+		return NewTwirpConn(config)
+	}
+	return NewConnectConn(ConnectConfig{
+		BaseURL: baseURL,
+		Client:  client,
+	})
+}
+
+func (c *TLSFileConfig) upstreamTLSConfig() UpstreamTLSConfig {
+	return UpstreamTLSConfig{
+		CAFile:             c.CAFile,
+		CertFile:           c.CertFile,
+		KeyFile:            c.KeyFile,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+}
@@ -0,0 +1,171 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testConfigYAML = `
+descriptor_sets: [image.binpb]
+upstream:
+  address: localhost:50051
+upstreams:
+  greeter.Greeter:
+    address: ${TEST_GREETER_ADDRESS:-greeter:50051}
+  user.UserService:
+    tls:
+      server_name: ${TEST_SERVER_NAME}
+limits:
+  max_depth: 2
+  timeout: 5s
+  operation_timeouts:
+    slow: 1m
+cors:
+  allowed_origins: ["https://app.example.com"]
+  max_age: 10m
+cache:
+  ttl: 30s
+middlewares:
+  introspection: false
+  redact: ["pa$$word"]
+`
+
+func TestLoadConfig(t *testing.T) {
+	setTestEnv(t, "TEST_SERVER_NAME", "user.internal")
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	if err := ioutil.WriteFile(path, []byte(testConfigYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadConfig(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, ":8888", c.Listen)
+	assert.Equal(t, "/graphql", c.Path)
+	assert.Equal(t, []string{"image.binpb"}, c.DescriptorSets)
+	assert.Equal(t, 5*time.Second, c.Limits.Timeout)
+	assert.Equal(t, map[string]time.Duration{"slow": time.Minute}, c.Limits.OperationTimeouts)
+	assert.Equal(t, 10*time.Minute, c.CORS.MaxAge)
+	assert.Equal(t, 30*time.Second, c.Cache.TTL)
+	assert.Equal(t, []string{"pa$word"}, c.Middlewares.Redact)
+
+	assert.Equal(t, "greeter:50051", c.UpstreamOf("greeter.Greeter").Address)
+	user := c.UpstreamOf("user.UserService")
+	assert.Equal(t, "localhost:50051", user.Address)
+	assert.Equal(t, "user.internal", user.TLS.ServerName)
+	assert.Equal(t, "localhost:50051", c.UpstreamOf("other.Service").Address)
+
+	conn, err := c.Dial(context.Background(), "greeter.Greeter")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "greeter:50051", conn.Target())
+		conn.Close() // nolint: errcheck
+	}
+
+	mux := NewServeMux(c.MiddlewareFuncs()...)
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	result := serveTestQuery(t, mux, `{ __schema { queryType { name } } }`)
+	assert.Equal(t, "INTROSPECTION_DISABLED", result.Errors[0].Extensions["code"])
+	result = serveTestQuery(t, mux, `{ user(id: "1") { id } }`)
+	assert.Len(t, result.Errors, 0)
+}
+
+func TestParseConfig(t *testing.T) {
+	t.Run("json", func(t *testing.T) {
+		c, err := parseConfig([]byte(`{"listen": ":9000", "limits": {"max_depth": 3}, "middlewares": {"csrf": true}}`))
+		assert.NoError(t, err)
+		assert.Equal(t, ":9000", c.Listen)
+		assert.Equal(t, 3, c.Limits.MaxDepth)
+		assert.True(t, c.Middlewares.CSRF)
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		_, err := parseConfig([]byte("limits:\n  max_dept: 3\n"))
+		assert.Error(t, err)
+	})
+
+	t.Run("missing environment variable", func(t *testing.T) {
+		_, err := parseConfig([]byte("upstream:\n  address: ${TEST_MISSING_ADDRESS}\n"))
+		assert.EqualError(t, err, "environment variables [TEST_MISSING_ADDRESS] are not set")
+	})
+}
+
+func TestLoadConfigEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gateway.yaml")
+	if err := ioutil.WriteFile(path, []byte(testConfigYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("override file", func(t *testing.T) {
+		setTestEnv(t, "TEST_SERVER_NAME", "user.internal")
+		setTestEnv(t, "GRAPHQL_LISTEN", ":9000")
+		setTestEnv(t, "GRAPHQL_UPSTREAM", "dns:///default:50051")
+		setTestEnv(t, "GRAPHQL_LIMITS_MAX_DEPTH", "5")
+		setTestEnv(t, "GRAPHQL_LIMITS_TIMEOUT", "3s")
+		setTestEnv(t, "GRAPHQL_CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+		setTestEnv(t, "GRAPHQL_MIDDLEWARES_INTROSPECTION", "true")
+		setTestEnv(t, "GRAPHQL_RATE_LIMIT_RATE", "2.5")
+
+		c, err := LoadConfig(path)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, ":9000", c.Listen)
+		assert.Equal(t, "dns:///default:50051", c.UpstreamOf("other.Service").Address)
+		assert.Equal(t, "greeter:50051", c.UpstreamOf("greeter.Greeter").Address)
+		assert.Equal(t, 5, c.Limits.MaxDepth)
+		assert.Equal(t, 3*time.Second, c.Limits.Timeout)
+		assert.Equal(t, []string{"https://a.example.com", "https://b.example.com"}, c.CORS.AllowedOrigins)
+		assert.Equal(t, 10*time.Minute, c.CORS.MaxAge)
+		assert.True(t, *c.Middlewares.Introspection)
+		assert.Equal(t, 2.5, c.RateLimit.Rate)
+	})
+
+	t.Run("without file", func(t *testing.T) {
+		setTestEnv(t, "GRAPHQL_DESCRIPTOR_SETS", "a.binpb,b.binpb")
+		setTestEnv(t, "GRAPHQL_SHUTDOWN_DRAIN_DELAY", "5s")
+
+		c, err := LoadConfig("")
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, ":8888", c.Listen)
+		assert.Equal(t, []string{"a.binpb", "b.binpb"}, c.DescriptorSets)
+		assert.Equal(t, 5*time.Second, c.Shutdown.DrainDelay)
+		assert.Equal(t, 30*time.Second, c.Shutdown.GracePeriod)
+		assert.Nil(t, c.CORS)
+		assert.Nil(t, c.RateLimit)
+	})
+
+	t.Run("invalid value", func(t *testing.T) {
+		setTestEnv(t, "GRAPHQL_LIMITS_MAX_DEPTH", "ten")
+
+		_, err := LoadConfig("")
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "GRAPHQL_LIMITS_MAX_DEPTH")
+	})
+	t.Run("unknown sanitizer", func(t *testing.T) {
+		setTestEnv(t, "GRAPHQL_MIDDLEWARES_SANITIZE", "trim_space,lowercase")
+
+		_, err := LoadConfig("")
+		assert.EqualError(t, err, `unknown sanitizer "lowercase"`)
+	})
+	t.Run("unknown enum policy", func(t *testing.T) {
+		setTestEnv(t, "GRAPHQL_MIDDLEWARES_UNKNOWN_ENUM", "ignore")
+
+		_, err := LoadConfig("")
+		assert.EqualError(t, err, `unknown enum policy "ignore"`)
+	})
+	t.Run("invalid rate limit", func(t *testing.T) {
+		setTestEnv(t, "GRAPHQL_RATE_LIMIT_RATE", "0")
+
+		_, err := LoadConfig("")
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,124 @@
+package runtime
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/grpc"
+)
+
+// ServiceNamer is implemented by generated handlers to describe the source service in diagnostics
+type ServiceNamer interface {
+	ServiceName() string
+}
+
+// handlerName returns service name of the handler, or type name if the handler doesn't implement ServiceNamer
+func handlerName(h GraphqlHandler) string {
+	if n, ok := h.(ServiceNamer); ok {
+		return n.ServiceName()
+	}
+	return fmt.Sprintf("%T", h)
+}
+
+// namespacedHandler renames conflicted root fields of the wrapped handler
+type namespacedHandler struct {
+	GraphqlHandler
+	queries   map[string]string
+	mutations map[string]string
+}
+
+func (h *namespacedHandler) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	return renameFields(h.GraphqlHandler.GetQueries(conn), h.queries)
+}
+
+func (h *namespacedHandler) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	return renameFields(h.GraphqlHandler.GetMutations(conn), h.mutations)
+}
+
+// snapshot keeps renaming of the handler whose schema is swapped at runtime
+func (h *namespacedHandler) snapshot() (GraphqlHandler, string) {
+	v, ok := h.GraphqlHandler.(schemaVersioner)
+	if !ok {
+		return h, ""
+	}
+	inner, version := v.snapshot()
+	return &namespacedHandler{
+		GraphqlHandler: inner,
+		queries:        h.queries,
+		mutations:      h.mutations,
+	}, version
+}
+
+func renameFields(fields graphql.Fields, renames map[string]string) graphql.Fields {
+	for from, to := range renames {
+		if f, ok := fields[from]; ok {
+			delete(fields, from)
+			fields[to] = f
+		}
+	}
+	return fields
+}
+
+// resolveConflicts checks root field name collisions between h and registered handlers.
+// If NamespaceConflicts is enabled, conflicted fields of h are renamed to "<Service>_<field>",
+// otherwise returns an error which names both source services.
+func (s *ServeMux) resolveConflicts(h GraphqlHandler, registered []GraphqlHandler) (GraphqlHandler, error) {
+	queryOwners := map[string]string{}
+	mutationOwners := map[string]string{}
+	for _, r := range registered {
+		for name := range r.GetQueries(nil) {
+			queryOwners[name] = handlerName(r)
+		}
+		for name := range r.GetMutations(nil) {
+			mutationOwners[name] = handlerName(r)
+		}
+	}
+
+	name := handlerName(h)
+	namespaced := &namespacedHandler{
+		GraphqlHandler: h,
+		queries:        map[string]string{},
+		mutations:      map[string]string{},
+	}
+	check := func(kind string, fields graphql.Fields, owners map[string]string, renames map[string]string) error {
+		for field := range fields {
+			owner, ok := owners[field]
+			if !ok {
+				continue
+			}
+			if !s.NamespaceConflicts {
+				return fmt.Errorf("%s field %q of %s conflicts with the field which is already registered by %s", kind, field, name, owner)
+			}
+			renamed := graphqlName(name) + "_" + field
+			if _, ok := owners[renamed]; ok {
+				return fmt.Errorf("%s field %q of %s conflicts with %s, and namespaced name %q is also registered", kind, field, name, owner, renamed)
+			}
+			renames[field] = renamed
+		}
+		return nil
+	}
+	if err := check("query", h.GetQueries(nil), queryOwners, namespaced.queries); err != nil {
+		return nil, err
+	}
+	if err := check("mutation", h.GetMutations(nil), mutationOwners, namespaced.mutations); err != nil {
+		return nil, err
+	}
+	if len(namespaced.queries) == 0 && len(namespaced.mutations) == 0 {
+		return h, nil
+	}
+	return namespaced, nil
+}
+
+// graphqlName converts service name like "greeter.Greeter" to valid GraphQL name "greeter_Greeter"
+func graphqlName(name string) string {
+	out := []rune{}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			out = append(out, r)
+		default:
+			out = append(out, '_')
+		}
+	}
+	return string(out)
+}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type namedTestHandler struct {
+	*testHandler
+	name string
+}
+
+func (h *namedTestHandler) ServiceName() string {
+	return h.name
+}
+
+func TestAddHandlerDetectsConflicts(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&namedTestHandler{newTestHandler(), "users.UserService"}))
+
+	err := mux.AddHandler(&namedTestHandler{newTestHandler(), "accounts.AccountService"})
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "users.UserService")
+		assert.Contains(t, err.Error(), "accounts.AccountService")
+	}
+}
+
+func TestNamespaceConflicts(t *testing.T) {
+	mux := NewServeMux()
+	mux.NamespaceConflicts = true
+	assert.NoError(t, mux.AddHandler(&namedTestHandler{newTestHandler(), "users.UserService"}))
+	assert.NoError(t, mux.AddHandler(&namedTestHandler{newTestHandler(), "accounts.AccountService"}))
+
+	result := serveTestQuery(t, mux, `{ hello accounts_AccountService_hello }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"hello":                         "world",
+		"accounts_AccountService_hello": "world",
+	}, result.Data)
+
+	result = serveTestQuery(t, mux, `mutation { accounts_AccountService_login(password: "x") }`)
+	assert.Len(t, result.Errors, 0)
+}
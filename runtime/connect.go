@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// ConnectConfig is upstream of Connect protocol (https://connectrpc.com/docs/protocol)
+type ConnectConfig struct {
+	// Base URL of the server like "https://users.internal:8443", RPCs are sent to "<BaseURL>/<package.Service>/<Method>"
+	BaseURL string
+	// HTTP client to send requests, default is http.DefaultClient which uses HTTP/2 for TLS servers and HTTP/1.1 otherwise.
+	// Use HTTP/2 transport of golang.org/x/net/http2 for servers which accept only h2c.
+	Client *http.Client
+}
+
+// connectTrailerPrefix is prefix of response headers which are trailers in Connect unary protocol
+const connectTrailerPrefix = "trailer-"
+
+// NewConnectConn returns gRPC connection which calls unary RPCs of Connect server in binary protobuf,
+// so that generated handlers and DynamicHandler talk to services which only expose Connect protocol.
+// Pass the connection to Register*GraphqlHandler or NewDynamicHandlers like gRPC connection.
+//
+// Metadata and deadline are sent as Connect headers, and Connect errors are converted to gRPC status errors.
+// Streaming RPCs are not supported.
+func NewConnectConn(config ConnectConfig) (*grpc.ClientConn, error) {
+	if config.BaseURL == "" {
+		return nil, errors.New("base URL of Connect upstream is not specified")
+	}
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	baseURL := strings.TrimSuffix(config.BaseURL, "/")
+
+	return newUnaryProxyConn(func(ctx context.Context, method string, md metadata.MD, body []byte) (metadata.MD, metadata.MD, []byte, error) {
+		req, err := http.NewRequest(http.MethodPost, baseURL+method, bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		req = req.WithContext(ctx)
+		proxyHeaders(req.Header, md)
+		req.Header.Set("Content-Type", "application/proto")
+		req.Header.Set("Connect-Protocol-Version", "1")
+		if deadline, ok := ctx.Deadline(); ok {
+			req.Header.Set("Connect-Timeout-Ms", strconv.FormatInt(int64(time.Until(deadline)/time.Millisecond), 10))
+		}
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, nil, proxyTransportError(ctx, err)
+		}
+		defer resp.Body.Close()
+		out, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, nil, proxyTransportError(ctx, err)
+		}
+
+		header, trailer := proxyMetadata(resp.Header, connectTrailerPrefix)
+		if resp.StatusCode != http.StatusOK {
+			return header, trailer, nil, connectError(resp.StatusCode, out)
+		}
+		return header, trailer, out, nil
+	})
+}
+
+// connectError converts error response of Connect unary protocol
+func connectError(statusCode int, body []byte) error {
+	var e struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil || e.Code == "" {
+		return proxyError("", fmt.Sprintf("Connect upstream responds HTTP %d", statusCode), connectHTTPCode(statusCode))
+	}
+	return proxyError(e.Code, e.Message, connectHTTPCode(statusCode))
+}
+
+// connectHTTPCode maps HTTP status which is not responded by Connect server, e.g. from proxies
+func connectHTTPCode(statusCode int) codes.Code {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return codes.Internal
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.Unimplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return codes.Unavailable
+	default:
+		return codes.Unknown
+	}
+}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestConnectConn(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/echo.Echo/Echo":
+			assert.Equal(t, "application/proto", r.Header.Get("Content-Type"))
+			assert.Equal(t, "1", r.Header.Get("Connect-Protocol-Version"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			assert.Equal(t, "AQI", r.Header.Get("Trace-Bin"))
+			assert.NotEmpty(t, r.Header.Get("Connect-Timeout-Ms"))
+
+			body, _ := ioutil.ReadAll(r.Body) // nolint: errcheck
+			in := &wrapperspb.StringValue{}
+			assert.NoError(t, proto.Unmarshal(body, in))
+			out, _ := proto.Marshal(&wrapperspb.StringValue{Value: "echo " + in.Value}) // nolint: errcheck
+			w.Header().Set("Content-Type", "application/proto")
+			w.Header().Set("X-Request-Id", "abc")
+			w.Header().Set("Trailer-X-Cost", "3")
+			w.Write(out) // nolint: errcheck
+		case "/echo.Echo/Fail":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"code":"not_found","message":"no such echo"}`)) // nolint: errcheck
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	conn, err := NewConnectConn(ConnectConfig{BaseURL: server.URL + "/"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer token", "trace-bin", string([]byte{1, 2}))
+
+	t.Run("unary", func(t *testing.T) {
+		var header, trailer metadata.MD
+		out := &wrapperspb.StringValue{}
+		err := conn.Invoke(ctx, "/echo.Echo/Echo", &wrapperspb.StringValue{Value: "hello"}, out, grpc.Header(&header), grpc.Trailer(&trailer))
+		assert.NoError(t, err)
+		assert.Equal(t, "echo hello", out.Value)
+		assert.Equal(t, []string{"abc"}, header.Get("x-request-id"))
+		assert.Equal(t, []string{"3"}, trailer.Get("x-cost"))
+	})
+
+	t.Run("connect error", func(t *testing.T) {
+		err := conn.Invoke(ctx, "/echo.Echo/Fail", &wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+		assert.Equal(t, "no such echo", status.Convert(err).Message())
+	})
+
+	t.Run("http error", func(t *testing.T) {
+		err := conn.Invoke(ctx, "/echo.Echo/Unknown", &wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+		assert.Equal(t, codes.Unavailable, status.Code(err))
+	})
+}
+
+func TestConfigDialConnect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		out, _ := proto.Marshal(&wrapperspb.StringValue{Value: r.URL.Path}) // nolint: errcheck
+		w.Write(out)                                                        // nolint: errcheck
+	}))
+	defer server.Close()
+
+	c, err := parseConfig([]byte("upstreams:\n  echo.Echo:\n    address: " + server.URL + "\n    protocol: connect\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := c.Dial(context.Background(), "echo.Echo")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	out := &wrapperspb.StringValue{}
+	assert.NoError(t, conn.Invoke(context.Background(), "/echo.Echo/Echo", &wrapperspb.StringValue{}, out))
+	assert.Equal(t, "/echo.Echo/Echo", out.Value)
+
+	c.Upstream.Protocol = "soap"
+	c.Upstream.Address = "localhost:50051"
+	_, err = c.Dial(context.Background(), "other.Service")
+	assert.EqualError(t, err, `unknown protocol "soap" of other.Service upstream`)
+}
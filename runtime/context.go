@@ -12,6 +12,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/graphql-go/graphql"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/grpclog"
 	"google.golang.org/grpc/metadata"
@@ -82,6 +83,10 @@ func AnnotateContext(ctx context.Context, mux *ServeMux, req *http.Request, rpcM
 	if md == nil {
 		return ctx, nil
 	}
+	// Join metadata which is already set by preceding middlewares like WithMetadata or APIKey
+	if outgoing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(outgoing, md)
+	}
 
 	return metadata.NewOutgoingContext(ctx, md), nil
 }
@@ -96,6 +101,9 @@ func AnnotateIncomingContext(ctx context.Context, mux *ServeMux, req *http.Reque
 	if md == nil {
 		return ctx, nil
 	}
+	if incoming, ok := metadata.FromIncomingContext(ctx); ok {
+		md = metadata.Join(incoming, md)
+	}
 
 	return metadata.NewIncomingContext(ctx, md), nil
 }
@@ -146,6 +154,7 @@ func annotateContext(ctx context.Context, mux *ServeMux, req *http.Request, rpcM
 		}
 	}
 	var pairs []string
+	matcher := withoutIncomingCookieMetadata(ctx, mux.incomingMatcher())
 	for key, vals := range req.Header {
 		key = textproto.CanonicalMIMEHeaderKey(key)
 		for _, val := range vals {
@@ -153,7 +162,7 @@ func annotateContext(ctx context.Context, mux *ServeMux, req *http.Request, rpcM
 			if key == "Authorization" {
 				pairs = append(pairs, "authorization", val)
 			}
-			if h, ok := mux.incomingHeaderMatcher(key); ok {
+			if h, ok := matcher(key); ok {
 				if !isValidGRPCMetadataKey(h) {
 					grpclog.Errorf("HTTP header name %q is not valid as gRPC metadata key; skipping", h)
 					continue
@@ -192,17 +201,18 @@ func annotateContext(ctx context.Context, mux *ServeMux, req *http.Request, rpcM
 	}
 
 	if timeout != 0 {
-		//nolint:govet  // The context outlives this function
-		ctx, _ = context.WithTimeout(ctx, timeout)
+		// The context outlives this function, so the timeout is released when the request is finished,
+		// or by its deadline if the context is not derived from ServeMux request
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		onFinish(ctx, func(*graphql.Result) {
+			cancel()
+		})
 	}
 	if len(pairs) == 0 {
 		return ctx, nil, nil
 	}
-	md := metadata.Pairs(pairs...)
-	for _, mda := range mux.metadataAnnotators {
-		md = metadata.Join(md, mda(ctx, req))
-	}
-	return ctx, md, nil
+	return ctx, metadata.Pairs(pairs...), nil
 }
 
 // ServerMetadata consists of metadata sent from gRPC server.
@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAnnotateContextTimeout(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.Header.Set("Grpc-Timeout", "10S")
+	ctx, err := AnnotateContext(context.Background(), NewServeMux(), r, "hello")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, ok := ctx.Deadline()
+	assert.True(t, ok)
+
+	r.Header.Set("Grpc-Timeout", "invalid")
+	_, err = AnnotateContext(context.Background(), NewServeMux(), r, "hello")
+	assert.Error(t, err)
+}
+
+func TestAnnotateContextJoinsMetadata(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.Header.Set("Grpc-Metadata-X-Client", "web")
+
+	// Metadata set by preceding middlewares like WithMetadata is kept
+	ctx := metadata.NewOutgoingContext(context.Background(), metadata.Pairs("x-api-key-id", "key-1"))
+	ctx, err := AnnotateContext(ctx, NewServeMux(), r, "hello")
+	if assert.NoError(t, err) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		assert.Equal(t, []string{"key-1"}, md.Get("x-api-key-id"))
+		assert.Equal(t, []string{"web"}, md.Get("x-client"))
+	}
+
+	ctx = metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key-id", "key-1"))
+	ctx, err = AnnotateIncomingContext(ctx, NewServeMux(), r, "hello")
+	if assert.NoError(t, err) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		assert.Equal(t, []string{"key-1"}, md.Get("x-api-key-id"))
+		assert.Equal(t, []string{"web"}, md.Get("x-client"))
+	}
+}
+
+func TestAnnotateContextReleasesTimeout(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.Header.Set("Grpc-Timeout", "10S")
+	scoped := withRequestScope(context.Background())
+	ctx, err := AnnotateContext(scoped, NewServeMux(), r, "hello")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, ctx.Err())
+
+	// The timeout is released when the request is finished
+	finishRequest(scoped, nil)
+	assert.Equal(t, context.Canceled, ctx.Err())
+}
@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/grpc/metadata"
+)
+
+// CookieMetadataConfig is configuration for CookieMetadata middleware
+type CookieMetadataConfig struct {
+	// Incoming maps request cookie name to gRPC metadata key which is sent to upstream,
+	// e.g. {"session_id": "x-session-id"}
+	Incoming map[string]string
+	// Outgoing maps upstream response metadata key to the cookie which is set to the HTTP response.
+	// Cookie value is taken from the metadata, and other attributes like Path or HttpOnly are used as they are.
+	// e.g. {"x-session-id": {Name: "session_id", Path: "/", HttpOnly: true}}
+	Outgoing map[string]http.Cookie
+}
+
+type cookieMetadataKey struct{}
+
+// cookieMetadataKeys are lower cased metadata keys which are mapped from and to cookies
+type cookieMetadataKeys struct {
+	incoming map[string]struct{}
+	outgoing map[string]struct{}
+}
+
+// CookieMetadata is middleware function to forward named cookies as gRPC metadata,
+// and to respond Set-Cookie headers from upstream response metadata.
+// Metadata keys which are mapped to cookies are excluded from generic header forwarding,
+// so that clients can't override them by Grpc-Metadata- headers, and cookies like HttpOnly sessions don't leak to response headers.
+func CookieMetadata(config CookieMetadataConfig) MiddlewareFunc {
+	keys := cookieMetadataKeys{
+		incoming: make(map[string]struct{}),
+		outgoing: make(map[string]struct{}),
+	}
+	for _, key := range config.Incoming {
+		keys.incoming[strings.ToLower(key)] = struct{}{}
+	}
+	for key := range config.Outgoing {
+		keys.outgoing[strings.ToLower(key)] = struct{}{}
+	}
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		ctx = context.WithValue(ctx, cookieMetadataKey{}, keys)
+		// Drop metadata which is forwarded from headers by preceding middlewares like AnnotateContext
+		if outgoing, ok := metadata.FromOutgoingContext(ctx); ok {
+			outgoing = outgoing.Copy()
+			for key := range keys.incoming {
+				delete(outgoing, key)
+			}
+			ctx = metadata.NewOutgoingContext(ctx, outgoing)
+		}
+		md := metadata.MD{}
+		for name, key := range config.Incoming {
+			if c, err := r.Cookie(name); err == nil {
+				md.Append(key, c.Value)
+			}
+		}
+		ctx = appendOutgoingMetadata(ctx, md)
+
+		if len(config.Outgoing) > 0 {
+			onFinish(ctx, func(result *graphql.Result) {
+				md := responseMetadata(ctx)
+				for key, cookie := range config.Outgoing {
+					for _, v := range md.Get(key) {
+						c := cookie
+						c.Value = v
+						http.SetCookie(w, &c)
+					}
+				}
+			})
+		}
+		return ctx, nil
+	}
+}
+
+// withoutIncomingCookieMetadata wraps the matcher of request headers to drop metadata keys which are mapped from cookies
+func withoutIncomingCookieMetadata(ctx context.Context, matcher HeaderMatcherFunc) HeaderMatcherFunc {
+	keys, ok := ctx.Value(cookieMetadataKey{}).(cookieMetadataKeys)
+	if !ok || len(keys.incoming) == 0 {
+		return matcher
+	}
+	return func(key string) (string, bool) {
+		h, ok := matcher(key)
+		if _, mapped := keys.incoming[strings.ToLower(h)]; mapped {
+			return "", false
+		}
+		return h, ok
+	}
+}
+
+// withoutOutgoingCookieMetadata wraps the matcher of response metadata to drop metadata keys which are mapped to cookies
+func withoutOutgoingCookieMetadata(ctx context.Context, matcher HeaderMatcherFunc) HeaderMatcherFunc {
+	keys, ok := ctx.Value(cookieMetadataKey{}).(cookieMetadataKeys)
+	if !ok || len(keys.outgoing) == 0 {
+		return matcher
+	}
+	return func(key string) (string, bool) {
+		if _, mapped := keys.outgoing[strings.ToLower(key)]; mapped {
+			return "", false
+		}
+		return matcher(key)
+	}
+}
@@ -0,0 +1,76 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCookieMetadata(t *testing.T) {
+	var sessionID []string
+	h := newTestHandler()
+	h.mutations["refresh"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			md, _ := metadata.FromOutgoingContext(p.Context)
+			sessionID = md.Get("x-session-id")
+			// Emulate gRPC call which responds new session in header
+			for _, o := range CallOptions(p.Context) {
+				if v, ok := o.(grpc.HeaderCallOption); ok {
+					*v.HeaderAddr = metadata.Pairs("x-session-id", "new-session")
+				}
+			}
+			return "ok", nil
+		},
+	}
+	mux := NewServeMux(func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return AnnotateContext(ctx, serveMux, r, "refresh")
+	}, CookieMetadata(CookieMetadataConfig{
+		Incoming: map[string]string{
+			"session_id": "x-session-id",
+		},
+		Outgoing: map[string]http.Cookie{
+			"x-session-id": {Name: "session_id", Path: "/", HttpOnly: true},
+		},
+	}))
+	assert.NoError(t, mux.AddHandler(h))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`mutation { refresh }`))
+	r.AddCookie(&http.Cookie{Name: "session_id", Value: "old-session"})
+	r.AddCookie(&http.Cookie{Name: "other", Value: "ignored"})
+	// Mapped metadata can't be overridden by headers
+	r.Header.Set("Grpc-Metadata-X-Session-Id", "forged-session")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	assert.Equal(t, []string{"old-session"}, sessionID)
+	assert.Equal(t, []string{"session_id=new-session; Path=/; HttpOnly"}, w.Header()["Set-Cookie"])
+	// HttpOnly cookie is not exposed as response header
+	assert.Empty(t, w.Header().Get("Grpc-Metadata-X-Session-Id"))
+}
+
+func TestCookieMetadataExcludesHeaders(t *testing.T) {
+	m := CookieMetadata(CookieMetadataConfig{
+		Incoming: map[string]string{"session_id": "x-session-id"},
+	})
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.Header.Set("Grpc-Metadata-X-Session-Id", "forged-session")
+	r.Header.Set("Grpc-Metadata-X-Client", "web")
+	ctx, err := m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+	assert.NoError(t, err)
+
+	// Headers are not forwarded to metadata keys of cookies even if AnnotateContext follows
+	ctx, err = AnnotateContext(ctx, NewServeMux(), r, "refresh")
+	assert.NoError(t, err)
+	md, _ := metadata.FromOutgoingContext(ctx)
+	assert.Empty(t, md.Get("x-session-id"))
+	assert.Equal(t, []string{"web"}, md.Get("x-client"))
+}
@@ -0,0 +1,106 @@
+package runtime
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"net/http"
+)
+
+// DefaultCorsHeaders are request headers which are allowed by default in CORS preflight
+var DefaultCorsHeaders = []string{"Content-Type", "Authorization", "X-Requested-With", "Apollo-Require-Preflight"}
+
+// CorsConfig is configuration for Cors middleware
+type CorsConfig struct {
+	// Allowed origins like "https://app.example.com".
+	// "*" allows any origin, and wildcard like "https://*.example.com" matches subdomains.
+	// If AllowedOrigins, AllowedOriginPatterns and OriginFunc are all empty, no origin is allowed.
+	AllowedOrigins []string
+	// Regular expressions which allowed origins match
+	AllowedOriginPatterns []*regexp.Regexp
+	// OriginFunc reports whether the origin is allowed, which is called if the origin doesn't match above
+	OriginFunc func(origin string) bool
+	// Request headers which are allowed in preflight, default is DefaultCorsHeaders
+	AllowedHeaders []string
+	// Response headers which browsers expose to the client like "Grpc-Metadata-Request-Id"
+	ExposedHeaders []string
+	// If true, browsers send cookies and authorization headers
+	AllowCredentials bool
+	// Duration that browsers cache preflight result, not sent if zero
+	MaxAge time.Duration
+}
+
+// Cors is middleware function to provide CORS headers to response headers.
+// Preflight request is responded by this middleware with 204 No Content.
+func Cors(config CorsConfig) MiddlewareFunc {
+	if len(config.AllowedHeaders) == 0 {
+		config.AllowedHeaders = DefaultCorsHeaders
+	}
+	allowedHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposedHeaders := strings.Join(config.ExposedHeaders, ", ")
+
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+		origin := r.Header.Get("Origin")
+		w.Header().Add("Vary", "Origin")
+
+		if origin != "" && config.allows(origin) {
+			// Wildcard can't be used with credentials, so reflect the origin in that case
+			if !config.AllowCredentials && containsString(config.AllowedOrigins, "*") {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if config.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			if preflight {
+				w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+				if config.MaxAge > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(config.MaxAge.Seconds())))
+				}
+			} else if exposedHeaders != "" {
+				w.Header().Set("Access-Control-Expose-Headers", exposedHeaders)
+			}
+		}
+
+		if preflight {
+			w.WriteHeader(http.StatusNoContent)
+			return ctx, errResponded
+		}
+		return ctx, nil
+	}
+}
+
+// allows reports whether the origin is allowed
+func (c CorsConfig) allows(origin string) bool {
+	for _, allowed := range c.AllowedOrigins {
+		if matchOrigin(allowed, origin) {
+			return true
+		}
+	}
+	for _, p := range c.AllowedOriginPatterns {
+		if p.MatchString(origin) {
+			return true
+		}
+	}
+	return c.OriginFunc != nil && c.OriginFunc(origin)
+}
+
+// matchOrigin matches the origin to allowed origin which may contain a wildcard
+func matchOrigin(allowed, origin string) bool {
+	if allowed == "*" {
+		return true
+	}
+	i := strings.Index(allowed, "*")
+	if i < 0 {
+		return strings.EqualFold(allowed, origin)
+	}
+	prefix, suffix := strings.ToLower(allowed[:i]), strings.ToLower(allowed[i+1:])
+	origin = strings.ToLower(origin)
+	return len(origin) > len(prefix)+len(suffix) && strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix)
+}
@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		allowed string
+		origin  string
+		expect  bool
+	}{
+		{allowed: "*", origin: "https://example.com", expect: true},
+		{allowed: "https://example.com", origin: "https://EXAMPLE.com", expect: true},
+		{allowed: "https://example.com", origin: "https://example.com.evil.com", expect: false},
+		{allowed: "https://*.example.com", origin: "https://app.example.com", expect: true},
+		{allowed: "https://*.example.com", origin: "https://example.com", expect: false},
+		{allowed: "https://*.example.com", origin: "https://app.evil.com", expect: false},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.expect, matchOrigin(tt.allowed, tt.origin), tt.allowed+" "+tt.origin)
+	}
+}
+
+func TestCors(t *testing.T) {
+	mux := NewServeMux(Cors(CorsConfig{
+		AllowedOrigins:        []string{"https://app.example.com"},
+		AllowedOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^https://pr-\d+\.preview\.example\.com$`)},
+		ExposedHeaders:        []string{SchemaVersionHeader},
+		AllowCredentials:      true,
+		MaxAge:                10 * time.Minute,
+	}))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	t.Run("preflight", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodOptions, "/graphql", nil)
+		r.Header.Set("Origin", "https://pr-12.preview.example.com")
+		r.Header.Set("Access-Control-Request-Method", "POST")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, 0, w.Body.Len())
+		assert.Equal(t, "https://pr-12.preview.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, "true", w.Header().Get("Access-Control-Allow-Credentials"))
+		assert.Equal(t, "GET, POST, OPTIONS", w.Header().Get("Access-Control-Allow-Methods"))
+		assert.Equal(t, strings.Join(DefaultCorsHeaders, ", "), w.Header().Get("Access-Control-Allow-Headers"))
+		assert.Equal(t, "600", w.Header().Get("Access-Control-Max-Age"))
+	})
+
+	t.Run("actual request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ hello }`))
+		r.Header.Set("Origin", "https://app.example.com")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"hello":"world"`)
+		assert.Equal(t, "https://app.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Equal(t, SchemaVersionHeader, w.Header().Get("Access-Control-Expose-Headers"))
+		assert.Equal(t, "Origin", w.Header().Get("Vary"))
+	})
+
+	t.Run("disallowed origin", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodOptions, "/graphql", nil)
+		r.Header.Set("Origin", "https://evil.example.com")
+		r.Header.Set("Access-Control-Request-Method", "POST")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+}
+
+func TestCorsWildcard(t *testing.T) {
+	mux := NewServeMux(Cors(CorsConfig{
+		AllowedOrigins: []string{"*"},
+	}))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ hello }`))
+	r.Header.Set("Origin", "https://any.example.com")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Equal(t, "*", w.Header().Get("Access-Control-Allow-Origin"))
+	assert.Empty(t, w.Header().Get("Access-Control-Allow-Credentials"))
+}
@@ -0,0 +1,119 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"strings"
+
+	"net/http"
+	"net/url"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// DefaultCSRFHeaders are request headers which prove that the browser sent preflight request
+var DefaultCSRFHeaders = []string{"X-Requested-With", "Apollo-Require-Preflight", "X-Apollo-Operation-Name"}
+
+// CSRFConfig is configuration for CSRFProtection middleware
+type CSRFConfig struct {
+	// Request must have one of these headers unless it is same-origin or preflighted, default is DefaultCSRFHeaders
+	Headers []string
+	// Origins which are trusted like same-origin, e.g. "https://app.example.com"
+	TrustedOrigins []string
+	// Mutation operation names which are exempted from the check, e.g. the mutation which is called from HTML form
+	ExemptOperations []string
+}
+
+type csrfKey struct{}
+
+// CSRFProtection is middleware function to block mutations of the request which browsers can send cross-site without preflight.
+// It follows common GraphQL CSRF prevention practice, the request is allowed if any of:
+//   - Content-Type is not one of simple types which HTML form can send, so browsers send preflight request
+//   - the request has one of the configured headers which can't be set without preflight
+//   - Origin or Sec-Fetch-Site header tells the request is same-origin, or Origin is trusted
+//
+// Queries are always allowed because they must not have side effects.
+func CSRFProtection(config CSRFConfig) MiddlewareFunc {
+	if len(config.Headers) == 0 {
+		config.Headers = DefaultCSRFHeaders
+	}
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		if isCSRFSafe(r, config) {
+			return ctx, nil
+		}
+		return context.WithValue(ctx, csrfKey{}, config), nil
+	}
+}
+
+func isCSRFSafe(r *http.Request, config CSRFConfig) bool {
+	if r.Method == http.MethodPost && !isSimpleContentType(r.Header.Get("Content-Type")) {
+		return true
+	}
+	for _, h := range config.Headers {
+		if r.Header.Get(h) != "" {
+			return true
+		}
+	}
+	if r.Header.Get("Sec-Fetch-Site") == "same-origin" {
+		return true
+	}
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return false
+	}
+	for _, trusted := range config.TrustedOrigins {
+		if strings.EqualFold(origin, trusted) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// isSimpleContentType reports whether browsers can send the content type cross-site without preflight
+func isSimpleContentType(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return true
+	}
+	switch mediaType {
+	case "text/plain", "application/x-www-form-urlencoded", "multipart/form-data":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkCSRF blocks mutation operations if CSRFProtection middleware marks the request as unsafe.
+// Note that the check is applied to all mutations in the document regardless of operationName.
+func checkCSRF(ctx context.Context, doc *ast.Document) []GraphqlError {
+	config, ok := ctx.Value(csrfKey{}).(CSRFConfig)
+	if !ok {
+		return nil
+	}
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok || op.Operation != ast.OperationTypeMutation {
+			continue
+		}
+		if op.Name != nil && containsString(config.ExemptOperations, op.Name.Value) {
+			continue
+		}
+		return []GraphqlError{
+			{
+				Message: fmt.Sprintf(
+					"mutation is blocked to prevent CSRF, send the request with Content-Type: application/json or one of %s headers",
+					strings.Join(config.Headers, ", "),
+				),
+				Extensions: map[string]interface{}{
+					"code": "CSRF_BLOCKED",
+				},
+			},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCSRFProtection(t *testing.T) {
+	mux := NewServeMux(CSRFProtection(CSRFConfig{
+		TrustedOrigins:   []string{"https://app.example.com"},
+		ExemptOperations: []string{"FormLogin"},
+	}))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	serve := func(query string, header http.Header) *graphql.Result {
+		r := httptest.NewRequest(http.MethodPost, "http://api.example.com/graphql", strings.NewReader(query))
+		for k, v := range header {
+			r.Header[k] = v
+		}
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var result graphql.Result
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		return &result
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		header  http.Header
+		blocked bool
+	}{
+		{name: "simple request mutation", query: `mutation { login(password: "p") }`, blocked: true},
+		{name: "form content type", query: `mutation { login(password: "p") }`, header: http.Header{"Content-Type": {"text/plain; charset=utf-8"}}, blocked: true},
+		{name: "cross-site origin", query: `mutation { login(password: "p") }`, header: http.Header{"Origin": {"https://evil.example.com"}}, blocked: true},
+		{name: "simple request query", query: `{ hello }`},
+		{name: "json content type", query: `mutation { login(password: "p") }`, header: http.Header{"Content-Type": {"application/json"}}},
+		{name: "custom header", query: `mutation { login(password: "p") }`, header: http.Header{"X-Requested-With": {"XMLHttpRequest"}}},
+		{name: "same origin", query: `mutation { login(password: "p") }`, header: http.Header{"Origin": {"http://api.example.com"}}},
+		{name: "fetch metadata", query: `mutation { login(password: "p") }`, header: http.Header{"Sec-Fetch-Site": {"same-origin"}}},
+		{name: "trusted origin", query: `mutation { login(password: "p") }`, header: http.Header{"Origin": {"https://app.example.com"}}},
+		{name: "exempt operation", query: `mutation FormLogin { login(password: "p") }`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := serve(tt.query, tt.header)
+			if tt.blocked {
+				assert.Nil(t, result.Data)
+				if assert.Len(t, result.Errors, 1) {
+					assert.Equal(t, "CSRF_BLOCKED", result.Errors[0].Extensions["code"])
+				}
+				return
+			}
+			assert.Len(t, result.Errors, 0)
+		})
+	}
+}
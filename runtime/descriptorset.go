@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"io/ioutil"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// NewDescriptorSetHandlers reads compiled descriptor set file and creates DynamicHandler for each service in the file,
+// so that the gateway can be configured with descriptors at deploy time instead of generating code.
+// The file is built by "buf build -o image.bin" or "protoc --include_imports --descriptor_set_out=image.bin",
+// and file which has ".json" extension is read as JSON encoded descriptor set like "buf build -o image.json".
+func NewDescriptorSetHandlers(conn *grpc.ClientConn, path string) ([]*DynamicHandler, error) {
+	services, err := LoadDescriptorSet(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewDynamicHandlers(conn, services...)
+}
+
+// LoadDescriptorSet reads compiled descriptor set file and returns services which are declared in the file.
+// Imports which are not included in the file are resolved from descriptors linked into the binary like well-known types.
+func LoadDescriptorSet(path string) ([]protoreflect.ServiceDescriptor, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if strings.HasSuffix(path, ".json") {
+		err = protojson.Unmarshal(b, set)
+	} else {
+		err = proto.Unmarshal(b, set)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse descriptor set %s: %w", path, err)
+	}
+	return descriptorSetServices(set)
+}
+
+func descriptorSetServices(set *descriptorpb.FileDescriptorSet) ([]protoreflect.ServiceDescriptor, error) {
+	files, err := newDynamicFiles(set.GetFile())
+	if err != nil {
+		return nil, err
+	}
+
+	var services []protoreflect.ServiceDescriptor
+	files.RangeFiles(func(fd protoreflect.FileDescriptor) bool {
+		for i := 0; i < fd.Services().Len(); i++ {
+			if sd := fd.Services().Get(i); !isInternalService(string(sd.FullName())) {
+				services = append(services, sd)
+			}
+		}
+		return true
+	})
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].FullName() < services[j].FullName()
+	})
+	return services, nil
+}
@@ -0,0 +1,69 @@
+package runtime
+
+import (
+	"testing"
+
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func TestLoadDescriptorSet(t *testing.T) {
+	fd := &descriptorpb.FileDescriptorProto{}
+	if err := prototext.Unmarshal([]byte(testBookFile), fd); err != nil {
+		t.Fatal(err)
+	}
+	set := &descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{fd},
+	}
+	binary, err := proto.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonSet, err := protojson.Marshal(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	files := map[string][]byte{
+		"image.bin":  binary,
+		"image.json": jsonSet,
+	}
+	for name, b := range files {
+		path := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(path, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		t.Run(name, func(t *testing.T) {
+			handlers, err := NewDescriptorSetHandlers(nil, path)
+			assert.NoError(t, err)
+			assert.Len(t, handlers, 1)
+			assert.Equal(t, "library.v1.LibraryService", handlers[0].ServiceName())
+			assert.Contains(t, handlers[0].GetQueries(nil), "books")
+			assert.Contains(t, handlers[0].GetMutations(nil), "addBook")
+		})
+	}
+
+	t.Run("unresolvable import", func(t *testing.T) {
+		broken := proto.Clone(fd).(*descriptorpb.FileDescriptorProto)
+		broken.Dependency = append(broken.Dependency, "missing.proto")
+		b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+			File: []*descriptorpb.FileDescriptorProto{broken},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(dir, "broken.bin")
+		if err := ioutil.WriteFile(path, b, 0600); err != nil {
+			t.Fatal(err)
+		}
+		_, err = LoadDescriptorSet(path)
+		assert.Error(t, err)
+	})
+}
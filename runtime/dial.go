@@ -0,0 +1,38 @@
+package runtime
+
+import (
+	"context"
+
+	"net/http"
+
+	"google.golang.org/grpc"
+)
+
+type dialOptionsKey struct{}
+
+// WithDialOptions is middleware function to add gRPC dial options to automatic connections of generated handlers,
+// e.g. grpc.WithDefaultServiceConfig for retry and load balancing policy which overrides service_config option.
+// Connections which are passed to Register*GraphqlHandler are used as they are.
+//
+// Generated handlers dial the host per request without connection, so register them with long-lived connection
+// for xds:/// targets which resolve endpoints via xDS management server:
+//
+//	import _ "google.golang.org/grpc/xds" // registers xds resolver and balancers
+//
+//	conn, err := grpc.Dial("xds:///greeter", grpc.WithInsecure())
+//	...
+//	RegisterGreeterGraphqlHandler(mux, conn)
+func WithDialOptions(opts ...grpc.DialOption) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		// Join to the options of preceding middleware, e.g. tenant specific options
+		joined := append(append([]grpc.DialOption{}, DialOptions(ctx)...), opts...)
+		return context.WithValue(ctx, dialOptionsKey{}, joined), nil
+	}
+}
+
+// DialOptions returns gRPC dial options which are added by WithDialOptions middleware.
+// DialUpstream adds these options to automatic connections of generated handlers.
+func DialOptions(ctx context.Context) []grpc.DialOption {
+	opts, _ := ctx.Value(dialOptionsKey{}).([]grpc.DialOption) // nolint: errcheck
+	return opts
+}
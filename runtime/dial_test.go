@@ -0,0 +1,28 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestWithDialOptions(t *testing.T) {
+	ctx := context.Background()
+	assert.Len(t, DialOptions(ctx), 0)
+
+	r := httptest.NewRequest("POST", "/graphql", nil)
+	first := WithDialOptions(grpc.WithInsecure())
+	second := WithDialOptions(grpc.WithDefaultServiceConfig(`{"loadBalancingConfig":[{"round_robin":{}}]}`))
+
+	for i := 0; i < 2; i++ {
+		c, err := first(ctx, nil, nil, r)
+		assert.NoError(t, err)
+		c, err = second(c, nil, nil, r)
+		assert.NoError(t, err)
+		assert.Len(t, DialOptions(c), 2)
+	}
+}
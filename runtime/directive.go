@@ -0,0 +1,187 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	gqldirective "github.com/ysugimoto/grpc-graphql-gateway/internal/directive"
+)
+
+// DirectiveHandler wraps resolution of the field which the directive is applied to.
+// args are coerced arguments of the directive, and next resolves the field,
+// e.g. handler of @uppercase calls next and transforms the value.
+type DirectiveHandler func(p graphql.ResolveParams, args map[string]interface{}, next graphql.FieldResolveFn) (interface{}, error)
+
+type directive struct {
+	definition *graphql.Directive
+	handler    DirectiveHandler
+}
+
+type directivesKey struct{}
+
+// RegisterDirective registers handler of the directive, which is applied to fields in queries like `{ user { name @uppercase } }`,
+// and to fields of messages which declare it by directives of graphql.field option. args declares arguments of the directive.
+// Directives are applied in order of declarations then queries, the latter one wraps the former.
+func (s *ServeMux) RegisterDirective(name string, args graphql.FieldConfigArgument, handler DirectiveHandler) error {
+	if s.built {
+		return ErrServeMuxBuilt
+	}
+	if !namePattern.MatchString(name) {
+		return errors.New("invalid directive name: '" + name + "'")
+	}
+	if handler == nil {
+		return errors.New("handler of directive " + name + " is nil")
+	}
+	for _, d := range graphql.SpecifiedDirectives {
+		if d.Name == name {
+			return errors.New("builtin directive " + name + " cannot be registered")
+		}
+	}
+	if _, ok := s.directives[name]; ok {
+		return errors.New("directive " + name + " is already registered")
+	}
+	definition := graphql.NewDirective(graphql.DirectiveConfig{
+		Name:      name,
+		Locations: []string{graphql.DirectiveLocationField, graphql.DirectiveLocationFieldDefinition},
+		Args:      args,
+	})
+	// NewDirective returns unnamed directive when arguments are invalid
+	if definition.Name == "" {
+		return errors.New("invalid arguments of directive " + name)
+	}
+	if s.directives == nil {
+		s.directives = make(map[string]*directive)
+	}
+	s.directives[name] = &directive{
+		definition: definition,
+		handler:    handler,
+	}
+	return nil
+}
+
+// directiveDefinitions returns definitions of registered directives which are declared in the schema
+func (s *ServeMux) directiveDefinitions() []*graphql.Directive {
+	names := make([]string, 0, len(s.directives))
+	for name := range s.directives {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	definitions := make([]*graphql.Directive, len(names))
+	for i, name := range names {
+		definitions[i] = s.directives[name].definition
+	}
+	return definitions
+}
+
+// withDirectiveHandlers stores registered directive handlers in the context for field resolvers
+func (s *ServeMux) withDirectiveHandlers(ctx context.Context) context.Context {
+	if len(s.directives) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, directivesKey{}, s.directives)
+}
+
+var fieldDirectives = struct {
+	sync.RWMutex
+	directives map[string][]*ast.Directive
+}{
+	directives: make(map[string][]*ast.Directive),
+}
+
+// SetFieldDirectives declares directives of type field which are applied when the field is resolved.
+// Directives are written in GraphQL syntax without "@" like `masked(char: "*")`, and invalid ones are ignored.
+// This function is called from generated code for fields which have (graphql.field).directives option.
+func SetFieldDirectives(typeName, fieldName string, directives ...string) {
+	var parsed []*ast.Directive
+	for _, d := range directives {
+		if pd, err := ParseDirective(d); err == nil {
+			parsed = append(parsed, pd)
+		}
+	}
+	fieldDirectives.Lock()
+	defer fieldDirectives.Unlock()
+	fieldDirectives.directives[typeName+"."+fieldName] = parsed
+}
+
+// ParseDirective parses the directive in GraphQL syntax without "@" like `masked(char: "*")`,
+// variables are not allowed in arguments
+func ParseDirective(directive string) (*ast.Directive, error) {
+	return gqldirective.Parse(directive)
+}
+
+func fieldDirectivesOf(typeName, fieldName string) []*ast.Directive {
+	fieldDirectives.RLock()
+	defer fieldDirectives.RUnlock()
+	return fieldDirectives.directives[typeName+"."+fieldName]
+}
+
+// withDirectives wraps the resolver by handlers of directives which are declared for the field and applied in the query
+func withDirectives(p graphql.ResolveParams, next graphql.FieldResolveFn) graphql.FieldResolveFn {
+	handlers, ok := p.Context.Value(directivesKey{}).(map[string]*directive)
+	if !ok || p.Info.ParentType == nil {
+		return next
+	}
+	applied := append([]*ast.Directive{}, fieldDirectivesOf(p.Info.ParentType.Name(), p.Info.FieldName)...)
+	// The same field may be selected multiple times in fragments, which are merged into one
+	seen := make(map[string]struct{})
+	for _, field := range p.Info.FieldASTs {
+		for _, d := range field.Directives {
+			if _, ok := seen[d.Name.Value]; ok {
+				continue
+			}
+			seen[d.Name.Value] = struct{}{}
+			applied = append(applied, d)
+		}
+	}
+
+	for _, d := range applied {
+		h, ok := handlers[d.Name.Value]
+		if !ok {
+			continue
+		}
+		args := h.arguments(d, p.Info.VariableValues)
+		inner := next
+		next = func(p graphql.ResolveParams) (interface{}, error) {
+			return h.handler(p, args, inner)
+		}
+	}
+	return next
+}
+
+// literalParser is implemented by scalars and enums
+type literalParser interface {
+	ParseLiteral(valueAST ast.Value) interface{}
+}
+
+// arguments returns argument values of the applied directive with default values.
+// Literals of leaf types are coerced by the types, and other values are converted as they are.
+func (d *directive) arguments(applied *ast.Directive, variables map[string]interface{}) map[string]interface{} {
+	values := make(map[string]interface{})
+	types := make(map[string]graphql.Input)
+	for _, arg := range d.definition.Args {
+		types[arg.Name()] = arg.Type
+		if arg.DefaultValue != nil {
+			values[arg.Name()] = arg.DefaultValue
+		}
+	}
+	e := &explainer{variables: variables}
+	for _, arg := range applied.Arguments {
+		if arg.Name == nil {
+			continue
+		}
+		if leaf, ok := graphql.GetNamed(types[arg.Name.Value]).(literalParser); ok {
+			if _, isVariable := arg.Value.(*ast.Variable); !isVariable {
+				values[arg.Name.Value] = leaf.ParseLiteral(arg.Value)
+				continue
+			}
+		}
+		if v, ok := e.astValue(arg.Value); ok {
+			values[arg.Name.Value] = v
+		}
+	}
+	return values
+}
@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMuxRegisterDirective(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	assert.NoError(t, mux.RegisterDirective("uppercase", nil,
+		func(p graphql.ResolveParams, args map[string]interface{}, next graphql.FieldResolveFn) (interface{}, error) {
+			value, err := next(p)
+			if s, ok := value.(string); ok {
+				return strings.ToUpper(s), err
+			}
+			return value, err
+		},
+	))
+	assert.NoError(t, mux.RegisterDirective("masked", graphql.FieldConfigArgument{
+		"char": &graphql.ArgumentConfig{Type: graphql.String, DefaultValue: "*"},
+		"keep": &graphql.ArgumentConfig{Type: graphql.Int},
+	}, func(p graphql.ResolveParams, args map[string]interface{}, next graphql.FieldResolveFn) (interface{}, error) {
+		value, err := next(p)
+		s, _ := value.(string)
+		keep, _ := args["keep"].(int)
+		if keep > len(s) {
+			keep = len(s)
+		}
+		return s[:keep] + strings.Repeat(args["char"].(string), len(s)-keep), err // nolint: errcheck
+	}))
+
+	built := NewServeMux()
+	assert.NoError(t, built.AddHandler(newTestHandler()))
+	if _, err := built.Build(); assert.NoError(t, err) {
+		assert.Equal(t, ErrServeMuxBuilt, built.RegisterDirective("uppercase", nil, nil))
+	}
+	assert.Error(t, mux.RegisterDirective("skip", nil, func(graphql.ResolveParams, map[string]interface{}, graphql.FieldResolveFn) (interface{}, error) {
+		return nil, nil
+	}))
+	assert.Error(t, mux.RegisterDirective("uppercase", nil, func(graphql.ResolveParams, map[string]interface{}, graphql.FieldResolveFn) (interface{}, error) {
+		return nil, nil
+	}))
+	assert.Error(t, mux.RegisterDirective("invalid-name", nil, nil))
+
+	// Query directives are applied with literal and variable arguments
+	result := serveTestQuery(t, mux, `{
+		"query": "query ($keep: Int) { hello @uppercase user(id: \"1\") { id @masked(char: \"#\") email @masked(keep: $keep) } }",
+		"variables": {"keep": 4}
+	}`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"hello": "WORLD",
+		"user": map[string]interface{}{
+			"id":    "#",
+			"email": "user************",
+		},
+	}, result.Data)
+
+	// Declared directives are applied first, and query directives wrap them
+	SetFieldDirectives("Test_Type_User", "email", "masked(keep: 2)", "unknown")
+	defer SetFieldDirectives("Test_Type_User", "email")
+	result = serveTestQuery(t, mux, `{ user(id: "1") { email @uppercase } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{
+			"email": "US**************",
+		},
+	}, result.Data)
+
+	// Directives which are not registered are invalid in queries
+	result = serveTestQuery(t, mux, `{ hello @unknown }`)
+	assert.Len(t, result.Errors, 1)
+}
+
+const testDirectiveFile = `
+name: "directive.proto"
+package: "account.v1"
+syntax: "proto3"
+dependency: "graphql.proto"
+message_type {
+  name: "Account"
+  field { name: "email" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "email" options { [graphql.field] { directives: ["masked(keep: 2)"] } } }
+}
+service {
+  name: "AccountService"
+  method { name: "GetAccount" input_type: ".account.v1.Account" output_type: ".account.v1.Account" }
+}
+`
+
+func TestDynamicFieldDirectives(t *testing.T) {
+	file := testDynamicFile(t, "directive.proto", testDirectiveFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	account := handlers[0].GetQueries(nil)["getAccount"].Type.(*graphql.Object) // nolint: errcheck
+
+	// Directives are declared when fields of the type are defined
+	account.Fields()
+	directives := fieldDirectivesOf(account.Name(), "email")
+	if assert.Len(t, directives, 1) {
+		assert.Equal(t, "masked", directives[0].Name.Value)
+	}
+}
@@ -0,0 +1,165 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"sync/atomic"
+
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	"github.com/iancoleman/strcase"
+	"google.golang.org/grpc/credentials"
+)
+
+// UpstreamEndpoint is dial target and transport credentials of the upstream which is resolved by Discovery
+type UpstreamEndpoint struct {
+	// gRPC dial target like "dns:///greeter.default.svc.cluster.local:50051"
+	Target string
+	// Transport credentials for the target, dial options of the handler are used if nil
+	Credentials credentials.TransportCredentials
+}
+
+// Discovery resolves upstream endpoint of gRPC service for automatic connections of generated handlers.
+// Service is full service name like "greeter.Greeter".
+// Resolve returns nil endpoint if the service is unknown, then host of proto option is used.
+type Discovery interface {
+	Resolve(ctx context.Context, service string) (*UpstreamEndpoint, error)
+}
+
+type discoveryKey struct{}
+
+// WithDiscovery is middleware function to resolve upstreams of generated handlers via discovery before dialing.
+// Hosts which are overridden by WithUpstreamHosts or environment variables take precedence.
+func WithDiscovery(d Discovery) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, discoveryKey{}, d), nil
+	}
+}
+
+// discoveryName returns discovery service name of gRPC service, e.g. "user-service" for "user.v1.UserService"
+func discoveryName(names map[string]string, service string) string {
+	if name, ok := names[service]; ok {
+		return name
+	}
+	for i := len(service) - 1; i >= 0; i-- {
+		if service[i] == '.' {
+			service = service[i+1:]
+			break
+		}
+	}
+	return strcase.ToKebab(service)
+}
+
+// KubernetesDNS is Discovery which resolves Kubernetes service DNS name via gRPC DNS resolver
+type KubernetesDNS struct {
+	// Namespace of services, default is "default"
+	Namespace string
+	// Cluster domain, default is "cluster.local"
+	ClusterDomain string
+	// gRPC port of services
+	Port int
+	// Kubernetes service names keyed by full gRPC service name.
+	// Default is kebab cased service name without package, e.g. "user-service" for "user.v1.UserService".
+	Names map[string]string
+	// Transport credentials of services
+	Credentials credentials.TransportCredentials
+}
+
+// Resolve implements Discovery
+func (k *KubernetesDNS) Resolve(ctx context.Context, service string) (*UpstreamEndpoint, error) {
+	namespace, domain := k.Namespace, k.ClusterDomain
+	if namespace == "" {
+		namespace = "default"
+	}
+	if domain == "" {
+		domain = "cluster.local"
+	}
+	return &UpstreamEndpoint{
+		Target:      fmt.Sprintf("dns:///%s.%s.svc.%s:%d", discoveryName(k.Names, service), namespace, domain, k.Port),
+		Credentials: k.Credentials,
+	}, nil
+}
+
+// Consul is Discovery which resolves healthy service instances via Consul HTTP API.
+// Instances are picked in round robin per connection.
+type Consul struct {
+	// Consul agent address, default is "http://127.0.0.1:8500"
+	Address string
+	// ACL token which is sent as X-Consul-Token header
+	Token string
+	// Filter instances by the tag if not empty
+	Tag string
+	// Consul service names keyed by full gRPC service name.
+	// Default is kebab cased service name without package, e.g. "user-service" for "user.v1.UserService".
+	Names map[string]string
+	// Transport credentials of services
+	Credentials credentials.TransportCredentials
+	// HTTP client to call Consul API, default is http.DefaultClient
+	Client *http.Client
+
+	next uint32
+}
+
+type consulServiceEntry struct {
+	Node struct {
+		Address string
+	}
+	Service struct {
+		Address string
+		Port    int
+	}
+}
+
+// Resolve implements Discovery
+func (c *Consul) Resolve(ctx context.Context, service string) (*UpstreamEndpoint, error) {
+	address, client := c.Address, c.Client
+	if address == "" {
+		address = "http://127.0.0.1:8500"
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	query := url.Values{"passing": {"true"}}
+	if c.Tag != "" {
+		query.Set("tag", c.Tag)
+	}
+	endpoint := address + "/v1/health/service/" + url.PathEscape(discoveryName(c.Names, service)) + "?" + query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("X-Consul-Token", c.Token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("consul responds status %d", resp.StatusCode)
+	}
+
+	var entries []consulServiceEntry
+	if err := json.NewDecoder(resp.Body).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("failed to decode consul response: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, nil
+	}
+	entry := entries[int(atomic.AddUint32(&c.next, 1)-1)%len(entries)]
+	// Service address is empty if it is the same as node address
+	host := entry.Service.Address
+	if host == "" {
+		host = entry.Node.Address
+	}
+	return &UpstreamEndpoint{
+		Target:      net.JoinHostPort(host, strconv.Itoa(entry.Service.Port)),
+		Credentials: c.Credentials,
+	}, nil
+}
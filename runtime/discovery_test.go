@@ -0,0 +1,88 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+func TestKubernetesDNS(t *testing.T) {
+	k := &KubernetesDNS{
+		Namespace: "backend",
+		Port:      50051,
+		Names:     map[string]string{"greeter.Greeter": "hello"},
+	}
+	endpoint, err := k.Resolve(context.Background(), "user.v1.UserService")
+	assert.NoError(t, err)
+	assert.Equal(t, "dns:///user-service.backend.svc.cluster.local:50051", endpoint.Target)
+
+	endpoint, err = k.Resolve(context.Background(), "greeter.Greeter")
+	assert.NoError(t, err)
+	assert.Equal(t, "dns:///hello.backend.svc.cluster.local:50051", endpoint.Target)
+}
+
+func TestConsul(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Consul-Token"))
+		assert.Equal(t, "true", r.URL.Query().Get("passing"))
+		switch r.URL.Path {
+		case "/v1/health/service/user-service":
+			w.Write([]byte(`[
+				{"Node": {"Address": "10.0.0.1"}, "Service": {"Address": "", "Port": 50051}},
+				{"Node": {"Address": "10.0.0.2"}, "Service": {"Address": "fd00::2", "Port": 50052}}
+			]`)) // nolint: errcheck
+		case "/v1/health/service/unknown":
+			w.Write([]byte(`[]`)) // nolint: errcheck
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	c := &Consul{Address: server.URL, Token: "secret"}
+	ctx := context.Background()
+
+	endpoint, err := c.Resolve(ctx, "user.v1.UserService")
+	assert.NoError(t, err)
+	assert.Equal(t, "10.0.0.1:50051", endpoint.Target)
+	endpoint, err = c.Resolve(ctx, "user.v1.UserService")
+	assert.NoError(t, err)
+	assert.Equal(t, "[fd00::2]:50052", endpoint.Target)
+
+	endpoint, err = c.Resolve(ctx, "Unknown")
+	assert.NoError(t, err)
+	assert.Nil(t, endpoint)
+
+	_, err = c.Resolve(ctx, "Broken")
+	assert.Error(t, err)
+}
+
+func TestDialUpstreamWithDiscovery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	ctx, err := WithDiscovery(&KubernetesDNS{Port: 50051})(context.Background(), nil, nil, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dial := func(ctx context.Context, service string) string {
+		conn, err := DialUpstream(ctx, service, "localhost:50051", grpc.WithInsecure())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		return conn.Target()
+	}
+	assert.Equal(t, "dns:///greeter.default.svc.cluster.local:50051", dial(ctx, "greeter.Greeter"))
+
+	// Explicit override takes precedence
+	ctx, err = WithUpstreamHosts(map[string]string{"greeter.Greeter": "localhost:60051"})(ctx, nil, nil, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "localhost:60051", dial(ctx, "greeter.Greeter"))
+}
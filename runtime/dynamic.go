@@ -0,0 +1,436 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/iancoleman/strcase"
+	gqlproto "github.com/ysugimoto/grpc-graphql-gateway/graphql"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Method name prefixes which are exposed as Query when the method doesn't have graphql.schema option
+var dynamicQueryPrefixes = []string{"Get", "List", "Find", "Search", "Query"}
+
+// DynamicHandler is GraphqlHandler which builds queries and mutations from protobuf descriptors at runtime
+// and calls RPCs with dynamic messages, so that the gateway works without generating code via protoc.
+// Methods which have graphql.schema option are exposed as declared,
+// and other unary methods are exposed as Query if the name starts with Get, List, Find, Search or Query, otherwise as Mutation.
+// Messages implement interfaces of graphql.interface file option which are declared in the files of services or their imports.
+// Server-streaming methods which are exposed as Query return list of the responses which are collected from the stream,
+// see WithStreamLimits for bounding the collection. Other streaming methods are not exposed.
+type DynamicHandler struct {
+	conn      *grpc.ClientConn
+	service   protoreflect.ServiceDescriptor
+	types     *dynamicTypes
+	queries   []*dynamicMethod
+	mutations []*dynamicMethod
+}
+
+// dynamicMethod is RPC method which is exposed as root field
+type dynamicMethod struct {
+	name       string
+	path       string
+	descriptor protoreflect.MethodDescriptor
+	// Argument name of the input object if the request message is dealt with an input
+	input string
+	// Request fields which are exposed as arguments, all fields if empty
+	plucks []string
+	// The only scalar field of the request message which is exposed as required argument
+	unwrap protoreflect.FieldDescriptor
+	// Response field which is responded instead of whole message
+	pluck    protoreflect.FieldDescriptor
+	required bool
+	// True if the pluck is the only field of the response message, then required is applied to the field
+	unwrapResponse bool
+	// True if the method is server-streaming, then responses are collected into list
+	stream bool
+	// Group name of the root field, not grouped if empty
+	group string
+}
+
+// NewDynamicHandlers creates DynamicHandler for each service which calls RPCs via conn.
+// GraphQL types are shared between returned handlers, so register all of them to the same ServeMux.
+func NewDynamicHandlers(conn *grpc.ClientConn, services ...protoreflect.ServiceDescriptor) ([]*DynamicHandler, error) {
+	types := newDynamicTypes()
+	var files []protoreflect.FileDescriptor
+	visited := map[string]struct{}{}
+	for _, sd := range services {
+		files = append(files, importedFiles(sd.ParentFile(), visited)...)
+	}
+	types.addInterfaces(files)
+	handlers := make([]*DynamicHandler, 0, len(services))
+	for _, sd := range services {
+		h := &DynamicHandler{
+			conn:    conn,
+			service: sd,
+			types:   types,
+		}
+		methods := sd.Methods()
+		for i := 0; i < methods.Len(); i++ {
+			if err := h.addMethod(methods.Get(i)); err != nil {
+				return nil, err
+			}
+		}
+		handlers = append(handlers, h)
+	}
+	return handlers, nil
+}
+
+func (h *DynamicHandler) addMethod(md protoreflect.MethodDescriptor) error {
+	if md.IsStreamingClient() {
+		return nil
+	}
+	if cycle := inputCycle(md.Input()); cycle != nil {
+		return fmt.Errorf(
+			"input %s of %s has a cycle of non-null fields %s which cannot be represented in GraphQL, "+
+				"make one of the fields optional by removing required option, or repeated",
+			cycle[len(cycle)-1], md.FullName(), strings.Join(cycle, " -> "),
+		)
+	}
+	m := &dynamicMethod{
+		name:       strcase.ToLowerCamel(string(md.Name())),
+		path:       fmt.Sprintf("/%s/%s", h.service.FullName(), md.Name()),
+		descriptor: md,
+		stream:     md.IsStreamingServer(),
+	}
+
+	schema, ok := methodSchema(md)
+	if !ok {
+		if isDynamicQuery(string(md.Name())) {
+			h.queries = append(h.queries, m)
+		} else if !m.stream {
+			h.mutations = append(h.mutations, m)
+		}
+		return nil
+	}
+
+	if schema.GetName() != "" {
+		m.name = schema.GetName()
+	}
+	m.input = schema.GetRequest().GetName()
+	m.plucks = schema.GetRequest().GetPlucks()
+	if schema.GetRequest().GetUnwrap() {
+		fields := md.Input().Fields()
+		if fields.Len() != 1 || fields.Get(0).IsList() || fields.Get(0).IsMap() || fields.Get(0).Message() != nil {
+			return fmt.Errorf("unwrap requires single scalar field request message but %s is not", md.Input().FullName())
+		}
+		m.unwrap = fields.Get(0)
+		m.input = ""
+	}
+	m.required = schema.GetResponse().GetRequired()
+	m.group = schema.GetGroup()
+	if pluck := schema.GetResponse().GetPluck(); pluck != "" {
+		m.pluck = md.Output().Fields().ByName(protoreflect.Name(pluck))
+		if m.pluck == nil {
+			return fmt.Errorf("pluck field %q is not found in %s", pluck, md.Output().FullName())
+		}
+	} else if schema.GetResponse().GetUnwrap() {
+		fields := md.Output().Fields()
+		if fields.Len() != 1 {
+			return fmt.Errorf("unwrap requires single field response message but %s has %d fields", md.Output().FullName(), fields.Len())
+		}
+		m.pluck = fields.Get(0)
+		m.unwrapResponse = true
+	}
+	switch schema.GetType() {
+	case gqlproto.GraphqlType_MUTATION:
+		// Mutation should be called once, so streams are not collected
+		if m.stream {
+			return nil
+		}
+		h.mutations = append(h.mutations, m)
+	default:
+		h.queries = append(h.queries, m)
+	}
+	return nil
+}
+
+func isDynamicQuery(name string) bool {
+	for _, prefix := range dynamicQueryPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func methodSchema(md protoreflect.MethodDescriptor) (*gqlproto.GraphqlSchema, bool) {
+	opts := md.Options()
+	if opts == nil || !proto.HasExtension(opts, gqlproto.E_Schema) {
+		return nil, false
+	}
+	schema, ok := proto.GetExtension(opts, gqlproto.E_Schema).(*gqlproto.GraphqlSchema)
+	return schema, ok && schema != nil
+}
+
+// CreateConnection returns connection which is passed to NewDynamicHandlers
+func (h *DynamicHandler) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	return h.conn, func() {}, nil
+}
+
+// ServiceName returns full name of the gRPC service
+func (h *DynamicHandler) ServiceName() string {
+	return string(h.service.FullName())
+}
+
+// GetQueries returns graphql.Fields for Query
+func (h *DynamicHandler) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	return h.fields(conn, h.queries)
+}
+
+// GetMutations returns graphql.Fields for Mutation
+func (h *DynamicHandler) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	return h.fields(conn, h.mutations)
+}
+
+// FieldMappings returns gRPC methods of queries and mutations
+func (h *DynamicHandler) FieldMappings() []FieldMapping {
+	mappings := make([]FieldMapping, 0, len(h.queries)+len(h.mutations))
+	for _, m := range h.queries {
+		mappings = append(mappings, h.fieldMapping("query", m))
+	}
+	for _, m := range h.mutations {
+		mappings = append(mappings, h.fieldMapping("mutation", m))
+	}
+	return mappings
+}
+
+// FieldGroups returns groups of root fields which are declared by group of graphql.schema option
+func (h *DynamicHandler) FieldGroups() map[string]string {
+	groups := map[string]string{}
+	for _, methods := range [][]*dynamicMethod{h.queries, h.mutations} {
+		for _, m := range methods {
+			if m.group != "" {
+				groups[m.name] = m.group
+			}
+		}
+	}
+	return groups
+}
+
+func (h *DynamicHandler) fieldMapping(operation string, m *dynamicMethod) FieldMapping {
+	mapping := FieldMapping{
+		Operation:    operation,
+		Field:        m.name,
+		Service:      h.ServiceName(),
+		Method:       m.path,
+		RequestType:  string(m.descriptor.Input().FullName()),
+		ResponseType: string(m.descriptor.Output().FullName()),
+		Input:        m.input,
+		Stream:       m.stream,
+	}
+	if m.pluck != nil {
+		mapping.ResponseField = string(m.pluck.Name())
+	}
+	// Same fields as arguments, or fields of input object
+	fields := m.descriptor.Input().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if m.input == "" && len(m.plucks) > 0 && !containsString(m.plucks, string(fd.Name())) {
+			continue
+		}
+		if fieldOption(fd).GetOmit() {
+			continue
+		}
+		mapping.Arguments = append(mapping.Arguments, ArgumentBinding{
+			Argument:     dynamicFieldName(fd),
+			RequestField: string(fd.Name()),
+		})
+	}
+	return mapping
+}
+
+func (h *DynamicHandler) fields(conn *grpc.ClientConn, methods []*dynamicMethod) graphql.Fields {
+	fields := graphql.Fields{}
+	for _, m := range methods {
+		fields[m.name] = &graphql.Field{
+			Type:    h.outputType(m),
+			Args:    h.arguments(m),
+			Resolve: h.resolver(conn, m),
+		}
+	}
+	return fields
+}
+
+func (h *DynamicHandler) outputType(m *dynamicMethod) graphql.Output {
+	var t graphql.Output
+	if m.pluck != nil {
+		t = h.types.fieldOutput(m.pluck)
+	} else {
+		t = h.types.object(m.descriptor.Output())
+	}
+	// Collected responses are never null
+	if m.stream {
+		if _, ok := t.(*graphql.NonNull); !ok {
+			t = graphql.NewNonNull(t)
+		}
+		t = graphql.NewList(t)
+	}
+	// Pluck respects the definition of plucked field like generated code
+	if m.required && (m.pluck == nil || m.unwrapResponse || m.stream) {
+		if _, ok := t.(*graphql.NonNull); !ok {
+			t = graphql.NewNonNull(t)
+		}
+	}
+	return t
+}
+
+func (h *DynamicHandler) arguments(m *dynamicMethod) graphql.FieldConfigArgument {
+	in := m.descriptor.Input()
+	if m.input != "" {
+		return graphql.FieldConfigArgument{
+			m.input: &graphql.ArgumentConfig{
+				Type: graphql.NewNonNull(h.types.input(in)),
+			},
+		}
+	}
+
+	if m.unwrap != nil {
+		t := h.types.fieldInput(m.unwrap)
+		if _, ok := t.(*graphql.NonNull); !ok {
+			t = graphql.NewNonNull(t)
+		}
+		return graphql.FieldConfigArgument{
+			dynamicFieldName(m.unwrap): &graphql.ArgumentConfig{Type: t},
+		}
+	}
+
+	args := graphql.FieldConfigArgument{}
+	fields := in.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if len(m.plucks) > 0 && !containsString(m.plucks, string(fd.Name())) {
+			continue
+		}
+		if fieldOption(fd).GetOmit() {
+			continue
+		}
+		args[dynamicFieldName(fd)] = &graphql.ArgumentConfig{
+			Type:         h.types.fieldInput(fd),
+			DefaultValue: defaultValue(fd),
+		}
+	}
+	return args
+}
+
+func (h *DynamicHandler) resolver(conn *grpc.ClientConn, m *dynamicMethod) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		args := p.Args
+		if m.input != "" {
+			args, _ = p.Args[m.input].(map[string]interface{}) // nolint: errcheck
+		}
+		req := dynamicpb.NewMessage(m.descriptor.Input())
+		if err := checkOneofs(req.Descriptor(), args, dynamicFieldName, m.input != ""); err != nil {
+			return nil, fmt.Errorf("Failed to marshal request for %s: %w", m.name, err)
+		}
+		if err := setMessage(req, args); err != nil {
+			return nil, fmt.Errorf("Failed to marshal request for %s: %w", m.name, err)
+		}
+		if m.stream {
+			return h.collect(p.Context, conn, m, req)
+		}
+		resp := dynamicpb.NewMessage(m.descriptor.Output())
+		if err := conn.Invoke(p.Context, m.path, req, resp, CallOptions(p.Context)...); err != nil {
+			return nil, fmt.Errorf("Failed to call RPC %s: %w", m.descriptor.Name(), err)
+		}
+		if m.pluck != nil {
+			return fieldValue(resp, m.pluck), nil
+		}
+		return messageValue(resp), nil
+	}
+}
+
+// collect calls server-streaming RPC and responds list of the collected responses
+func (h *DynamicHandler) collect(ctx context.Context, conn *grpc.ClientConn, m *dynamicMethod, req proto.Message) (interface{}, error) {
+	messages, err := collectServerStream(ctx, conn, m.path, req, func() proto.Message {
+		return dynamicpb.NewMessage(m.descriptor.Output())
+	}, CallOptions(ctx)...)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to call RPC %s: %w", m.descriptor.Name(), err)
+	}
+	values := make([]interface{}, 0, len(messages))
+	for _, msg := range messages {
+		if m.pluck != nil {
+			values = append(values, fieldValue(msg.ProtoReflect(), m.pluck))
+		} else {
+			values = append(values, messageValue(msg.ProtoReflect()))
+		}
+	}
+	return values, nil
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// dynamicResolver resolves dependencies from the files which are built dynamically,
+// and falls back to the files which are linked into this binary like well-known types
+type dynamicResolver struct {
+	files *protoregistry.Files
+}
+
+func (r dynamicResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) {
+	if fd, err := r.files.FindFileByPath(path); err == nil {
+		return fd, nil
+	}
+	return protoregistry.GlobalFiles.FindFileByPath(path)
+}
+
+func (r dynamicResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) {
+	if d, err := r.files.FindDescriptorByName(name); err == nil {
+		return d, nil
+	}
+	return protoregistry.GlobalFiles.FindDescriptorByName(name)
+}
+
+// newDynamicFiles builds file registry from file descriptors in dependency order.
+// Dependencies which are not in fds are resolved from the files linked into this binary.
+func newDynamicFiles(fds []*descriptorpb.FileDescriptorProto) (*protoregistry.Files, error) {
+	pending := make(map[string]*descriptorpb.FileDescriptorProto, len(fds))
+	for _, fd := range fds {
+		pending[fd.GetName()] = fd
+	}
+	files := &protoregistry.Files{}
+	resolver := dynamicResolver{files: files}
+
+	var register func(fd *descriptorpb.FileDescriptorProto) error
+	register = func(fd *descriptorpb.FileDescriptorProto) error {
+		delete(pending, fd.GetName())
+		for _, dep := range fd.GetDependency() {
+			if d, ok := pending[dep]; ok {
+				if err := register(d); err != nil {
+					return err
+				}
+			}
+		}
+		f, err := protodesc.NewFile(fd, resolver)
+		if err != nil {
+			return fmt.Errorf("failed to build descriptor of %s: %w", fd.GetName(), err)
+		}
+		return files.RegisterFile(f)
+	}
+
+	for _, fd := range fds {
+		if _, ok := pending[fd.GetName()]; !ok {
+			continue
+		}
+		if err := register(fd); err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,275 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"encoding/base64"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// fieldNamer returns the name of the field in GraphQL values
+type fieldNamer func(protoreflect.FieldDescriptor) string
+
+// setMessage sets GraphQL arguments to the dynamic message
+func setMessage(msg protoreflect.Message, args map[string]interface{}) error {
+	return setMessageBy(msg, args, dynamicFieldName)
+}
+
+// setMessageBy sets GraphQL values to the message, whose fields are looked up by the name
+func setMessageBy(msg protoreflect.Message, args map[string]interface{}, name fieldNamer) error {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		v, ok := args[name(fd)]
+		if !ok || v == nil {
+			continue
+		}
+		if err := setField(msg, fd, v, name); err != nil {
+			return fmt.Errorf("field %s: %w", fd.Name(), err)
+		}
+	}
+	return nil
+}
+
+func setField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, v interface{}, name fieldNamer) error {
+	switch {
+	case fd.IsMap():
+		entries, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("map value must be a list of key-value object, got %T", v)
+		}
+		m := msg.Mutable(fd).Map()
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("map entry must be an object, got %T", e)
+			}
+			key, err := scalarValue(fd.MapKey(), entry["key"])
+			if err != nil {
+				return err
+			}
+			value, err := elementValue(fd.MapValue(), entry["value"], m.NewValue, name)
+			if err != nil {
+				return err
+			}
+			m.Set(key.MapKey(), value)
+		}
+	case fd.IsList():
+		items, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("list value must be an array, got %T", v)
+		}
+		list := msg.Mutable(fd).List()
+		for _, item := range items {
+			value, err := elementValue(fd, item, list.NewElement, name)
+			if err != nil {
+				return err
+			}
+			list.Append(value)
+		}
+	default:
+		value, err := elementValue(fd, v, func() protoreflect.Value {
+			return msg.NewField(fd)
+		}, name)
+		if err != nil {
+			return err
+		}
+		msg.Set(fd, value)
+	}
+	return nil
+}
+
+// elementValue converts singular value, newMessage creates empty message for the message field
+func elementValue(
+	fd protoreflect.FieldDescriptor,
+	v interface{},
+	newMessage func() protoreflect.Value,
+	name fieldNamer,
+) (protoreflect.Value, error) {
+
+	if fd.Message() == nil {
+		return scalarValue(fd, v)
+	}
+	value := newMessage()
+	if v == nil {
+		return value, nil
+	}
+	args, ok := v.(map[string]interface{})
+	if !ok {
+		return value, fmt.Errorf("message value must be an object, got %T", v)
+	}
+	return value, setMessageBy(value.Message(), args, name)
+}
+
+func scalarValue(fd protoreflect.FieldDescriptor, v interface{}) (protoreflect.Value, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		b, ok := v.(bool)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("expected bool, got %T", v)
+		}
+		return protoreflect.ValueOfBool(b), nil
+	case protoreflect.StringKind:
+		s, ok := v.(string)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("expected string, got %T", v)
+		}
+		return protoreflect.ValueOfString(s), nil
+	case protoreflect.BytesKind:
+		s, ok := v.(string)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("expected base64 encoded string, got %T", v)
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return protoreflect.Value{}, err
+		}
+		return protoreflect.ValueOfBytes(b), nil
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		f, ok := toFloat64(v)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("expected number, got %T", v)
+		}
+		if fd.Kind() == protoreflect.FloatKind {
+			return protoreflect.ValueOfFloat32(float32(f)), nil
+		}
+		return protoreflect.ValueOfFloat64(f), nil
+	case protoreflect.EnumKind:
+		// Enums of generated code are values of enum types
+		if e, ok := v.(protoreflect.Enum); ok {
+			return protoreflect.ValueOfEnum(e.Number()), nil
+		}
+		// Enum values are responded by name
+		if s, ok := v.(string); ok {
+			ev := fd.Enum().Values().ByName(protoreflect.Name(s))
+			if ev == nil {
+				return protoreflect.Value{}, fmt.Errorf("unknown enum value %s", s)
+			}
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		n, ok := toInt64(v)
+		if !ok {
+			return protoreflect.Value{}, fmt.Errorf("expected enum, got %T", v)
+		}
+		return protoreflect.ValueOfEnum(protoreflect.EnumNumber(n)), nil
+	}
+
+	n, ok := toInt64(v)
+	if !ok {
+		return protoreflect.Value{}, fmt.Errorf("expected integer, got %T", v)
+	}
+	switch fd.Kind() {
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n < math.MinInt32 || n > math.MaxInt32 {
+			return protoreflect.Value{}, fmt.Errorf("%d overflows int32", n)
+		}
+		return protoreflect.ValueOfInt32(int32(n)), nil
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n < 0 || n > math.MaxUint32 {
+			return protoreflect.Value{}, fmt.Errorf("%d overflows uint32", n)
+		}
+		return protoreflect.ValueOfUint32(uint32(n)), nil
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n < 0 {
+			return protoreflect.Value{}, fmt.Errorf("%d overflows uint64", n)
+		}
+		return protoreflect.ValueOfUint64(uint64(n)), nil
+	default:
+		return protoreflect.ValueOfInt64(n), nil
+	}
+}
+
+func toInt64(v interface{}) (int64, bool) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case float64:
+		return int64(n), n == math.Trunc(n)
+	default:
+		return 0, false
+	}
+}
+
+func toFloat64(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+// messageValue converts dynamic message to the value which GraphQL can resolve.
+// All fields are included with zero values like MarshalResponse, except unset message fields are nil.
+func messageValue(msg protoreflect.Message) map[string]interface{} {
+	ret := make(map[string]interface{})
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if fieldOption(fd).GetOmit() {
+			continue
+		}
+		ret[dynamicFieldName(fd)] = fieldValue(msg, fd)
+	}
+	return ret
+}
+
+func fieldValue(msg protoreflect.Message, fd protoreflect.FieldDescriptor) interface{} {
+	v := msg.Get(fd)
+	switch {
+	case fd.IsMap():
+		entries := make([]interface{}, 0, v.Map().Len())
+		v.Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			entries = append(entries, map[string]interface{}{
+				"key":   singularValue(fd.MapKey(), k.Value()),
+				"value": singularValue(fd.MapValue(), mv),
+			})
+			return true
+		})
+		// Map iteration order is random, sort entries by key for the stable response
+		sort.Slice(entries, func(i, j int) bool {
+			ki := entries[i].(map[string]interface{})["key"]
+			kj := entries[j].(map[string]interface{})["key"]
+			return fmt.Sprint(ki) < fmt.Sprint(kj)
+		})
+		return entries
+	case fd.IsList():
+		list := v.List()
+		items := make([]interface{}, list.Len())
+		for i := 0; i < list.Len(); i++ {
+			items[i] = singularValue(fd, list.Get(i))
+		}
+		return items
+	case fd.Message() != nil && !msg.Has(fd):
+		return nil
+	default:
+		return singularValue(fd, v)
+	}
+}
+
+func singularValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) interface{} {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return messageValue(v.Message())
+	case protoreflect.EnumKind:
+		return int32(v.Enum())
+	case protoreflect.BytesKind:
+		// Encoded into base64 string by the resolver of String field, or in the response for Bytes scalar
+		return v.Bytes()
+	default:
+		return v.Interface()
+	}
+}
@@ -0,0 +1,581 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const testBookFile = `
+name: "book.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "graphql.proto"
+message_type {
+  name: "Book"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  field { name: "genre" number: 2 type: TYPE_ENUM type_name: ".library.v1.Genre" label: LABEL_OPTIONAL json_name: "genre" }
+  field { name: "ratings" number: 3 type: TYPE_MESSAGE type_name: ".library.v1.Book.RatingsEntry" label: LABEL_REPEATED json_name: "ratings" }
+  field { name: "cover" number: 4 type: TYPE_BYTES label: LABEL_OPTIONAL json_name: "cover" }
+  field { name: "related" number: 5 type: TYPE_MESSAGE type_name: ".library.v1.Book" label: LABEL_REPEATED json_name: "related" }
+  field { name: "secret" number: 6 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "secret" options { [graphql.field] { omit: true } } }
+  field { name: "page_count" number: 7 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "pageCount" }
+  nested_type {
+    name: "RatingsEntry"
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "key" }
+    field { name: "value" number: 2 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "value" }
+    options { map_entry: true }
+  }
+}
+message_type {
+  name: "GetBookRequest"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" options { [graphql.field] { required: true } } }
+}
+message_type {
+  name: "ListBooksRequest"
+}
+message_type {
+  name: "ListBooksResponse"
+  field { name: "books" number: 1 type: TYPE_MESSAGE type_name: ".library.v1.Book" label: LABEL_REPEATED json_name: "books" }
+}
+enum_type {
+  name: "Genre"
+  value { name: "UNKNOWN" number: 0 }
+  value { name: "NOVEL" number: 1 }
+}
+service {
+  name: "LibraryService"
+  method { name: "GetBook" input_type: ".library.v1.GetBookRequest" output_type: ".library.v1.Book" }
+  method { name: "DeleteBook" input_type: ".library.v1.GetBookRequest" output_type: ".library.v1.Book" }
+  method {
+    name: "ListBooks" input_type: ".library.v1.ListBooksRequest" output_type: ".library.v1.ListBooksResponse"
+    options { [graphql.schema] { type: QUERY name: "books" response { pluck: "books" } } }
+  }
+  method {
+    name: "AddBook" input_type: ".library.v1.Book" output_type: ".library.v1.Book"
+    options { [graphql.schema] { type: MUTATION name: "addBook" request { name: "book" } } }
+  }
+}
+`
+
+func testBookFiles(t *testing.T) protoreflect.FileDescriptor {
+	t.Helper()
+	return testDynamicFile(t, "book.proto", testBookFile)
+}
+
+// testDynamicFile builds files from text format descriptors and returns the file of the path
+func testDynamicFile(t *testing.T, path string, srcs ...string) protoreflect.FileDescriptor {
+	t.Helper()
+	fds := make([]*descriptorpb.FileDescriptorProto, 0, len(srcs))
+	for _, src := range srcs {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := prototext.Unmarshal([]byte(src), fd); err != nil {
+			t.Fatal(err)
+		}
+		fds = append(fds, fd)
+	}
+	files, err := newDynamicFiles(fds)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := files.FindFileByPath(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestDynamicHandlerFields(t *testing.T) {
+	file := testBookFiles(t)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	assert.NoError(t, err)
+	assert.Len(t, handlers, 1)
+	h := handlers[0]
+
+	queries := h.GetQueries(nil)
+	assert.Len(t, queries, 2)
+	assert.Equal(t, "LibraryV1_Type_Book", queries["getBook"].Type.Name())
+	assert.Equal(t, "String!", queries["getBook"].Args["title"].Type.String())
+	assert.Equal(t, "[LibraryV1_Type_Book]", queries["books"].Type.String())
+
+	mutations := h.GetMutations(nil)
+	assert.Len(t, mutations, 2)
+	assert.Contains(t, mutations, "deleteBook")
+	assert.Equal(t, "LibraryV1_Input_Book!", mutations["addBook"].Args["book"].Type.String())
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(h))
+
+	book := queries["getBook"].Type.(*graphql.Object).Fields()
+	assert.NotContains(t, book, "secret")
+	assert.Equal(t, "LibraryV1_Enum_Genre", book["genre"].Type.Name())
+	assert.Equal(t, "[LibraryV1_Type_Book_RatingsEntry]", book["ratings"].Type.String())
+	assert.Equal(t, "[LibraryV1_Type_Book]", book["related"].Type.String())
+}
+
+func TestDynamicMessageConversion(t *testing.T) {
+	file := testBookFiles(t)
+	args := map[string]interface{}{
+		"title": "Dune",
+		"genre": int32(1),
+		"ratings": []interface{}{
+			map[string]interface{}{"key": "bob", "value": 4},
+			map[string]interface{}{"key": "alice", "value": 5},
+		},
+		"cover":     "aGVsbG8=",
+		"pageCount": 412,
+		"related": []interface{}{
+			map[string]interface{}{"title": "Dune Messiah"},
+		},
+	}
+
+	msg := dynamicpb.NewMessage(file.Messages().ByName("Book"))
+	assert.NoError(t, setMessage(msg, args))
+	assert.Equal(t, int64(412), msg.Get(msg.Descriptor().Fields().ByName("page_count")).Int())
+
+	v := messageValue(msg)
+	assert.Equal(t, "Dune", v["title"])
+	assert.Equal(t, int32(1), v["genre"])
+	assert.Equal(t, []byte("hello"), v["cover"])
+	assert.Equal(t, int64(412), v["pageCount"])
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"key": "alice", "value": int32(5)},
+		map[string]interface{}{"key": "bob", "value": int32(4)},
+	}, v["ratings"])
+	assert.Equal(t, "Dune Messiah", v["related"].([]interface{})[0].(map[string]interface{})["title"])
+	assert.NotContains(t, v, "secret")
+
+	t.Run("invalid value", func(t *testing.T) {
+		msg := dynamicpb.NewMessage(file.Messages().ByName("Book"))
+		assert.Error(t, setMessage(msg, map[string]interface{}{"cover": "not base64!"}))
+		assert.Error(t, setMessage(msg, map[string]interface{}{"title": 1}))
+	})
+}
+
+const testInterfaceFile = `
+name: "interfaces.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "graphql.proto"
+options {
+  [graphql.interface] { name: "Titled" fields: "title" }
+  [graphql.interface] { name: "Unused" fields: "isbn" }
+}
+`
+
+const testShelfFile = `
+name: "shelf.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "book.proto"
+dependency: "interfaces.proto"
+message_type {
+  name: "Shelf"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+  field { name: "books" number: 2 type: TYPE_MESSAGE type_name: ".library.v1.Book" label: LABEL_REPEATED json_name: "books" }
+  field { name: "issue" number: 3 type: TYPE_MESSAGE type_name: ".library.v1.Magazine" label: LABEL_OPTIONAL json_name: "issue" }
+}
+message_type {
+  name: "Magazine"
+  field { name: "title" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "title" }
+}
+service {
+  name: "ShelfService"
+  method { name: "GetShelf" input_type: ".library.v1.ListBooksRequest" output_type: ".library.v1.Shelf" }
+}
+`
+
+func TestDynamicHandlerInterfaces(t *testing.T) {
+	file := testDynamicFile(t, "shelf.proto", testBookFile, testInterfaceFile, testShelfFile)
+	shelf := file.Messages().ByName("Shelf")
+	conn, stop, err := NewInProcessConn(
+		func(*grpc.Server) {},
+		grpc.CustomCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+			if err := stream.RecvMsg(&rawFrame{}); err != nil {
+				return err
+			}
+			out := dynamicpb.NewMessage(shelf)
+			assert.NoError(t, setMessage(out, map[string]interface{}{
+				"title": "Sci-Fi",
+				"books": []interface{}{map[string]interface{}{"title": "Dune"}},
+				"issue": map[string]interface{}{"title": 42},
+			}))
+			data, _ := proto.Marshal(out) // nolint: errcheck
+			return stream.SendMsg(&rawFrame{data: data})
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stop()
+
+	handlers, err := NewDynamicHandlers(conn, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(handlers[0]))
+
+	result := serveTestQuery(t, mux, `{
+		"query": "{ getShelf { ...titled books { ...titled } issue { title } } } fragment titled on LibraryV1_Interface_Titled { title }"
+	}`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"getShelf": map[string]interface{}{
+			"title": "Sci-Fi",
+			"books": []interface{}{map[string]interface{}{"title": "Dune"}},
+			"issue": map[string]interface{}{"title": float64(42)},
+		},
+	}, result.Data)
+
+	// Magazine doesn't implement the interface because the type of title is different
+	result = serveTestQuery(t, mux, `{"query": "{ getShelf { issue { ... on LibraryV1_Interface_Titled { title } } } }"}`)
+	assert.Len(t, result.Errors, 1)
+}
+
+const testUnwrapFile = `
+name: "unwrap.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "book.proto"
+dependency: "graphql.proto"
+message_type {
+  name: "GetTitleResponse"
+  field { name: "title" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" }
+}
+message_type {
+  name: "SetTitleRequest"
+  field { name: "book_id" number: 1 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "bookId" }
+}
+service {
+  name: "TitleService"
+  method {
+    name: "GetTitle" input_type: ".library.v1.GetBookRequest" output_type: ".library.v1.GetTitleResponse"
+    options { [graphql.schema] { type: QUERY name: "title" response { unwrap: true required: true } } }
+  }
+  method {
+    name: "FindTitle" input_type: ".library.v1.GetBookRequest" output_type: ".library.v1.GetTitleResponse"
+    options { [graphql.schema] { type: QUERY name: "findTitle" response { unwrap: true } } }
+  }
+  method {
+    name: "SetTitle" input_type: ".library.v1.SetTitleRequest" output_type: ".library.v1.GetTitleResponse"
+    options { [graphql.schema] { type: MUTATION name: "setTitle" request { name: "title" unwrap: true } } }
+  }
+}
+`
+
+func TestDynamicHandlerUnwrap(t *testing.T) {
+	file := testDynamicFile(t, "unwrap.proto", testBookFile, testUnwrapFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	h := handlers[0]
+
+	queries := h.GetQueries(nil)
+	assert.Equal(t, "String!", queries["title"].Type.String())
+	assert.Equal(t, "String", queries["findTitle"].Type.String())
+	assert.Equal(t, "title", h.FieldMappings()[0].ResponseField)
+
+	// Unwrapped request takes the field as required argument instead of input
+	args := h.GetMutations(nil)["setTitle"].Args
+	assert.Len(t, args, 1)
+	assert.Equal(t, "Int!", args["bookId"].Type.String())
+
+	// Unwrap of multiple fields message is error
+	src := strings.Replace(testUnwrapFile, ".library.v1.GetTitleResponse", ".library.v1.Book", 1)
+	_, err = NewDynamicHandlers(nil, testDynamicFile(t, "unwrap.proto", testBookFile, src).Services().Get(0))
+	assert.Error(t, err)
+	src = strings.Replace(testUnwrapFile, ".library.v1.SetTitleRequest", ".library.v1.Book", 1)
+	_, err = NewDynamicHandlers(nil, testDynamicFile(t, "unwrap.proto", testBookFile, src).Services().Get(0))
+	assert.Error(t, err)
+}
+
+const testListFile = `
+name: "list.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "graphql.proto"
+options { [graphql.list_nullability]: NON_NULL_ITEMS }
+message_type {
+  name: "Shelf"
+  field { name: "titles" number: 1 type: TYPE_STRING label: LABEL_REPEATED json_name: "titles" }
+  field { name: "tags" number: 2 type: TYPE_STRING label: LABEL_REPEATED json_name: "tags" options { [graphql.field] { required: true } } }
+  field { name: "notes" number: 3 type: TYPE_STRING label: LABEL_REPEATED json_name: "notes" options { [graphql.field] { list: NULLABLE_LIST } } }
+  field { name: "authors" number: 4 type: TYPE_STRING label: LABEL_REPEATED json_name: "authors" options { [graphql.field] { list: NON_NULL_LIST required: true } } }
+}
+service {
+  name: "ShelfService"
+  method { name: "GetShelf" input_type: ".library.v1.Shelf" output_type: ".library.v1.Shelf" }
+}
+`
+
+func TestDynamicListNullability(t *testing.T) {
+	file := testDynamicFile(t, "list.proto", testListFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	query := handlers[0].GetQueries(nil)["getShelf"]
+
+	fields := query.Type.(*graphql.Object).Fields()
+	assert.Equal(t, "[String!]", fields["titles"].Type.String())
+	assert.Equal(t, "[String!]!", fields["tags"].Type.String())
+	assert.Equal(t, "[String]", fields["notes"].Type.String())
+	assert.Equal(t, "[String]!", fields["authors"].Type.String())
+
+	// Arguments follow the same nullability
+	assert.Equal(t, "[String!]", query.Args["titles"].Type.String())
+	assert.Equal(t, "[String]!", query.Args["authors"].Type.String())
+}
+
+const testStrictFile = `
+name: "strict.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "book.proto"
+dependency: "graphql.proto"
+options { [graphql.strict_non_null]: true }
+message_type {
+  name: "Loan"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" }
+  field { name: "genre" number: 2 type: TYPE_ENUM type_name: ".library.v1.Genre" label: LABEL_OPTIONAL json_name: "genre" }
+  field { name: "book" number: 3 type: TYPE_MESSAGE type_name: ".library.v1.Book" label: LABEL_OPTIONAL json_name: "book" }
+  field { name: "note" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" options { [graphql.field] { nullable: true } } }
+  field { name: "days" number: 5 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "days" oneof_index: 0 }
+  oneof_decl { name: "period" }
+}
+service {
+  name: "LoanService"
+  method { name: "GetLoan" input_type: ".library.v1.Loan" output_type: ".library.v1.Loan" }
+}
+`
+
+func TestDynamicStrictNonNull(t *testing.T) {
+	file := testDynamicFile(t, "strict.proto", testBookFile, testStrictFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	query := handlers[0].GetQueries(nil)["getLoan"]
+
+	fields := query.Type.(*graphql.Object).Fields()
+	assert.Equal(t, "String!", fields["id"].Type.String())
+	assert.Equal(t, "LibraryV1_Enum_Genre!", fields["genre"].Type.String())
+	assert.Equal(t, "LibraryV1_Type_Book", fields["book"].Type.String())
+	assert.Equal(t, "String", fields["note"].Type.String())
+	assert.Equal(t, "Int", fields["days"].Type.String())
+
+	// Fields of files without the option and arguments are not affected
+	book := fields["book"].Type.(*graphql.Object).Fields()
+	assert.Equal(t, "String", book["title"].Type.String())
+	assert.Equal(t, "String", query.Args["id"].Type.String())
+}
+
+const testIDFile = `
+name: "id.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "graphql.proto"
+options { [graphql.id_fields]: true }
+message_type {
+  name: "Member"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" }
+  field { name: "book_ids" number: 2 type: TYPE_STRING label: LABEL_REPEATED json_name: "bookIds" }
+  field { name: "card_id" number: 3 type: TYPE_INT64 label: LABEL_OPTIONAL json_name: "cardId" }
+  field { name: "code" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "code" options { [graphql.field] { is_id: true } } }
+  field { name: "branch_id" number: 5 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "branchId" }
+  field { name: "name" number: 6 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+}
+service {
+  name: "MemberService"
+  method { name: "GetMember" input_type: ".library.v1.Member" output_type: ".library.v1.Member" }
+}
+`
+
+func TestDynamicIDFields(t *testing.T) {
+	file := testDynamicFile(t, "id.proto", testIDFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	query := handlers[0].GetQueries(nil)["getMember"]
+
+	fields := query.Type.(*graphql.Object).Fields()
+	assert.Equal(t, "ID", fields["id"].Type.String())
+	assert.Equal(t, "String", fields["name"].Type.String())
+	assert.Equal(t, "ID", fields["branchId"].Type.String())
+	assert.Equal(t, "ID", fields["code"].Type.String())
+	// Only string fields are exposed as ID
+	assert.Equal(t, "Int", fields["cardId"].Type.String())
+	assert.Equal(t, "[String]", fields["bookIds"].Type.String())
+	assert.Equal(t, "ID", query.Args["id"].Type.String())
+
+	// Name based mapping is enabled only by the file option
+	src := strings.Replace(testIDFile, "options { [graphql.id_fields]: true }", "", 1)
+	handlers, err = NewDynamicHandlers(nil, testDynamicFile(t, "id.proto", src).Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	fields = handlers[0].GetQueries(nil)["getMember"].Type.(*graphql.Object).Fields()
+	assert.Equal(t, "String", fields["id"].Type.String())
+	assert.Equal(t, "ID", fields["code"].Type.String())
+}
+
+const testMapFile = `
+name: "branch.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "book.proto"
+message_type {
+  name: "Branch"
+  field { name: "sections" number: 1 type: TYPE_MESSAGE type_name: ".library.v1.Branch.SectionsEntry" label: LABEL_REPEATED json_name: "sections" }
+  nested_type {
+    name: "SectionsEntry"
+    field { name: "key" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "key" }
+    field { name: "value" number: 2 type: TYPE_MESSAGE type_name: ".library.v1.Section" label: LABEL_OPTIONAL json_name: "value" }
+    options { map_entry: true }
+  }
+}
+message_type {
+  name: "Section"
+  field { name: "books" number: 1 type: TYPE_MESSAGE type_name: ".library.v1.Section.BooksEntry" label: LABEL_REPEATED json_name: "books" }
+  nested_type {
+    name: "BooksEntry"
+    field { name: "key" number: 1 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "key" }
+    field { name: "value" number: 2 type: TYPE_MESSAGE type_name: ".library.v1.Book" label: LABEL_OPTIONAL json_name: "value" }
+    options { map_entry: true }
+  }
+}
+service {
+  name: "BranchService"
+  method { name: "GetBranch" input_type: ".library.v1.Branch" output_type: ".library.v1.Branch" }
+}
+`
+
+func TestDynamicNestedMaps(t *testing.T) {
+	file := testDynamicFile(t, "branch.proto", testBookFile, testMapFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	query := handlers[0].GetQueries(nil)["getBranch"]
+
+	sections := query.Type.(*graphql.Object).Fields()["sections"].Type.(*graphql.List).OfType.(*graphql.Object).Fields()
+	assert.Equal(t, "String!", sections["key"].Type.String())
+	assert.Equal(t, "LibraryV1_Type_Section!", sections["value"].Type.String())
+	books := sections["value"].Type.(*graphql.NonNull).OfType.(*graphql.Object).Fields()["books"]
+	assert.Equal(t, "[LibraryV1_Type_Section_BooksEntry]", books.Type.String())
+	assert.Equal(t, "[LibraryV1_Input_Branch_SectionsEntry]", query.Args["sections"].Type.String())
+
+	msg := dynamicpb.NewMessage(file.Messages().ByName("Branch"))
+	assert.NoError(t, setMessage(msg, map[string]interface{}{
+		"sections": []interface{}{
+			map[string]interface{}{"key": "sf", "value": map[string]interface{}{
+				"books": []interface{}{
+					map[string]interface{}{"key": 2, "value": map[string]interface{}{"title": "Dune Messiah"}},
+					map[string]interface{}{"key": 1, "value": map[string]interface{}{"title": "Dune"}},
+				},
+			}},
+		},
+	}))
+	v := messageValue(msg)
+	section := v["sections"].([]interface{})[0].(map[string]interface{})
+	assert.Equal(t, "sf", section["key"])
+	entries := section["value"].(map[string]interface{})["books"].([]interface{})
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, int32(1), entries[0].(map[string]interface{})["key"])
+		assert.Equal(t, "Dune", entries[0].(map[string]interface{})["value"].(map[string]interface{})["title"])
+	}
+}
+
+const testEnumFile = `
+name: "search.proto"
+package: "library.v1"
+syntax: "proto2"
+dependency: "book.proto"
+dependency: "graphql.proto"
+message_type {
+  name: "SearchRequest"
+  field { name: "genre" number: 1 type: TYPE_ENUM type_name: ".library.v1.Genre" label: LABEL_OPTIONAL json_name: "genre" default_value: "UNKNOWN" options { [graphql.field] { default: "NOVEL" } } }
+  field { name: "exclude" number: 2 type: TYPE_ENUM type_name: ".library.v1.Genre" label: LABEL_OPTIONAL json_name: "exclude" default_value: "UNKNOWN" }
+  field { name: "limit" number: 3 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "limit" default_value: "10" }
+  field { name: "ratio" number: 4 type: TYPE_DOUBLE label: LABEL_OPTIONAL json_name: "ratio" default_value: "inf" }
+}
+service {
+  name: "SearchService"
+  method { name: "Search" input_type: ".library.v1.SearchRequest" output_type: ".library.v1.Book" }
+}
+`
+
+func TestDynamicEnumArguments(t *testing.T) {
+	file := testDynamicFile(t, "search.proto", testBookFile, testEnumFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	args := handlers[0].GetQueries(nil)["search"].Args
+	assert.Equal(t, "LibraryV1_Enum_Genre", args["genre"].Type.Name())
+	// Default value of graphql.field option takes precedence over proto2 default value
+	assert.Equal(t, int32(1), args["genre"].DefaultValue)
+	assert.Equal(t, int32(0), args["exclude"].DefaultValue)
+	assert.Equal(t, 10, args["limit"].DefaultValue)
+	assert.Nil(t, args["ratio"].DefaultValue)
+
+	msg := dynamicpb.NewMessage(file.Messages().ByName("SearchRequest"))
+	assert.NoError(t, setMessage(msg, map[string]interface{}{"genre": int32(1)}))
+	assert.Equal(t, protoreflect.EnumNumber(1), msg.Get(msg.Descriptor().Fields().ByName("genre")).Enum())
+}
+
+const testCycleFile = `
+name: "category.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "graphql.proto"
+message_type {
+  name: "Category"
+  field { name: "name" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "name" }
+  field { name: "parent" number: 2 type: TYPE_MESSAGE type_name: ".library.v1.Category" label: LABEL_OPTIONAL json_name: "parent" }
+  field { name: "section" number: 3 type: TYPE_MESSAGE type_name: ".library.v1.Section" label: LABEL_OPTIONAL json_name: "section" options { [graphql.field] { required: true } } }
+}
+message_type {
+  name: "Section"
+  field { name: "category" number: 1 type: TYPE_MESSAGE type_name: ".library.v1.Category" label: LABEL_OPTIONAL json_name: "category" }
+}
+service {
+  name: "CategoryService"
+  method { name: "GetCategory" input_type: ".library.v1.Category" output_type: ".library.v1.Category" }
+}
+`
+
+func TestDynamicInputCycles(t *testing.T) {
+	// Cycles which have nullable fields can be given
+	handlers, err := NewDynamicHandlers(nil, testDynamicFile(t, "category.proto", testCycleFile).Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(handlers[0]))
+	result := serveTestQuery(t, mux, `{"query":"{ __type(name: \"LibraryV1_Input_Section\") { inputFields { name } } }"}`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"__type": map[string]interface{}{
+			"inputFields": []interface{}{map[string]interface{}{"name": "category"}},
+		},
+	}, result.Data)
+
+	src := strings.Replace(testCycleFile, `json_name: "category" }`, `json_name: "category" options { [graphql.field] { required: true } } }`, 1)
+	_, err = NewDynamicHandlers(nil, testDynamicFile(t, "category.proto", src).Services().Get(0))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "library.v1.Category.section -> library.v1.Section.category -> library.v1.Category")
+	}
+}
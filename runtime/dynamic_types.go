@@ -0,0 +1,596 @@
+package runtime
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/iancoleman/strcase"
+	gqlproto "github.com/ysugimoto/grpc-graphql-gateway/graphql"
+	"github.com/ysugimoto/grpc-graphql-gateway/ptypes/empty"
+	"github.com/ysugimoto/grpc-graphql-gateway/ptypes/timestamp"
+	"github.com/ysugimoto/grpc-graphql-gateway/ptypes/wrappers"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Well-known types which use the same GraphQL types as generated code
+var (
+	dynamicWellKnownTypes = map[protoreflect.FullName]func() *graphql.Object{
+		"google.protobuf.Empty":       empty.Gql__type_Empty,
+		"google.protobuf.Timestamp":   timestamp.Gql__type_Timestamp,
+		"google.protobuf.DoubleValue": wrappers.Gql__type_DoubleValue,
+		"google.protobuf.FloatValue":  wrappers.Gql__type_FloatValue,
+		"google.protobuf.Int64Value":  wrappers.Gql__type_Int64Value,
+		"google.protobuf.UInt64Value": wrappers.Gql__type_Uint64Value,
+		"google.protobuf.Int32Value":  wrappers.Gql__type_Int32Value,
+		"google.protobuf.UInt32Value": wrappers.Gql__type_Uint32Value,
+		"google.protobuf.BoolValue":   wrappers.Gql__type_BoolValue,
+		"google.protobuf.StringValue": wrappers.Gql__type_StringValue,
+	}
+	dynamicWellKnownInputs = map[protoreflect.FullName]func() *graphql.InputObject{
+		"google.protobuf.Empty":       empty.Gql__input_Empty,
+		"google.protobuf.Timestamp":   timestamp.Gql__input_Timestamp,
+		"google.protobuf.DoubleValue": wrappers.Gql__input_DoubleValue,
+		"google.protobuf.FloatValue":  wrappers.Gql__input_FloatValue,
+		"google.protobuf.Int64Value":  wrappers.Gql__input_Int64Value,
+		"google.protobuf.UInt64Value": wrappers.Gql__input_Uint64Value,
+		"google.protobuf.Int32Value":  wrappers.Gql__input_Int32Value,
+		"google.protobuf.UInt32Value": wrappers.Gql__input_Uint32Value,
+		"google.protobuf.BoolValue":   wrappers.Gql__input_BoolValue,
+		"google.protobuf.StringValue": wrappers.Gql__input_StringValue,
+	}
+)
+
+// dynamicTypes builds GraphQL types from protobuf descriptors and caches them by full name,
+// because a schema must not contain different types with the same name
+type dynamicTypes struct {
+	mu         sync.Mutex
+	objects    map[protoreflect.FullName]*graphql.Object
+	inputs     map[protoreflect.FullName]*graphql.InputObject
+	enums      map[protoreflect.FullName]*graphql.Enum
+	interfaces []*dynamicInterface
+}
+
+// dynamicInterface is interface of graphql.interface file option which messages implement automatically
+type dynamicInterface struct {
+	fields []string
+	iface  *graphql.Interface
+	// The first message which exposes all of the fields in file order, whose field types are the interface fields
+	implement protoreflect.MessageDescriptor
+}
+
+func newDynamicTypes() *dynamicTypes {
+	return &dynamicTypes{
+		objects: make(map[protoreflect.FullName]*graphql.Object),
+		inputs:  make(map[protoreflect.FullName]*graphql.InputObject),
+		enums:   make(map[protoreflect.FullName]*graphql.Enum),
+	}
+}
+
+// addInterfaces declares interfaces of graphql.interface option in the files.
+// Interfaces are declared once per name, and ones which no message implements are ignored.
+func (t *dynamicTypes) addInterfaces(files []protoreflect.FileDescriptor) {
+	for _, fd := range files {
+		opts := fd.Options()
+		if opts == nil || !proto.HasExtension(opts, gqlproto.E_Interface) {
+			continue
+		}
+		decls, _ := proto.GetExtension(opts, gqlproto.E_Interface).([]*gqlproto.GraphqlInterface) // nolint: errcheck
+		for _, decl := range decls {
+			name := strcase.ToCamel(string(fd.Package())) + "_Interface_" + decl.GetName()
+			if decl.GetName() == "" || len(decl.GetFields()) == 0 || t.hasInterface(name) {
+				continue
+			}
+			di := &dynamicInterface{
+				fields:    decl.GetFields(),
+				implement: firstImplementing(files, decl.GetFields()),
+			}
+			if di.implement == nil {
+				continue
+			}
+			di.iface = graphql.NewInterface(graphql.InterfaceConfig{
+				Name: name,
+				Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+					fields := graphql.Fields{}
+					for _, name := range di.fields {
+						fields[name] = &graphql.Field{
+							Type: t.fieldOutput(exposedField(di.implement, name)),
+						}
+					}
+					return fields
+				}),
+			})
+			t.interfaces = append(t.interfaces, di)
+		}
+	}
+}
+
+func (t *dynamicTypes) hasInterface(name string) bool {
+	for _, di := range t.interfaces {
+		if di.iface.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// implemented returns interfaces which the message implements.
+// The message implements the interface if it exposes all of the fields with the same types as the first implementing message.
+func (t *dynamicTypes) implemented(md protoreflect.MessageDescriptor) []*graphql.Interface {
+	var ifaces []*graphql.Interface
+	for _, di := range t.interfaces {
+		implements := true
+		for _, name := range di.fields {
+			fd := exposedField(md, name)
+			if fd == nil || t.fieldOutput(fd).String() != t.fieldOutput(exposedField(di.implement, name)).String() {
+				implements = false
+				break
+			}
+		}
+		if implements {
+			ifaces = append(ifaces, di.iface)
+		}
+	}
+	return ifaces
+}
+
+// firstImplementing returns the first message in the files which exposes all of the fields
+func firstImplementing(files []protoreflect.FileDescriptor, fields []string) protoreflect.MessageDescriptor {
+	var find func(messages protoreflect.MessageDescriptors) protoreflect.MessageDescriptor
+	find = func(messages protoreflect.MessageDescriptors) protoreflect.MessageDescriptor {
+		for i := 0; i < messages.Len(); i++ {
+			md := messages.Get(i)
+			implements := !md.IsMapEntry()
+			for _, name := range fields {
+				if exposedField(md, name) == nil {
+					implements = false
+					break
+				}
+			}
+			if implements {
+				return md
+			}
+			if nested := find(md.Messages()); nested != nil {
+				return nested
+			}
+		}
+		return nil
+	}
+	for _, fd := range files {
+		// Messages of options and well-known types are not the ones of services
+		if pkg := fd.Package(); pkg == "google.protobuf" || pkg == "graphql" {
+			continue
+		}
+		if md := find(fd.Messages()); md != nil {
+			return md
+		}
+	}
+	return nil
+}
+
+// exposedField returns the field of GraphQL field name which is not omitted
+func exposedField(md protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if dynamicFieldName(fd) == name && !fieldOption(fd).GetOmit() {
+			return fd
+		}
+	}
+	return nil
+}
+
+// importedFiles returns the file and its imports transitively in dependency order
+func importedFiles(fd protoreflect.FileDescriptor, visited map[string]struct{}) []protoreflect.FileDescriptor {
+	if _, ok := visited[fd.Path()]; ok {
+		return nil
+	}
+	visited[fd.Path()] = struct{}{}
+	var files []protoreflect.FileDescriptor
+	for i := 0; i < fd.Imports().Len(); i++ {
+		files = append(files, importedFiles(fd.Imports().Get(i).FileDescriptor, visited)...)
+	}
+	return append(files, fd)
+}
+
+// dynamicTypeName returns type name like generated code, e.g. "Package_Type_Message_Nested"
+func dynamicTypeName(d protoreflect.Descriptor, kind string) string {
+	pkg := d.ParentFile().Package()
+	name := strings.TrimPrefix(string(d.FullName()), string(pkg)+".")
+	return strcase.ToCamel(string(pkg)) + "_" + kind + "_" + strings.ReplaceAll(name, ".", "_")
+}
+
+// dynamicFieldName returns GraphQL field name which is declared in graphql.field option or lower camel cased field name
+func dynamicFieldName(fd protoreflect.FieldDescriptor) string {
+	if name := fieldOption(fd).GetName(); name != "" {
+		return name
+	}
+	return fd.JSONName()
+}
+
+// fieldOption returns graphql.field option of the field, nil getters are safe to call if the field doesn't have it
+func fieldOption(fd protoreflect.FieldDescriptor) *gqlproto.GraphqlField {
+	opts := fd.Options()
+	if opts == nil || !proto.HasExtension(opts, gqlproto.E_Field) {
+		return nil
+	}
+	opt, _ := proto.GetExtension(opts, gqlproto.E_Field).(*gqlproto.GraphqlField) // nolint: errcheck
+	return opt
+}
+
+func (t *dynamicTypes) object(md protoreflect.MessageDescriptor) *graphql.Object {
+	if fn, ok := dynamicWellKnownTypes[md.FullName()]; ok {
+		return fn()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if obj, ok := t.objects[md.FullName()]; ok {
+		return obj
+	}
+	name := dynamicTypeName(md, "Type")
+	// Fields are resolved lazily to support cyclic messages
+	obj := graphql.NewObject(graphql.ObjectConfig{
+		Name: name,
+		Interfaces: (graphql.InterfacesThunk)(func() []*graphql.Interface {
+			return t.implemented(md)
+		}),
+		Fields: (graphql.FieldsThunk)(func() graphql.Fields {
+			fields := graphql.Fields{}
+			for i := 0; i < md.Fields().Len(); i++ {
+				fd := md.Fields().Get(i)
+				if fieldOption(fd).GetOmit() {
+					continue
+				}
+				fields[dynamicFieldName(fd)] = &graphql.Field{
+					Type: t.fieldOutput(fd),
+				}
+				if directives := fieldOption(fd).GetDirectives(); len(directives) > 0 {
+					SetFieldDirectives(name, dynamicFieldName(fd), directives...)
+				}
+			}
+			// GraphQL object must have at least one field, declare placeholder for empty message
+			if len(fields) == 0 {
+				fields["_"] = &graphql.Field{
+					Type: graphql.Boolean,
+				}
+			}
+			return fields
+		}),
+	})
+	t.objects[md.FullName()] = obj
+	return obj
+}
+
+func (t *dynamicTypes) input(md protoreflect.MessageDescriptor) *graphql.InputObject {
+	if fn, ok := dynamicWellKnownInputs[md.FullName()]; ok {
+		return fn()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if in, ok := t.inputs[md.FullName()]; ok {
+		return in
+	}
+	name := dynamicTypeName(md, "Input")
+	if isOneofMessage(md) {
+		SetOneofInput(name)
+	}
+	in := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: name,
+		Fields: (graphql.InputObjectConfigFieldMapThunk)(func() graphql.InputObjectConfigFieldMap {
+			fields := graphql.InputObjectConfigFieldMap{}
+			for i := 0; i < md.Fields().Len(); i++ {
+				fd := md.Fields().Get(i)
+				opt := fieldOption(fd)
+				if opt.GetOmit() {
+					continue
+				}
+				field := &graphql.InputObjectFieldConfig{
+					Type: t.fieldInput(fd),
+				}
+				field.DefaultValue = defaultValue(fd)
+				fields[dynamicFieldName(fd)] = field
+			}
+			if len(fields) == 0 {
+				fields["_"] = &graphql.InputObjectFieldConfig{
+					Type: graphql.Boolean,
+				}
+			}
+			return fields
+		}),
+	})
+	t.inputs[md.FullName()] = in
+	return in
+}
+
+func (t *dynamicTypes) enum(ed protoreflect.EnumDescriptor) *graphql.Enum {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.enums[ed.FullName()]; ok {
+		return e
+	}
+	values := graphql.EnumValueConfigMap{}
+	for i := 0; i < ed.Values().Len(); i++ {
+		v := ed.Values().Get(i)
+		values[string(v.Name())] = &graphql.EnumValueConfig{
+			Value: int32(v.Number()),
+		}
+	}
+	e := graphql.NewEnum(graphql.EnumConfig{
+		Name:   dynamicTypeName(ed, "Enum"),
+		Values: values,
+	})
+	t.enums[ed.FullName()] = e
+	return e
+}
+
+// fieldOutput returns GraphQL output type of the field.
+// Map field is exposed as a list of key-value object like generated code.
+func (t *dynamicTypes) fieldOutput(fd protoreflect.FieldDescriptor) graphql.Output {
+	var typ graphql.Output
+	switch kind := fd.Kind(); {
+	case isCustomScalarField(fd):
+		typ = CustomScalar(fieldOption(fd).GetScalar())
+	case kind == protoreflect.MessageKind || kind == protoreflect.GroupKind:
+		typ = t.object(fd.Message())
+	case kind == protoreflect.EnumKind:
+		typ = t.enum(fd.Enum())
+	default:
+		typ = fieldScalar(fd)
+	}
+	if !fd.IsList() && !fd.IsMap() {
+		if fieldOption(fd).GetRequired() || isMapEntryField(fd) || isStrictNonNull(fd) {
+			typ = graphql.NewNonNull(typ)
+		}
+		return typ
+	}
+	list, items := listNullability(fd)
+	if items {
+		typ = graphql.NewNonNull(typ)
+	}
+	typ = graphql.NewList(typ)
+	if list {
+		typ = graphql.NewNonNull(typ)
+	}
+	return typ
+}
+
+// fieldInput returns GraphQL input type of the field
+func (t *dynamicTypes) fieldInput(fd protoreflect.FieldDescriptor) graphql.Input {
+	var typ graphql.Input
+	switch kind := fd.Kind(); {
+	case isCustomScalarField(fd):
+		typ = CustomScalar(fieldOption(fd).GetScalar())
+	case kind == protoreflect.MessageKind || kind == protoreflect.GroupKind:
+		typ = t.input(fd.Message())
+	case kind == protoreflect.EnumKind:
+		typ = t.enum(fd.Enum())
+	default:
+		typ = fieldScalar(fd)
+	}
+	if !fd.IsList() && !fd.IsMap() {
+		if fieldOption(fd).GetRequired() || isMapEntryField(fd) {
+			typ = graphql.NewNonNull(typ)
+		}
+		return typ
+	}
+	list, items := listNullability(fd)
+	if items {
+		typ = graphql.NewNonNull(typ)
+	}
+	typ = graphql.NewList(typ)
+	if list {
+		typ = graphql.NewNonNull(typ)
+	}
+	return typ
+}
+
+// inputCycle returns fields of a cycle of non-null input fields reachable from md like ["pkg.A.b", "pkg.B.a", "pkg.A"],
+// or nil if not found. GraphQL cannot represent the cycle because a value of the input never ends.
+func inputCycle(md protoreflect.MessageDescriptor) []string {
+	var reachable []protoreflect.MessageDescriptor
+	seen := map[protoreflect.FullName]struct{}{}
+	var collect func(md protoreflect.MessageDescriptor)
+	collect = func(md protoreflect.MessageDescriptor) {
+		if _, ok := seen[md.FullName()]; ok {
+			return
+		}
+		seen[md.FullName()] = struct{}{}
+		reachable = append(reachable, md)
+		for i := 0; i < md.Fields().Len(); i++ {
+			if fd := md.Fields().Get(i); fd.Message() != nil && !fieldOption(fd).GetOmit() {
+				collect(fd.Message())
+			}
+		}
+	}
+	collect(md)
+
+	var path []string
+	onPath := map[protoreflect.FullName]int{}
+	done := map[protoreflect.FullName]struct{}{}
+	var visit func(md protoreflect.MessageDescriptor) []string
+	visit = func(md protoreflect.MessageDescriptor) []string {
+		onPath[md.FullName()] = len(path)
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			opt := fieldOption(fd)
+			if fd.Message() == nil || fd.IsList() || fd.IsMap() || opt.GetOmit() || !opt.GetRequired() {
+				continue
+			}
+			path = append(path, string(md.FullName())+"."+string(fd.Name()))
+			dep := fd.Message().FullName()
+			if start, ok := onPath[dep]; ok {
+				return append(path[start:], string(dep))
+			}
+			if _, ok := done[dep]; !ok {
+				if cycle := visit(fd.Message()); cycle != nil {
+					return cycle
+				}
+			}
+			path = path[:len(path)-1]
+		}
+		delete(onPath, md.FullName())
+		done[md.FullName()] = struct{}{}
+		return nil
+	}
+	for _, m := range reachable {
+		if _, ok := done[m.FullName()]; ok {
+			continue
+		}
+		if cycle := visit(m); cycle != nil {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// isMapEntryField returns true if the field is key or value of map entry, which are always non-null like generated types
+func isMapEntryField(fd protoreflect.FieldDescriptor) bool {
+	md, ok := fd.Parent().(protoreflect.MessageDescriptor)
+	return ok && md.IsMapEntry()
+}
+
+// isStrictNonNull returns true if the field is non-null in output types by strict_non_null file option.
+// Scalar and enum fields always have zero values in proto3 unless they are in oneof.
+func isStrictNonNull(fd protoreflect.FieldDescriptor) bool {
+	if fd.Syntax() != protoreflect.Proto3 || fd.ContainingOneof() != nil || fd.Message() != nil || fieldOption(fd).GetNullable() {
+		return false
+	}
+	opts := fd.ParentFile().Options()
+	if opts == nil || !proto.HasExtension(opts, gqlproto.E_StrictNonNull) {
+		return false
+	}
+	strict, _ := proto.GetExtension(opts, gqlproto.E_StrictNonNull).(bool) // nolint: errcheck
+	return strict
+}
+
+// listNullability returns whether the list and its items of repeated field are non-null.
+// The list option of the field takes precedence, then required fields have non-null list
+// with the nullability of list_nullability file option.
+func listNullability(fd protoreflect.FieldDescriptor) (list, items bool) {
+	mode := fieldOption(fd).GetList()
+	if mode == gqlproto.GraphqlListNullability_LIST_DEFAULT {
+		if opts := fd.ParentFile().Options(); opts != nil && proto.HasExtension(opts, gqlproto.E_ListNullability) {
+			mode, _ = proto.GetExtension(opts, gqlproto.E_ListNullability).(gqlproto.GraphqlListNullability) // nolint: errcheck
+		}
+		if fieldOption(fd).GetRequired() {
+			list = true
+		}
+	}
+	switch mode {
+	case gqlproto.GraphqlListNullability_NON_NULL_LIST:
+		return true, false
+	case gqlproto.GraphqlListNullability_NON_NULL_ITEMS:
+		return list, true
+	case gqlproto.GraphqlListNullability_NON_NULL_LIST_AND_ITEMS:
+		return true, true
+	}
+	return list, false
+}
+
+// fieldScalar returns GraphQL scalar of the field, string fields of identifiers are exposed as ID
+// and bytes fields in the file of bytes_scalar option are exposed as Bytes
+func fieldScalar(fd protoreflect.FieldDescriptor) *graphql.Scalar {
+	if isIDField(fd) {
+		return graphql.ID
+	}
+	if fd.Kind() == protoreflect.BytesKind && isBytesScalarFile(fd.ParentFile()) {
+		return Bytes
+	}
+	return scalarType(fd.Kind())
+}
+
+// isBytesScalarFile returns true if bytes fields of the file are exposed as Bytes scalar by bytes_scalar option
+func isBytesScalarFile(file protoreflect.FileDescriptor) bool {
+	opts := file.Options()
+	if opts == nil || !proto.HasExtension(opts, gqlproto.E_BytesScalar) {
+		return false
+	}
+	enabled, _ := proto.GetExtension(opts, gqlproto.E_BytesScalar).(bool) // nolint: errcheck
+	return enabled
+}
+
+// isCustomScalarField returns true if the field is exposed as custom scalar of graphql.field scalar option.
+// Map fields are exposed as lists of entries, so that the option is ignored.
+func isCustomScalarField(fd protoreflect.FieldDescriptor) bool {
+	return fieldOption(fd).GetScalar() != "" && !fd.IsMap()
+}
+
+// isIDField returns true if the string field is declared as ID by is_id option,
+// or it is named "id" or suffixed with "_id" in the file of id_fields option
+func isIDField(fd protoreflect.FieldDescriptor) bool {
+	if fd.Kind() != protoreflect.StringKind {
+		return false
+	}
+	if fieldOption(fd).GetIsId() {
+		return true
+	}
+	name := strcase.ToSnake(string(fd.Name()))
+	if name != "id" && !strings.HasSuffix(name, "_id") {
+		return false
+	}
+	opts := fd.ParentFile().Options()
+	if opts == nil || !proto.HasExtension(opts, gqlproto.E_IdFields) {
+		return false
+	}
+	enabled, _ := proto.GetExtension(opts, gqlproto.E_IdFields).(bool) // nolint: errcheck
+	return enabled
+}
+
+// scalarType returns GraphQL scalar of protobuf kind, bytes are exposed as String of base64 encoded string
+func scalarType(kind protoreflect.Kind) *graphql.Scalar {
+	switch kind {
+	case protoreflect.BoolKind:
+		return graphql.Boolean
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return graphql.Float
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return graphql.String
+	default:
+		return graphql.Int
+	}
+}
+
+// defaultValue parses default value of graphql.field option, or default value of proto2 field.
+// Returns nil if the field doesn't have default value or the value is invalid for the field.
+func defaultValue(fd protoreflect.FieldDescriptor) interface{} {
+	v := fieldOption(fd).GetDefault()
+	if v == "" && fd.HasDefault() && fd.Kind() != protoreflect.BytesKind {
+		if fd.Kind() == protoreflect.EnumKind {
+			v = string(fd.DefaultEnumValue().Name())
+		} else {
+			v = fmt.Sprint(fd.Default().Interface())
+		}
+	}
+	if v == "" {
+		return nil
+	}
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		// GraphQL cannot express infinity and NaN of proto2 default value
+		if f, err := strconv.ParseFloat(v, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+			return f
+		}
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		return v
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(v)); ev != nil {
+			return int32(ev.Number())
+		}
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+	default:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// UnknownEnumPolicy declares how enum values which are not defined in the schema are resolved.
+// Upstreams which are built with newer proto definitions may return such values.
+type UnknownEnumPolicy int
+
+const (
+	// UnknownEnumNull resolves unknown values as null, which is the behavior of GraphQL enum
+	UnknownEnumNull UnknownEnumPolicy = iota
+	// UnknownEnumError resolves the field with an error which reports the unknown value
+	UnknownEnumError
+	// UnknownEnumPassThrough responds numeric values of unknown values as they are
+	UnknownEnumPassThrough
+)
+
+// unknownEnumPolicies are policies by name which are enabled by "unknown_enum" of the config file
+var unknownEnumPolicies = map[string]UnknownEnumPolicy{
+	"null":         UnknownEnumNull,
+	"error":        UnknownEnumError,
+	"pass_through": UnknownEnumPassThrough,
+}
+
+type unknownEnumKey struct{}
+
+// unknownEnums is request scoped state of the policy, which holds numeric values to be responded in pass-through policy
+type unknownEnums struct {
+	policy UnknownEnumPolicy
+
+	mu      sync.Mutex
+	patches []enumPatch
+}
+
+// enumPatch is numeric value of unknown enum value at the response path
+type enumPatch struct {
+	path  []interface{}
+	value int64
+}
+
+// WithUnknownEnumPolicy is middleware function to declare how unknown enum values returned by upstreams are resolved.
+// In pass-through policy, unknown values are resolved as the first value of the enum during execution
+// so that non-null fields are not nullified, then replaced with numeric values in the result.
+func WithUnknownEnumPolicy(policy UnknownEnumPolicy) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, unknownEnumKey{}, &unknownEnums{policy: policy}), nil
+	}
+}
+
+// resolveEnum applies unknown enum policy in the context to the value of enum and list of enum field
+func resolveEnum(p graphql.ResolveParams, value interface{}) (interface{}, error) {
+	u, ok := p.Context.Value(unknownEnumKey{}).(*unknownEnums)
+	if !ok || u.policy == UnknownEnumNull || value == nil {
+		return value, nil
+	}
+	t := graphql.Type(p.Info.ReturnType)
+	if nn, ok := t.(*graphql.NonNull); ok {
+		t = nn.OfType
+	}
+	if e, ok := t.(*graphql.Enum); ok {
+		return u.resolve(e, p.Info.Path, value)
+	}
+	list, ok := t.(*graphql.List)
+	if !ok {
+		return value, nil
+	}
+	t = list.OfType
+	if nn, ok := t.(*graphql.NonNull); ok {
+		t = nn.OfType
+	}
+	e, ok := t.(*graphql.Enum)
+	if !ok {
+		return value, nil
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return value, nil
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		item, err := u.resolve(e, p.Info.Path.WithKey(i), rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+	return items, nil
+}
+
+func (u *unknownEnums) resolve(e *graphql.Enum, path *graphql.ResponsePath, v interface{}) (interface{}, error) {
+	if e.Serialize(v) != nil {
+		return v, nil
+	}
+	rv := derefValue(reflect.ValueOf(v))
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+	default:
+		// Nil pointer or not a numeric value
+		return v, nil
+	}
+	if u.policy == UnknownEnumError {
+		return nil, NewFieldError("UNKNOWN_ENUM_VALUE", fmt.Sprintf("unknown value %d of enum %s", rv.Int(), e.Name()))
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.patches = append(u.patches, enumPatch{
+		path:  path.AsArray(),
+		value: rv.Int(),
+	})
+	return e.Values()[0].Value, nil
+}
+
+// patchUnknownEnums replaces placeholders of unknown enum values in the result with numeric values
+func patchUnknownEnums(ctx context.Context, result *graphql.Result) {
+	u, ok := ctx.Value(unknownEnumKey{}).(*unknownEnums)
+	if !ok {
+		return
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for _, patch := range u.patches {
+		setResultValue(result.Data, patch.path, patch.value)
+	}
+}
+
+// setResultValue sets v at the path of the result data, does nothing if the path has been nullified by errors
+func setResultValue(data interface{}, path []interface{}, v interface{}) {
+	for i, key := range path {
+		last := i == len(path)-1
+		switch k := key.(type) {
+		case string:
+			m, ok := data.(map[string]interface{})
+			if !ok || m[k] == nil {
+				return
+			}
+			if last {
+				m[k] = v
+				return
+			}
+			data = m[k]
+		case int:
+			list, ok := data.([]interface{})
+			if !ok || k >= len(list) || list[k] == nil {
+				return
+			}
+			if last {
+				list[k] = v
+				return
+			}
+			data = list[k]
+		}
+	}
+}
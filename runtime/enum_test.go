@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+var testGenreEnum = graphql.NewEnum(graphql.EnumConfig{
+	Name: "Genre",
+	Values: graphql.EnumValueConfigMap{
+		"NOVEL": &graphql.EnumValueConfig{Value: int32(1)},
+		"POEM":  &graphql.EnumValueConfig{Value: int32(2)},
+	},
+})
+
+func newEnumTestHandler() *testHandler {
+	return &testHandler{
+		queries: graphql.Fields{
+			"book": &graphql.Field{
+				Type: graphql.NewObject(graphql.ObjectConfig{
+					Name: "Book",
+					Fields: graphql.Fields{
+						"genre":    &graphql.Field{Type: testGenreEnum},
+						"required": &graphql.Field{Type: graphql.NewNonNull(testGenreEnum)},
+						"genres":   &graphql.Field{Type: graphql.NewList(testGenreEnum)},
+					},
+				}),
+				Args: graphql.FieldConfigArgument{
+					"genre": &graphql.ArgumentConfig{
+						Type:         testGenreEnum,
+						DefaultValue: int32(2),
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return map[string]interface{}{
+						"genre":    p.Args["genre"],
+						"required": int32(9),
+						"genres":   []int32{1, 7},
+					}, nil
+				},
+			},
+		},
+	}
+}
+
+func TestUnknownEnumPolicy(t *testing.T) {
+	query := `{ book { genre genres } }`
+
+	t.Run("null", func(t *testing.T) {
+		mux := NewServeMux()
+		assert.NoError(t, mux.AddHandler(newEnumTestHandler()))
+		result := serveTestQuery(t, mux, query)
+		assert.Len(t, result.Errors, 0)
+		assert.Equal(t, map[string]interface{}{
+			"book": map[string]interface{}{
+				"genre":  "POEM",
+				"genres": []interface{}{"NOVEL", nil},
+			},
+		}, result.Data)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		mux := NewServeMux()
+		mux.Use(WithUnknownEnumPolicy(UnknownEnumError))
+		assert.NoError(t, mux.AddHandler(newEnumTestHandler()))
+		result := serveTestQuery(t, mux, `{ book(genre: NOVEL) { genre genres } }`)
+		if assert.Len(t, result.Errors, 1) {
+			assert.Equal(t, "unknown value 7 of enum Genre", result.Errors[0].Message)
+			assert.Equal(t, "UNKNOWN_ENUM_VALUE", result.Errors[0].Extensions["code"])
+		}
+		assert.Equal(t, map[string]interface{}{
+			"book": map[string]interface{}{
+				"genre":  "NOVEL",
+				"genres": nil,
+			},
+		}, result.Data)
+	})
+
+	t.Run("pass through", func(t *testing.T) {
+		mux := NewServeMux()
+		mux.Use(WithUnknownEnumPolicy(UnknownEnumPassThrough))
+		assert.NoError(t, mux.AddHandler(newEnumTestHandler()))
+		result := serveTestQuery(t, mux, `{ book { genre required genres } alias: book { required } }`)
+		assert.Len(t, result.Errors, 0)
+		assert.Equal(t, map[string]interface{}{
+			"book": map[string]interface{}{
+				"genre":    "POEM",
+				"required": float64(9),
+				"genres":   []interface{}{"NOVEL", float64(7)},
+			},
+			"alias": map[string]interface{}{
+				"required": float64(9),
+			},
+		}, result.Data)
+	})
+}
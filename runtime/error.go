@@ -38,3 +38,28 @@ func defaultGraphqlErrorHandler(errs []GraphqlError) {
 		errs[i] = e
 	}
 }
+
+// FieldError is an error which is returned from field resolver with extension code.
+// graphql-go puts the code into "extensions" of formatted error.
+type FieldError struct {
+	Code    string
+	Message string
+}
+
+func (f *FieldError) Error() string {
+	return f.Message
+}
+
+// Extensions implements gqlerrors.ExtendedError
+func (f *FieldError) Extensions() map[string]interface{} {
+	return map[string]interface{}{
+		"code": f.Code,
+	}
+}
+
+func NewFieldError(code, message string) *FieldError {
+	return &FieldError{
+		Code:    code,
+		Message: message,
+	}
+}
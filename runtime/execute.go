@@ -0,0 +1,67 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+
+	"encoding/json"
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// resultRecorder is http.ResponseWriter of in-process execution which keeps the result instead of writing it
+type resultRecorder struct {
+	header http.Header
+	result *graphql.Result
+}
+
+func (r *resultRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *resultRecorder) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+func (r *resultRecorder) WriteHeader(statusCode int) {}
+
+// Execute runs the operation against registered handlers in the same process without HTTP server,
+// e.g. from backend code or integration tests. The operation is served like POST request to the gateway,
+// so middlewares run with the request which has the context, and variables are passed as JSON.
+// Response headers which are set by middlewares and upstream metadata are discarded.
+func (s *ServeMux) Execute(ctx context.Context, query string, variables map[string]interface{}, operationName string) *graphql.Result {
+	body, err := json.Marshal(GraphqlRequest{
+		Query:         query,
+		Variables:     variables,
+		OperationName: operationName,
+	})
+	if err != nil {
+		return executeError("Failed to encode request: " + err.Error())
+	}
+	r, err := http.NewRequestWithContext(ctx, http.MethodPost, "/graphql", bytes.NewReader(body))
+	if err != nil {
+		return executeError("Failed to create request: " + err.Error())
+	}
+	r.Header.Set("Content-Type", "application/json")
+
+	w := &resultRecorder{header: http.Header{}}
+	s.serve(w, r, s.middlewares)
+	if w.result == nil {
+		return executeError("Response is written by middleware")
+	}
+	return w.result
+}
+
+func executeError(message string) *graphql.Result {
+	return &graphql.Result{
+		Errors: []GraphqlError{
+			{
+				Message: message,
+				Extensions: map[string]interface{}{
+					"code": "EXECUTE_ERROR",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"net/http"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type executeTestKey struct{}
+
+func TestServeMuxExecute(t *testing.T) {
+	var value interface{}
+	mux := NewServeMux(func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		value = r.Context().Value(executeTestKey{})
+		return ctx, nil
+	})
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	ctx := context.WithValue(context.Background(), executeTestKey{}, "value")
+	result := mux.Execute(ctx, `query GetUser($id: String) { user(id: $id) { id } }`, map[string]interface{}{"id": "1"}, "GetUser")
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{"id": "1"},
+	}, result.Data)
+	assert.Equal(t, "value", value)
+
+	result = mux.Execute(context.Background(), `{ unknown }`, nil, "")
+	assert.Nil(t, result.Data)
+	assert.Len(t, result.Errors, 1)
+}
+
+func TestServeMuxExecuteResponded(t *testing.T) {
+	mux := NewServeMux(func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		w.WriteHeader(http.StatusNoContent)
+		return ctx, errResponded
+	})
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := mux.Execute(context.Background(), `{ hello }`, nil, "")
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "EXECUTE_ERROR", result.Errors[0].Extensions["code"])
+	}
+}
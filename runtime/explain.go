@@ -0,0 +1,326 @@
+package runtime
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+const (
+	// ExplainHeader is request header to explain the query instead of executing it, which is same as ExplainExtension
+	ExplainHeader = "X-GraphQL-Explain"
+	// ExplainExtension is request extension flag like {"extensions": {"explain": true}},
+	// and response extension key which has ExplainPlan
+	ExplainExtension = "explain"
+)
+
+// ExplainPlan describes gRPC calls which the operation would trigger
+type ExplainPlan struct {
+	// Operation is "query" or "mutation"
+	Operation string `json:"operation"`
+	Name      string `json:"name,omitempty"`
+	// Parallel reports whether the calls run concurrently.
+	// Root fields are resolved one by one, and mutations are executed serially by the spec.
+	Parallel bool          `json:"parallel"`
+	Calls    []ExplainCall `json:"calls"`
+}
+
+// ExplainCall is gRPC call of the root field
+type ExplainCall struct {
+	// Path is response path of the field which respects aliases, like "books" or "legacy.hello"
+	Path string `json:"path"`
+	// Field is schema field which is mapped via FieldMappings
+	Field   string `json:"field"`
+	Service string `json:"service,omitempty"`
+	// Method is empty if the handler doesn't implement FieldMapper
+	Method string `json:"method,omitempty"`
+	// Request is payload which is derived from arguments and variables, keyed by request message field names
+	Request map[string]interface{} `json:"request"`
+}
+
+type explainAllowedKey struct{}
+type explainRequestedKey struct{}
+
+// WithExplain is middleware function to allow explain mode for requests which pass the predicate.
+// Requests which have ExplainHeader header or ExplainExtension extension are validated and checked against limits,
+// then responded with ExplainPlan in the extension instead of executing.
+// The plan exposes internal gRPC methods, so that explain requests which are not allowed are rejected with EXPLAIN_DISABLED error.
+func WithExplain(allow IntrospectionPredicate) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		if !allow(ctx, r) {
+			return ctx, nil
+		}
+		return context.WithValue(ctx, explainAllowedKey{}, true), nil
+	}
+}
+
+// requestExplain marks the context if the request asks explain mode
+func requestExplain(ctx context.Context, r *http.Request, req *GraphqlRequest) context.Context {
+	requested, _ := req.Extensions[ExplainExtension].(bool) // nolint: errcheck
+	if v := r.Header.Get(ExplainHeader); v != "" {
+		requested, _ = strconv.ParseBool(v) // nolint: errcheck
+	}
+	if !requested {
+		return ctx
+	}
+	return context.WithValue(ctx, explainRequestedKey{}, true)
+}
+
+func isExplainRequested(ctx context.Context) bool {
+	requested, _ := ctx.Value(explainRequestedKey{}).(bool) // nolint: errcheck
+	return requested
+}
+
+// explain responds the plan of the validated document instead of executing
+func (s *ServeMux) explain(ctx context.Context, schema *graphql.Schema, doc *ast.Document, req *GraphqlRequest) *graphql.Result {
+	if allowed, _ := ctx.Value(explainAllowedKey{}).(bool); !allowed { // nolint: errcheck
+		return &graphql.Result{
+			Errors: []GraphqlError{
+				{
+					Message: "explain is not allowed",
+					Extensions: map[string]interface{}{
+						"code": "EXPLAIN_DISABLED",
+					},
+				},
+			},
+		}
+	}
+
+	op := selectOperation(doc, req.OperationName)
+	if op == nil {
+		return &graphql.Result{
+			Errors: []GraphqlError{
+				{
+					Message: "operation to explain is not found",
+					Extensions: map[string]interface{}{
+						"code": "OPERATION_NOT_FOUND",
+					},
+				},
+			},
+		}
+	}
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	mappings := make(map[string]FieldMapping)
+	for _, m := range s.FieldMappings() {
+		mappings[m.Operation+":"+m.Field] = m
+	}
+	e := &explainer{
+		operation: op.Operation,
+		mappings:  mappings,
+		fragments: collectFragments(doc),
+		variables: req.Variables,
+	}
+	plan := &ExplainPlan{
+		Operation: op.Operation,
+		Calls:     []ExplainCall{},
+	}
+	if op.Name != nil {
+		plan.Name = op.Name.Value
+	}
+	e.explainSelections(plan, op.SelectionSet, root, "", "", map[string]struct{}{})
+
+	return &graphql.Result{
+		Extensions: map[string]interface{}{
+			ExplainExtension: plan,
+		},
+	}
+}
+
+// selectOperation returns the operation of the name, or the only operation if the name is empty
+func selectOperation(doc *ast.Document, name string) *ast.OperationDefinition {
+	var found *ast.OperationDefinition
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if name == "" {
+			if found != nil {
+				return nil
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op
+		}
+	}
+	return found
+}
+
+type explainer struct {
+	operation string
+	mappings  map[string]FieldMapping
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+}
+
+// explainSelections appends calls of root fields, and descends into namespace fields which group root fields
+func (e *explainer) explainSelections(
+	plan *ExplainPlan,
+	set *ast.SelectionSet,
+	parent *graphql.Object,
+	pathPrefix, fieldPrefix string,
+	visited map[string]struct{},
+) {
+	if set == nil || parent == nil {
+		return
+	}
+	for _, s := range set.Selections {
+		switch v := s.(type) {
+		case *ast.Field:
+			e.explainField(plan, v, parent, pathPrefix, fieldPrefix)
+		case *ast.InlineFragment:
+			e.explainSelections(plan, v.SelectionSet, parent, pathPrefix, fieldPrefix, visited)
+		case *ast.FragmentSpread:
+			if v.Name == nil {
+				continue
+			}
+			f, ok := e.fragments[v.Name.Value]
+			if !ok {
+				continue
+			}
+			if _, ok := visited[v.Name.Value]; ok {
+				continue
+			}
+			visited[v.Name.Value] = struct{}{}
+			e.explainSelections(plan, f.SelectionSet, parent, pathPrefix, fieldPrefix, visited)
+			delete(visited, v.Name.Value)
+		}
+	}
+}
+
+func (e *explainer) explainField(plan *ExplainPlan, field *ast.Field, parent *graphql.Object, pathPrefix, fieldPrefix string) {
+	if field.Name == nil || strings.HasPrefix(field.Name.Value, "__") {
+		return
+	}
+	name := field.Name.Value
+	key := name
+	if field.Alias != nil {
+		key = field.Alias.Value
+	}
+	def, ok := parent.Fields()[name]
+	if !ok {
+		return
+	}
+
+	mapping, ok := e.mappings[e.operation+":"+fieldPrefix+name]
+	if !ok && e.isNamespace(fieldPrefix+name) {
+		if obj, ok := graphql.GetNullable(def.Type).(*graphql.Object); ok {
+			e.explainSelections(plan, field.SelectionSet, obj, pathPrefix+key+".", fieldPrefix+name+".", map[string]struct{}{})
+		}
+		return
+	}
+
+	args := e.argumentValues(def, field)
+	call := ExplainCall{
+		Path:    pathPrefix + key,
+		Field:   fieldPrefix + name,
+		Service: mapping.Service,
+		Method:  mapping.Method,
+		Request: args,
+	}
+	// Arguments are bound as is if the method is unknown
+	if mapping.Method != "" {
+		call.Request = requestPayload(mapping, args)
+	}
+	plan.Calls = append(plan.Calls, call)
+}
+
+// isNamespace reports whether the field groups root fields of namespaced handler
+func (e *explainer) isNamespace(field string) bool {
+	prefix := e.operation + ":" + field + "."
+	for key := range e.mappings {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// argumentValues returns argument values of the field with variables and default values
+func (e *explainer) argumentValues(def *graphql.FieldDefinition, field *ast.Field) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, arg := range def.Args {
+		if arg.DefaultValue != nil {
+			values[arg.Name()] = arg.DefaultValue
+		}
+	}
+	for _, arg := range field.Arguments {
+		if arg.Name == nil {
+			continue
+		}
+		if v, ok := e.astValue(arg.Value); ok {
+			values[arg.Name.Value] = v
+		}
+	}
+	return values
+}
+
+// astValue converts literal of the argument to Go value, unspecified variables are omitted
+func (e *explainer) astValue(value ast.Value) (interface{}, bool) {
+	switch v := value.(type) {
+	case *ast.Variable:
+		if v.Name == nil {
+			return nil, false
+		}
+		val, ok := e.variables[v.Name.Value]
+		return val, ok
+	case *ast.IntValue:
+		if n, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return n, true
+		}
+		return v.Value, true
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f, true
+		}
+		return v.Value, true
+	case *ast.StringValue:
+		return v.Value, true
+	case *ast.BooleanValue:
+		return v.Value, true
+	case *ast.EnumValue:
+		return v.Value, true
+	case *ast.ListValue:
+		list := make([]interface{}, 0, len(v.Values))
+		for _, item := range v.Values {
+			val, _ := e.astValue(item) // nolint: errcheck
+			list = append(list, val)
+		}
+		return list, true
+	case *ast.ObjectValue:
+		obj := make(map[string]interface{}, len(v.Fields))
+		for _, f := range v.Fields {
+			if f.Name == nil {
+				continue
+			}
+			if val, ok := e.astValue(f.Value); ok {
+				obj[f.Name.Value] = val
+			}
+		}
+		return obj, true
+	}
+	return nil, true
+}
+
+// requestPayload renames arguments to request message fields via argument bindings of the mapping
+func requestPayload(mapping FieldMapping, args map[string]interface{}) map[string]interface{} {
+	if mapping.Input != "" {
+		args, _ = args[mapping.Input].(map[string]interface{}) // nolint: errcheck
+	}
+	payload := make(map[string]interface{}, len(args))
+	for _, b := range mapping.Arguments {
+		if v, ok := args[b.Argument]; ok {
+			payload[b.RequestField] = v
+		}
+	}
+	return payload
+}
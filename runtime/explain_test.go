@@ -0,0 +1,105 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func serveTestExplain(t *testing.T, mux *ServeMux, body string, header bool) map[string]interface{} {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if header {
+		r.Header.Set(ExplainHeader, "true")
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+func TestExplain(t *testing.T) {
+	file := testBookFiles(t)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowAll := func(ctx context.Context, r *http.Request) bool { return true }
+
+	mux := NewServeMux(WithExplain(allowAll))
+	assert.NoError(t, mux.AddHandler(handlers[0]))
+	assert.NoError(t, mux.AddNamespacedHandler("legacy", newTestHandler()))
+
+	t.Run("query", func(t *testing.T) {
+		result := serveTestExplain(t, mux, `{
+			"query": "query Books($title: String!) { first: getBook(title: $title) { title } books { title } legacy { user(id: \"1\") { id } } }",
+			"variables": {"title": "Dune"},
+			"extensions": {"explain": true}
+		}`, false)
+		assert.Nil(t, result["data"])
+		assert.Equal(t, map[string]interface{}{
+			"operation": "query",
+			"name":      "Books",
+			"parallel":  false,
+			"calls": []interface{}{
+				map[string]interface{}{
+					"path":    "first",
+					"field":   "getBook",
+					"service": "library.v1.LibraryService",
+					"method":  "/library.v1.LibraryService/GetBook",
+					"request": map[string]interface{}{"title": "Dune"},
+				},
+				map[string]interface{}{
+					"path":    "books",
+					"field":   "books",
+					"service": "library.v1.LibraryService",
+					"method":  "/library.v1.LibraryService/ListBooks",
+					"request": map[string]interface{}{},
+				},
+				map[string]interface{}{
+					"path":    "legacy.user",
+					"field":   "legacy.user",
+					"service": "*runtime.testHandler",
+					"request": map[string]interface{}{"id": "1"},
+				},
+			},
+		}, result["extensions"].(map[string]interface{})[ExplainExtension])
+	})
+
+	t.Run("mutation via header", func(t *testing.T) {
+		result := serveTestExplain(t, mux, `{
+			"query": "mutation { addBook(book: {title: \"Dune\", pageCount: 412}) { title } }"
+		}`, true)
+		plan := result["extensions"].(map[string]interface{})[ExplainExtension].(map[string]interface{})
+		assert.Equal(t, "mutation", plan["operation"])
+		call := plan["calls"].([]interface{})[0].(map[string]interface{})
+		assert.Equal(t, "/library.v1.LibraryService/AddBook", call["method"])
+		assert.Equal(t, map[string]interface{}{"title": "Dune", "page_count": float64(412)}, call["request"])
+	})
+
+	t.Run("invalid document is not explained", func(t *testing.T) {
+		result := serveTestExplain(t, mux, `{"query": "{ unknown }", "extensions": {"explain": true}}`, false)
+		assert.NotEmpty(t, result["errors"])
+		assert.Nil(t, result["extensions"])
+	})
+
+	t.Run("not allowed", func(t *testing.T) {
+		mux := NewServeMux(WithExplain(func(ctx context.Context, r *http.Request) bool { return false }))
+		assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+		result := serveTestExplain(t, mux, `{"query": "mutation { login(password: \"secret\") }"}`, true)
+		assert.Nil(t, result["data"])
+		assert.Equal(t, "EXPLAIN_DISABLED", result["errors"].([]interface{})[0].(map[string]interface{})["extensions"].(map[string]interface{})["code"])
+	})
+}
@@ -0,0 +1,39 @@
+package runtime
+
+import (
+	"context"
+
+	"github.com/graphql-go/graphql"
+)
+
+// AddExtension adds value to "extensions" object of the GraphQL response.
+// Middlewares and resolvers can use this function in order to respond extra data like tracing IDs or cache hints.
+// If the context is not derived from ServeMux request, this function does nothing.
+func AddExtension(ctx context.Context, key string, value interface{}) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.extensions[key] = value
+}
+
+// mergeExtensions puts extension values which are added via AddExtension into the result
+func mergeExtensions(ctx context.Context, result *graphql.Result) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.extensions) == 0 {
+		return
+	}
+	if result.Extensions == nil {
+		result.Extensions = make(map[string]interface{})
+	}
+	for k, v := range s.extensions {
+		result.Extensions[k] = v
+	}
+}
@@ -0,0 +1,48 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddExtension(t *testing.T) {
+	h := newTestHandler()
+	h.queries["traced"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			AddExtension(p.Context, "cacheHint", "public")
+			return "ok", nil
+		},
+	}
+	mux := NewServeMux(func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		AddExtension(ctx, "traceId", "abc")
+		return ctx, nil
+	})
+	assert.NoError(t, mux.AddHandler(h))
+
+	result := serveTestQuery(t, mux, `{ traced }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"traceId":   "abc",
+		"cacheHint": "public",
+	}, result.Extensions)
+
+	// Extensions are also responded with middleware error
+	mux = NewServeMux(func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		AddExtension(ctx, "rateLimit", map[string]interface{}{"remaining": 0})
+		return nil, NewMiddlewareError("RATE_LIMITED", "rate limited")
+	})
+	result = serveTestQuery(t, mux, `{ hello }`)
+	assert.Len(t, result.Errors, 1)
+	assert.Equal(t, map[string]interface{}{
+		"rateLimit": map[string]interface{}{"remaining": float64(0)},
+	}, result.Extensions)
+
+	// Without ServeMux context, nothing happens
+	AddExtension(context.Background(), "key", "value")
+}
@@ -0,0 +1,147 @@
+// Package gatewaytest provides the gateway whose upstreams are in-memory gRPC servers,
+// so that end-to-end tests of generated handlers are written in pure Go without network.
+//
+//	func TestHello(t *testing.T) {
+//		gw := gatewaytest.New(t)
+//		gw.Upstream(func(s *grpc.Server) {
+//			greeter.RegisterGreeterServer(s, &fakeGreeter{})
+//		}, greeter.RegisterGreeterGraphqlHandler)
+//
+//		res := gw.Query(`{ hello(name: "GraphQL") { message } }`, nil)
+//		res.AssertNoErrors(t)
+//		res.AssertData(t, `{"hello":{"message":"Hello, GraphQL!"}}`)
+//	}
+package gatewaytest
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/grpc"
+)
+
+// RegisterFunc registers graphql handler of the upstream connection like generated Register*GraphqlHandler
+type RegisterFunc func(mux *runtime.ServeMux, conn *grpc.ClientConn) error
+
+// Gateway serves GraphQL requests of the test via ServeMux
+type Gateway struct {
+	// Mux is the gateway, configure it before sending requests
+	Mux *runtime.ServeMux
+
+	t testing.TB
+}
+
+// Response is GraphQL response of the request
+type Response struct {
+	StatusCode int                    `json:"-"`
+	Header     http.Header            `json:"-"`
+	Data       json.RawMessage        `json:"data"`
+	Errors     []runtime.GraphqlError `json:"errors"`
+}
+
+// New creates gateway with middlewares, upstreams are stopped when the test finishes
+func New(t testing.TB, ms ...runtime.MiddlewareFunc) *Gateway {
+	return &Gateway{
+		Mux: runtime.NewServeMux(ms...),
+		t:   t,
+	}
+}
+
+// Upstream starts in-memory gRPC server whose services are registered in register function,
+// and registers graphql handler of the connection to the server via handler function.
+func (g *Gateway) Upstream(register func(*grpc.Server), handler RegisterFunc, opts ...grpc.ServerOption) {
+	g.t.Helper()
+	conn, closer, err := runtime.NewInProcessConn(register, opts...)
+	if err != nil {
+		g.t.Fatalf("failed to start upstream: %s", err)
+	}
+	g.t.Cleanup(closer)
+	if err := handler(g.Mux, conn); err != nil {
+		g.t.Fatalf("failed to register handler: %s", err)
+	}
+}
+
+// Query sends the query with variables, which may be nil
+func (g *Gateway) Query(query string, variables map[string]interface{}) *Response {
+	g.t.Helper()
+	body, err := json.Marshal(runtime.GraphqlRequest{
+		Query:     query,
+		Variables: variables,
+	})
+	if err != nil {
+		g.t.Fatalf("failed to encode request: %s", err)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	return g.Do(r)
+}
+
+// Do sends the request which is customized like headers
+func (g *Gateway) Do(r *http.Request) *Response {
+	g.t.Helper()
+	w := httptest.NewRecorder()
+	g.Mux.ServeHTTP(w, r)
+
+	res := &Response{
+		StatusCode: w.Code,
+		Header:     w.Header(),
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), res); err != nil {
+		g.t.Fatalf("failed to decode response %q: %s", w.Body.String(), err)
+	}
+	return res
+}
+
+// AssertNoErrors fails the test if the response has errors
+func (r *Response) AssertNoErrors(t testing.TB) {
+	t.Helper()
+	if len(r.Errors) == 0 {
+		return
+	}
+	messages := make([]string, len(r.Errors))
+	for i, err := range r.Errors {
+		messages[i] = err.Message
+	}
+	t.Errorf("unexpected errors: %s", strings.Join(messages, ", "))
+}
+
+// AssertErrorCode fails the test if the response doesn't have the error of the code in extensions
+func (r *Response) AssertErrorCode(t testing.TB, code string) {
+	t.Helper()
+	for _, err := range r.Errors {
+		if err.Extensions["code"] == code {
+			return
+		}
+	}
+	t.Errorf("error code %s is not responded, errors: %v", code, r.Errors)
+}
+
+// AssertData fails the test if data of the response is not equal to expected JSON regardless of formatting
+func (r *Response) AssertData(t testing.TB, expected string) {
+	t.Helper()
+	var want, got interface{}
+	if err := json.Unmarshal([]byte(expected), &want); err != nil {
+		t.Fatalf("expected data is not JSON: %s", err)
+	}
+	if err := json.Unmarshal(r.Data, &got); err != nil {
+		t.Fatalf("failed to decode data: %s", err)
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("data is not equal\nexpected: %s\nactual:   %s", expected, string(r.Data))
+	}
+}
+
+// Decode unmarshals data of the response to v
+func (r *Response) Decode(t testing.TB, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(r.Data, v); err != nil {
+		t.Fatalf("failed to decode data: %s", err)
+	}
+}
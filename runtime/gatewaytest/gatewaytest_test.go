@@ -0,0 +1,74 @@
+package gatewaytest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ysugimoto/grpc-graphql-gateway/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	pb "google.golang.org/grpc/reflection/grpc_testing"
+	"google.golang.org/grpc/status"
+)
+
+type searchServer struct{}
+
+func (s *searchServer) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	if req.Query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	return &pb.SearchResponse{
+		Results: []*pb.SearchResponse_Result{
+			{Title: "Result of " + req.Query},
+		},
+	}, nil
+}
+
+func (s *searchServer) StreamingSearch(stream pb.SearchService_StreamingSearchServer) error {
+	return nil
+}
+
+func registerReflectionHandlers(mux *runtime.ServeMux, conn *grpc.ClientConn) error {
+	handlers, err := runtime.NewReflectionHandlers(context.Background(), conn)
+	if err != nil {
+		return err
+	}
+	for _, h := range handlers {
+		if err := mux.AddHandler(h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestGateway(t *testing.T) {
+	gw := New(t)
+	gw.Upstream(func(s *grpc.Server) {
+		pb.RegisterSearchServiceServer(s, &searchServer{})
+		reflection.Register(s)
+	}, registerReflectionHandlers)
+
+	res := gw.Query(`query Search($query: String) { search(query: $query) { results { title } } }`, map[string]interface{}{
+		"query": "gateway",
+	})
+	res.AssertNoErrors(t)
+	res.AssertData(t, `{"search": {"results": [{"title": "Result of gateway"}]}}`)
+
+	var data struct {
+		Search struct {
+			Results []struct {
+				Title string `json:"title"`
+			} `json:"results"`
+		} `json:"search"`
+	}
+	res.Decode(t, &data)
+	if len(data.Search.Results) != 1 || data.Search.Results[0].Title != "Result of gateway" {
+		t.Errorf("unexpected data: %+v", data)
+	}
+
+	res = gw.Query(`{ search(query: "") { results { title } } }`, nil)
+	if len(res.Errors) != 1 {
+		t.Fatalf("expected an error, got %v", res.Errors)
+	}
+}
@@ -0,0 +1,128 @@
+package runtime
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/iancoleman/strcase"
+)
+
+// FieldGrouper is implemented by handlers whose root fields are grouped under namespace objects
+// independent of service boundaries, which is declared by group of graphql.schema option.
+// Fields of the same group from multiple handlers are merged into one namespace field like "query { billing { invoices } }".
+type FieldGrouper interface {
+	// FieldGroups returns group name by root field name, fields which are not grouped are not included
+	FieldGroups() map[string]string
+}
+
+// groupObject is namespace object of the group which is rebuilt only when grouped fields are changed
+type groupObject struct {
+	signature string
+	object    *graphql.Object
+}
+
+// fieldGroupsOf returns groups of root fields of the handler
+func fieldGroupsOf(h GraphqlHandler) map[string]string {
+	if g, ok := h.(FieldGrouper); ok {
+		return g.FieldGroups()
+	}
+	return nil
+}
+
+// groupFields moves grouped fields under the namespace field of the group.
+// Namespace objects are shared between requests, and resolvers of them delegate to the request scoped fields
+// like namespaced handlers.
+func (s *ServeMux) groupFields(operation string, fields graphql.Fields, groups map[string]string) graphql.Fields {
+	if len(groups) == 0 {
+		return fields
+	}
+	grouped := map[string]graphql.Fields{}
+	out := graphql.Fields{}
+	for name, f := range fields {
+		group, ok := groups[name]
+		if !ok {
+			out[name] = f
+			continue
+		}
+		if grouped[group] == nil {
+			grouped[group] = graphql.Fields{}
+		}
+		grouped[group][name] = f
+	}
+	for group, scoped := range grouped {
+		scoped := scoped
+		out[group] = &graphql.Field{
+			Type: graphql.NewNonNull(s.groupObject(strcase.ToCamel(group)+operation, scoped, operation == "Mutation")),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return scoped, nil
+			},
+		}
+	}
+	return out
+}
+
+// groupObject returns namespace object of the fields, which is cached by the type name
+func (s *ServeMux) groupObject(name string, fields graphql.Fields, mutation bool) *graphql.Object {
+	signatures := make([]string, 0, len(fields))
+	for fieldName, f := range fields {
+		signatures = append(signatures, fieldName+":"+f.Type.String())
+	}
+	sort.Strings(signatures)
+	signature := strings.Join(signatures, ",")
+
+	s.groupMu.Lock()
+	defer s.groupMu.Unlock()
+	if g, ok := s.groupObjects[name]; ok && g.signature == signature {
+		return g.object
+	}
+	if s.groupObjects == nil {
+		s.groupObjects = make(map[string]*groupObject)
+	}
+	if mutation {
+		namespaceMutations.Store(name, struct{}{})
+	}
+	g := &groupObject{
+		signature: signature,
+		object:    newNamespaceObject(name, fields),
+	}
+	s.groupObjects[name] = g
+	return g.object
+}
+
+// checkGroupConflicts returns an error if group names of h collide with root fields of registered handlers, or vice versa
+func checkGroupConflicts(h GraphqlHandler, registered []GraphqlHandler) error {
+	check := func(a, b GraphqlHandler) error {
+		groups := fieldGroupsOf(a)
+		if len(groups) == 0 {
+			return nil
+		}
+		bGroups := fieldGroupsOf(b)
+		for _, fields := range []graphql.Fields{b.GetQueries(nil), b.GetMutations(nil)} {
+			for name := range fields {
+				if _, ok := bGroups[name]; ok {
+					continue
+				}
+				for _, group := range groups {
+					if group == name {
+						return fmt.Errorf("group %q of %s conflicts with the root field of %s", group, handlerName(a), handlerName(b))
+					}
+				}
+			}
+		}
+		return nil
+	}
+	if err := check(h, h); err != nil {
+		return err
+	}
+	for _, r := range registered {
+		if err := check(h, r); err != nil {
+			return err
+		}
+		if err := check(r, h); err != nil {
+			return err
+		}
+	}
+	return nil
+}
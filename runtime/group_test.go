@@ -0,0 +1,96 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+type groupedTestHandler struct {
+	*testHandler
+	groups map[string]string
+}
+
+func (h *groupedTestHandler) FieldGroups() map[string]string {
+	return h.groups
+}
+
+func newGroupedTestHandler(field, value, group string) *groupedTestHandler {
+	return &groupedTestHandler{
+		testHandler: &testHandler{
+			queries: graphql.Fields{
+				field: &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return value, nil
+					},
+				},
+			},
+			mutations: graphql.Fields{
+				"update" + value: &graphql.Field{
+					Type: graphql.String,
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return value, nil
+					},
+				},
+			},
+		},
+		groups: map[string]string{
+			field:            group,
+			"update" + value: group,
+		},
+	}
+}
+
+func TestFieldGroups(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newGroupedTestHandler("invoices", "Invoice", "billing")))
+	assert.NoError(t, mux.AddHandler(newGroupedTestHandler("payments", "Payment", "billing")))
+	assert.NoError(t, mux.AddHandler(newGroupedTestHandler("products", "Product", "catalog")))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ billing { invoices payments } catalog { products } hello }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"billing": map[string]interface{}{
+			"invoices": "Invoice",
+			"payments": "Payment",
+		},
+		"catalog": map[string]interface{}{
+			"products": "Product",
+		},
+		"hello": "world",
+	}, result.Data)
+
+	result = serveTestQuery(t, mux, `mutation { billing { updateInvoice } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"billing": map[string]interface{}{
+			"updateInvoice": "Invoice",
+		},
+	}, result.Data)
+
+	// Namespace object is named by the group and the operation
+	result = serveTestQuery(t, mux, `{ __type(name: "BillingQuery") { fields { name } } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Len(t, result.Data.(map[string]interface{})["__type"].(map[string]interface{})["fields"], 2)
+
+	var fields []string
+	for _, m := range mux.FieldMappings() {
+		fields = append(fields, m.Field)
+	}
+	assert.Contains(t, fields, "billing.invoices")
+	assert.Contains(t, fields, "catalog.updateProduct")
+	assert.Contains(t, fields, "hello")
+}
+
+func TestFieldGroupConflicts(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	err := mux.AddHandler(newGroupedTestHandler("invoices", "Invoice", "hello"))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `group "hello"`)
+	}
+}
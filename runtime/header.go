@@ -0,0 +1,132 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// CallOptions returns gRPC call options which capture response header and trailer metadata of the upstream call,
+// and the captured metadata is forwarded to HTTP response headers through outgoing header and trailer matchers.
+// Generated resolvers pass these options to every gRPC call.
+func CallOptions(ctx context.Context) []grpc.CallOption {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return nil
+	}
+	header, trailer := new(metadata.MD), new(metadata.MD)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.headers = append(s.headers, header)
+	s.trailers = append(s.trailers, trailer)
+	return []grpc.CallOption{
+		grpc.Header(header),
+		grpc.Trailer(trailer),
+	}
+}
+
+// defaultOutgoingHeaderMatcher forwards all response metadata with Grpc-Metadata- prefix like grpc-gateway
+func defaultOutgoingHeaderMatcher(key string) (string, bool) {
+	return fmt.Sprintf("%s%s", MetadataHeaderPrefix, key), true
+}
+
+// defaultOutgoingTrailerMatcher forwards all trailer metadata with Grpc-Trailer- prefix like grpc-gateway
+func defaultOutgoingTrailerMatcher(key string) (string, bool) {
+	return fmt.Sprintf("%s%s", MetadataTrailerPrefix, key), true
+}
+
+type responseHeaderAllowlistKey struct{}
+
+// WithResponseHeaderAllowlist is middleware function to limit upstream response metadata which is forwarded
+// to HTTP response headers to the keys, so that internal metadata like debug hints or routing keys never reaches browsers.
+// Keys are gRPC metadata keys like "x-request-id", and the allowlist is applied to both header and trailer metadata.
+// Note that metadata which is mapped explicitly like CookieMetadata is not limited.
+func WithResponseHeaderAllowlist(keys ...string) MiddlewareFunc {
+	allowed := make(map[string]struct{}, len(keys))
+	for _, k := range keys {
+		allowed[strings.ToLower(k)] = struct{}{}
+	}
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, responseHeaderAllowlistKey{}, allowed), nil
+	}
+}
+
+// allowlistedMatcher wraps the matcher to drop metadata keys which are not in the allowlist of the context
+func allowlistedMatcher(ctx context.Context, matcher HeaderMatcherFunc) HeaderMatcherFunc {
+	allowed, ok := ctx.Value(responseHeaderAllowlistKey{}).(map[string]struct{})
+	if !ok {
+		return matcher
+	}
+	return func(key string) (string, bool) {
+		if _, ok := allowed[strings.ToLower(key)]; !ok {
+			return "", false
+		}
+		return matcher(key)
+	}
+}
+
+// forwardResponseHeaders writes captured header and trailer metadata of gRPC calls to HTTP response headers.
+// Trailers are also sent as headers because the response body is written at once after all gRPC calls finish.
+func (s *ServeMux) forwardResponseHeaders(ctx context.Context, w http.ResponseWriter) {
+	scope := scopeFromContext(ctx)
+	if scope == nil {
+		return
+	}
+	headerMatcher := s.outgoingHeaderMatcher
+	if headerMatcher == nil {
+		headerMatcher = defaultOutgoingHeaderMatcher
+	}
+	trailerMatcher := s.outgoingTrailerMatcher
+	if trailerMatcher == nil {
+		trailerMatcher = defaultOutgoingTrailerMatcher
+	}
+
+	headerMatcher = withoutOutgoingCookieMetadata(ctx, allowlistedMatcher(ctx, headerMatcher))
+	trailerMatcher = withoutOutgoingCookieMetadata(ctx, allowlistedMatcher(ctx, trailerMatcher))
+
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+	for _, md := range scope.headers {
+		writeMetadataHeaders(w, *md, headerMatcher)
+	}
+	for _, md := range scope.trailers {
+		writeMetadataHeaders(w, *md, trailerMatcher)
+	}
+}
+
+func writeMetadataHeaders(w http.ResponseWriter, md metadata.MD, matcher HeaderMatcherFunc) {
+	for k, vs := range md {
+		h, ok := matcher(k)
+		if !ok {
+			continue
+		}
+		for _, v := range vs {
+			w.Header().Add(h, v)
+		}
+	}
+}
+
+// responseMetadata returns joined header and trailer metadata which are captured from gRPC calls
+func responseMetadata(ctx context.Context) metadata.MD {
+	scope := scopeFromContext(ctx)
+	if scope == nil {
+		return nil
+	}
+	scope.mu.Lock()
+	defer scope.mu.Unlock()
+
+	mds := make([]metadata.MD, 0, len(scope.headers)+len(scope.trailers))
+	for _, md := range scope.headers {
+		mds = append(mds, *md)
+	}
+	for _, md := range scope.trailers {
+		mds = append(mds, *md)
+	}
+	return metadata.Join(mds...)
+}
@@ -0,0 +1,120 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestForwardResponseHeaders(t *testing.T) {
+	h := newTestHandler()
+	h.queries["cached"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			// Emulate gRPC call which receives response header
+			for _, o := range CallOptions(p.Context) {
+				switch v := o.(type) {
+				case grpc.HeaderCallOption:
+					*v.HeaderAddr = metadata.Pairs("x-cache", "hit")
+				case grpc.TrailerCallOption:
+					*v.TrailerAddr = metadata.Pairs("x-ratelimit-remaining", "9")
+				}
+			}
+			return "ok", nil
+		},
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(h))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ cached }`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	assert.Equal(t, "hit", w.Header().Get("Grpc-Metadata-X-Cache"))
+	assert.Equal(t, "9", w.Header().Get("Grpc-Trailer-X-Ratelimit-Remaining"))
+}
+
+func TestWithResponseHeaderAllowlist(t *testing.T) {
+	h := newTestHandler()
+	h.queries["debug"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			for _, o := range CallOptions(p.Context) {
+				switch v := o.(type) {
+				case grpc.HeaderCallOption:
+					*v.HeaderAddr = metadata.Pairs("x-request-id", "abc", "x-debug-shard", "db-3")
+				case grpc.TrailerCallOption:
+					*v.TrailerAddr = metadata.Pairs("x-routing-key", "tenant-1")
+				}
+			}
+			return "ok", nil
+		},
+	}
+	mux := NewServeMux(WithResponseHeaderAllowlist("X-Request-Id"))
+	assert.NoError(t, mux.AddHandler(h))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ debug }`))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	assert.Equal(t, "abc", w.Header().Get("Grpc-Metadata-X-Request-Id"))
+	assert.Empty(t, w.Header().Get("Grpc-Metadata-X-Debug-Shard"))
+	assert.Empty(t, w.Header().Get("Grpc-Trailer-X-Routing-Key"))
+}
+
+func TestServeMuxHeaderMatchers(t *testing.T) {
+	h := newTestHandler()
+	h.queries["cached"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			md, _ := metadata.FromOutgoingContext(p.Context) // nolint: errcheck
+			for _, o := range CallOptions(p.Context) {
+				switch v := o.(type) {
+				case grpc.HeaderCallOption:
+					*v.HeaderAddr = metadata.Pairs("x-cache", "hit", "x-debug-shard", "db-3")
+				case grpc.TrailerCallOption:
+					*v.TrailerAddr = metadata.Pairs("x-ratelimit-remaining", "9", "x-routing-key", "tenant-1")
+				}
+			}
+			return strings.Join(md.Get("tenant"), ","), nil
+		},
+	}
+	mux := NewServeMux(func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return AnnotateContext(ctx, serveMux, r, "cached")
+	})
+	assert.NoError(t, mux.Configure(
+		WithIncomingHeaderMatcher(func(key string) (string, bool) {
+			return "tenant", key == "X-Tenant"
+		}),
+		WithOutgoingHeaderMatcher(func(key string) (string, bool) {
+			return "X-Cache", key == "x-cache"
+		}),
+		WithOutgoingTrailerMatcher(func(key string) (string, bool) {
+			return "X-Ratelimit-Remaining", key == "x-ratelimit-remaining"
+		}),
+	))
+	assert.NoError(t, mux.AddHandler(h))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ cached }`))
+	r.Header.Set("X-Tenant", "tenant-a")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	assert.Contains(t, w.Body.String(), `"cached":"tenant-a"`)
+	assert.Equal(t, "hit", w.Header().Get("X-Cache"))
+	assert.Empty(t, w.Header().Get("Grpc-Metadata-X-Debug-Shard"))
+	assert.Equal(t, "9", w.Header().Get("X-Ratelimit-Remaining"))
+	assert.Empty(t, w.Header().Get("Grpc-Trailer-X-Routing-Key"))
+
+	_, err := mux.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, ErrServeMuxBuilt, mux.Configure(WithIncomingHeaderMatcher(DefaultHeaderMatcher)))
+}
@@ -0,0 +1,40 @@
+package runtime
+
+import (
+	"context"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// inProcessBufferSize is buffer size of in-memory connection between gateway and in-process server
+const inProcessBufferSize = 1024 * 1024
+
+// NewInProcessConn starts gRPC server with options in the same process, and returns client connection to it
+// via in-memory listener instead of loopback network, so that small services can embed the gateway in one binary.
+// Services are registered to the server in register function, e.g. pb.RegisterGreeterServer(server, impl),
+// then the connection is passed to Register*GraphqlHandler.
+// Returned function stops the server and closes the connection.
+func NewInProcessConn(register func(*grpc.Server), opts ...grpc.ServerOption) (*grpc.ClientConn, func(), error) {
+	lis := bufconn.Listen(inProcessBufferSize)
+	server := grpc.NewServer(opts...)
+	register(server)
+	go server.Serve(lis) // nolint: errcheck
+
+	conn, err := grpc.Dial(
+		"passthrough:///in-process",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+	)
+	if err != nil {
+		server.Stop()
+		return nil, nil, err
+	}
+	return conn, func() {
+		conn.Close()
+		server.Stop()
+	}, nil
+}
@@ -0,0 +1,39 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	pb "google.golang.org/grpc/reflection/grpc_testing"
+)
+
+func TestInProcessConn(t *testing.T) {
+	conn, closer, err := NewInProcessConn(func(server *grpc.Server) {
+		pb.RegisterSearchServiceServer(server, &testSearchServer{})
+		reflection.Register(server)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer closer()
+
+	handlers, err := NewReflectionHandlers(context.Background(), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(handlers[0]))
+	result := serveTestQuery(t, mux, `{ search(query: "embedded") { results { title } } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"search": map[string]interface{}{
+			"results": []interface{}{
+				map[string]interface{}{"title": "Result of embedded"},
+			},
+		},
+	}, result.Data)
+}
@@ -0,0 +1,30 @@
+package runtime
+
+import (
+	"google.golang.org/grpc"
+)
+
+// ClientInterceptors is chain of gRPC client interceptors which are called in order,
+// e.g. auth, retry, prometheus and zap interceptors of go-grpc-middleware
+type ClientInterceptors struct {
+	Unary  []grpc.UnaryClientInterceptor
+	Stream []grpc.StreamClientInterceptor
+}
+
+// DialOptions returns dial options which install the interceptors.
+// Use this for connections which are passed to Register*GraphqlHandler or dynamic handlers.
+func (c ClientInterceptors) DialOptions() []grpc.DialOption {
+	var opts []grpc.DialOption
+	if len(c.Unary) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(c.Unary...))
+	}
+	if len(c.Stream) > 0 {
+		opts = append(opts, grpc.WithChainStreamInterceptor(c.Stream...))
+	}
+	return opts
+}
+
+// WithClientInterceptors is middleware function to install the interceptors on automatic connections of generated handlers
+func WithClientInterceptors(c ClientInterceptors) MiddlewareFunc {
+	return WithDialOptions(c.DialOptions()...)
+}
@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	pb "google.golang.org/grpc/reflection/grpc_testing"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestClientInterceptors(t *testing.T) {
+	var calls []string
+	interceptor := func(name string) grpc.UnaryClientInterceptor {
+		return func(
+			ctx context.Context,
+			method string,
+			req, reply interface{},
+			cc *grpc.ClientConn,
+			invoker grpc.UnaryInvoker,
+			opts ...grpc.CallOption,
+		) error {
+			calls = append(calls, name+" "+method)
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+	}
+	interceptors := ClientInterceptors{
+		Unary: []grpc.UnaryClientInterceptor{interceptor("auth"), interceptor("metrics")},
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	ctx, err := WithClientInterceptors(interceptors)(context.Background(), nil, nil, r)
+	assert.NoError(t, err)
+	assert.Len(t, DialOptions(ctx), 1)
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterSearchServiceServer(server, &testSearchServer{})
+	reflection.Register(server)
+	go server.Serve(lis) // nolint: errcheck
+	defer server.Stop()
+
+	opts := append(interceptors.DialOptions(), grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	conn, err := grpc.Dial("bufnet", opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	handlers, err := NewReflectionHandlers(context.Background(), conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(handlers[0]))
+	result := serveTestQuery(t, mux, `{ search(query: "grpc") { results { title } } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, []string{
+		"auth /grpc.testing.SearchService/Search",
+		"metrics /grpc.testing.SearchService/Search",
+	}, calls)
+}
@@ -0,0 +1,108 @@
+package runtime
+
+import (
+	"context"
+	"regexp"
+
+	"net"
+	"net/http"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// IntrospectionPredicate reports whether the request is allowed to introspect the schema
+type IntrospectionPredicate func(ctx context.Context, r *http.Request) bool
+
+type introspectionDeniedKey struct{}
+
+// WithIntrospection is middleware function to allow introspection queries only for requests which pass the predicate,
+// e.g. requests with admin role claim or from internal network.
+// Denied requests which select __schema or __type field are rejected with INTROSPECTION_DISABLED error before execution.
+// __typename is always allowed because clients rely on it for normal queries.
+// Note that the predicate is called after the preceding middlewares, so the context has identity of them like APIKeyFromContext.
+func WithIntrospection(allow IntrospectionPredicate) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		if allow(ctx, r) {
+			return ctx, nil
+		}
+		return context.WithValue(ctx, introspectionDeniedKey{}, true), nil
+	}
+}
+
+// IntrospectionFromNetworks returns IntrospectionPredicate which allows requests whose remote address is in the networks.
+// Networks are CIDR notations like "10.0.0.0/8". The remote address is taken from the connection,
+// so forwarded headers are not trusted.
+func IntrospectionFromNetworks(cidrs ...string) (IntrospectionPredicate, error) {
+	networks := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, err
+		}
+		networks[i] = network
+	}
+	return func(ctx context.Context, r *http.Request) bool {
+		ip := net.ParseIP(RateLimitByIP(r))
+		if ip == nil {
+			return false
+		}
+		for _, network := range networks {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}, nil
+}
+
+// checkIntrospection rejects introspection fields if WithIntrospection middleware denies the request
+func checkIntrospection(ctx context.Context, doc *ast.Document) []GraphqlError {
+	if denied, _ := ctx.Value(introspectionDeniedKey{}).(bool); !denied { // nolint: errcheck
+		return nil
+	}
+	c := newFieldCounter(doc, 0, true, func(f *ast.Field) bool {
+		return f.Name != nil && (f.Name.Value == "__schema" || f.Name.Value == "__type")
+	})
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		count := c.countFields(op.SelectionSet)
+		if count > 0 {
+			metricsFromContext(ctx).reject("introspection_disabled")
+			return []GraphqlError{
+				{
+					Message: "introspection is not allowed",
+					Extensions: map[string]interface{}{
+						"code": "INTROSPECTION_DISABLED",
+					},
+				},
+			}
+		}
+	}
+	return nil
+}
+
+type suggestionsDisabledKey struct{}
+
+// suggestionPattern matches hints of validation errors like ` Did you mean "name"?`
+var suggestionPattern = regexp.MustCompile(` Did you mean .+\?$`)
+
+// WithoutSuggestions is middleware function to strip "Did you mean" hints from validation errors,
+// which leak field, argument and type names of the schema even if introspection is disabled.
+func WithoutSuggestions() MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, suggestionsDisabledKey{}, true), nil
+	}
+}
+
+// stripSuggestions removes suggestion hints from error messages if WithoutSuggestions middleware is used
+func stripSuggestions(ctx context.Context, errs []GraphqlError) {
+	if disabled, _ := ctx.Value(suggestionsDisabledKey{}).(bool); !disabled { // nolint: errcheck
+		return
+	}
+	for i := range errs {
+		errs[i].Message = suggestionPattern.ReplaceAllString(errs[i].Message, "")
+	}
+}
@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"encoding/json"
+
+	"github.com/graphql-go/graphql"
+)
+
+// IntrospectionQuery is the standard introspection query which tools like GraphiQL and graphql-codegen send
+const IntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    subscriptionType { name }
+    types { ...FullType }
+    directives {
+      name
+      description
+      locations
+      args { ...InputValue }
+    }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+// IntrospectionJSON executes IntrospectionQuery against the schema and returns indented JSON of the result,
+// which is the same as the response of the server like {"data": {"__schema": ...}}.
+// Tools like graphql-voyager, graphql-codegen and linters consume it without a running server.
+func IntrospectionJSON(schema *graphql.Schema) ([]byte, error) {
+	result := graphql.Do(graphql.Params{
+		Schema:        *schema,
+		RequestString: IntrospectionQuery,
+		Context:       context.Background(),
+	})
+	if result.HasErrors() {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return nil, errors.New("failed to introspect schema: " + strings.Join(messages, ", "))
+	}
+	return json.MarshalIndent(result, "", "  ")
+}
+
+// WriteIntrospection writes introspection JSON of the registered handlers, see IntrospectionJSON.
+// This is useful to export the schema of generated handlers, e.g. in the program which is run by go generate.
+// Note that handlers which are added via AddTenantHandler are not included.
+func (s *ServeMux) WriteIntrospection(w io.Writer) error {
+	schema, err := s.Schema()
+	if err != nil {
+		return err
+	}
+	b, err := IntrospectionJSON(&schema)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(b, '\n'))
+	return err
+}
@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"bytes"
+	"testing"
+
+	"encoding/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteIntrospection(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	var buf bytes.Buffer
+	assert.NoError(t, mux.WriteIntrospection(&buf))
+
+	var result struct {
+		Data struct {
+			Schema struct {
+				QueryType struct {
+					Name string `json:"name"`
+				} `json:"queryType"`
+				MutationType struct {
+					Name string `json:"name"`
+				} `json:"mutationType"`
+				Types []struct {
+					Name string `json:"name"`
+				} `json:"types"`
+			} `json:"__schema"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Query", result.Data.Schema.QueryType.Name)
+	assert.Equal(t, "Mutation", result.Data.Schema.MutationType.Name)
+
+	names := make([]string, len(result.Data.Schema.Types))
+	for i, typ := range result.Data.Schema.Types {
+		names[i] = typ.Name
+	}
+	assert.Contains(t, names, "Test_Type_User")
+	assert.Contains(t, names, "__Schema")
+}
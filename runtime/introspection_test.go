@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithIntrospection(t *testing.T) {
+	mux := NewServeMux(WithIntrospection(func(ctx context.Context, r *http.Request) bool {
+		return r.Header.Get("X-Role") == "admin"
+	}))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	serve := func(query, role string) *graphql.Result {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query))
+		r.Header.Set("X-Role", role)
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var result graphql.Result
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		return &result
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		role    string
+		blocked bool
+	}{
+		{name: "schema", query: `{ __schema { queryType { name } } }`, blocked: true},
+		{name: "type", query: `{ __type(name: "Query") { name } }`, blocked: true},
+		{name: "nested in fragment", query: `query { ...F } fragment F on Query { hello __schema { types { name } } }`, blocked: true},
+		{name: "typename", query: `{ __typename hello }`},
+		{name: "normal query", query: `{ hello }`},
+		{name: "admin", query: `{ __schema { queryType { name } } }`, role: "admin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := serve(tt.query, tt.role)
+			if tt.blocked {
+				assert.Nil(t, result.Data)
+				if assert.Len(t, result.Errors, 1) {
+					assert.Equal(t, "INTROSPECTION_DISABLED", result.Errors[0].Extensions["code"])
+				}
+				return
+			}
+			assert.Len(t, result.Errors, 0)
+		})
+	}
+}
+
+func TestIntrospectionFromNetworks(t *testing.T) {
+	allow, err := IntrospectionFromNetworks("10.0.0.0/8", "::1/128")
+	assert.NoError(t, err)
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.RemoteAddr = "10.1.2.3:5000"
+	assert.True(t, allow(r.Context(), r))
+	r.RemoteAddr = "[::1]:5000"
+	assert.True(t, allow(r.Context(), r))
+	r.RemoteAddr = "203.0.113.1:5000"
+	r.Header.Set("X-Forwarded-For", "10.1.2.3")
+	assert.False(t, allow(r.Context(), r))
+
+	_, err = IntrospectionFromNetworks("10.0.0.0")
+	assert.Error(t, err)
+}
+
+func TestWithoutSuggestions(t *testing.T) {
+	query := `{ helo user(idd: "1") { id } }`
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	result := serveTestQuery(t, mux, query)
+	if assert.Len(t, result.Errors, 2) {
+		assert.Equal(t, `Cannot query field "helo" on type "Query". Did you mean "hello"?`, result.Errors[0].Message)
+	}
+
+	mux = NewServeMux(WithoutSuggestions())
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	result = serveTestQuery(t, mux, query)
+	if assert.Len(t, result.Errors, 2) {
+		assert.Equal(t, `Cannot query field "helo" on type "Query".`, result.Errors[0].Message)
+		assert.Equal(t, `Unknown argument "idd" on field "user" of type "Query".`, result.Errors[1].Message)
+	}
+}
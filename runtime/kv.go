@@ -0,0 +1,174 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// KVStore is small key-value store interface which gateway features share their state through,
+// like rate limiting and persisted operations. Implement this with shared storage like Redis
+// in order to share the state across multiple gateway replicas, see contrib/redis package.
+type KVStore interface {
+	// Get returns the value of the key, ok is false if the key doesn't exist or is expired
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set stores the value with TTL, zero TTL means the value never expires
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Incr increments the counter of the key atomically and returns incremented value.
+	// TTL is applied when the counter is created.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+}
+
+type kvEntry struct {
+	value   []byte
+	counter int64
+	expires time.Time
+}
+
+func (e *kvEntry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// MemoryKVStore is in-memory KVStore which is used in single gateway instance
+type MemoryKVStore struct {
+	mu        sync.Mutex
+	entries   map[string]*kvEntry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryKVStore creates MemoryKVStore pointer
+func NewMemoryKVStore() *MemoryKVStore {
+	return &MemoryKVStore{
+		entries: make(map[string]*kvEntry),
+		now:     time.Now,
+	}
+}
+
+// Get implements KVStore
+func (s *MemoryKVStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || e.expired(s.now()) {
+		return nil, false, nil
+	}
+	if e.value == nil {
+		return []byte(fmt.Sprint(e.counter)), true, nil
+	}
+	return e.value, true, nil
+}
+
+// Set implements KVStore
+func (s *MemoryKVStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	e := &kvEntry{
+		value: append([]byte{}, value...),
+	}
+	if ttl > 0 {
+		e.expires = now.Add(ttl)
+	}
+	s.entries[key] = e
+	return nil
+}
+
+// Incr implements KVStore
+func (s *MemoryKVStore) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+	e, ok := s.entries[key]
+	if !ok || e.expired(now) {
+		e = &kvEntry{}
+		if ttl > 0 {
+			e.expires = now.Add(ttl)
+		}
+		s.entries[key] = e
+	}
+	if e.value != nil {
+		return 0, fmt.Errorf("value of key %s is not a counter", key)
+	}
+	e.counter++
+	return e.counter, nil
+}
+
+// sweep drops expired entries in order to avoid growing memory
+func (s *MemoryKVStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, e := range s.entries {
+		if e.expired(now) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// kvFixedWindowRateLimitStore is RateLimitStore on KVStore which counts requests per fixed window
+type kvFixedWindowRateLimitStore struct {
+	kv     KVStore
+	prefix string
+	now    func() time.Time
+}
+
+// NewKVFixedWindowRateLimitStore creates RateLimitStore on the KVStore, keys are prefixed with prefix.
+// Since KVStore doesn't support atomic update of token bucket, requests are limited by fixed window counter
+// instead of token bucket: burst requests are allowed per window of burst / rate seconds, which is the same average rate,
+// but up to 2 * burst requests may pass around the boundary of windows.
+func NewKVFixedWindowRateLimitStore(kv KVStore, prefix string) RateLimitStore {
+	return &kvFixedWindowRateLimitStore{
+		kv:     kv,
+		prefix: prefix,
+		now:    time.Now,
+	}
+}
+
+// Take implements RateLimitStore
+func (s *kvFixedWindowRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	window := time.Duration(float64(burst) / rate * float64(time.Second))
+	if window <= 0 {
+		return false, time.Second, nil
+	}
+	now := s.now()
+	start := now.Truncate(window)
+	count, err := s.kv.Incr(ctx, fmt.Sprintf("%s%s:%d", s.prefix, key, start.UnixNano()), window)
+	if err != nil {
+		return false, 0, err
+	}
+	if count > int64(burst) {
+		return false, start.Add(window).Sub(now), nil
+	}
+	return true, 0, nil
+}
+
+// KVPersistedOperations is PersistedOperationStore on the KVStore which can register operations at runtime
+type KVPersistedOperations struct {
+	KV KVStore
+	// Prefix of keys
+	Prefix string
+	// TTL of registered operations, zero means operations never expire
+	TTL time.Duration
+}
+
+// Lookup implements PersistedOperationStore
+func (p *KVPersistedOperations) Lookup(ctx context.Context, id string) (string, bool, error) {
+	v, ok, err := p.KV.Get(ctx, p.Prefix+id)
+	if err != nil || !ok {
+		return "", ok, err
+	}
+	return string(v), true, nil
+}
+
+// Register stores the operation document keyed by SHA-256 hash of it
+func (p *KVPersistedOperations) Register(ctx context.Context, query string) error {
+	return p.KV.Set(ctx, p.Prefix+persistedOperationHash(query), []byte(query), p.TTL)
+}
@@ -0,0 +1,95 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryKVStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	s := NewMemoryKVStore()
+	s.now = func() time.Time { return now }
+
+	_, ok, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, s.Set(ctx, "key", []byte("value"), time.Second))
+	assert.NoError(t, s.Set(ctx, "forever", []byte("value"), 0))
+	v, ok, err := s.Get(ctx, "key")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, []byte("value"), v)
+
+	for i := int64(1); i <= 3; i++ {
+		n, err := s.Incr(ctx, "counter", time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, i, n)
+	}
+	v, _, _ = s.Get(ctx, "counter") // nolint: errcheck
+	assert.Equal(t, []byte("3"), v)
+	_, err = s.Incr(ctx, "key", time.Second)
+	assert.Error(t, err)
+
+	// Expired
+	now = now.Add(time.Second)
+	_, ok, _ = s.Get(ctx, "key") // nolint: errcheck
+	assert.False(t, ok)
+	_, ok, _ = s.Get(ctx, "forever") // nolint: errcheck
+	assert.True(t, ok)
+	n, err := s.Incr(ctx, "counter", time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), n)
+
+	// Expired entries are swept
+	now = now.Add(time.Minute)
+	assert.NoError(t, s.Set(ctx, "other", []byte("value"), 0))
+	assert.Len(t, s.entries, 2)
+}
+
+func TestKVFixedWindowRateLimitStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1000, 0)
+	s := NewKVFixedWindowRateLimitStore(NewMemoryKVStore(), "ratelimit:").(*kvFixedWindowRateLimitStore)
+	s.now = func() time.Time { return now }
+
+	// 2 requests per 2 seconds window
+	for i := 0; i < 2; i++ {
+		ok, _, err := s.Take(ctx, "key", 1, 2)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	now = now.Add(500 * time.Millisecond)
+	ok, wait, err := s.Take(ctx, "key", 1, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, 1500*time.Millisecond, wait)
+
+	ok, _, _ = s.Take(ctx, "other", 1, 2) // nolint: errcheck
+	assert.True(t, ok)
+
+	// Next window
+	now = now.Add(wait)
+	ok, _, _ = s.Take(ctx, "key", 1, 2) // nolint: errcheck
+	assert.True(t, ok)
+}
+
+func TestKVPersistedOperations(t *testing.T) {
+	ctx := context.Background()
+	store := &KVPersistedOperations{KV: NewMemoryKVStore(), Prefix: "apq:"}
+	query := "{ hello }"
+
+	_, ok, err := store.Lookup(ctx, persistedOperationHash(query))
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	assert.NoError(t, store.Register(ctx, query))
+	q, ok, err := store.Lookup(ctx, persistedOperationHash(query))
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, query, q)
+}
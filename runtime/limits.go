@@ -0,0 +1,353 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/lexer"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+type maxQueryDepthKey struct{}
+
+// WithMaxQueryDepth is middleware function to limit selection depth of the query document.
+// The depth is counted by nested field levels, e.g. "{ user { id } }" has depth 2.
+// Introspection fields which start with "__" are not counted in order to keep developer tools working.
+// When the document exceeds the limit, request is rejected with QUERY_TOO_DEEP error before execution.
+func WithMaxQueryDepth(depth int) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, maxQueryDepthKey{}, depth), nil
+	}
+}
+
+type maxAliasesKey struct{}
+
+// WithMaxAliases is middleware function to limit the number of aliased fields in the query document.
+// This blocks alias amplification attacks, e.g. hundreds of aliased login mutations in one document.
+// When the document exceeds the limit, request is rejected with TOO_MANY_ALIASES error before execution.
+func WithMaxAliases(aliases int) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, maxAliasesKey{}, aliases), nil
+	}
+}
+
+type maxRootFieldsKey struct{}
+
+// WithMaxRootFields is middleware function to limit the number of root fields per operation.
+// When some operation exceeds the limit, request is rejected with TOO_MANY_ROOT_FIELDS error before execution.
+func WithMaxRootFields(fields int) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, maxRootFieldsKey{}, fields), nil
+	}
+}
+
+type maxQuerySizeKey struct{}
+
+// WithMaxQuerySize is middleware function to limit byte size of the query document.
+// When the document exceeds the limit, request is rejected with QUERY_TOO_LARGE error before parsing.
+func WithMaxQuerySize(bytes int) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, maxQuerySizeKey{}, bytes), nil
+	}
+}
+
+type maxQueryTokensKey struct{}
+
+// WithMaxQueryTokens is middleware function to limit the number of lexical tokens in the query document.
+// Tokens are counted by the lexer which stops as soon as the limit is exceeded, so that huge documents don't reach the parser.
+// When the document exceeds the limit, request is rejected with TOO_MANY_TOKENS error before parsing.
+func WithMaxQueryTokens(tokens int) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, maxQueryTokensKey{}, tokens), nil
+	}
+}
+
+// checkSource checks the query document against size limits which are stored in the context before parsing
+func checkSource(ctx context.Context, query string) []GraphqlError {
+	if limit, ok := ctx.Value(maxQuerySizeKey{}).(int); ok && len(query) > limit {
+		return []GraphqlError{
+			{
+				Message: fmt.Sprintf("query size %d bytes exceeds maximum %d bytes", len(query), limit),
+				Extensions: map[string]interface{}{
+					"code": "QUERY_TOO_LARGE",
+				},
+			},
+		}
+	}
+	if limit, ok := ctx.Value(maxQueryTokensKey{}).(int); ok && queryTokensExceed(query, limit) {
+		return []GraphqlError{
+			{
+				Message: fmt.Sprintf("query exceeds maximum %d tokens", limit),
+				Extensions: map[string]interface{}{
+					"code": "TOO_MANY_TOKENS",
+				},
+			},
+		}
+	}
+	return nil
+}
+
+// queryTokensExceed reports whether the number of tokens in query exceeds limit.
+// If query has syntax error, returns false and the parser reports it.
+func queryTokensExceed(query string, limit int) bool {
+	lex := lexer.Lex(source.NewSource(&source.Source{
+		Body: []byte(query),
+	}))
+	for count := 0; ; count++ {
+		token, err := lex(0)
+		if err != nil || token.Kind == lexer.EOF {
+			return false
+		}
+		if count >= limit {
+			return true
+		}
+	}
+}
+
+// checkDocument checks the parsed document against limits which are stored in the context
+func checkDocument(ctx context.Context, doc *ast.Document) []GraphqlError {
+	var errs []GraphqlError
+	if limit, ok := ctx.Value(maxQueryDepthKey{}).(int); ok {
+		if queryDepth(doc, limit) > limit {
+			errs = append(errs, GraphqlError{
+				Message: fmt.Sprintf("query depth exceeds maximum depth %d", limit),
+				Extensions: map[string]interface{}{
+					"code": "QUERY_TOO_DEEP",
+				},
+			})
+		}
+	}
+	if limit, ok := ctx.Value(maxAliasesKey{}).(int); ok {
+		if aliasCount(doc, limit) > limit {
+			errs = append(errs, GraphqlError{
+				Message: fmt.Sprintf("query exceeds maximum %d aliases", limit),
+				Extensions: map[string]interface{}{
+					"code": "TOO_MANY_ALIASES",
+				},
+			})
+		}
+	}
+	if limit, ok := ctx.Value(maxRootFieldsKey{}).(int); ok {
+		if rootFieldCount(doc, limit) > limit {
+			errs = append(errs, GraphqlError{
+				Message: fmt.Sprintf("operation exceeds maximum %d root fields", limit),
+				Extensions: map[string]interface{}{
+					"code": "TOO_MANY_ROOT_FIELDS",
+				},
+			})
+		}
+	}
+	errs = append(errs, checkCSRF(ctx, doc)...)
+	errs = append(errs, checkIntrospection(ctx, doc)...)
+	errs = append(errs, checkOperationFilter(ctx, doc)...)
+	return errs
+}
+
+// collectFragments returns fragment definitions in the document, keyed by fragment name
+func collectFragments(doc *ast.Document) map[string]*ast.FragmentDefinition {
+	fragments := make(map[string]*ast.FragmentDefinition)
+	for _, d := range doc.Definitions {
+		if f, ok := d.(*ast.FragmentDefinition); ok && f.Name != nil {
+			fragments[f.Name.Value] = f
+		}
+	}
+	return fragments
+}
+
+// queryDepth returns maximum selection depth of all operations in the document.
+// The depth saturates at limit+1, so that counting stops as soon as the document exceeds the limit.
+func queryDepth(doc *ast.Document, limit int) int {
+	c := &depthCounter{
+		fragments: collectFragments(doc),
+		depths:    make(map[string]int),
+		visited:   make(map[string]struct{}),
+		limit:     limit,
+	}
+	var deepest int
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if depth := c.selectionDepth(op.SelectionSet); depth > deepest {
+			deepest = depth
+		}
+		if deepest > limit {
+			break
+		}
+	}
+	return deepest
+}
+
+// depthCounter memoizes depth of each fragment, because fragments may be spread many times like "{ ...a ...a }"
+// and walking them at every spread takes exponential time
+type depthCounter struct {
+	fragments map[string]*ast.FragmentDefinition
+	depths    map[string]int
+	visited   map[string]struct{}
+	limit     int
+}
+
+func (c *depthCounter) selectionDepth(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+	var deepest int
+	for _, s := range set.Selections {
+		var depth int
+		switch v := s.(type) {
+		case *ast.Field:
+			if v.Name != nil && strings.HasPrefix(v.Name.Value, "__") {
+				continue
+			}
+			depth = 1 + c.selectionDepth(v.SelectionSet)
+		case *ast.InlineFragment:
+			depth = c.selectionDepth(v.SelectionSet)
+		case *ast.FragmentSpread:
+			if v.Name == nil {
+				continue
+			}
+			depth = c.fragmentDepth(v.Name.Value)
+		}
+		if depth > deepest {
+			deepest = depth
+		}
+		if deepest > c.limit {
+			return c.limit + 1
+		}
+	}
+	return deepest
+}
+
+func (c *depthCounter) fragmentDepth(name string) int {
+	if depth, ok := c.depths[name]; ok {
+		return depth
+	}
+	f, ok := c.fragments[name]
+	if !ok {
+		return 0
+	}
+	// Guard from cyclic fragment spreads, validation will report it later
+	if _, ok := c.visited[name]; ok {
+		return 0
+	}
+	c.visited[name] = struct{}{}
+	depth := c.selectionDepth(f.SelectionSet)
+	delete(c.visited, name)
+	c.depths[name] = depth
+	return depth
+}
+
+// aliasCount returns total number of aliased fields in all operations.
+// Aliases in fragments are counted for each spread, and the count saturates at limit+1.
+func aliasCount(doc *ast.Document, limit int) int {
+	c := newFieldCounter(doc, limit, true, func(f *ast.Field) bool {
+		return f.Alias != nil
+	})
+	var count int
+	for _, d := range doc.Definitions {
+		if op, ok := d.(*ast.OperationDefinition); ok {
+			count = c.add(count, c.countFields(op.SelectionSet))
+		}
+	}
+	return count
+}
+
+// rootFieldCount returns maximum number of root fields of all operations, which saturates at limit+1
+func rootFieldCount(doc *ast.Document, limit int) int {
+	c := newFieldCounter(doc, limit, false, func(f *ast.Field) bool {
+		return true
+	})
+	var most int
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if count := c.countFields(op.SelectionSet); count > most {
+			most = count
+		}
+	}
+	return most
+}
+
+// fieldCounter counts fields which match to predicate in selection sets.
+// If recursive is false, only counts fields on the same level including fragments.
+// Counts of fragments are memoized like depthCounter, and saturate at limit+1.
+type fieldCounter struct {
+	fragments map[string]*ast.FragmentDefinition
+	counts    map[string]int
+	visited   map[string]struct{}
+	limit     int
+	recursive bool
+	match     func(*ast.Field) bool
+}
+
+func newFieldCounter(doc *ast.Document, limit int, recursive bool, match func(*ast.Field) bool) *fieldCounter {
+	return &fieldCounter{
+		fragments: collectFragments(doc),
+		counts:    make(map[string]int),
+		visited:   make(map[string]struct{}),
+		limit:     limit,
+		recursive: recursive,
+		match:     match,
+	}
+}
+
+// add returns sum of counts which saturates at limit+1
+func (c *fieldCounter) add(a, b int) int {
+	if a+b > c.limit {
+		return c.limit + 1
+	}
+	return a + b
+}
+
+func (c *fieldCounter) countFields(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+	var count int
+	for _, s := range set.Selections {
+		switch v := s.(type) {
+		case *ast.Field:
+			if c.match(v) {
+				count = c.add(count, 1)
+			}
+			if c.recursive {
+				count = c.add(count, c.countFields(v.SelectionSet))
+			}
+		case *ast.InlineFragment:
+			count = c.add(count, c.countFields(v.SelectionSet))
+		case *ast.FragmentSpread:
+			if v.Name != nil {
+				count = c.add(count, c.fragmentCount(v.Name.Value))
+			}
+		}
+		if count > c.limit {
+			return count
+		}
+	}
+	return count
+}
+
+func (c *fieldCounter) fragmentCount(name string) int {
+	if count, ok := c.counts[name]; ok {
+		return count
+	}
+	f, ok := c.fragments[name]
+	if !ok {
+		return 0
+	}
+	if _, ok := c.visited[name]; ok {
+		return 0
+	}
+	c.visited[name] = struct{}{}
+	count := c.countFields(f.SelectionSet)
+	delete(c.visited, name)
+	c.counts[name] = count
+	return count
+}
@@ -0,0 +1,148 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueryDepth(t *testing.T) {
+	tests := []struct {
+		query  string
+		expect int
+	}{
+		{query: `{ hello }`, expect: 1},
+		{query: `{ user { id } }`, expect: 2},
+		{query: `{ user { ...on Test_Type_User { id } } }`, expect: 2},
+		{query: `{ user { ...userFields } } fragment userFields on Test_Type_User { id }`, expect: 2},
+		{query: `{ __schema { types { fields { name } } } hello }`, expect: 1},
+		{query: `query a { hello } query b { user { id } }`, expect: 2},
+	}
+
+	for _, tt := range tests {
+		doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.expect, queryDepth(doc, 10), tt.query)
+		}
+	}
+
+	// Depth saturates at the limit
+	doc, err := parser.Parse(parser.ParseParams{Source: `{ user { id } }`})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, queryDepth(doc, 0))
+	}
+}
+
+// fragmentBomb returns the document whose fragments spread the next one twice,
+// so that walking fragments at every spread takes 2^n steps
+func fragmentBomb(n int, selection string) string {
+	var b strings.Builder
+	b.WriteString("{ ...f0 }")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, " fragment f%d on Query { ...f%d ...f%d }", i, i+1, i+1)
+	}
+	fmt.Fprintf(&b, " fragment f%d on Query { %s }", n, selection)
+	return b.String()
+}
+
+func TestQueryDepthFragmentBomb(t *testing.T) {
+	doc, err := parser.Parse(parser.ParseParams{Source: fragmentBomb(64, "user { id }")})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, queryDepth(doc, 10))
+	}
+}
+
+func TestMaxQueryDepth(t *testing.T) {
+	mux := NewServeMux(WithMaxQueryDepth(1))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ hello }`)
+	assert.Len(t, result.Errors, 0)
+
+	result = serveTestQuery(t, mux, `{ user(id: "1") { id } }`)
+	assert.Nil(t, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "QUERY_TOO_DEEP", result.Errors[0].Extensions["code"])
+	}
+}
+
+func TestAliasAndRootFieldCount(t *testing.T) {
+	tests := []struct {
+		query   string
+		aliases int
+		roots   int
+	}{
+		{query: `{ hello }`, aliases: 0, roots: 1},
+		{query: `{ a: hello b: hello user { id } }`, aliases: 2, roots: 3},
+		{query: `{ user { a: id b: id } }`, aliases: 2, roots: 1},
+		{query: `{ ...f ...f } fragment f on Query { a: hello b: hello }`, aliases: 4, roots: 4},
+		{query: `query x { hello } query y { a: hello b: hello }`, aliases: 2, roots: 2},
+	}
+
+	for _, tt := range tests {
+		doc, err := parser.Parse(parser.ParseParams{Source: tt.query})
+		if assert.NoError(t, err) {
+			assert.Equal(t, tt.aliases, aliasCount(doc, 10), tt.query)
+			assert.Equal(t, tt.roots, rootFieldCount(doc, 10), tt.query)
+		}
+	}
+
+	// Counts saturate at the limit, and fragments are not walked at every spread
+	doc, err := parser.Parse(parser.ParseParams{Source: fragmentBomb(64, "a: hello")})
+	if assert.NoError(t, err) {
+		assert.Equal(t, 11, aliasCount(doc, 10))
+		assert.Equal(t, 11, rootFieldCount(doc, 10))
+	}
+}
+
+func TestMaxAliasesAndRootFields(t *testing.T) {
+	mux := NewServeMux(WithMaxAliases(1), WithMaxRootFields(2))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `mutation { a: login(password: "x") }`)
+	assert.Len(t, result.Errors, 0)
+
+	result = serveTestQuery(t, mux, `mutation { a: login(password: "x") b: login(password: "y") }`)
+	assert.Nil(t, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "TOO_MANY_ALIASES", result.Errors[0].Extensions["code"])
+	}
+
+	result = serveTestQuery(t, mux, `{ hello user { id } x: hello }`)
+	assert.Nil(t, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "TOO_MANY_ROOT_FIELDS", result.Errors[0].Extensions["code"])
+	}
+}
+
+func TestQueryTokensExceed(t *testing.T) {
+	// "{", "user", "(", "id", ":", "\"1\"", ")", "{", "id", "}", "}"
+	query := `{ user(id: "1") { id } }`
+	assert.False(t, queryTokensExceed(query, 11))
+	assert.True(t, queryTokensExceed(query, 10))
+	// Syntax errors are reported by the parser
+	assert.False(t, queryTokensExceed(`{ "unterminated`, 1))
+}
+
+func TestMaxQuerySizeAndTokens(t *testing.T) {
+	mux := NewServeMux(WithMaxQuerySize(20), WithMaxQueryTokens(5))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ hello }`)
+	assert.Len(t, result.Errors, 0)
+
+	result = serveTestQuery(t, mux, `{ hello hello hello hello }`)
+	assert.Nil(t, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "QUERY_TOO_LARGE", result.Errors[0].Extensions["code"])
+	}
+
+	result = serveTestQuery(t, mux, `{ a:hello b:hello }`)
+	assert.Nil(t, result.Data)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "TOO_MANY_TOKENS", result.Errors[0].Extensions["code"])
+	}
+}
@@ -0,0 +1,25 @@
+package runtime
+
+import (
+	"log"
+)
+
+// Logger is interface for logging gateway events like slow queries.
+// *log.Logger satisfies this interface, and also it's easy to adapt to structured loggers.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type defaultLogger struct{}
+
+func (defaultLogger) Printf(format string, v ...interface{}) {
+	log.Printf(format, v...)
+}
+
+// logger returns configured logger of ServeMux, or default logger which writes to standard logger
+func (s *ServeMux) logger() Logger {
+	if s == nil || s.Logger == nil {
+		return defaultLogger{}
+	}
+	return s.Logger
+}
@@ -0,0 +1,118 @@
+package runtime
+
+import (
+	"sort"
+
+	"encoding/json"
+	"net/http"
+)
+
+// FieldMapper is implemented by generated handlers to describe gRPC methods which back root fields
+type FieldMapper interface {
+	FieldMappings() []FieldMapping
+}
+
+// ArgumentBinding binds GraphQL argument to the field of request message
+type ArgumentBinding struct {
+	Argument     string `json:"argument"`
+	RequestField string `json:"requestField"`
+}
+
+// FieldMapping describes gRPC method which backs root field of the schema
+type FieldMapping struct {
+	// Operation is "query" or "mutation"
+	Operation string `json:"operation"`
+	// Field is root field name, or dot separated path if the field is grouped under namespace
+	Field   string `json:"field"`
+	Service string `json:"service"`
+	// Method is full method name like "/greeter.Greeter/SayHello", empty if the handler doesn't implement FieldMapper
+	Method       string `json:"method,omitempty"`
+	RequestType  string `json:"requestType,omitempty"`
+	ResponseType string `json:"responseType,omitempty"`
+	// Input is argument name which binds whole request message as input object, then Arguments are fields of the input
+	Input     string            `json:"input,omitempty"`
+	Arguments []ArgumentBinding `json:"arguments,omitempty"`
+	// ResponseField is the field of response message which is responded instead of whole message
+	ResponseField string `json:"responseField,omitempty"`
+	// Stream is true if the method is server-streaming and the field responds list of collected responses
+	Stream bool `json:"stream,omitempty"`
+}
+
+// FieldMappings returns gRPC methods of root fields of the registered handlers, sorted by operation and field.
+// Note that handlers which are added via AddTenantHandler are not included.
+func (s *ServeMux) FieldMappings() []FieldMapping {
+	mappings := []FieldMapping{}
+	for _, h := range s.handlers {
+		mappings = append(mappings, fieldMappingsOf(h)...)
+	}
+	sort.SliceStable(mappings, func(i, j int) bool {
+		// Queries come first
+		if mappings[i].Operation != mappings[j].Operation {
+			return mappings[i].Operation > mappings[j].Operation
+		}
+		return mappings[i].Field < mappings[j].Field
+	})
+	return mappings
+}
+
+// fieldMappingsOf returns mappings of the handler which respect renaming and namespacing of the handler
+func fieldMappingsOf(h GraphqlHandler) []FieldMapping {
+	if v, ok := h.(schemaVersioner); ok {
+		h, _ = v.snapshot()
+	}
+	switch v := h.(type) {
+	case *namespacedHandler:
+		mappings := fieldMappingsOf(v.GraphqlHandler)
+		for i, m := range mappings {
+			renames := v.queries
+			if m.Operation == "mutation" {
+				renames = v.mutations
+			}
+			if to, ok := renames[m.Field]; ok {
+				mappings[i].Field = to
+			}
+		}
+		return mappings
+	case *namespaceHandler:
+		mappings := fieldMappingsOf(v.GraphqlHandler)
+		for i := range mappings {
+			mappings[i].Field = v.namespace + "." + mappings[i].Field
+		}
+		return mappings
+	}
+
+	var mappings []FieldMapping
+	if v, ok := h.(FieldMapper); ok {
+		mappings = v.FieldMappings()
+	} else {
+		// Fallback to field names for handlers which don't describe their methods
+		for name := range h.GetQueries(nil) {
+			mappings = append(mappings, FieldMapping{Operation: "query", Field: name, Service: handlerName(h)})
+		}
+		for name := range h.GetMutations(nil) {
+			mappings = append(mappings, FieldMapping{Operation: "mutation", Field: name, Service: handlerName(h)})
+		}
+	}
+	groups := fieldGroupsOf(h)
+	for i := range mappings {
+		if group, ok := groups[mappings[i].Field]; ok {
+			mappings[i].Field = group + "." + mappings[i].Field
+		}
+	}
+	return mappings
+}
+
+// MappingHandler returns http.Handler which responds FieldMappings in JSON, mount it on like "/graphql/debug/mapping".
+// The mapping exposes internal gRPC services, so requests are allowed only if they pass the predicate,
+// e.g. IntrospectionFromNetworks of internal networks. Denied requests are responded with 403.
+func (s *ServeMux) MappingHandler(allow IntrospectionPredicate) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if allow == nil || !allow(r.Context(), r) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+		out, _ := json.Marshal(s.FieldMappings()) // nolint: errcheck
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(out) // nolint: errcheck
+	})
+}
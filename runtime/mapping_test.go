@@ -0,0 +1,91 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldMappings(t *testing.T) {
+	file := testBookFiles(t)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(handlers[0]))
+	assert.NoError(t, mux.AddNamespacedHandler("legacy", newTestHandler()))
+
+	mappings := mux.FieldMappings()
+	fields := make([]string, len(mappings))
+	for i, m := range mappings {
+		fields[i] = m.Operation + ":" + m.Field
+	}
+	assert.Equal(t, []string{
+		"query:books",
+		"query:getBook",
+		"query:legacy.hello",
+		"query:legacy.user",
+		"mutation:addBook",
+		"mutation:deleteBook",
+		"mutation:legacy.login",
+	}, fields)
+
+	assert.Equal(t, FieldMapping{
+		Operation:     "query",
+		Field:         "books",
+		Service:       "library.v1.LibraryService",
+		Method:        "/library.v1.LibraryService/ListBooks",
+		RequestType:   "library.v1.ListBooksRequest",
+		ResponseType:  "library.v1.ListBooksResponse",
+		ResponseField: "books",
+	}, mappings[0])
+	assert.Equal(t, []ArgumentBinding{{Argument: "title", RequestField: "title"}}, mappings[1].Arguments)
+
+	addBook := mappings[4]
+	assert.Equal(t, "book", addBook.Input)
+	assert.Contains(t, addBook.Arguments, ArgumentBinding{Argument: "pageCount", RequestField: "page_count"})
+	assert.NotContains(t, addBook.Arguments, ArgumentBinding{Argument: "secret", RequestField: "secret"})
+
+	// Handlers which don't implement FieldMapper only have field names
+	assert.Equal(t, "", mappings[2].Method)
+	assert.Equal(t, "*runtime.testHandler", mappings[2].Service)
+}
+
+func TestMappingHandler(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	t.Run("denied", func(t *testing.T) {
+		for _, h := range []http.Handler{
+			mux.MappingHandler(nil),
+			mux.MappingHandler(func(ctx context.Context, r *http.Request) bool { return false }),
+		} {
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql/debug/mapping", nil))
+			assert.Equal(t, http.StatusForbidden, w.Code)
+		}
+	})
+
+	t.Run("allowed", func(t *testing.T) {
+		allow, err := IntrospectionFromNetworks("192.0.2.0/24")
+		if err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		mux.MappingHandler(allow).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql/debug/mapping", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var mappings []FieldMapping
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &mappings))
+		assert.Len(t, mappings, 3)
+		assert.Equal(t, "hello", mappings[0].Field)
+	})
+}
@@ -0,0 +1,277 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// DefaultMetricsBuckets is default histogram buckets in seconds, which is the same as Prometheus client default
+var DefaultMetricsBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// DefaultMetricsMaxOperations is default number of distinct operation labels of Metrics
+var DefaultMetricsMaxOperations = 100
+
+// otherOperation is the label of operations beyond MaxOperations
+const otherOperation = "other"
+
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(buckets []float64, v float64) {
+	if h.counts == nil {
+		h.counts = make([]uint64, len(buckets))
+	}
+	for i, b := range buckets {
+		if v <= b {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// Metrics collects request and resolver metrics,
+// and exposes them in Prometheus text exposition format so it can be scraped as it is like promhttp handler.
+// Metrics are labeled with the operation which is the operation name, or the document hash for anonymous operation,
+// see OperationLabelFromContext. Operations are chosen by clients, so operations beyond MaxOperations are labeled
+// as "other" in order to bound cardinality of metrics.
+//
+//	metrics := runtime.NewMetrics()
+//	mux := runtime.NewServeMux(runtime.WithMetrics(metrics))
+//	http.Handle("/metrics", metrics)
+type Metrics struct {
+	// Namespace is prefix of metric names, default is "graphql"
+	Namespace string
+	// Buckets is histogram buckets in seconds, default is DefaultMetricsBuckets
+	Buckets []float64
+	// MaxOperations is the number of distinct operation labels, default is DefaultMetricsMaxOperations
+	MaxOperations int
+
+	mu         sync.Mutex
+	operations map[string]struct{}
+	requests   map[string]uint64
+	errors     map[[2]string]uint64
+	inFlight   int64
+	durations  map[string]*histogram
+	resolvers  map[[3]string]*histogram
+	rejected   map[string]uint64
+}
+
+// NewMetrics creates Metrics pointer
+func NewMetrics() *Metrics {
+	return &Metrics{
+		Namespace:     "graphql",
+		Buckets:       DefaultMetricsBuckets,
+		MaxOperations: DefaultMetricsMaxOperations,
+		operations:    make(map[string]struct{}),
+		requests:      make(map[string]uint64),
+		errors:        make(map[[2]string]uint64),
+		durations:     make(map[string]*histogram),
+		resolvers:     make(map[[3]string]*histogram),
+		rejected:      make(map[string]uint64),
+	}
+}
+
+type metricsKey struct{}
+
+// WithMetrics is middleware function to collect metrics of requests into m
+func WithMetrics(m *Metrics) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		start := time.Now()
+		m.mu.Lock()
+		m.inFlight++
+		m.mu.Unlock()
+
+		onFinish(ctx, func(result *graphql.Result) {
+			m.observeRequest(OperationLabelFromContext(ctx), result, time.Since(start))
+		})
+		return context.WithValue(ctx, metricsKey{}, m), nil
+	}
+}
+
+// metricsFromContext returns metrics in the context.
+// Returned metrics may be nil, and then observations do nothing.
+func metricsFromContext(ctx context.Context) *Metrics {
+	m, _ := ctx.Value(metricsKey{}).(*Metrics) // nolint: errcheck
+	return m
+}
+
+// label returns the operation label, which is "other" if the number of labels reaches MaxOperations.
+// This must be called with the lock.
+func (m *Metrics) label(operation string) string {
+	if _, ok := m.operations[operation]; ok {
+		return operation
+	}
+	if len(m.operations) >= m.MaxOperations {
+		return otherOperation
+	}
+	m.operations[operation] = struct{}{}
+	return operation
+}
+
+func (m *Metrics) observeRequest(operation string, result *graphql.Result, d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	operation = m.label(operation)
+	m.inFlight--
+	m.requests[operation]++
+	for _, e := range result.Errors {
+		code, _ := e.Extensions["code"].(string) // nolint: errcheck
+		if code == "" {
+			code = "UNKNOWN"
+		}
+		m.errors[[2]string{operation, code}]++
+	}
+	h, ok := m.durations[operation]
+	if !ok {
+		h = &histogram{}
+		m.durations[operation] = h
+	}
+	h.observe(m.Buckets, d.Seconds())
+}
+
+// resolver records latency of the resolver which calls upstream, and returns function to finish it
+func (m *Metrics) resolver(ctx context.Context, info graphql.ResolveInfo) func() {
+	if m == nil {
+		return func() {}
+	}
+	start := time.Now()
+	operation := OperationLabelFromContext(ctx)
+	return func() {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		key := [3]string{m.label(operation), info.ParentType.Name(), info.FieldName}
+		h, ok := m.resolvers[key]
+		if !ok {
+			h = &histogram{}
+			m.resolvers[key] = h
+		}
+		h.observe(m.Buckets, time.Since(start).Seconds())
+	}
+}
+
+// reject counts the operation which is rejected by policy like persisted operations enforcement.
+// Rejected operations are labeled only by the reason, because they are not validated.
+func (m *Metrics) reject(reason string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rejected[reason]++
+}
+
+// ServeHTTP implements http.Handler which responds metrics in Prometheus text exposition format
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	m.WriteTo(w) // nolint: errcheck
+}
+
+// WriteTo writes metrics in Prometheus text exposition format
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var b strings.Builder
+	name := func(s string) string {
+		return m.Namespace + "_" + s
+	}
+
+	writeHeader(&b, name("requests_total"), "counter", "Total number of GraphQL requests by operation.")
+	for _, k := range sortedKeys(m.requests) {
+		fmt.Fprintf(&b, "%s{operation=%q} %d\n", name("requests_total"), k, m.requests[k])
+	}
+
+	writeHeader(&b, name("errors_total"), "counter", "Total number of GraphQL errors by operation and error code.")
+	for _, k := range sortedPairs(m.errors) {
+		fmt.Fprintf(&b, "%s{operation=%q,code=%q} %d\n", name("errors_total"), k[0], k[1], m.errors[k])
+	}
+
+	writeHeader(&b, name("requests_in_flight"), "gauge", "Number of GraphQL requests currently being served.")
+	fmt.Fprintf(&b, "%s %d\n", name("requests_in_flight"), m.inFlight)
+
+	writeHeader(&b, name("request_duration_seconds"), "histogram", "GraphQL request latency by operation.")
+	operations := make([]string, 0, len(m.durations))
+	for k := range m.durations {
+		operations = append(operations, k)
+	}
+	sort.Strings(operations)
+	for _, k := range operations {
+		m.writeHistogram(&b, name("request_duration_seconds"), fmt.Sprintf("operation=%q", k), m.durations[k])
+	}
+
+	writeHeader(&b, name("resolver_duration_seconds"), "histogram", "Upstream resolver latency by operation, parent type and field.")
+	fields := make([][3]string, 0, len(m.resolvers))
+	for k := range m.resolvers {
+		fields = append(fields, k)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		for n := range fields[i] {
+			if fields[i][n] != fields[j][n] {
+				return fields[i][n] < fields[j][n]
+			}
+		}
+		return false
+	})
+	for _, k := range fields {
+		labels := fmt.Sprintf("operation=%q,parent_type=%q,field=%q", k[0], k[1], k[2])
+		m.writeHistogram(&b, name("resolver_duration_seconds"), labels, m.resolvers[k])
+	}
+
+	writeHeader(&b, name("rejected_operations_total"), "counter", "Total number of operations rejected by policy by reason.")
+	for _, k := range sortedKeys(m.rejected) {
+		fmt.Fprintf(&b, "%s{reason=%q} %d\n", name("rejected_operations_total"), k, m.rejected[k])
+	}
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (m *Metrics) writeHistogram(b *strings.Builder, name, labels string, h *histogram) {
+	for i, bucket := range m.Buckets {
+		fmt.Fprintf(b, "%s_bucket{%s,le=\"%g\"} %d\n", name, labels, bucket, h.counts[i])
+	}
+	fmt.Fprintf(b, "%s_bucket{%s,le=\"+Inf\"} %d\n", name, labels, h.count)
+	fmt.Fprintf(b, "%s_sum{%s} %g\n", name, labels, h.sum)
+	fmt.Fprintf(b, "%s_count{%s} %d\n", name, labels, h.count)
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func sortedPairs(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithMetrics(t *testing.T) {
+	m := NewMetrics()
+	mux := NewServeMux(WithMetrics(m), WithMaxRootFields(2))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	serveTestQuery(t, mux, `query GetUser { user(id: "1") { id } }`)
+	serveTestQuery(t, mux, `{ a: hello b: hello c: hello }`)
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+
+	// Operation is named by the document, or the document hash for anonymous operation
+	anonymous := "anonymous:" + documentHash(`{ a: hello b: hello c: hello }`)
+	assert.Contains(t, body, "# TYPE graphql_requests_total counter\n")
+	assert.Contains(t, body, `graphql_requests_total{operation="GetUser"} 1`)
+	assert.Contains(t, body, `graphql_requests_total{operation="`+anonymous+`"} 1`)
+	assert.Contains(t, body, `graphql_errors_total{operation="`+anonymous+`",code="TOO_MANY_ROOT_FIELDS"} 1`)
+	assert.Contains(t, body, "graphql_requests_in_flight 0\n")
+	assert.Contains(t, body, `graphql_request_duration_seconds_count{operation="GetUser"} 1`)
+	assert.Contains(t, body, `graphql_resolver_duration_seconds_count{operation="GetUser",parent_type="Query",field="user"} 1`)
+	// Default resolvers are not measured
+	assert.NotContains(t, body, `field="id"`)
+}
+
+func TestMetricsMaxOperations(t *testing.T) {
+	m := NewMetrics()
+	m.MaxOperations = 2
+	mux := NewServeMux(WithMetrics(m))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	serveTestQuery(t, mux, `query A { hello }`)
+	serveTestQuery(t, mux, `query B { hello }`)
+	serveTestQuery(t, mux, `query C { hello }`)
+	serveTestQuery(t, mux, `{ a: hello }`)
+	serveTestQuery(t, mux, `query A { hello }`)
+
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	assert.Contains(t, body, `graphql_requests_total{operation="A"} 2`)
+	assert.Contains(t, body, `graphql_requests_total{operation="B"} 1`)
+	assert.Contains(t, body, `graphql_requests_total{operation="other"} 2`)
+	assert.NotContains(t, body, `operation="C"`)
+}
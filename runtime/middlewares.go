@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"errors"
 	"net/http"
 
 	"google.golang.org/grpc/metadata"
@@ -23,20 +24,44 @@ func NewMiddlewareError(code, message string) *MiddlewareError {
 	}
 }
 
-// Cors is middelware function to provide CORS headers to response headers
-func Cors() MiddlewareFunc {
-	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
-		w.Header().Set("Access-Control-Allow-Origin", r.URL.Host)
-		w.Header().Set("Access-Control-Allow-Credentials", "true")
-		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-		w.Header().Set("Access-Control-Max-Age", "1728000")
-		return ctx, nil
+// middlewareError converts the error which is returned from middleware to GraphQL error
+func middlewareError(err error) GraphqlError {
+	if me, ok := err.(*MiddlewareError); ok {
+		return GraphqlError{
+			Message: me.Message,
+			Extensions: map[string]interface{}{
+				"code": me.Code,
+			},
+		}
+	}
+	return GraphqlError{
+		Message: err.Error(),
+		Extensions: map[string]interface{}{
+			"code": "MIDDLEWARE_ERROR",
+		},
 	}
 }
 
+// errResponded is returned from middleware which has already written the response like CORS preflight,
+// ServeMux stops serving the request without writing GraphQL response
+var errResponded = errors.New("response has already been written by middleware")
+
+// WithMetadata is middleware function to annotate outgoing gRPC metadata per request.
+// Metadata which is returned from annotator is joined to the metadata of the outgoing context,
+// so that all gRPC calls of the request send it.
 func WithMetadata(annotator func(context.Context, *http.Request) metadata.MD) MiddlewareFunc {
 	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
-		serveMux.metadataAnnotators = append(serveMux.metadataAnnotators, annotator)
-		return ctx, nil
+		return appendOutgoingMetadata(ctx, annotator(ctx, r)), nil
+	}
+}
+
+// appendOutgoingMetadata joins md to the metadata of the outgoing context
+func appendOutgoingMetadata(ctx context.Context, md metadata.MD) context.Context {
+	if md.Len() == 0 {
+		return ctx
+	}
+	if outgoing, ok := metadata.FromOutgoingContext(ctx); ok {
+		md = metadata.Join(outgoing, md)
 	}
+	return metadata.NewOutgoingContext(ctx, md)
 }
@@ -11,6 +11,7 @@ import (
 
 	"github.com/graphql-go/graphql"
 	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/metadata"
 )
 
 func TestMiddlewareError(t *testing.T) {
@@ -66,3 +67,39 @@ func TestMiddlewareError(t *testing.T) {
 		}
 	})
 }
+
+func TestWithMetadata(t *testing.T) {
+	var received []metadata.MD
+	h := newTestHandler()
+	h.queries["metadata"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			md, _ := metadata.FromOutgoingContext(p.Context)
+			received = append(received, md)
+			return "ok", nil
+		},
+	}
+	mux := NewServeMux(
+		WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+			return metadata.Pairs("x-request-path", r.URL.Path)
+		}),
+		WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+			return metadata.Pairs("x-tenant", "acme")
+		}),
+		// Nothing is added for empty metadata
+		WithMetadata(func(ctx context.Context, r *http.Request) metadata.MD {
+			return nil
+		}),
+	)
+	assert.NoError(t, mux.AddHandler(h))
+
+	// Annotators must not accumulate across requests
+	serveTestQuery(t, mux, `{ metadata }`)
+	serveTestQuery(t, mux, `{ metadata }`)
+
+	if assert.Len(t, received, 2) {
+		for _, md := range received {
+			assert.Equal(t, metadata.Pairs("x-request-path", "/graphql", "x-tenant", "acme"), md)
+		}
+	}
+}
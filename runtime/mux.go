@@ -2,16 +2,19 @@ package runtime
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
+	"sync"
 
-	"encoding/json"
 	"net/http"
 	"net/textproto"
 
 	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/metadata"
 )
 
 type (
@@ -30,15 +33,37 @@ type HeaderMatcherFunc func(string) (string, bool)
 
 // ServeMux is struct can execute graphql request via incoming HTTP request.
 // This is inspired from grpc-gateway implementation, thanks!
+// ServeMux is safe for concurrent requests, but handlers and exported fields must be configured before serving
+// because they are shared by all requests. Request scoped state is kept in the context.
 type ServeMux struct {
 	middlewares  []MiddlewareFunc
 	ErrorHandler GraphqlErrorHandler
+	// Logger is used for gateway logs like slow queries, default writes to standard logger
+	Logger Logger
+	// TenantSelector selects tenant of the request for handlers which are registered via AddTenantHandler, default is TenantByHost
+	TenantSelector TenantSelector
+	// NamespaceConflicts renames conflicted root fields of later registered handler to "<Service>_<field>"
+	// instead of returning an error from AddHandler
+	NamespaceConflicts bool
+	// NamespaceByService groups root fields of each handler under the field of lower camel cased service name
+	// like "query { userService { getUser } }" instead of flat root fields
+	NamespaceByService bool
+
+	handlers       []GraphqlHandler
+	tenantHandlers map[string][]GraphqlHandler
+	scoped         []handlerMiddlewares
+	directives     map[string]*directive
+	built          bool
 
-	handlers []GraphqlHandler
+	groupMu      sync.Mutex
+	groupObjects map[string]*groupObject
 
-	incomingHeaderMatcher HeaderMatcherFunc
-	outgoingHeaderMatcher HeaderMatcherFunc
-	metadataAnnotators    []func(context.Context, *http.Request) metadata.MD
+	servedMu sync.Mutex
+	served   map[string]*servedSchema
+
+	incomingHeaderMatcher  HeaderMatcherFunc
+	outgoingHeaderMatcher  HeaderMatcherFunc
+	outgoingTrailerMatcher HeaderMatcherFunc
 }
 
 // NewServeMux creates ServeMux pointer
@@ -49,15 +74,42 @@ func NewServeMux(ms ...MiddlewareFunc) *ServeMux {
 	}
 }
 
-// AddHandler registers graphql handler which is built via plugin
+// AddHandler registers graphql handler which is built via plugin.
+// Returns an error if root field names conflict with already registered handlers, unless NamespaceConflicts is enabled.
 func (s *ServeMux) AddHandler(h GraphqlHandler) error {
-	if err := s.validateHandler(h); err != nil {
+	registered := append([]GraphqlHandler{}, s.handlers...)
+	for _, hs := range s.tenantHandlers {
+		registered = append(registered, hs...)
+	}
+	h, err := s.prepareHandler(h, registered)
+	if err != nil {
 		return err
 	}
 	s.handlers = append(s.handlers, h)
 	return nil
 }
 
+// prepareHandler namespaces and validates the handler, then checks conflicts with registered handlers which serve the same requests
+func (s *ServeMux) prepareHandler(h GraphqlHandler, registered []GraphqlHandler) (GraphqlHandler, error) {
+	if s.built {
+		return nil, ErrServeMuxBuilt
+	}
+	if s.NamespaceByService {
+		switch h.(type) {
+		case *namespaceHandler, schemaVersioner:
+		default:
+			h = newNamespaceHandler(namespaceOf(h), h)
+		}
+	}
+	if err := s.validateHandler(h); err != nil {
+		return nil, err
+	}
+	if err := checkGroupConflicts(h, registered); err != nil {
+		return nil, err
+	}
+	return s.resolveConflicts(h, registered)
+}
+
 // Validate handler definition
 func (s *ServeMux) validateHandler(h GraphqlHandler) error {
 	queries := h.GetQueries(nil)
@@ -68,7 +120,32 @@ func (s *ServeMux) validateHandler(h GraphqlHandler) error {
 		return nil
 	}
 
+	// Try to generate Schema and check error
+	if _, err := newSchema(queries, mutations); err != nil {
+		return fmt.Errorf("Schema validation error: %s", err)
+	}
+	return nil
+}
+
+// Object types lazily initialize their fields when the schema is built at first, and generated types are shared
+// between requests, so schemas are built one by one. instrumentSchema also holds the lock while wrapping resolvers.
+var schemaMu sync.Mutex
+
+// newSchema builds schema of Query and Mutation root fields, directives are declared in addition to specified ones
+func newSchema(queries, mutations graphql.Fields, directives ...*graphql.Directive) (graphql.Schema, error) {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+
 	schemaConfig := graphql.SchemaConfig{}
+	if len(directives) > 0 {
+		schemaConfig.Directives = append(append([]*graphql.Directive{}, graphql.SpecifiedDirectives...), directives...)
+		// graphql-go doesn't collect types of directive arguments
+		for _, d := range directives {
+			for _, arg := range d.Args {
+				schemaConfig.Types = append(schemaConfig.Types, arg.Type)
+			}
+		}
+	}
 	if len(queries) > 0 {
 		schemaConfig.Query = graphql.NewObject(graphql.ObjectConfig{
 			Name:   "Query",
@@ -81,16 +158,51 @@ func (s *ServeMux) validateHandler(h GraphqlHandler) error {
 			Fields: mutations,
 		})
 	}
+	schema, err := graphql.NewSchema(schemaConfig)
+	if err != nil {
+		return schema, err
+	}
+	// Objects which implement declared interfaces may be referred only via fields of the interface
+	if types := declaredImplementations(schema); len(types) > 0 {
+		schemaConfig.Types = append(schemaConfig.Types, types...)
+		return graphql.NewSchema(schemaConfig)
+	}
+	return schema, nil
+}
 
-	// Try to generate Schema and check error
-	if _, err := graphql.NewSchema(schemaConfig); err != nil {
-		return fmt.Errorf("Schema validation error: %s", err)
+// staticSchemaOf builds schema of the handlers without connections
+func (s *ServeMux) staticSchemaOf(handlers []GraphqlHandler) (graphql.Schema, error) {
+	queries := graphql.Fields{}
+	mutations := graphql.Fields{}
+	groups := map[string]string{}
+	for _, h := range handlers {
+		if v, ok := h.(schemaVersioner); ok {
+			h, _ = v.snapshot()
+		}
+		for k, v := range h.GetQueries(nil) {
+			queries[k] = v
+		}
+		for k, v := range h.GetMutations(nil) {
+			mutations[k] = v
+		}
+		for k, v := range fieldGroupsOf(h) {
+			groups[k] = v
+		}
 	}
-	return nil
+	return newSchema(
+		s.groupFields("Query", queries, groups),
+		s.groupFields("Mutation", mutations, groups),
+		s.directiveDefinitions()...,
+	)
 }
 
-// Use adds more middlwares
+// Use adds more middlwares. Middlewares are not added after ServeMux is built,
+// which is logged instead of returning ErrServeMuxBuilt so that calls can be chained.
 func (s *ServeMux) Use(ms ...MiddlewareFunc) *ServeMux {
+	if s.built {
+		s.logger().Printf("%d middlewares are not added: %s", len(ms), ErrServeMuxBuilt)
+		return s
+	}
 	s.middlewares = append(s.middlewares, ms...)
 	return s
 }
@@ -109,48 +221,107 @@ func DefaultHeaderMatcher(key string) (string, bool) {
 	return "", false
 }
 
+// ServeMuxOption configures ServeMux like grpc-gateway, see ServeMux.Configure
+type ServeMuxOption func(*ServeMux)
+
+// WithIncomingHeaderMatcher sets the matcher of HTTP request headers which are forwarded to upstream as gRPC metadata,
+// default is DefaultHeaderMatcher
+func WithIncomingHeaderMatcher(fn HeaderMatcherFunc) ServeMuxOption {
+	return func(s *ServeMux) {
+		s.incomingHeaderMatcher = fn
+	}
+}
+
+// WithOutgoingHeaderMatcher sets the matcher of upstream response header metadata which is forwarded to HTTP response headers.
+// Default forwards all metadata with Grpc-Metadata- prefix, so set the matcher or use WithResponseHeaderAllowlist
+// in order not to expose internal metadata.
+func WithOutgoingHeaderMatcher(fn HeaderMatcherFunc) ServeMuxOption {
+	return func(s *ServeMux) {
+		s.outgoingHeaderMatcher = fn
+	}
+}
+
+// WithOutgoingTrailerMatcher sets the matcher of upstream response trailer metadata which is forwarded to HTTP response headers,
+// default forwards all metadata with Grpc-Trailer- prefix
+func WithOutgoingTrailerMatcher(fn HeaderMatcherFunc) ServeMuxOption {
+	return func(s *ServeMux) {
+		s.outgoingTrailerMatcher = fn
+	}
+}
+
+// Configure applies options to ServeMux, returns ErrServeMuxBuilt if ServeMux is already built
+func (s *ServeMux) Configure(opts ...ServeMuxOption) error {
+	if s.built {
+		return ErrServeMuxBuilt
+	}
+	for _, o := range opts {
+		o(s)
+	}
+	return nil
+}
+
+// incomingMatcher returns the matcher of incoming request headers, which is DefaultHeaderMatcher by default
+func (s *ServeMux) incomingMatcher() HeaderMatcherFunc {
+	if s.incomingHeaderMatcher == nil {
+		return DefaultHeaderMatcher
+	}
+	return s.incomingHeaderMatcher
+}
+
 // ServeHTTP implements http.Handler
 func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	for _, m := range s.middlewares {
-		var err error
-		ctx, err = m(ctx, s, w, r)
+	s.serve(w, r, s.middlewares)
+}
+
+// serve executes graphql request with middlewares
+func (s *ServeMux) serve(w http.ResponseWriter, r *http.Request, middlewares []MiddlewareFunc) {
+	ctx := withRequestScope(r.Context())
+	for _, m := range middlewares {
+		next, err := m(ctx, s, w, r)
+		if err == errResponded {
+			// Complete the request for preceding middlewares like metrics
+			finishRequest(ctx, &graphql.Result{})
+			return
+		}
 		if err != nil {
-			ge := GraphqlError{}
-			if me, ok := err.(*MiddlewareError); ok {
-				ge.Message = me.Message
-				ge.Extensions = map[string]interface{}{
-					"code": me.Code,
-				}
-			} else {
-				ge.Message = err.Error()
-				ge.Extensions = map[string]interface{}{
-					"code": "MIDDLEWARE_ERROR",
-				}
-			}
-			respondResult(w, &graphql.Result{
-				Errors: []GraphqlError{ge},
+			s.respondResult(ctx, w, &graphql.Result{
+				Errors: []GraphqlError{middlewareError(err)},
 			})
 			return
 		}
+		ctx = next
 	}
 
-	if s.incomingHeaderMatcher == nil {
-		s.incomingHeaderMatcher = DefaultHeaderMatcher
-	}
-
-	if s.outgoingHeaderMatcher == nil {
-		s.outgoingHeaderMatcher = func(key string) (string, bool) {
-			return fmt.Sprintf("%s%s", MetadataHeaderPrefix, key), true
+	tenant, served := s.handlersFor(r)
+	handlers := snapshotHandlers(served)
+	for _, hs := range handlers {
+		if hs.version != "" {
+			respondSchemaVersion(ctx, w, hs.version)
 		}
 	}
+	schema, err := s.servedSchemaOf(tenant, handlers)
+	if err != nil {
+		s.respondResult(ctx, w, &graphql.Result{
+			Errors: []GraphqlError{
+				{
+					Message: "Failed to build schema: " + err.Error(),
+					Extensions: map[string]interface{}{
+						"code": "SCHEMA_GENERATION_ERROR",
+					},
+				},
+			},
+		})
+		return
+	}
 
-	queries := graphql.Fields{}
-	mutations := graphql.Fields{}
-	for _, h := range s.handlers {
+	bound := boundFields{}
+	scoped := newScopedMiddlewares(w, r)
+	for _, hs := range handlers {
+		ms := s.middlewaresOf(hs.handler)
+		h := hs.snapshot
 		c, closer, err := h.CreateConnection(ctx)
 		if err != nil {
-			respondResult(w, &graphql.Result{
+			s.respondResult(ctx, w, &graphql.Result{
 				Errors: []GraphqlError{
 					{
 						Message: "Failed to create grpc connection: " + err.Error(),
@@ -164,51 +335,47 @@ func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 		defer closer()
 
+		var fields []string
 		for k, v := range h.GetQueries(c) {
-			queries[k] = v
+			bound["Query."+k] = v.Resolve
+			fields = append(fields, k)
+			if c != nil {
+				setUpstream(ctx, k, c.Target())
+			}
 		}
 		for k, v := range h.GetMutations(c) {
-			mutations[k] = v
+			bound["Mutation."+k] = v.Resolve
+			fields = append(fields, k)
+			if c != nil {
+				setUpstream(ctx, k, c.Target())
+			}
 		}
+		scoped.add(ms, fields, fieldGroupsOf(h))
 	}
+	ctx = bindFields(ctx, bound)
 
-	schemaConfig := graphql.SchemaConfig{}
-	if len(queries) > 0 {
-		schemaConfig.Query = graphql.NewObject(graphql.ObjectConfig{
-			Name:   "Query",
-			Fields: queries,
-		})
-	}
-	if len(mutations) > 0 {
-		schemaConfig.Mutation = graphql.NewObject(graphql.ObjectConfig{
-			Name:   "Mutation",
-			Fields: mutations,
-		})
-	}
-
-	schema, err := graphql.NewSchema(schemaConfig)
+	req, err := ParseGraphqlRequest(r, requestParseLimitsFromContext(ctx))
 	if err != nil {
-		respondResult(w, &graphql.Result{
+		s.respondResult(ctx, w, &graphql.Result{
 			Errors: []GraphqlError{
 				{
-					Message: "Failed to build schema: " + err.Error(),
+					Message: "Failed to parse request: " + err.Error(),
 					Extensions: map[string]interface{}{
-						"code": "SCHEMA_GENERATION_ERROR",
+						"code": "REQUEST_PARSE_ERROR",
 					},
 				},
 			},
 		})
 		return
 	}
-
-	req, err := parseRequest(r)
-	if err != nil {
-		respondResult(w, &graphql.Result{
+	setRequest(ctx, req)
+	if err := s.resolvePersistedOperation(ctx, req); err != nil {
+		s.respondResult(ctx, w, &graphql.Result{
 			Errors: []GraphqlError{
 				{
-					Message: "Failed to parse request: " + err.Error(),
+					Message: err.Message,
 					Extensions: map[string]interface{}{
-						"code": "REQUEST_PARSE_ERROR",
+						"code": err.Code,
 					},
 				},
 			},
@@ -216,13 +383,22 @@ func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	result := graphql.Do(graphql.Params{
-		Schema:         schema,
-		RequestString:  req.Query,
-		VariableValues: req.Variables,
-		Context:        ctx,
-	})
+	ctx = requestExplain(ctx, r, req)
+	ctx = s.withDirectiveHandlers(ctx)
+
+	timeout, hasTimeout := operationTimeout(ctx, req.OperationName)
+	if hasTimeout {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	result := s.execute(ctx, schema, req, scoped)
 
+	if hasTimeout && ctx.Err() == context.DeadlineExceeded {
+		markDeadlineExceeded(result.Errors, timeout)
+	}
+	redactErrors(ctx, result.Errors)
 	if len(result.Errors) > 0 {
 		if s.ErrorHandler != nil {
 			s.ErrorHandler(result.Errors)
@@ -230,14 +406,128 @@ func (s *ServeMux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			defaultGraphqlErrorHandler(result.Errors)
 		}
 	}
-	respondResult(w, result)
+	s.respondResult(ctx, w, result)
 }
 
-func respondResult(w http.ResponseWriter, result *graphql.Result) {
-	out, _ := json.Marshal(result) // nolint: errcheck
+// execute parses, validates and executes graphql request against the schema.
+// Unlike graphql.Do, checks the parsed document against configured limits before executing any resolvers.
+func (s *ServeMux) execute(ctx context.Context, schema graphql.Schema, req *GraphqlRequest, scoped *scopedMiddlewares) *graphql.Result {
+	tr := tracerFromContext(ctx)
+	defer tr.finish(ctx)
 
+	if errs := checkSource(ctx, req.Query); len(errs) > 0 {
+		return &graphql.Result{
+			Errors: errs,
+		}
+	}
+
+	parsed := tr.parsing()
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{
+			Body: []byte(req.Query),
+			Name: "GraphQL request",
+		}),
+	})
+	parsed()
+	if err != nil {
+		return &graphql.Result{
+			Errors: gqlerrors.FormatErrors(err),
+		}
+	}
+	setDocumentOperation(ctx, doc)
+
+	if errs := checkDocument(ctx, doc); len(errs) > 0 {
+		return &graphql.Result{
+			Errors: errs,
+		}
+	}
+
+	if errs := checkOperation(doc, req.OperationName); len(errs) > 0 {
+		return &graphql.Result{
+			Errors: errs,
+		}
+	}
+
+	ctx, errs := authenticate(ctx, s, doc, req)
+	if len(errs) > 0 {
+		return &graphql.Result{
+			Errors: errs,
+		}
+	}
+
+	validated := tr.validation()
+	v := graphql.ValidateDocument(&schema, doc, nil)
+	validated()
+	if !v.IsValid {
+		stripSuggestions(ctx, v.Errors)
+		return &graphql.Result{
+			Errors: v.Errors,
+		}
+	}
+
+	if errs := checkComplexity(ctx, &schema, doc, req.Variables); len(errs) > 0 {
+		return &graphql.Result{
+			Errors: errs,
+		}
+	}
+
+	ctx, errs = scoped.run(ctx, s, doc, req.OperationName)
+	if len(errs) > 0 {
+		return &graphql.Result{
+			Errors: errs,
+		}
+	}
+
+	if isExplainRequested(ctx) {
+		return s.explain(ctx, &schema, doc, req)
+	}
+
+	cached, store := cachedResponse(ctx, doc, req)
+	if cached != nil {
+		return cached
+	}
+
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        schema,
+		AST:           doc,
+		OperationName: req.OperationName,
+		Args:          req.Variables,
+		Context:       detachedContext{ctx},
+	})
+	patchUnknownEnums(ctx, result)
+	if store != nil {
+		store(result)
+	}
+	return result
+}
+
+func (s *ServeMux) respondResult(ctx context.Context, w http.ResponseWriter, result *graphql.Result) {
+	finishRequest(ctx, result)
+	mergeExtensions(ctx, result)
+	s.forwardResponseHeaders(ctx, w)
+
+	// In-process execution takes the result as is
+	if rec, ok := w.(*resultRecorder); ok {
+		rec.result = result
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	w.Header().Set("Content-Length", fmt.Sprint(len(out)))
+	// Results which have values of Bytes scalar are encoded in streaming without Content-Length,
+	// otherwise they are small enough to be encoded at once
+	if !hasBytesValue(result.Data) {
+		out, err := json.Marshal(result)
+		if err != nil {
+			s.logger().Printf("failed to encode GraphQL response: %s", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprint(len(out)))
+		w.WriteHeader(http.StatusOK)
+		w.Write(out) // nolint: errcheck
+		return
+	}
 	w.WriteHeader(http.StatusOK)
-	w.Write(out) // nolint: errcheck
+	if err := writeResult(w, result); err != nil {
+		s.logger().Printf("failed to write GraphQL response: %s", err)
+	}
 }
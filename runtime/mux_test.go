@@ -0,0 +1,156 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+var testUserType = graphql.NewObject(graphql.ObjectConfig{
+	Name: "Test_Type_User",
+	Fields: graphql.Fields{
+		"id": &graphql.Field{
+			Type: graphql.String,
+		},
+		"email": &graphql.Field{
+			Type: graphql.String,
+		},
+	},
+})
+
+// testHandler is fake GraphqlHandler which does not need any gRPC connection
+type testHandler struct {
+	queries   graphql.Fields
+	mutations graphql.Fields
+}
+
+func (h *testHandler) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	return nil, func() {}, nil
+}
+
+func (h *testHandler) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	fields := graphql.Fields{}
+	for k, v := range h.queries {
+		f := *v
+		fields[k] = &f
+	}
+	return fields
+}
+
+func (h *testHandler) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	fields := graphql.Fields{}
+	for k, v := range h.mutations {
+		f := *v
+		fields[k] = &f
+	}
+	return fields
+}
+
+func newTestHandler() *testHandler {
+	return &testHandler{
+		queries: graphql.Fields{
+			"user": &graphql.Field{
+				Type: testUserType,
+				Args: graphql.FieldConfigArgument{
+					"id": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return map[string]interface{}{
+						"id":    p.Args["id"],
+						"email": "user@example.com",
+					}, nil
+				},
+			},
+			"hello": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "world", nil
+				},
+			},
+		},
+		mutations: graphql.Fields{
+			"login": &graphql.Field{
+				Type: graphql.String,
+				Args: graphql.FieldConfigArgument{
+					"password": &graphql.ArgumentConfig{
+						Type: graphql.String,
+					},
+				},
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return "token", nil
+				},
+			},
+		},
+	}
+}
+
+func serveTestQuery(t *testing.T, mux *ServeMux, query string) *graphql.Result {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query))
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	var result graphql.Result
+	if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+		t.Fatal(err)
+	}
+	return &result
+}
+
+func TestServeMuxExecutesQuery(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ hello user(id: "1") { id email } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"hello": "world",
+		"user": map[string]interface{}{
+			"id":    "1",
+			"email": "user@example.com",
+		},
+	}, result.Data)
+}
+
+func TestServeMuxConcurrentRequests(t *testing.T) {
+	// Middlewares which lock shared state are not used so that the race detector catches unsynchronized access
+	for _, build := range []bool{false, true} {
+		mux := NewServeMux()
+		assert.NoError(t, mux.AddHandler(newTestHandler()))
+		assert.NoError(t, mux.AddHandler(newGroupedTestHandler("invoices", "Invoice", "billing")))
+		var handler http.Handler = mux
+		if build {
+			var err error
+			handler, err = mux.Build()
+			if !assert.NoError(t, err) {
+				return
+			}
+		}
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(fmt.Sprintf(`{ hello user(id: "%d") { id } billing { invoices } }`, i%3)))
+				r.Header.Set("Grpc-Metadata-X-Request-Id", fmt.Sprint(i))
+				w := httptest.NewRecorder()
+				handler.ServeHTTP(w, r)
+				assert.Contains(t, w.Body.String(), `"hello":"world"`)
+			}(i)
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,128 @@
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/iancoleman/strcase"
+	"google.golang.org/grpc"
+)
+
+// namespaceHandler groups root fields of the wrapped handler under a nested root field like "query { users { get } }"
+type namespaceHandler struct {
+	GraphqlHandler
+	namespace string
+	query     *graphql.Object
+	mutation  *graphql.Object
+}
+
+// Type names of namespace objects which group mutations, in order to treat their fields as mutations like audit hook
+var namespaceMutations sync.Map
+
+// isMutationType reports whether fields of the type are mutation operations
+func isMutationType(t graphql.Composite) bool {
+	if t.Name() == "Mutation" {
+		return true
+	}
+	_, ok := namespaceMutations.Load(t.Name())
+	return ok
+}
+
+// isNamespaceMutationField reports whether the field returns namespace object which groups mutations
+func isNamespaceMutationField(t graphql.Output) bool {
+	if nn, ok := t.(*graphql.NonNull); ok {
+		t = nn.OfType
+	}
+	obj, ok := t.(*graphql.Object)
+	if !ok {
+		return false
+	}
+	_, ok = namespaceMutations.Load(obj.Name())
+	return ok
+}
+
+// AddNamespacedHandler registers graphql handler whose queries and mutations are grouped under the namespace field,
+// so that operations of multiple services don't collide in the flat root namespace.
+func (s *ServeMux) AddNamespacedHandler(namespace string, h GraphqlHandler) error {
+	// Namespace objects are built once, so they can't follow the schema which is swapped at runtime
+	if _, ok := h.(schemaVersioner); ok {
+		return errors.New("handler whose schema is reloaded at runtime can't be namespaced")
+	}
+	return s.AddHandler(newNamespaceHandler(namespace, h))
+}
+
+// namespaceOf returns default namespace of the handler which is lower camel cased service name without package
+func namespaceOf(h GraphqlHandler) string {
+	name := handlerName(h)
+	if i := strings.LastIndexAny(name, "."); i >= 0 {
+		name = name[i+1:]
+	}
+	return strcase.ToLowerCamel(graphqlName(name))
+}
+
+func newNamespaceHandler(namespace string, h GraphqlHandler) *namespaceHandler {
+	typeName := strcase.ToCamel(namespace)
+	namespaceMutations.Store(typeName+"Mutation", struct{}{})
+	return &namespaceHandler{
+		GraphqlHandler: h,
+		namespace:      namespace,
+		query:          newNamespaceObject(typeName+"Query", h.GetQueries(nil)),
+		mutation:       newNamespaceObject(typeName+"Mutation", h.GetMutations(nil)),
+	}
+}
+
+// newNamespaceObject creates object type which is shared between requests.
+// Field resolvers delegate to the request scoped fields which are passed as the source from the namespace field,
+// because generated fields capture gRPC connection of the request.
+func newNamespaceObject(name string, fields graphql.Fields) *graphql.Object {
+	if len(fields) == 0 {
+		return nil
+	}
+	delegated := graphql.Fields{}
+	for fieldName, f := range fields {
+		fieldName := fieldName
+		delegated[fieldName] = &graphql.Field{
+			Type:              f.Type,
+			Args:              f.Args,
+			Description:       f.Description,
+			DeprecationReason: f.DeprecationReason,
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				scoped, ok := p.Source.(graphql.Fields)
+				if !ok || scoped[fieldName] == nil || scoped[fieldName].Resolve == nil {
+					return nil, nil
+				}
+				// Behave as root field
+				p.Source = nil
+				return scoped[fieldName].Resolve(p)
+			},
+		}
+	}
+	return graphql.NewObject(graphql.ObjectConfig{
+		Name:   name,
+		Fields: delegated,
+	})
+}
+
+func (h *namespaceHandler) namespaceField(object *graphql.Object, fields graphql.Fields) graphql.Fields {
+	if object == nil {
+		return graphql.Fields{}
+	}
+	return graphql.Fields{
+		h.namespace: &graphql.Field{
+			Type: graphql.NewNonNull(object),
+			Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+				return fields, nil
+			},
+		},
+	}
+}
+
+func (h *namespaceHandler) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	return h.namespaceField(h.query, h.GraphqlHandler.GetQueries(conn))
+}
+
+func (h *namespaceHandler) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	return h.namespaceField(h.mutation, h.GraphqlHandler.GetMutations(conn))
+}
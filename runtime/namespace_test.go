@@ -0,0 +1,56 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddNamespacedHandler(t *testing.T) {
+	var audited []string
+	mux := NewServeMux(WithAuditHook(AuditConfig{
+		Hook: func(ctx context.Context, entry AuditEntry) {
+			audited = append(audited, entry.Field)
+		},
+	}))
+	assert.NoError(t, mux.AddNamespacedHandler("users", newTestHandler()))
+	assert.NoError(t, mux.AddNamespacedHandler("accounts", newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ users { hello user(id: "1") { id } } accounts { hello } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"users": map[string]interface{}{
+			"hello": "world",
+			"user": map[string]interface{}{
+				"id": "1",
+			},
+		},
+		"accounts": map[string]interface{}{
+			"hello": "world",
+		},
+	}, result.Data)
+
+	result = serveTestQuery(t, mux, `mutation { accounts { login(password: "x") } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"accounts": map[string]interface{}{
+			"login": "token",
+		},
+	}, result.Data)
+	assert.Equal(t, []string{"login"}, audited)
+}
+
+func TestNamespaceByService(t *testing.T) {
+	mux := NewServeMux()
+	mux.NamespaceByService = true
+	assert.NoError(t, mux.AddHandler(&namedTestHandler{newTestHandler(), "users.UserService"}))
+
+	result := serveTestQuery(t, mux, `{ userService { hello } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"userService": map[string]interface{}{
+			"hello": "world",
+		},
+	}, result.Data)
+}
@@ -0,0 +1,185 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Members of proto oneofs are fields of input objects, and inputs of messages which consist of a single oneof
+// are @oneOf input objects, which accept exactly one of the members. Other inputs, and root field arguments
+// which are fields of the request message, accept at most one member of each oneof because proto messages can't hold more.
+
+var oneofInputs = struct {
+	sync.RWMutex
+	names map[string]struct{}
+}{
+	names: make(map[string]struct{}),
+}
+
+// SetOneofInput declares the input object as @oneOf input, which is printed in SDL.
+// This function is called from generated code for inputs of messages which consist of a single oneof.
+func SetOneofInput(typeName string) {
+	oneofInputs.Lock()
+	defer oneofInputs.Unlock()
+	oneofInputs.names[typeName] = struct{}{}
+}
+
+func isOneofInputName(typeName string) bool {
+	oneofInputs.RLock()
+	defer oneofInputs.RUnlock()
+	_, ok := oneofInputs.names[typeName]
+	return ok
+}
+
+// isOneofMessage returns true if all fields of the message are members of a single oneof
+func isOneofMessage(md protoreflect.MessageDescriptor) bool {
+	if md.Fields().Len() == 0 {
+		return false
+	}
+	od := md.Fields().Get(0).ContainingOneof()
+	if od == nil || isSyntheticOneof(od) {
+		return false
+	}
+	return od.Fields().Len() == md.Fields().Len()
+}
+
+// isSyntheticOneof returns true if the oneof wraps proto3 optional field, which is named "_<field>" by protoc
+func isSyntheticOneof(od protoreflect.OneofDescriptor) bool {
+	return od.Fields().Len() == 1 && strings.HasPrefix(string(od.Name()), "_")
+}
+
+// checkOneofs validates that GraphQL values of the message provide at most one member of each oneof,
+// and exactly one member if the values are input object of @oneOf input. Values are checked recursively into message fields,
+// which are input objects.
+func checkOneofs(md protoreflect.MessageDescriptor, values map[string]interface{}, name fieldNamer, input bool) error {
+	oneofs := md.Oneofs()
+	for i := 0; i < oneofs.Len(); i++ {
+		od := oneofs.Get(i)
+		if isSyntheticOneof(od) {
+			continue
+		}
+		var provided, members []string
+		for j := 0; j < od.Fields().Len(); j++ {
+			n := name(od.Fields().Get(j))
+			members = append(members, n)
+			if v, ok := values[n]; ok && v != nil {
+				provided = append(provided, n)
+			}
+		}
+		if len(provided) > 1 {
+			return fmt.Errorf("oneof %s accepts only one of %s, got %s",
+				od.Name(), strings.Join(members, ", "), strings.Join(provided, ", "))
+		}
+		if len(provided) == 0 && input && isOneofMessage(md) {
+			return fmt.Errorf("@oneOf input %s requires exactly one of %s", md.Name(), strings.Join(members, ", "))
+		}
+	}
+
+	fields := md.Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if v, ok := values[name(fd)]; ok && v != nil {
+			if err := checkOneofValue(fd, v, name); err != nil {
+				return fmt.Errorf("field %s: %w", fd.Name(), err)
+			}
+		}
+	}
+	return nil
+}
+
+func checkOneofValue(fd protoreflect.FieldDescriptor, v interface{}, name fieldNamer) error {
+	switch {
+	case fd.IsMap():
+		if fd.MapValue().Message() == nil {
+			return nil
+		}
+		entries, _ := v.([]interface{})
+		for _, e := range entries {
+			entry, _ := e.(map[string]interface{})
+			if value, ok := entry["value"].(map[string]interface{}); ok {
+				if err := checkOneofs(fd.MapValue().Message(), value, name, true); err != nil {
+					return err
+				}
+			}
+		}
+	case fd.Message() == nil:
+		return nil
+	case fd.IsList():
+		items, _ := v.([]interface{})
+		for _, item := range items {
+			if value, ok := item.(map[string]interface{}); ok {
+				if err := checkOneofs(fd.Message(), value, name, true); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		if value, ok := v.(map[string]interface{}); ok {
+			return checkOneofs(fd.Message(), value, name, true)
+		}
+	}
+	return nil
+}
+
+// setOneofMembers sets members of oneofs to the generated message which is unmarshaled from JSON of the values,
+// because oneofs are interface fields of generated structs which encoding/json can't unmarshal.
+// Values are keyed by proto field names, and maps are objects like MarshalRequest converts.
+func setOneofMembers(msg protoreflect.Message, values map[string]interface{}) error {
+	fields := msg.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		v, ok := values[string(fd.Name())]
+		if !ok || v == nil {
+			continue
+		}
+		if err := setOneofField(msg, fd, v); err != nil {
+			return fmt.Errorf("field %s: %w", fd.Name(), err)
+		}
+	}
+	return nil
+}
+
+func setOneofField(msg protoreflect.Message, fd protoreflect.FieldDescriptor, v interface{}) error {
+	if od := fd.ContainingOneof(); od != nil && !isSyntheticOneof(od) {
+		return setField(msg, fd, v, protoFieldName)
+	}
+	switch {
+	case fd.IsMap():
+		object, ok := v.(map[string]interface{})
+		if !ok || fd.MapValue().Message() == nil {
+			return nil
+		}
+		var err error
+		msg.Mutable(fd).Map().Range(func(k protoreflect.MapKey, mv protoreflect.Value) bool {
+			if value, ok := object[k.String()].(map[string]interface{}); ok {
+				err = setOneofMembers(mv.Message(), value)
+			}
+			return err == nil
+		})
+		return err
+	case fd.Message() == nil:
+		return nil
+	case fd.IsList():
+		items, _ := v.([]interface{})
+		list := msg.Mutable(fd).List()
+		for i := 0; i < list.Len() && i < len(items); i++ {
+			if value, ok := items[i].(map[string]interface{}); ok {
+				if err := setOneofMembers(list.Get(i).Message(), value); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		if value, ok := v.(map[string]interface{}); ok {
+			return setOneofMembers(msg.Mutable(fd).Message(), value)
+		}
+	}
+	return nil
+}
+
+func protoFieldName(fd protoreflect.FieldDescriptor) string {
+	return string(fd.Name())
+}
@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestMarshalRequestOneofs(t *testing.T) {
+	for _, isCamel := range []bool{false, true} {
+		name := func(s string) string {
+			if isCamel {
+				return strings.Replace(s, "_value", "Value", 1)
+			}
+			return s
+		}
+
+		var v structpb.Value
+		assert.NoError(t, MarshalRequest(map[string]interface{}{name("string_value"): "Go"}, &v, isCamel))
+		assert.Equal(t, "Go", v.GetStringValue())
+
+		// Members are set in nested messages, lists and maps
+		var list structpb.ListValue
+		assert.NoError(t, MarshalRequest(map[string]interface{}{
+			"values": []interface{}{
+				map[string]interface{}{name("number_value"): 1.5},
+				map[string]interface{}{name("bool_value"): true},
+			},
+		}, &list, isCamel))
+		if assert.Len(t, list.GetValues(), 2) {
+			assert.Equal(t, 1.5, list.GetValues()[0].GetNumberValue())
+			assert.True(t, list.GetValues()[1].GetBoolValue())
+		}
+		var s structpb.Struct
+		assert.NoError(t, MarshalRequest(map[string]interface{}{
+			"fields": []interface{}{
+				map[string]interface{}{"key": "name", "value": map[string]interface{}{name("string_value"): "Gopher"}},
+			},
+		}, &s, isCamel))
+		assert.Equal(t, "Gopher", s.GetFields()["name"].GetStringValue())
+	}
+
+	// Oneof accepts only one member
+	var v structpb.Value
+	assert.Error(t, MarshalRequest(map[string]interface{}{"string_value": "Go", "bool_value": true}, &v, false))
+	var list structpb.ListValue
+	assert.Error(t, MarshalRequest(map[string]interface{}{
+		"values": []interface{}{
+			map[string]interface{}{"string_value": "Go", "bool_value": true},
+		},
+	}, &list, false))
+
+	// @oneOf input requires exactly one member, while root arguments may omit all members
+	assert.NoError(t, MarshalRequest(map[string]interface{}{}, &v, false))
+	assert.Error(t, MarshalInput(map[string]interface{}{}, &v, false))
+	assert.NoError(t, MarshalInput(map[string]interface{}{"bool_value": false}, &v, false))
+	assert.False(t, v.GetBoolValue())
+	assert.IsType(t, &structpb.Value_BoolValue{}, v.GetKind())
+	assert.Error(t, MarshalRequest(map[string]interface{}{
+		"values": []interface{}{map[string]interface{}{}},
+	}, &list, false))
+}
+
+const testOneofFile = `
+name: "oneof.proto"
+package: "catalog.v1"
+syntax: "proto3"
+message_type {
+  name: "Key"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" oneof_index: 0 }
+  field { name: "isbn" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "isbn" oneof_index: 0 }
+  oneof_decl { name: "key" }
+}
+message_type {
+  name: "FindRequest"
+  field { name: "key" number: 1 type: TYPE_MESSAGE type_name: ".catalog.v1.Key" label: LABEL_OPTIONAL json_name: "key" }
+  field { name: "title" number: 2 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "title" oneof_index: 0 }
+  field { name: "year" number: 3 type: TYPE_INT32 label: LABEL_OPTIONAL json_name: "year" oneof_index: 0 }
+  field { name: "note" number: 4 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" oneof_index: 1 }
+  oneof_decl { name: "filter" }
+  oneof_decl { name: "_note" }
+}
+service {
+  name: "CatalogService"
+  method { name: "Find" input_type: ".catalog.v1.FindRequest" output_type: ".catalog.v1.Key" }
+}
+`
+
+func TestDynamicOneofInputs(t *testing.T) {
+	file := testDynamicFile(t, "oneof.proto", testOneofFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	schema, err := newSchema(handlers[0].GetQueries(nil), graphql.Fields{})
+	if !assert.NoError(t, err) {
+		return
+	}
+	sdl := PrintSchema(&schema)
+	assert.Contains(t, sdl, "input CatalogV1_Input_Key @oneOf {")
+	assert.NotContains(t, sdl, "input CatalogV1_Input_FindRequest @oneOf")
+
+	request := file.Messages().ByName("FindRequest")
+	tests := []struct {
+		args  map[string]interface{}
+		valid bool
+	}{
+		{args: map[string]interface{}{}, valid: true},
+		{args: map[string]interface{}{"title": "Go", "note": "new"}, valid: true},
+		{args: map[string]interface{}{"key": map[string]interface{}{"isbn": "978"}, "year": 2015}, valid: true},
+		{args: map[string]interface{}{"title": "Go", "year": 2015}},
+		{args: map[string]interface{}{"key": map[string]interface{}{}}},
+		{args: map[string]interface{}{"key": map[string]interface{}{"id": "1", "isbn": "978"}}},
+	}
+	for _, tt := range tests {
+		err := checkOneofs(request, tt.args, dynamicFieldName, false)
+		if tt.valid {
+			assert.NoError(t, err, tt.args)
+		} else {
+			assert.Error(t, err, tt.args)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package runtime
+
+import (
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// checkOperation checks that the document has the operation to execute.
+// The document may contain multiple operations which clients bundle, then operationName selects the one to execute
+// as GraphQL specification describes in GetOperation().
+func checkOperation(doc *ast.Document, operationName string) []GraphqlError {
+	var count int
+	for _, d := range doc.Definitions {
+		op, ok := d.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		count++
+		if operationName != "" && op.Name != nil && op.Name.Value == operationName {
+			return nil
+		}
+	}
+
+	switch {
+	case operationName != "":
+		return operationError(`Unknown operation named "`+operationName+`".`, "OPERATION_NOT_FOUND")
+	case count == 0:
+		return operationError("Must provide an operation.", "OPERATION_NOT_FOUND")
+	case count > 1:
+		return operationError("Must provide operation name if query contains multiple operations.", "OPERATION_NAME_REQUIRED")
+	default:
+		return nil
+	}
+}
+
+func operationError(message, code string) []GraphqlError {
+	return []GraphqlError{
+		{
+			Message: message,
+			Extensions: map[string]interface{}{
+				"code": code,
+			},
+		},
+	}
+}
@@ -0,0 +1,138 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sync"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// OperationFilter rejects operations by operation name.
+// Patterns are regular expressions which must match the whole operation name,
+// and they can be replaced at runtime e.g. to kill-switch an abusive operation without redeploying clients.
+// Anonymous operations have empty name, so they are rejected if allow list is set.
+type OperationFilter struct {
+	mu    sync.RWMutex
+	allow []*regexp.Regexp
+	deny  []*regexp.Regexp
+}
+
+// NewOperationFilter creates OperationFilter which allows all operations
+func NewOperationFilter() *OperationFilter {
+	return &OperationFilter{}
+}
+
+// Deny replaces deny list, the operation whose name matches any of patterns is rejected
+func (f *OperationFilter) Deny(patterns ...string) error {
+	deny, err := compileOperationPatterns(patterns)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.deny = deny
+	return nil
+}
+
+// Allow replaces allow list, the operation whose name doesn't match any of patterns is rejected.
+// Empty patterns disables allow list.
+func (f *OperationFilter) Allow(patterns ...string) error {
+	allow, err := compileOperationPatterns(patterns)
+	if err != nil {
+		return err
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.allow = allow
+	return nil
+}
+
+// check returns error code and message if the operation name is rejected
+func (f *OperationFilter) check(name string) (string, string) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	for _, p := range f.deny {
+		if p.MatchString(name) {
+			return "OPERATION_BLOCKED", fmt.Sprintf("operation %q is blocked", name)
+		}
+	}
+	if len(f.allow) == 0 {
+		return "", ""
+	}
+	for _, p := range f.allow {
+		if p.MatchString(name) {
+			return "", ""
+		}
+	}
+	return "OPERATION_NOT_ALLOWED", fmt.Sprintf("operation %q is not allowed", name)
+}
+
+func compileOperationPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid operation name pattern %q: %w", p, err)
+		}
+		compiled[i] = re
+	}
+	return compiled, nil
+}
+
+type operationFilterKey struct{}
+
+// WithOperationFilter is middleware function to reject operations by the filter before execution.
+// Rejected operations are counted in rejected_operations_total metric if WithMetrics is used.
+func WithOperationFilter(filter *OperationFilter) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, operationFilterKey{}, filter), nil
+	}
+}
+
+// checkOperationFilter checks the operation which is selected by operationName,
+// or all operations in the document if operationName is not specified
+func checkOperationFilter(ctx context.Context, doc *ast.Document) []GraphqlError {
+	filter, ok := ctx.Value(operationFilterKey{}).(*OperationFilter)
+	if !ok {
+		return nil
+	}
+
+	var names []string
+	if name := OperationNameFromContext(ctx); name != "" {
+		names = append(names, name)
+	} else {
+		for _, d := range doc.Definitions {
+			op, ok := d.(*ast.OperationDefinition)
+			if !ok {
+				continue
+			}
+			var name string
+			if op.Name != nil {
+				name = op.Name.Value
+			}
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		code, message := filter.check(name)
+		if code == "" {
+			continue
+		}
+		metricsFromContext(ctx).reject("operation_filter")
+		return []GraphqlError{
+			{
+				Message: message,
+				Extensions: map[string]interface{}{
+					"code": code,
+				},
+			},
+		}
+	}
+	return nil
+}
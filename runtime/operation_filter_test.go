@@ -0,0 +1,82 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithOperationFilter(t *testing.T) {
+	filter := NewOperationFilter()
+	metrics := NewMetrics()
+	mux := NewServeMux(WithMetrics(metrics), WithOperationFilter(filter))
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	serve := func(req GraphqlRequest) *graphql.Result {
+		body, err := json.Marshal(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+		r.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var result graphql.Result
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		return &result
+	}
+	assertCode := func(t *testing.T, result *graphql.Result, code string) {
+		if code == "" {
+			assert.Len(t, result.Errors, 0)
+			return
+		}
+		assert.Nil(t, result.Data)
+		if assert.Len(t, result.Errors, 1) {
+			assert.Equal(t, code, result.Errors[0].Extensions["code"])
+		}
+	}
+
+	t.Run("allow all by default", func(t *testing.T) {
+		assertCode(t, serve(GraphqlRequest{Query: `query Hello { hello }`}), "")
+		assertCode(t, serve(GraphqlRequest{Query: `{ hello }`}), "")
+	})
+
+	t.Run("deny list", func(t *testing.T) {
+		assert.NoError(t, filter.Deny("Expensive.*"))
+		defer filter.Deny() // nolint: errcheck
+
+		assertCode(t, serve(GraphqlRequest{Query: `query ExpensiveSearch { hello }`}), "OPERATION_BLOCKED")
+		assertCode(t, serve(GraphqlRequest{Query: `query NotExpensive { hello }`}), "")
+		assertCode(t, serve(GraphqlRequest{
+			Query:         `query Hello { hello } query ExpensiveSearch { hello }`,
+			OperationName: "ExpensiveSearch",
+		}), "OPERATION_BLOCKED")
+	})
+
+	t.Run("allow list", func(t *testing.T) {
+		assert.NoError(t, filter.Allow("Hello", "Login"))
+		defer filter.Allow() // nolint: errcheck
+
+		assertCode(t, serve(GraphqlRequest{Query: `query Hello { hello }`}), "")
+		assertCode(t, serve(GraphqlRequest{Query: `query HelloWorld { hello }`}), "OPERATION_NOT_ALLOWED")
+		assertCode(t, serve(GraphqlRequest{Query: `{ hello }`}), "OPERATION_NOT_ALLOWED")
+	})
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		assert.Error(t, filter.Deny("("))
+	})
+
+	var b strings.Builder
+	if _, err := metrics.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	assert.Contains(t, b.String(), `graphql_rejected_operations_total{reason="operation_filter"} 4`)
+}
@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMuxSelectsOperation(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	document := `query Hello { hello } query User { user(id: \"1\") { id } }`
+
+	result := serveTestQuery(t, mux, `{"query":"`+document+`","operationName":"User"}`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"user": map[string]interface{}{"id": "1"},
+	}, result.Data)
+
+	result = serveTestQuery(t, mux, `{"query":"`+document+`","operationName":"Hello"}`)
+	assert.Len(t, result.Errors, 0)
+	assert.Contains(t, result.Data, "hello")
+	assert.NotContains(t, result.Data, "user")
+
+	// Operation name selects the operation of single operation document as well
+	result = serveTestQuery(t, mux, `{"query":"query Hello { hello }","operationName":"Hello"}`)
+	assert.Len(t, result.Errors, 0)
+
+	for body, expected := range map[string][2]string{
+		`{"query":"` + document + `"}`: {
+			"OPERATION_NAME_REQUIRED", "Must provide operation name if query contains multiple operations.",
+		},
+		`{"query":"` + document + `","operationName":"Unknown"}`: {
+			"OPERATION_NOT_FOUND", `Unknown operation named "Unknown".`,
+		},
+		`{"query":"query Hello { hello }","operationName":"Unknown"}`: {
+			"OPERATION_NOT_FOUND", `Unknown operation named "Unknown".`,
+		},
+		`{"query":"fragment Unused on Query { hello }"}`: {
+			"OPERATION_NOT_FOUND", "Must provide an operation.",
+		},
+	} {
+		result = serveTestQuery(t, mux, body)
+		assert.Nil(t, result.Data, body)
+		if assert.Len(t, result.Errors, 1, body) {
+			assert.Equal(t, expected[0], result.Errors[0].Extensions["code"], body)
+			assert.Equal(t, expected[1], result.Errors[0].Message, body)
+		}
+	}
+}
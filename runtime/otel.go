@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+// Instrumentation name which is passed to TracerProvider
+const otelTracerName = "github.com/ysugimoto/grpc-graphql-gateway/runtime"
+
+type otelTracerKey struct{}
+
+// WithTracerProvider is middleware function to trace requests with OpenTelemetry.
+// It starts server span for each HTTP request which continues W3C traceparent of incoming request,
+// starts child span for each field resolver which calls gRPC, and propagates traceparent to gRPC metadata.
+// Spans are tagged with operation label, see OperationLabelFromContext.
+// Use WithTracePropagator before this middleware to change propagation format.
+func WithTracerProvider(tp trace.TracerProvider) MiddlewareFunc {
+	tracer := tp.Tracer(otelTracerName)
+
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		ctx = tracePropagatorFromContext(ctx).Extract(ctx, propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, "graphql.request",
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.method", r.Method),
+				attribute.String("http.target", r.URL.Path),
+			),
+		)
+		onFinish(ctx, func(result *graphql.Result) {
+			if label := OperationLabelFromContext(ctx); label != "" {
+				span.SetName("graphql.request " + label)
+				span.SetAttributes(attribute.String("graphql.operation.name", label))
+			}
+			if len(result.Errors) > 0 {
+				span.SetStatus(codes.Error, result.Errors[0].Message)
+			}
+			span.End()
+		})
+		return context.WithValue(ctx, otelTracerKey{}, tracer), nil
+	}
+}
+
+// startResolverSpan starts child span for the field resolver and returns context which should be passed to the resolver,
+// and function to end the span. If tracer provider is not configured, returns the context as it is.
+func startResolverSpan(ctx context.Context, info graphql.ResolveInfo) (context.Context, func(error)) {
+	tracer, ok := ctx.Value(otelTracerKey{}).(trace.Tracer)
+	if !ok {
+		return ctx, func(error) {}
+	}
+
+	path := make([]string, 0)
+	for _, p := range info.Path.AsArray() {
+		path = append(path, fmt.Sprint(p))
+	}
+	ctx, span := tracer.Start(ctx, "graphql.resolve "+info.ParentType.Name()+"."+info.FieldName,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("graphql.parent_type", info.ParentType.Name()),
+			attribute.String("graphql.field.name", info.FieldName),
+			attribute.String("graphql.field.path", strings.Join(path, ".")),
+		),
+	)
+	if label := OperationLabelFromContext(ctx); label != "" {
+		span.SetAttributes(attribute.String("graphql.operation.name", label))
+	}
+
+	md, _ := metadata.FromOutgoingContext(ctx)
+	md = md.Copy()
+	tracePropagatorFromContext(ctx).Inject(ctx, metadataCarrier(md))
+	ctx = metadata.NewOutgoingContext(ctx, md)
+
+	return ctx, func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// metadataCarrier adapts gRPC metadata to propagation.TextMapCarrier
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if v := metadata.MD(c).Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) {
+	metadata.MD(c).Set(key, value)
+}
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for k := range c {
+		keys = append(keys, k)
+	}
+	return keys
+}
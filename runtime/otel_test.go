@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/metadata"
+)
+
+type testSpan struct {
+	trace.Span
+	name   string
+	sc     trace.SpanContext
+	parent trace.SpanContext
+	ended  bool
+}
+
+func (s *testSpan) SpanContext() trace.SpanContext { return s.sc }
+func (s *testSpan) SetName(name string)            { s.name = name }
+func (s *testSpan) End(...trace.SpanEndOption)     { s.ended = true }
+
+// testTracerProvider records started spans
+type testTracerProvider struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (tp *testTracerProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return tp
+}
+
+func (tp *testTracerProvider) Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	tp.mu.Lock()
+	defer tp.mu.Unlock()
+
+	parent := trace.SpanContextFromContext(ctx)
+	traceID := parent.TraceID()
+	if !traceID.IsValid() {
+		traceID = trace.TraceID{0x01}
+	}
+	_, noop := trace.NewNoopTracerProvider().Tracer("").Start(ctx, name)
+	span := &testSpan{
+		Span:   noop,
+		name:   name,
+		parent: parent,
+		sc: trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    traceID,
+			SpanID:     trace.SpanID{byte(len(tp.spans) + 1)},
+			TraceFlags: trace.FlagsSampled,
+		}),
+	}
+	tp.spans = append(tp.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	var traceparent string
+	h := newTestHandler()
+	h.queries["traced"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			md, _ := metadata.FromOutgoingContext(p.Context)
+			if v := md.Get("traceparent"); len(v) > 0 {
+				traceparent = v[0]
+			}
+			return "ok", nil
+		},
+	}
+	tp := &testTracerProvider{}
+	mux := NewServeMux(WithTracerProvider(tp))
+	assert.NoError(t, mux.AddHandler(h))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"query":"query Traced { traced }","operationName":"Traced"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	mux.ServeHTTP(httptest.NewRecorder(), r)
+
+	if !assert.Len(t, tp.spans, 2) {
+		t.FailNow()
+	}
+	server, resolver := tp.spans[0], tp.spans[1]
+
+	// Server span continues incoming trace
+	assert.Equal(t, "graphql.request Traced", server.name)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", server.parent.TraceID().String())
+	assert.True(t, server.ended)
+
+	// Resolver span is a child of server span and propagated to gRPC metadata
+	assert.Equal(t, "graphql.resolve Query.traced", resolver.name)
+	assert.Equal(t, server.sc.SpanID(), resolver.parent.SpanID())
+	assert.True(t, resolver.ended)
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-"+resolver.sc.SpanID().String()+"-01", traceparent)
+}
@@ -0,0 +1,213 @@
+package runtime
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"net/http"
+)
+
+// PersistedOperationStore looks up operation documents which are registered in advance, like allowlist built from client code
+type PersistedOperationStore interface {
+	// Lookup returns the operation document of the ID, ok is false if the ID is not registered
+	Lookup(ctx context.Context, id string) (query string, ok bool, err error)
+}
+
+// PersistedOperations is in-memory PersistedOperationStore which maps operation ID to the document
+type PersistedOperations map[string]string
+
+// NewPersistedOperations creates PersistedOperations of the documents keyed by SHA-256 hash of them,
+// which is the same ID as Apollo automatic persisted queries
+func NewPersistedOperations(queries ...string) PersistedOperations {
+	p := PersistedOperations{}
+	for _, q := range queries {
+		p[persistedOperationHash(q)] = q
+	}
+	return p
+}
+
+// Lookup implements PersistedOperationStore
+func (p PersistedOperations) Lookup(ctx context.Context, id string) (string, bool, error) {
+	q, ok := p[id]
+	return q, ok, nil
+}
+
+// PersistedOperationsConfig is configuration for WithPersistedOperations middleware
+type PersistedOperationsConfig struct {
+	// Store of persisted operations, required
+	Store PersistedOperationStore
+	// If true, free-form queries which are not in the store are rejected
+	Enforce bool
+	// Client IDs which are allowed to send free-form queries even if Enforce is true, like internal tools
+	ExemptClients []string
+	// ClientID extracts client ID from the request, default is Apollographql-Client-Name header.
+	// Use authenticated identity instead of the header which any client can send if exemption matters.
+	ClientID func(r *http.Request) string
+	// Keys of HMAC-SHA256 signature which operation IDs must carry, see SignPersistedOperation.
+	// Multiple keys are accepted in order to rotate them. If empty, IDs are not signed.
+	SigningKeys [][]byte
+}
+
+type persistedOperationsKey struct{}
+
+type persistedOperations struct {
+	store       PersistedOperationStore
+	enforce     bool
+	signingKeys [][]byte
+}
+
+// SignPersistedOperation returns signed ID of the operation like "<id>.<signature>" which trusted build pipelines issue to clients.
+// The signature covers the document as well as the ID, so that the gateway executes only documents which are signed
+// even if the store is compromised.
+func SignPersistedOperation(key []byte, id, query string) string {
+	return id + "." + hex.EncodeToString(persistedOperationSignature(key, id, query))
+}
+
+func persistedOperationSignature(key []byte, id, query string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + ":" + persistedOperationHash(query))) // nolint: errcheck
+	return mac.Sum(nil)
+}
+
+// verifyPersistedOperation reports whether the signature is made by any of keys for the operation
+func verifyPersistedOperation(keys [][]byte, id, signature, query string) bool {
+	sig, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	for _, key := range keys {
+		if hmac.Equal(sig, persistedOperationSignature(key, id, query)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithPersistedOperations is middleware function to resolve operations which are sent by ID,
+// as "id" field or Apollo "extensions.persistedQuery.sha256Hash", from the store.
+// If Enforce is true, free-form queries are rejected unless the document is in the store or the client is exempted.
+// If SigningKeys are set, IDs must be signed by SignPersistedOperation, and free-form queries are not looked up in the store.
+// Rejected operations are counted in rejected_operations_total metric if WithMetrics is used.
+func WithPersistedOperations(config PersistedOperationsConfig) MiddlewareFunc {
+	if config.ClientID == nil {
+		config.ClientID = func(r *http.Request) string {
+			return r.Header.Get("Apollographql-Client-Name")
+		}
+	}
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		enforce := config.Enforce
+		if enforce && len(config.ExemptClients) > 0 {
+			if id := config.ClientID(r); id != "" && containsString(config.ExemptClients, id) {
+				enforce = false
+			}
+		}
+		return context.WithValue(ctx, persistedOperationsKey{}, persistedOperations{
+			store:       config.Store,
+			enforce:     enforce,
+			signingKeys: config.SigningKeys,
+		}), nil
+	}
+}
+
+// resolvePersistedOperation fills the query of the request which is sent by ID,
+// and rejects free-form query if persisted operations are enforced.
+// Errors of the store are logged and not exposed to the client.
+func (s *ServeMux) resolvePersistedOperation(ctx context.Context, req *GraphqlRequest) *MiddlewareError {
+	p, ok := ctx.Value(persistedOperationsKey{}).(persistedOperations)
+	if !ok {
+		return nil
+	}
+
+	id := persistedOperationID(req)
+	if id == "" {
+		if !p.enforce {
+			return nil
+		}
+		// Free-form query is allowed if the same document is persisted, unless the store is not trusted without signature
+		if len(p.signingKeys) > 0 {
+			metricsFromContext(ctx).reject("persisted_query_required")
+			return NewMiddlewareError("PERSISTED_QUERY_REQUIRED", "only persisted operations are allowed")
+		}
+		persisted, merr := s.lookupPersistedDocument(ctx, p.store, req.Query)
+		if merr != nil {
+			return merr
+		}
+		if persisted {
+			return nil
+		}
+		metricsFromContext(ctx).reject("persisted_query_required")
+		return NewMiddlewareError("PERSISTED_QUERY_REQUIRED", "only persisted operations are allowed")
+	}
+
+	var signature string
+	if len(p.signingKeys) > 0 {
+		i := strings.LastIndex(id, ".")
+		if i < 0 {
+			metricsFromContext(ctx).reject("persisted_query_signature_invalid")
+			return NewMiddlewareError("PERSISTED_QUERY_SIGNATURE_INVALID", "persisted operation ID is not signed")
+		}
+		id, signature = id[:i], id[i+1:]
+	}
+
+	query, ok, err := p.store.Lookup(ctx, id)
+	if err != nil {
+		s.logger().Printf("failed to look up persisted operation %q: %s", id, err)
+		return NewMiddlewareError("PERSISTED_QUERY_ERROR", "failed to look up persisted operation")
+	}
+	if !ok {
+		metricsFromContext(ctx).reject("persisted_query_not_found")
+		return NewMiddlewareError("PERSISTED_QUERY_NOT_FOUND", "persisted operation is not found")
+	}
+	if len(p.signingKeys) > 0 && !verifyPersistedOperation(p.signingKeys, id, signature, query) {
+		metricsFromContext(ctx).reject("persisted_query_signature_invalid")
+		return NewMiddlewareError("PERSISTED_QUERY_SIGNATURE_INVALID", "signature of persisted operation is invalid")
+	}
+	req.Query = query
+	return nil
+}
+
+// isPersistedDocument reports whether the document of the request is registered in the store of WithPersistedOperations middleware
+func (s *ServeMux) isPersistedDocument(ctx context.Context, req *GraphqlRequest) (bool, *MiddlewareError) {
+	p, ok := ctx.Value(persistedOperationsKey{}).(persistedOperations)
+	if !ok || req.Query == "" {
+		return false, nil
+	}
+	// The document of the request which is sent by ID has been resolved from the store
+	if persistedOperationID(req) != "" {
+		return true, nil
+	}
+	return s.lookupPersistedDocument(ctx, p.store, req.Query)
+}
+
+// lookupPersistedDocument reports whether the free-form query is registered in the store by its hash.
+// Errors of the store are logged and not exposed to the client.
+func (s *ServeMux) lookupPersistedDocument(ctx context.Context, store PersistedOperationStore, query string) (bool, *MiddlewareError) {
+	id := persistedOperationHash(query)
+	_, ok, err := store.Lookup(ctx, id)
+	if err != nil {
+		s.logger().Printf("failed to look up persisted operation %q: %s", id, err)
+		return false, NewMiddlewareError("PERSISTED_QUERY_ERROR", "failed to look up persisted operation")
+	}
+	return ok, nil
+}
+
+// persistedOperationID returns persisted operation ID of the request, or empty for free-form query
+func persistedOperationID(req *GraphqlRequest) string {
+	if req.ID != "" {
+		return req.ID
+	}
+	pq, ok := req.Extensions["persistedQuery"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	hash, _ := pq["sha256Hash"].(string) // nolint: errcheck
+	return hash
+}
+
+func persistedOperationHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
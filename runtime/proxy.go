@@ -0,0 +1,176 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"encoding/base64"
+	"net/http"
+
+	"github.com/iancoleman/strcase"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/connectivity"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// unaryProxyFunc calls unary RPC of the full method like "/greeter.Greeter/SayHello" over another protocol
+// with serialized request message and metadata, and returns serialized response message.
+type unaryProxyFunc func(ctx context.Context, method string, md metadata.MD, body []byte) (header, trailer metadata.MD, resp []byte, err error)
+
+// rawFrame is serialized message which is passed through the proxy as it is
+type rawFrame struct {
+	data []byte
+}
+
+// rawCodec is gRPC codec of the proxy server which doesn't need message types
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v interface{}) ([]byte, error) {
+	f, ok := v.(*rawFrame)
+	if !ok {
+		return nil, fmt.Errorf("unexpected message type %T", v)
+	}
+	return f.data, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v interface{}) error {
+	f, ok := v.(*rawFrame)
+	if !ok {
+		return fmt.Errorf("unexpected message type %T", v)
+	}
+	f.data = append([]byte(nil), data...)
+	return nil
+}
+
+func (rawCodec) String() string {
+	return "proto"
+}
+
+// newUnaryProxyConn returns client connection to in-process gRPC server which proxies any unary RPC via the function,
+// so that generated handlers and DynamicHandler call upstreams of other protocols as gRPC.
+// The proxy server is stopped when the connection is closed.
+func newUnaryProxyConn(call unaryProxyFunc) (*grpc.ClientConn, error) {
+	lis := bufconn.Listen(inProcessBufferSize)
+	server := grpc.NewServer(
+		grpc.CustomCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream) // nolint: errcheck
+			req := &rawFrame{}
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			md, _ := metadata.FromIncomingContext(stream.Context()) // nolint: errcheck
+			header, trailer, resp, err := call(stream.Context(), method, md, req.data)
+			if len(header) > 0 {
+				stream.SetHeader(header) // nolint: errcheck
+			}
+			if len(trailer) > 0 {
+				stream.SetTrailer(trailer)
+			}
+			if err != nil {
+				return err
+			}
+			return stream.SendMsg(&rawFrame{data: resp})
+		}),
+	)
+	go server.Serve(lis) // nolint: errcheck
+
+	conn, err := grpc.Dial(
+		"passthrough:///proxy",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}),
+	)
+	if err != nil {
+		server.Stop()
+		return nil, err
+	}
+	go func() {
+		for state := conn.GetState(); state != connectivity.Shutdown; state = conn.GetState() {
+			conn.WaitForStateChange(context.Background(), state)
+		}
+		server.Stop()
+	}()
+	return conn, nil
+}
+
+// proxyHeaders puts outgoing metadata to HTTP request headers except reserved ones of gRPC.
+// Binary metadata is encoded in base64 like gRPC.
+func proxyHeaders(h http.Header, md metadata.MD) {
+	for key, values := range md {
+		switch {
+		case strings.HasPrefix(key, ":"), strings.HasPrefix(key, "grpc-"):
+			continue
+		case key == "content-type", key == "user-agent", key == "te":
+			continue
+		}
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				v = base64.RawStdEncoding.EncodeToString([]byte(v))
+			}
+			h.Add(key, v)
+		}
+	}
+}
+
+// proxyMetadata converts HTTP response headers to metadata.
+// Headers which have the trailer prefix are returned as trailer without the prefix.
+func proxyMetadata(h http.Header, trailerPrefix string) (header, trailer metadata.MD) {
+	header, trailer = metadata.MD{}, metadata.MD{}
+	for key, values := range h {
+		key = strings.ToLower(key)
+		md := header
+		if trailerPrefix != "" && strings.HasPrefix(key, trailerPrefix) {
+			key = strings.TrimPrefix(key, trailerPrefix)
+			md = trailer
+		}
+		switch key {
+		case "content-type", "content-length", "content-encoding", "date":
+			continue
+		}
+		for _, v := range values {
+			if strings.HasSuffix(key, "-bin") {
+				if b, err := base64.RawStdEncoding.DecodeString(strings.TrimRight(v, "=")); err == nil {
+					v = string(b)
+				}
+			}
+			md.Append(key, v)
+		}
+	}
+	return header, trailer
+}
+
+// snakeCodes maps snake cased gRPC code names like "invalid_argument" which Connect and Twirp errors use
+var snakeCodes = func() map[string]codes.Code {
+	m := make(map[string]codes.Code)
+	for c := codes.OK; c <= codes.Unauthenticated; c++ {
+		m[strcase.ToSnake(c.String())] = c
+	}
+	return m
+}()
+
+// proxyError creates gRPC status error from the code name of the upstream protocol
+func proxyError(code, message string, fallback codes.Code) error {
+	c, ok := snakeCodes[code]
+	if !ok {
+		c = fallback
+	}
+	return status.Error(c, message)
+}
+
+// proxyTransportError converts HTTP client error of the proxy respecting cancellation of the call
+func proxyTransportError(ctx context.Context, err error) error {
+	switch ctx.Err() {
+	case context.DeadlineExceeded:
+		return proxyError("deadline_exceeded", err.Error(), codes.DeadlineExceeded)
+	case context.Canceled:
+		return proxyError("canceled", err.Error(), codes.Canceled)
+	}
+	return proxyError("unavailable", err.Error(), codes.Unavailable)
+}
@@ -0,0 +1,158 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"net/http"
+)
+
+// RateLimitKeyFunc extracts rate limit key from request.
+// If returned key is empty, the request is rate limited by RateLimitByIP in order not to let clients bypass the limit.
+type RateLimitKeyFunc func(r *http.Request) string
+
+// RateLimitByIP uses remote IP address as rate limit key
+func RateLimitByIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// RateLimitByAPIKey uses X-Api-Key header value as rate limit key
+func RateLimitByAPIKey(r *http.Request) string {
+	return r.Header.Get(APIKeyHeader)
+}
+
+// RateLimitStore stores token buckets.
+// Take consumes a token from the bucket of key which is refilled by rate tokens per second up to burst,
+// and returns true if the token could be consumed, otherwise returns duration until next token is available.
+// Implement this interface with shared storage like Redis in order to rate limit across multiple gateway instances,
+// or use NewKVFixedWindowRateLimitStore which approximates the bucket on KVStore.
+type RateLimitStore interface {
+	Take(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// MemoryRateLimitStore is in-memory RateLimitStore which is used in single gateway instance
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryRateLimitStore creates MemoryRateLimitStore pointer
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Take implements RateLimitStore
+func (s *MemoryRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now, rate, burst)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{
+			tokens:  float64(burst),
+			updated: now,
+		}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(float64(burst), b.tokens+now.Sub(b.updated).Seconds()*rate)
+	b.updated = now
+
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0, nil
+	}
+	wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// sweep drops buckets which have been refilled completely in order to avoid growing memory
+func (s *MemoryRateLimitStore) sweep(now time.Time, rate float64, burst int) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, b := range s.buckets {
+		if b.tokens+now.Sub(b.updated).Seconds()*rate >= float64(burst) {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// RateLimitConfig is configuration for RateLimit middleware
+type RateLimitConfig struct {
+	// Tokens per second which are refilled to the bucket
+	Rate float64
+	// Maximum bucket size
+	Burst int
+	// Key extractor, default is RateLimitByIP
+	KeyFunc RateLimitKeyFunc
+	// Token bucket store, default is in-memory store
+	Store RateLimitStore
+}
+
+// Validate returns an error if Rate is not positive or Burst is less than 1, which reject all requests
+func (c RateLimitConfig) Validate() error {
+	if c.Rate <= 0 || c.Burst < 1 {
+		return fmt.Errorf("invalid rate limit: rate %v must be positive and burst %d must be at least 1", c.Rate, c.Burst)
+	}
+	return nil
+}
+
+// RateLimit is middleware function to limit request rate with token bucket algorithm.
+// If the request exceeds the rate, responds RATE_LIMITED error with Retry-After header.
+//
+// RateLimit panics if the config is invalid, use RateLimitConfig.Validate to check the config which is given at runtime.
+func RateLimit(config RateLimitConfig) MiddlewareFunc {
+	if err := config.Validate(); err != nil {
+		panic(err)
+	}
+	if config.KeyFunc == nil {
+		config.KeyFunc = RateLimitByIP
+	}
+	if config.Store == nil {
+		config.Store = NewMemoryRateLimitStore()
+	}
+
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		key := config.KeyFunc(r)
+		if key == "" {
+			// Prefixed in order not to share buckets with keys which look like IP addresses
+			key = "ip:" + RateLimitByIP(r)
+		}
+		ok, wait, err := config.Store.Take(ctx, key, config.Rate, config.Burst)
+		if err != nil {
+			// Store is unavailable, let the request pass rather than blocking all traffic
+			serveMux.logger().Printf("failed to take rate limit token: %s", err)
+			return ctx, nil
+		}
+		if !ok {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			w.Header().Set("Retry-After", fmt.Sprint(retryAfter))
+			AddExtension(ctx, "rateLimit", map[string]interface{}{
+				"retryAfter": retryAfter,
+			})
+			return ctx, NewMiddlewareError("RATE_LIMITED", "rate limit exceeded, retry after "+wait.String())
+		}
+		return ctx, nil
+	}
+}
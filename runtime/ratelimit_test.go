@@ -0,0 +1,98 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryRateLimitStore(t *testing.T) {
+	now := time.Now()
+	s := NewMemoryRateLimitStore()
+	s.now = func() time.Time { return now }
+
+	for i := 0; i < 2; i++ {
+		ok, _, err := s.Take(context.Background(), "key", 1, 2)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, wait, err := s.Take(context.Background(), "key", 1, 2)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.Equal(t, time.Second, wait)
+
+	// Other key has its own bucket
+	ok, _, _ = s.Take(context.Background(), "other", 1, 2) // nolint: errcheck
+	assert.True(t, ok)
+
+	// Refilled after a second
+	now = now.Add(time.Second)
+	ok, _, _ = s.Take(context.Background(), "key", 1, 2) // nolint: errcheck
+	assert.True(t, ok)
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	m := RateLimit(RateLimitConfig{
+		Rate:    1,
+		Burst:   1,
+		KeyFunc: RateLimitByAPIKey,
+	})
+
+	r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	r.Header.Set(APIKeyHeader, "key")
+	_, err := m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+	assert.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	_, err = m(r.Context(), NewServeMux(), w, r)
+	if assert.Error(t, err) {
+		me, ok := err.(*MiddlewareError)
+		assert.True(t, ok)
+		assert.Equal(t, "RATE_LIMITED", me.Code)
+	}
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// Request without key is limited by IP address
+	r = httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	_, err = m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+	assert.NoError(t, err)
+	_, err = m(r.Context(), NewServeMux(), httptest.NewRecorder(), r)
+	assert.Error(t, err)
+
+	assert.Error(t, RateLimitConfig{Burst: 1}.Validate())
+	assert.Error(t, RateLimitConfig{Rate: 1}.Validate())
+	assert.NoError(t, RateLimitConfig{Rate: 1, Burst: 1}.Validate())
+	assert.Panics(t, func() {
+		RateLimit(RateLimitConfig{Burst: 1})
+	})
+	assert.Panics(t, func() {
+		RateLimit(RateLimitConfig{Rate: 1})
+	})
+}
+
+type failingRateLimitStore struct{}
+
+func (failingRateLimitStore) Take(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	return false, 0, errors.New("connection refused")
+}
+
+func TestRateLimitStoreError(t *testing.T) {
+	m := RateLimit(RateLimitConfig{Rate: 1, Burst: 1, Store: failingRateLimitStore{}})
+	logger := &testLogger{}
+	mux := NewServeMux()
+	mux.Logger = logger
+
+	// Request passes and the error is logged to the logger of ServeMux
+	r := httptest.NewRequest(http.MethodGet, "/graphql", nil)
+	_, err := m(r.Context(), mux, httptest.NewRecorder(), r)
+	assert.NoError(t, err)
+	if assert.Len(t, logger.lines, 1) {
+		assert.Contains(t, logger.lines[0], "connection refused")
+	}
+}
@@ -0,0 +1,126 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"net/http"
+)
+
+// RedactedValue is put instead of sensitive values
+const RedactedValue = "[REDACTED]"
+
+// DefaultRedactPatterns is field name patterns whose values are masked when the gateway logs or echoes variables.
+// Field names are matched case-insensitively as substring.
+var DefaultRedactPatterns = []string{"password", "token", "secret"}
+
+// redactValue returns copy of v which masks values of sensitive fields
+func redactValue(v interface{}, patterns []string) interface{} {
+	switch t := v.(type) {
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, vv := range t {
+			if matchRedactPattern(k, patterns) {
+				out[k] = RedactedValue
+				continue
+			}
+			out[k] = redactValue(vv, patterns)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, vv := range t {
+			out[i] = redactValue(vv, patterns)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func matchRedactPattern(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, p := range patterns {
+		if strings.Contains(name, strings.ToLower(p)) {
+			return true
+		}
+	}
+	return false
+}
+
+// WithRedaction is middleware function to register additional field name patterns like "ssn" whose values are masked
+// anywhere the gateway logs or echoes variables, including slow query log, audit entries and error messages.
+// DefaultRedactPatterns are always applied.
+func WithRedaction(patterns ...string) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		// Patterns are stored in the request scope so that they are applied regardless of middleware order
+		if s := scopeFromContext(ctx); s != nil {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			s.redactPatterns = append(s.redactPatterns, patterns...)
+		}
+		return ctx, nil
+	}
+}
+
+// redactPatterns returns default patterns and patterns which are registered via WithRedaction
+func redactPatterns(ctx context.Context) []string {
+	patterns := append([]string{}, DefaultRedactPatterns...)
+	if s := scopeFromContext(ctx); s != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		patterns = append(patterns, s.redactPatterns...)
+	}
+	return patterns
+}
+
+// sensitiveValues collects values of sensitive fields in v which should not be echoed
+func sensitiveValues(v interface{}, patterns []string, sensitive bool) []string {
+	var values []string
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for k, vv := range t {
+			values = append(values, sensitiveValues(vv, patterns, sensitive || matchRedactPattern(k, patterns))...)
+		}
+	case []interface{}:
+		for _, vv := range t {
+			values = append(values, sensitiveValues(vv, patterns, sensitive)...)
+		}
+	case nil:
+	default:
+		if s := fmt.Sprint(t); sensitive && s != "" {
+			values = append(values, s)
+		}
+	}
+	return values
+}
+
+// redactErrors masks values of sensitive variables which are echoed in error messages or extensions.
+// graphql-go puts invalid variable value into the validation error message, for example.
+func redactErrors(ctx context.Context, errs []GraphqlError) {
+	req := requestFromContext(ctx)
+	if req == nil || len(errs) == 0 {
+		return
+	}
+	values := sensitiveValues(req.Variables, redactPatterns(ctx), false)
+	if len(values) == 0 {
+		return
+	}
+	mask := func(s string) string {
+		for _, v := range values {
+			s = strings.ReplaceAll(s, v, RedactedValue)
+		}
+		return s
+	}
+	for i := 0; i < len(errs); i++ {
+		e := errs[i]
+		e.Message = mask(e.Message)
+		for k, v := range e.Extensions {
+			if s, ok := v.(string); ok {
+				e.Extensions[k] = mask(s)
+			}
+		}
+		errs[i] = e
+	}
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactValue(t *testing.T) {
+	v := map[string]interface{}{
+		"name":     "alice",
+		"Password": "p@ss",
+		"profile": map[string]interface{}{
+			"accessToken": "abc",
+		},
+		"list": []interface{}{
+			map[string]interface{}{"ssn": "123"},
+		},
+	}
+	assert.Equal(t, map[string]interface{}{
+		"name":     "alice",
+		"Password": RedactedValue,
+		"profile": map[string]interface{}{
+			"accessToken": RedactedValue,
+		},
+		"list": []interface{}{
+			map[string]interface{}{"ssn": "123"},
+		},
+	}, redactValue(v, DefaultRedactPatterns))
+}
+
+func TestWithRedaction(t *testing.T) {
+	ctx := withRequestScope(context.Background())
+	assert.Equal(t, DefaultRedactPatterns, redactPatterns(ctx))
+
+	ctx, err := WithRedaction("ssn")(ctx, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, append(DefaultRedactPatterns, "ssn"), redactPatterns(ctx))
+}
+
+func TestRedactErrors(t *testing.T) {
+	h := newTestHandler()
+	h.mutations["register"] = &graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{
+			"ssn": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			// Upstream may echo the invalid value
+			return nil, NewFieldError("INVALID_ARGUMENT", fmt.Sprintf("ssn %s is invalid", p.Args["ssn"]))
+		},
+	}
+	mux := NewServeMux(WithRedaction("ssn"))
+	assert.NoError(t, mux.AddHandler(h))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(
+		`{"query":"mutation ($ssn: String) { register(ssn: $ssn) }","variables":{"ssn":"123-45-6789"}}`,
+	))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	var result graphql.Result
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "ssn [REDACTED] is invalid", result.Errors[0].Message)
+	}
+}
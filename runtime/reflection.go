@@ -0,0 +1,155 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// NewReflectionHandlers discovers services of the upstream gRPC server via server reflection,
+// and creates DynamicHandler for each service. The upstream must register reflection service.
+// Services of gRPC itself like reflection and health checking are not exposed.
+func NewReflectionHandlers(ctx context.Context, conn *grpc.ClientConn) ([]*DynamicHandler, error) {
+	services, err := reflectServices(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	return NewDynamicHandlers(conn, services...)
+}
+
+// reflectionClient resolves file descriptors via server reflection stream
+type reflectionClient struct {
+	stream rpb.ServerReflection_ServerReflectionInfoClient
+	files  map[string]*descriptorpb.FileDescriptorProto
+}
+
+func reflectServices(ctx context.Context, conn *grpc.ClientConn) ([]protoreflect.ServiceDescriptor, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open reflection stream: %w", err)
+	}
+	c := &reflectionClient{
+		stream: stream,
+		files:  make(map[string]*descriptorpb.FileDescriptorProto),
+	}
+
+	resp, err := c.request(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{ListServices: "*"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, s := range resp.GetListServicesResponse().GetService() {
+		if isInternalService(s.GetName()) {
+			continue
+		}
+		names = append(names, s.GetName())
+		if _, err := c.request(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: s.GetName()},
+		}); err != nil {
+			return nil, err
+		}
+	}
+	if err := c.resolveDependencies(); err != nil {
+		return nil, err
+	}
+
+	fds := make([]*descriptorpb.FileDescriptorProto, 0, len(c.files))
+	for _, fd := range c.files {
+		fds = append(fds, fd)
+	}
+	files, err := newDynamicFiles(fds)
+	if err != nil {
+		return nil, err
+	}
+
+	services := make([]protoreflect.ServiceDescriptor, 0, len(names))
+	for _, name := range names {
+		d, err := files.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("service %s is not found in reflected descriptors: %w", name, err)
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a service", name)
+		}
+		services = append(services, sd)
+	}
+	return services, nil
+}
+
+// gRPC provided services which are not exposed via reflection mode
+var internalServicePrefixes = []string{"grpc.reflection.", "grpc.health.", "grpc.channelz."}
+
+func isInternalService(name string) bool {
+	for _, prefix := range internalServicePrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// request sends reflection request and stores file descriptors in the response
+func (c *reflectionClient) request(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+	if err := c.stream.Send(req); err != nil {
+		return nil, fmt.Errorf("failed to send reflection request: %w", err)
+	}
+	resp, err := c.stream.Recv()
+	if err != nil {
+		return nil, fmt.Errorf("failed to receive reflection response: %w", err)
+	}
+	if e := resp.GetErrorResponse(); e != nil {
+		return nil, fmt.Errorf("reflection error: %s", e.GetErrorMessage())
+	}
+	for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+		fd := &descriptorpb.FileDescriptorProto{}
+		if err := proto.Unmarshal(b, fd); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal file descriptor: %w", err)
+		}
+		c.files[fd.GetName()] = fd
+	}
+	return resp, nil
+}
+
+// resolveDependencies requests dependencies which are neither responded yet nor linked into this binary
+func (c *reflectionClient) resolveDependencies() error {
+	for {
+		var missing []string
+		for _, fd := range c.files {
+			for _, dep := range fd.GetDependency() {
+				if _, ok := c.files[dep]; ok {
+					continue
+				}
+				if _, err := protoregistry.GlobalFiles.FindFileByPath(dep); err == nil {
+					continue
+				}
+				missing = append(missing, dep)
+			}
+		}
+		if len(missing) == 0 {
+			return nil
+		}
+		for _, dep := range missing {
+			if _, err := c.request(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			}); err != nil {
+				return err
+			}
+			if _, ok := c.files[dep]; !ok {
+				return fmt.Errorf("dependency %s is not found via reflection", dep)
+			}
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package runtime
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	pb "google.golang.org/grpc/reflection/grpc_testing"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type testSearchServer struct{}
+
+func (s *testSearchServer) Search(ctx context.Context, req *pb.SearchRequest) (*pb.SearchResponse, error) {
+	return &pb.SearchResponse{
+		Results: []*pb.SearchResponse_Result{
+			{
+				Url:      "https://example.com/" + req.Query,
+				Title:    "Result of " + req.Query,
+				Snippets: []string{"foo", "bar"},
+			},
+		},
+	}, nil
+}
+
+func (s *testSearchServer) StreamingSearch(stream pb.SearchService_StreamingSearchServer) error {
+	return nil
+}
+
+func dialTestSearchServer(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	pb.RegisterSearchServiceServer(server, &testSearchServer{})
+	reflection.Register(server)
+	go server.Serve(lis) // nolint: errcheck
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithInsecure(), grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return lis.Dial()
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestReflectionHandlers(t *testing.T) {
+	conn := dialTestSearchServer(t)
+	handlers, err := NewReflectionHandlers(context.Background(), conn)
+	assert.NoError(t, err)
+	assert.Len(t, handlers, 1)
+	assert.Equal(t, "grpc.testing.SearchService", handlers[0].ServiceName())
+	// Streaming method is not exposed
+	assert.Len(t, handlers[0].GetQueries(nil), 1)
+	assert.Len(t, handlers[0].GetMutations(nil), 0)
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(handlers[0]))
+
+	result := serveTestQuery(t, mux, `{ search(query: "grpc") { results { url title snippets } } }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{
+		"search": map[string]interface{}{
+			"results": []interface{}{
+				map[string]interface{}{
+					"url":      "https://example.com/grpc",
+					"title":    "Result of grpc",
+					"snippets": []interface{}{"foo", "bar"},
+				},
+			},
+		},
+	}, result.Data)
+}
@@ -0,0 +1,289 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+)
+
+const (
+	// DefaultApolloRegistryEndpoint is Apollo Platform API endpoint
+	DefaultApolloRegistryEndpoint = "https://api.apollographql.com/api/graphql"
+	// DefaultHiveRegistryEndpoint is GraphQL Hive registry endpoint
+	DefaultHiveRegistryEndpoint = "https://app.graphql-hive.com/graphql"
+)
+
+// SchemaPublication is the schema and its metadata which are published to schema registry
+type SchemaPublication struct {
+	// Service or subgraph name in the registry
+	ServiceName string
+	// URL which the registry or router reaches the service at
+	URL string
+	// Version of the schema, default is hash of SDL
+	Version string
+	// VCS commit of the deployment, default is Version
+	Commit string
+	// Author of the publication, default is the agent name of the gateway
+	Author string
+	// SDL of the schema, which is filled by ServeMux.PublishSchema
+	SDL string
+}
+
+// SchemaRegistry publishes schema to the registry so that schema checks and composition are driven by the registry
+type SchemaRegistry interface {
+	Publish(ctx context.Context, p SchemaPublication) error
+}
+
+// PublishSchema prints SDL of the registered handlers and publishes it to the registry with the metadata.
+// Call this on startup after all handlers are added. Note that handlers which are added via AddTenantHandler are not included.
+func (s *ServeMux) PublishSchema(ctx context.Context, registry SchemaRegistry, p SchemaPublication) error {
+	schema, err := s.Schema()
+	if err != nil {
+		return err
+	}
+	p.SDL = PrintSchema(&schema)
+	if p.Version == "" {
+		p.Version = sdlVersion(p.SDL)
+	}
+	if p.Commit == "" {
+		p.Commit = p.Version
+	}
+	if p.Author == "" {
+		p.Author = apolloAgentVersion
+	}
+	return registry.Publish(ctx, p)
+}
+
+// sdlVersion returns short hash of SDL which identifies the schema
+func sdlVersion(sdl string) string {
+	sum := sha256.Sum256([]byte(sdl))
+	return hex.EncodeToString(sum[:])[:12]
+}
+
+// ApolloRegistry publishes schema to Apollo GraphOS
+type ApolloRegistry struct {
+	// Apollo graph API key, required
+	APIKey string
+	// Graph reference like "my-graph@current", required
+	GraphRef string
+	// If true, the schema is published as subgraph of ServiceName, otherwise as monolith schema of the graph
+	Subgraph bool
+	// Platform API endpoint, default is DefaultApolloRegistryEndpoint
+	Endpoint string
+	// HTTP client, default is http.DefaultClient
+	Client *http.Client
+}
+
+const apolloPublishSubgraphMutation = `mutation PublishSubgraph($graphId: ID!, $variant: String!, $name: String!, $url: String, $revision: String!, $sdl: String!) {
+  graph(id: $graphId) {
+    publishSubgraph(graphVariant: $variant, name: $name, url: $url, revision: $revision, activePartialSchema: {sdl: $sdl}) {
+      errors { message }
+    }
+  }
+}`
+
+const apolloUploadSchemaMutation = `mutation UploadSchema($graphId: ID!, $variant: String!, $sdl: String!, $commit: String) {
+  service(id: $graphId) {
+    uploadSchema(schemaDocument: $sdl, tag: $variant, gitContext: {commit: $commit}) {
+      success
+      message
+    }
+  }
+}`
+
+// Publish implements SchemaRegistry
+func (a *ApolloRegistry) Publish(ctx context.Context, p SchemaPublication) error {
+	graphID, variant := a.GraphRef, "current"
+	if i := strings.Index(a.GraphRef, "@"); i >= 0 {
+		graphID, variant = a.GraphRef[:i], a.GraphRef[i+1:]
+	}
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultApolloRegistryEndpoint
+	}
+	header := http.Header{
+		"X-Api-Key":                    {a.APIKey},
+		"Apollographql-Client-Name":    {apolloAgentVersion},
+		"Apollographql-Client-Version": {p.Version},
+	}
+
+	if a.Subgraph {
+		var data struct {
+			Graph *struct {
+				PublishSubgraph *struct {
+					Errors []struct {
+						Message string `json:"message"`
+					} `json:"errors"`
+				} `json:"publishSubgraph"`
+			} `json:"graph"`
+		}
+		err := postRegistryOperation(ctx, a.Client, endpoint, header, apolloPublishSubgraphMutation, map[string]interface{}{
+			"graphId":  graphID,
+			"variant":  variant,
+			"name":     p.ServiceName,
+			"url":      p.URL,
+			"revision": p.Commit,
+			"sdl":      p.SDL,
+		}, &data)
+		if err != nil {
+			return err
+		}
+		if data.Graph == nil || data.Graph.PublishSubgraph == nil {
+			return fmt.Errorf("graph %s is not found", graphID)
+		}
+		if errs := data.Graph.PublishSubgraph.Errors; len(errs) > 0 {
+			messages := make([]string, len(errs))
+			for i, e := range errs {
+				messages[i] = e.Message
+			}
+			return fmt.Errorf("failed to compose subgraph: %s", strings.Join(messages, ", "))
+		}
+		return nil
+	}
+
+	var data struct {
+		Service *struct {
+			UploadSchema *struct {
+				Success bool   `json:"success"`
+				Message string `json:"message"`
+			} `json:"uploadSchema"`
+		} `json:"service"`
+	}
+	err := postRegistryOperation(ctx, a.Client, endpoint, header, apolloUploadSchemaMutation, map[string]interface{}{
+		"graphId": graphID,
+		"variant": variant,
+		"sdl":     p.SDL,
+		"commit":  p.Commit,
+	}, &data)
+	if err != nil {
+		return err
+	}
+	if data.Service == nil || data.Service.UploadSchema == nil {
+		return fmt.Errorf("graph %s is not found", graphID)
+	}
+	if !data.Service.UploadSchema.Success {
+		return fmt.Errorf("failed to upload schema: %s", data.Service.UploadSchema.Message)
+	}
+	return nil
+}
+
+// HiveRegistry publishes schema to GraphQL Hive
+type HiveRegistry struct {
+	// Registry access token, required
+	Token string
+	// Registry endpoint, default is DefaultHiveRegistryEndpoint
+	Endpoint string
+	// HTTP client, default is http.DefaultClient
+	Client *http.Client
+}
+
+const hiveSchemaPublishMutation = `mutation SchemaPublish($input: SchemaPublishInput!) {
+  schemaPublish(input: $input) {
+    __typename
+  }
+}`
+
+// Publish implements SchemaRegistry
+func (h *HiveRegistry) Publish(ctx context.Context, p SchemaPublication) error {
+	endpoint := h.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultHiveRegistryEndpoint
+	}
+	input := map[string]interface{}{
+		"sdl":    p.SDL,
+		"author": p.Author,
+		"commit": p.Commit,
+	}
+	if p.ServiceName != "" {
+		input["service"] = p.ServiceName
+	}
+	if p.URL != "" {
+		input["url"] = p.URL
+	}
+
+	var data struct {
+		SchemaPublish *struct {
+			Typename string `json:"__typename"`
+		} `json:"schemaPublish"`
+	}
+	err := postRegistryOperation(ctx, h.Client, endpoint, http.Header{
+		"Authorization": {"Bearer " + h.Token},
+	}, hiveSchemaPublishMutation, map[string]interface{}{
+		"input": input,
+	}, &data)
+	if err != nil {
+		return err
+	}
+	if data.SchemaPublish == nil {
+		return errors.New("schema publish returned no result")
+	}
+	// Rejected publications are responded as SchemaPublishError or other *Error types
+	if strings.HasSuffix(data.SchemaPublish.Typename, "Error") {
+		return fmt.Errorf("failed to publish schema: %s", data.SchemaPublish.Typename)
+	}
+	return nil
+}
+
+// postRegistryOperation sends GraphQL operation to the registry and decodes the data into out
+func postRegistryOperation(
+	ctx context.Context,
+	client *http.Client,
+	endpoint string,
+	header http.Header,
+	query string,
+	variables map[string]interface{},
+	out interface{},
+) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("unexpected registry response of status %d: %w", resp.StatusCode, err)
+	}
+	if len(result.Errors) > 0 {
+		messages := make([]string, len(result.Errors))
+		for i, e := range result.Errors {
+			messages[i] = e.Message
+		}
+		return fmt.Errorf("registry responds errors: %s", strings.Join(messages, ", "))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry responds status %d", resp.StatusCode)
+	}
+	return json.Unmarshal(result.Data, out)
+}
@@ -0,0 +1,117 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testRegistryRequest struct {
+	Header    http.Header
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func startTestRegistry(t *testing.T, response string) (*httptest.Server, *testRegistryRequest) {
+	t.Helper()
+	received := &testRegistryRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received.Header = r.Header
+		json.NewDecoder(r.Body).Decode(received) // nolint: errcheck
+		w.Write([]byte(response))                // nolint: errcheck
+	}))
+	t.Cleanup(server.Close)
+	return server, received
+}
+
+func TestPublishSchema(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	schema, err := mux.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sdl := PrintSchema(&schema)
+
+	t.Run("apollo subgraph", func(t *testing.T) {
+		server, received := startTestRegistry(t, `{"data":{"graph":{"publishSubgraph":{"errors":[]}}}}`)
+		err := mux.PublishSchema(context.Background(), &ApolloRegistry{
+			APIKey:   "key",
+			GraphRef: "graph@prod",
+			Subgraph: true,
+			Endpoint: server.URL,
+		}, SchemaPublication{
+			ServiceName: "users",
+			URL:         "http://users:8888/graphql",
+			Commit:      "abc123",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "key", received.Header.Get("X-Api-Key"))
+		assert.Equal(t, sdlVersion(sdl), received.Header.Get("Apollographql-Client-Version"))
+		assert.Equal(t, map[string]interface{}{
+			"graphId":  "graph",
+			"variant":  "prod",
+			"name":     "users",
+			"url":      "http://users:8888/graphql",
+			"revision": "abc123",
+			"sdl":      sdl,
+		}, received.Variables)
+	})
+
+	t.Run("apollo composition error", func(t *testing.T) {
+		server, _ := startTestRegistry(t, `{"data":{"graph":{"publishSubgraph":{"errors":[{"message":"conflict"}]}}}}`)
+		err := mux.PublishSchema(context.Background(), &ApolloRegistry{
+			GraphRef: "graph",
+			Subgraph: true,
+			Endpoint: server.URL,
+		}, SchemaPublication{ServiceName: "users"})
+		assert.EqualError(t, err, "failed to compose subgraph: conflict")
+	})
+
+	t.Run("apollo monolith", func(t *testing.T) {
+		server, received := startTestRegistry(t, `{"data":{"service":{"uploadSchema":{"success":false,"message":"invalid"}}}}`)
+		err := mux.PublishSchema(context.Background(), &ApolloRegistry{
+			GraphRef: "graph",
+			Endpoint: server.URL,
+		}, SchemaPublication{})
+		assert.EqualError(t, err, "failed to upload schema: invalid")
+		assert.Equal(t, "current", received.Variables["variant"])
+		assert.Equal(t, sdlVersion(sdl), received.Variables["commit"])
+	})
+
+	t.Run("hive", func(t *testing.T) {
+		server, received := startTestRegistry(t, `{"data":{"schemaPublish":{"__typename":"SchemaPublishSuccess"}}}`)
+		err := mux.PublishSchema(context.Background(), &HiveRegistry{
+			Token:    "token",
+			Endpoint: server.URL,
+		}, SchemaPublication{
+			ServiceName: "users",
+			Version:     "v1.2.3",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "Bearer token", received.Header.Get("Authorization"))
+		assert.Equal(t, map[string]interface{}{
+			"sdl":     sdl,
+			"author":  "grpc-graphql-gateway",
+			"commit":  "v1.2.3",
+			"service": "users",
+		}, received.Variables["input"])
+	})
+
+	t.Run("hive rejected", func(t *testing.T) {
+		server, _ := startTestRegistry(t, `{"data":{"schemaPublish":{"__typename":"SchemaPublishError"}}}`)
+		err := mux.PublishSchema(context.Background(), &HiveRegistry{Endpoint: server.URL}, SchemaPublication{})
+		assert.EqualError(t, err, "failed to publish schema: SchemaPublishError")
+	})
+
+	t.Run("graphql errors", func(t *testing.T) {
+		server, _ := startTestRegistry(t, `{"errors":[{"message":"unauthorized"}]}`)
+		err := mux.PublishSchema(context.Background(), &HiveRegistry{Endpoint: server.URL}, SchemaPublication{})
+		assert.EqualError(t, err, "registry responds errors: unauthorized")
+	})
+}
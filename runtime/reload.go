@@ -0,0 +1,261 @@
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const (
+	// SchemaVersionHeader is response header which has the schema version of DynamicSchema
+	SchemaVersionHeader = "X-Schema-Version"
+	// SchemaVersionExtension is response extension key which has the schema version of DynamicSchema
+	SchemaVersionExtension = "schemaVersion"
+)
+
+// DescriptorSource loads service descriptors for DynamicSchema
+type DescriptorSource func(ctx context.Context) ([]protoreflect.ServiceDescriptor, error)
+
+// ReflectionSource loads service descriptors via server reflection of the upstream
+func ReflectionSource(conn *grpc.ClientConn) DescriptorSource {
+	return func(ctx context.Context) ([]protoreflect.ServiceDescriptor, error) {
+		return reflectServices(ctx, conn)
+	}
+}
+
+// DescriptorSetSource loads service descriptors from the descriptor set file, see LoadDescriptorSet
+func DescriptorSetSource(path string) DescriptorSource {
+	return func(ctx context.Context) ([]protoreflect.ServiceDescriptor, error) {
+		return LoadDescriptorSet(path)
+	}
+}
+
+// schemaVersioner is implemented by handlers whose schema is swapped at runtime.
+// ServeMux takes a snapshot once per request, so that queries and mutations are built from the same schema version.
+type schemaVersioner interface {
+	snapshot() (GraphqlHandler, string)
+}
+
+// DynamicSchema is GraphqlHandler which serves dynamic handlers of the descriptor source,
+// and swaps them atomically when Reload finds changed descriptors.
+// The active schema version is responded in SchemaVersionHeader header and SchemaVersionExtension extension.
+// Note that root field conflicts are checked against the version at registration,
+// and DynamicSchema can't be grouped via AddNamespacedHandler.
+type DynamicSchema struct {
+	conn    *grpc.ClientConn
+	source  DescriptorSource
+	current atomic.Value // *dynamicSnapshot
+
+	// reloadMu serializes Reload so that concurrent reloads don't swap back to an older version
+	reloadMu sync.Mutex
+}
+
+// dynamicSnapshot is GraphqlHandler of single schema version
+type dynamicSnapshot struct {
+	conn     *grpc.ClientConn
+	handlers []*DynamicHandler
+	version  string
+}
+
+// NewDynamicSchema loads descriptors from the source and creates DynamicSchema which calls RPCs via conn
+func NewDynamicSchema(ctx context.Context, conn *grpc.ClientConn, source DescriptorSource) (*DynamicSchema, error) {
+	s := &DynamicSchema{
+		conn:   conn,
+		source: source,
+	}
+	if _, err := s.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Reload loads descriptors from the source and swaps the schema if the version is changed.
+// Returns true if the schema is swapped. On error, the current schema continues to be served.
+func (s *DynamicSchema) Reload(ctx context.Context) (bool, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	services, err := s.source(ctx)
+	if err != nil {
+		return false, err
+	}
+	version := schemaVersion(services)
+	if version == s.Version() {
+		return false, nil
+	}
+	handlers, err := NewDynamicHandlers(s.conn, services...)
+	if err != nil {
+		return false, err
+	}
+	next := &dynamicSnapshot{
+		conn:     s.conn,
+		handlers: handlers,
+		version:  version,
+	}
+	// Check the new schema before swapping so that broken descriptors don't break serving
+	if err := (&ServeMux{}).validateHandler(next); err != nil {
+		return false, err
+	}
+	s.current.Store(next)
+	return true, nil
+}
+
+// Watch reloads the schema periodically until ctx is done.
+// Reload errors are logged and the current schema continues to be served.
+func (s *DynamicSchema) Watch(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			swapped, err := s.Reload(ctx)
+			if err != nil {
+				grpclog.Errorf("Failed to reload dynamic schema: %s", err)
+			} else if swapped {
+				grpclog.Infof("Dynamic schema is reloaded to version %s", s.Version())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// Version returns the active schema version
+func (s *DynamicSchema) Version() string {
+	if snapshot, ok := s.current.Load().(*dynamicSnapshot); ok {
+		return snapshot.version
+	}
+	return ""
+}
+
+func (s *DynamicSchema) snapshot() (GraphqlHandler, string) {
+	snapshot := s.current.Load().(*dynamicSnapshot) // nolint: errcheck
+	return snapshot, snapshot.version
+}
+
+// CreateConnection returns connection which is passed to NewDynamicSchema
+func (s *DynamicSchema) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	return s.conn, func() {}, nil
+}
+
+// ServiceName returns service names of the active schema
+func (s *DynamicSchema) ServiceName() string {
+	return s.current.Load().(*dynamicSnapshot).ServiceName() // nolint: errcheck
+}
+
+// GetQueries returns graphql.Fields for Query of the active schema
+func (s *DynamicSchema) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	h, _ := s.snapshot()
+	return h.GetQueries(conn)
+}
+
+// GetMutations returns graphql.Fields for Mutation of the active schema
+func (s *DynamicSchema) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	h, _ := s.snapshot()
+	return h.GetMutations(conn)
+}
+
+func (s *dynamicSnapshot) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	return s.conn, func() {}, nil
+}
+
+func (s *dynamicSnapshot) ServiceName() string {
+	names := make([]string, len(s.handlers))
+	for i, h := range s.handlers {
+		names[i] = h.ServiceName()
+	}
+	return strings.Join(names, ",")
+}
+
+func (s *dynamicSnapshot) GetQueries(conn *grpc.ClientConn) graphql.Fields {
+	fields := graphql.Fields{}
+	for _, h := range s.handlers {
+		for k, v := range h.GetQueries(conn) {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+func (s *dynamicSnapshot) GetMutations(conn *grpc.ClientConn) graphql.Fields {
+	fields := graphql.Fields{}
+	for _, h := range s.handlers {
+		for k, v := range h.GetMutations(conn) {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// FieldMappings returns gRPC methods of all services in the snapshot
+func (s *dynamicSnapshot) FieldMappings() []FieldMapping {
+	var mappings []FieldMapping
+	for _, h := range s.handlers {
+		mappings = append(mappings, h.FieldMappings()...)
+	}
+	return mappings
+}
+
+// FieldGroups returns groups of root fields of all services in the snapshot
+func (s *dynamicSnapshot) FieldGroups() map[string]string {
+	groups := map[string]string{}
+	for _, h := range s.handlers {
+		for k, v := range h.FieldGroups() {
+			groups[k] = v
+		}
+	}
+	return groups
+}
+
+// schemaVersion returns hash of the files which declare services and their dependencies
+func schemaVersion(services []protoreflect.ServiceDescriptor) string {
+	files := map[string]protoreflect.FileDescriptor{}
+	var collect func(fd protoreflect.FileDescriptor)
+	collect = func(fd protoreflect.FileDescriptor) {
+		if _, ok := files[fd.Path()]; ok {
+			return
+		}
+		files[fd.Path()] = fd
+		for i := 0; i < fd.Imports().Len(); i++ {
+			collect(fd.Imports().Get(i).FileDescriptor)
+		}
+	}
+	for _, sd := range services {
+		collect(sd.ParentFile())
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	hash := sha256.New()
+	marshaler := proto.MarshalOptions{Deterministic: true}
+	for _, path := range paths {
+		b, _ := marshaler.Marshal(protodesc.ToFileDescriptorProto(files[path])) // nolint: errcheck
+		hash.Write(b)                                                           // nolint: errcheck
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:12]
+}
+
+// respondSchemaVersion responds the schema version of the request
+func respondSchemaVersion(ctx context.Context, w http.ResponseWriter, version string) {
+	AddExtension(ctx, SchemaVersionExtension, version)
+	w.Header().Set(SchemaVersionHeader, version)
+}
@@ -0,0 +1,148 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func writeTestDescriptorSet(t *testing.T, path string, edit func(fd *descriptorpb.FileDescriptorProto)) {
+	t.Helper()
+	fd := &descriptorpb.FileDescriptorProto{}
+	if err := prototext.Unmarshal([]byte(testBookFile), fd); err != nil {
+		t.Fatal(err)
+	}
+	edit(fd)
+	b, err := proto.Marshal(&descriptorpb.FileDescriptorSet{
+		File: []*descriptorpb.FileDescriptorProto{fd},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDynamicSchemaReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.bin")
+	writeTestDescriptorSet(t, path, func(fd *descriptorpb.FileDescriptorProto) {})
+
+	schema, err := NewDynamicSchema(context.Background(), nil, DescriptorSetSource(path))
+	assert.NoError(t, err)
+	initial := schema.Version()
+	assert.Len(t, initial, 12)
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(schema))
+	// Built schema is rebuilt when the handler is reloaded
+	handler, err := mux.Build()
+	assert.NoError(t, err)
+
+	serve := func() (*graphql.Result, http.Header) {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ __schema { queryType { fields { name } } } }`))
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		var result graphql.Result
+		if err := json.NewDecoder(w.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		return &result, w.Header()
+	}
+
+	result, header := serve()
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, initial, header.Get(SchemaVersionHeader))
+	assert.Equal(t, initial, result.Extensions[SchemaVersionExtension])
+
+	t.Run("unchanged", func(t *testing.T) {
+		swapped, err := schema.Reload(context.Background())
+		assert.NoError(t, err)
+		assert.False(t, swapped)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		writeTestDescriptorSet(t, path, func(fd *descriptorpb.FileDescriptorProto) {
+			fd.Service[0].Method = append(fd.Service[0].Method, &descriptorpb.MethodDescriptorProto{
+				Name:       proto.String("FindBook"),
+				InputType:  proto.String(".library.v1.GetBookRequest"),
+				OutputType: proto.String(".library.v1.Book"),
+			})
+		})
+		swapped, err := schema.Reload(context.Background())
+		assert.NoError(t, err)
+		assert.True(t, swapped)
+		assert.NotEqual(t, initial, schema.Version())
+
+		result, header := serve()
+		assert.Len(t, result.Errors, 0)
+		assert.Equal(t, schema.Version(), header.Get(SchemaVersionHeader))
+		assert.Contains(t, schema.GetQueries(nil), "findBook")
+		b, _ := json.Marshal(result.Data) // nolint: errcheck
+		assert.Contains(t, string(b), `"findBook"`)
+	})
+
+	t.Run("broken descriptors keep current schema", func(t *testing.T) {
+		current := schema.Version()
+		writeTestDescriptorSet(t, path, func(fd *descriptorpb.FileDescriptorProto) {
+			fd.Dependency = append(fd.Dependency, "missing.proto")
+		})
+		swapped, err := schema.Reload(context.Background())
+		assert.Error(t, err)
+		assert.False(t, swapped)
+		assert.Equal(t, current, schema.Version())
+	})
+}
+
+func TestDynamicSchemaConcurrentReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.bin")
+	writeTestDescriptorSet(t, path, func(fd *descriptorpb.FileDescriptorProto) {})
+	schema, err := NewDynamicSchema(context.Background(), nil, DescriptorSetSource(path))
+	assert.NoError(t, err)
+
+	writeTestDescriptorSet(t, path, func(fd *descriptorpb.FileDescriptorProto) {
+		fd.Service[0].Method = append(fd.Service[0].Method, &descriptorpb.MethodDescriptorProto{
+			Name:       proto.String("FindBook"),
+			InputType:  proto.String(".library.v1.GetBookRequest"),
+			OutputType: proto.String(".library.v1.Book"),
+		})
+	})
+	// Only one of concurrent reloads swaps the schema
+	var swaps int32
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if swapped, err := schema.Reload(context.Background()); assert.NoError(t, err) && swapped {
+				atomic.AddInt32(&swaps, 1)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), swaps)
+}
+
+func TestDynamicSchemaCannotBeNamespaced(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "image.bin")
+	writeTestDescriptorSet(t, path, func(fd *descriptorpb.FileDescriptorProto) {})
+	schema, err := NewDynamicSchema(context.Background(), nil, DescriptorSetSource(path))
+	assert.NoError(t, err)
+
+	mux := NewServeMux()
+	assert.Error(t, mux.AddNamespacedHandler("library", schema))
+}
@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorReport is reported to ErrorReporter when the GraphQL result has errors
+type ErrorReport struct {
+	// Incoming HTTP request
+	Request *http.Request
+	// Operation name (or the document hash for anonymous operation, see OperationLabelFromContext),
+	// query and variables which are masked with redaction patterns.
+	// They are empty if the request body is not parsed yet.
+	OperationName string
+	Query         string
+	Variables     map[string]interface{}
+	// Errors in the GraphQL response
+	Errors []GraphqlError
+	// gRPC status of upstream calls which caused the errors
+	UpstreamErrors []*status.Status
+}
+
+// ErrorReporter reports errors to error tracking service like Sentry.
+// Report is called synchronously before responding, so implementations should not block.
+type ErrorReporter interface {
+	Report(ctx context.Context, report ErrorReport)
+}
+
+// WithErrorReporter is middleware function to report the request which results in errors,
+// see contrib/sentry package for Sentry.
+func WithErrorReporter(reporter ErrorReporter) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		onFinish(ctx, func(result *graphql.Result) {
+			if len(result.Errors) == 0 {
+				return
+			}
+			report := ErrorReport{
+				Request: r,
+				Errors:  result.Errors,
+			}
+			if req := requestFromContext(ctx); req != nil {
+				report.OperationName = OperationLabelFromContext(ctx)
+				report.Query = req.Query
+				if req.Variables != nil {
+					report.Variables = redactValue(req.Variables, redactPatterns(ctx)).(map[string]interface{})
+				}
+			}
+			for _, e := range result.Errors {
+				if st, ok := upstreamStatus(e); ok {
+					report.UpstreamErrors = append(report.UpstreamErrors, st)
+				}
+			}
+			reporter.Report(ctx, report)
+		})
+		return ctx, nil
+	}
+}
+
+// upstreamStatus returns gRPC status of the error which is returned from upstream call in the resolver
+func upstreamStatus(e GraphqlError) (*status.Status, bool) {
+	err := e.OriginalError()
+	if ge, ok := err.(*gqlerrors.Error); ok {
+		err = ge.OriginalError
+	}
+	var grpcErr interface {
+		GRPCStatus() *status.Status
+	}
+	if err == nil || !errors.As(err, &grpcErr) {
+		return nil, false
+	}
+	st := grpcErr.GRPCStatus()
+	return st, st.Code() != codes.OK
+}
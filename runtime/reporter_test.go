@@ -0,0 +1,71 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type testErrorReporter struct {
+	reports []ErrorReport
+}
+
+func (r *testErrorReporter) Report(ctx context.Context, report ErrorReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestWithErrorReporter(t *testing.T) {
+	h := newTestHandler()
+	h.mutations["signup"] = &graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{
+			"password": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return nil, fmt.Errorf("Failed to call RPC Signup: %w", status.Error(codes.Unavailable, "user service is down"))
+		},
+	}
+	reporter := &testErrorReporter{}
+	mux := NewServeMux(WithErrorReporter(reporter))
+	assert.NoError(t, mux.AddHandler(h))
+
+	serve := func(body string) {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		r.Header.Set("Content-Type", "application/json")
+		mux.ServeHTTP(httptest.NewRecorder(), r)
+	}
+
+	serve(`{"query":"{ hello }"}`)
+	assert.Len(t, reporter.reports, 0)
+
+	serve(`{"query":"mutation Signup($password: String) { signup(password: $password) }","operationName":"Signup","variables":{"password":"hunter2"}}`)
+	if !assert.Len(t, reporter.reports, 1) {
+		t.FailNow()
+	}
+	report := reporter.reports[0]
+	assert.Equal(t, "/graphql", report.Request.URL.Path)
+	assert.Equal(t, "Signup", report.OperationName)
+	assert.Equal(t, "mutation Signup($password: String) { signup(password: $password) }", report.Query)
+	assert.Equal(t, map[string]interface{}{"password": RedactedValue}, report.Variables)
+	assert.Len(t, report.Errors, 1)
+	if assert.Len(t, report.UpstreamErrors, 1) {
+		assert.Equal(t, codes.Unavailable, report.UpstreamErrors[0].Code())
+		assert.Equal(t, "user service is down", report.UpstreamErrors[0].Message())
+	}
+
+	serve(`{"query":"{ unknown }"}`)
+	if assert.Len(t, reporter.reports, 2) {
+		assert.Len(t, reporter.reports[1].UpstreamErrors, 0)
+	}
+}
@@ -1,23 +1,90 @@
 package runtime
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
 
 	"encoding/json"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strings"
 
 	"github.com/iancoleman/strcase"
+	"google.golang.org/protobuf/proto"
 )
 
 type GraphqlRequest struct {
 	Query         string                 `json:"query"`
 	Variables     map[string]interface{} `json:"variables"`
 	OperationName string                 `json:"operationName"`
+	// ID of persisted operation which is sent instead of query
+	ID         string                 `json:"id,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
-// ParseRequest parses graphql query and variables from each request methods
-func parseRequest(r *http.Request) (*GraphqlRequest, error) {
+// RequestParseLimits defines limits on decoding graphql request envelope.
+// Zero value means unlimited.
+type RequestParseLimits struct {
+	// Maximum nesting depth of JSON objects and arrays in the request envelope
+	MaxJSONDepth int
+	// Maximum number of top-level keys in variables
+	MaxVariables int
+}
+
+type requestParseLimitsKey struct{}
+
+// WithRequestParseLimits is middleware function to limit request envelope decoding
+// in order to defend against decoder amplification attacks.
+func WithRequestParseLimits(limits RequestParseLimits) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, requestParseLimitsKey{}, limits), nil
+	}
+}
+
+func requestParseLimitsFromContext(ctx context.Context) RequestParseLimits {
+	limits, _ := ctx.Value(requestParseLimitsKey{}).(RequestParseLimits) // nolint: errcheck
+	return limits
+}
+
+// Errors of parsing graphql request, RequestParseError which is returned from ParseGraphqlRequest wraps one of them
+var (
+	ErrRequestMethod    = errors.New("invalid request method")
+	ErrRequestBody      = errors.New("malformed request body")
+	ErrRequestDepth     = errors.New("request JSON exceeds maximum nesting depth")
+	ErrRequestVariables = errors.New("malformed variables")
+	ErrTooManyVariables = errors.New("too many variables")
+)
+
+// RequestParseError is an error of parsing graphql request, use errors.Is to check the kind of error
+type RequestParseError struct {
+	// Err is the kind of error like ErrRequestVariables
+	Err     error
+	Message string
+}
+
+func (e *RequestParseError) Error() string {
+	return e.Message
+}
+
+func (e *RequestParseError) Unwrap() error {
+	return e.Err
+}
+
+func newRequestParseError(err error, message string) *RequestParseError {
+	return &RequestParseError{
+		Err:     err,
+		Message: message,
+	}
+}
+
+// ParseGraphqlRequest parses graphql query and variables from each request methods in the same way as ServeMux,
+// so that handlers which serve GraphQL outside the gateway accept the same requests.
+// Variables may be sent as JSON encoded string, and GET request can have operationName and variables query parameters.
+// Zero value of limits means unlimited, and returned error is always *RequestParseError.
+func ParseGraphqlRequest(r *http.Request, limits RequestParseLimits) (*GraphqlRequest, error) {
 	var body []byte
 
 	// Get request body
@@ -25,26 +92,123 @@ func parseRequest(r *http.Request) (*GraphqlRequest, error) {
 	case http.MethodPost:
 		buf, err := ioutil.ReadAll(r.Body)
 		if err != nil {
-			return nil, errors.New("malformed request body, " + err.Error())
+			return nil, newRequestParseError(ErrRequestBody, "malformed request body, "+err.Error())
 		}
 		body = buf
 	case http.MethodGet:
 		body = []byte(r.URL.Query().Get("query"))
 	default:
-		return nil, errors.New("invalid request method: '" + r.Method + "'")
+		return nil, newRequestParseError(ErrRequestMethod, "invalid request method: '"+r.Method+"'")
+	}
+
+	if limits.MaxJSONDepth > 0 && jsonDepthExceeds(body, limits.MaxJSONDepth) {
+		return nil, newRequestParseError(ErrRequestDepth,
+			fmt.Sprintf("request JSON exceeds maximum nesting depth %d", limits.MaxJSONDepth))
 	}
 
 	// And try to parse
 	var req GraphqlRequest
-	if err := json.Unmarshal(body, &req); err != nil {
+	var envelope requestEnvelope
+	if err := json.Unmarshal(body, &envelope); err != nil {
 		// If error, the request body may come with single query line
 		req.Query = string(body)
+		if r.Method == http.MethodGet {
+			query := r.URL.Query()
+			req.OperationName = query.Get("operationName")
+			envelope.Variables = json.RawMessage(query.Get("variables"))
+		}
+	} else {
+		req = envelope.GraphqlRequest
+	}
+	variables, err := decodeVariables(envelope.Variables, limits)
+	if err != nil {
+		return nil, err
+	}
+	req.Variables = variables
+	if limits.MaxVariables > 0 && len(req.Variables) > limits.MaxVariables {
+		return nil, newRequestParseError(ErrTooManyVariables,
+			fmt.Sprintf("request has %d variables which exceeds maximum %d", len(req.Variables), limits.MaxVariables))
 	}
 	return &req, nil
 }
 
+// requestEnvelope is JSON request body whose variables are decoded by decodeVariables
+type requestEnvelope struct {
+	GraphqlRequest
+	Variables json.RawMessage `json:"variables"`
+}
+
+// decodeVariables decodes variables which are JSON object or null,
+// or JSON encoded string of them which many clients and curl users send.
+func decodeVariables(raw json.RawMessage, limits RequestParseLimits) (map[string]interface{}, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return nil, nil
+	}
+	if raw[0] == '"' {
+		var encoded string
+		if err := json.Unmarshal(raw, &encoded); err != nil {
+			return nil, newRequestParseError(ErrRequestVariables, "malformed variables, "+err.Error())
+		}
+		raw = bytes.TrimSpace([]byte(encoded))
+		if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+			return nil, nil
+		}
+		// Encoded variables are nested in the request envelope
+		if limits.MaxJSONDepth > 0 && jsonDepthExceeds(raw, limits.MaxJSONDepth-1) {
+			return nil, newRequestParseError(ErrRequestDepth,
+				fmt.Sprintf("request JSON exceeds maximum nesting depth %d", limits.MaxJSONDepth))
+		}
+	}
+	if raw[0] != '{' {
+		return nil, newRequestParseError(ErrRequestVariables, "variables must be a JSON object, or a string of JSON encoded object")
+	}
+	var variables map[string]interface{}
+	if err := json.Unmarshal(raw, &variables); err != nil {
+		return nil, newRequestParseError(ErrRequestVariables, "malformed variables, "+err.Error())
+	}
+	return variables, nil
+}
+
+// jsonDepthExceeds reports whether JSON nesting depth in body exceeds limit.
+// Tokens are read in streaming so that decoding stops as soon as the limit is exceeded.
+// If body is not a valid JSON (e.g. raw query string), returns false.
+func jsonDepthExceeds(body []byte, limit int) bool {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	var depth int
+	for {
+		t, err := dec.Token()
+		if err != nil {
+			return false
+		}
+		d, ok := t.(json.Delim)
+		if !ok {
+			continue
+		}
+		switch d {
+		case '{', '[':
+			depth++
+			if depth > limit {
+				return true
+			}
+		default:
+			depth--
+		}
+	}
+}
+
 // MarshalRequest marshals graphql request arguments to gRPC request message
 func MarshalRequest(args, v interface{}, isCamel bool) error {
+	return marshalRequest(args, v, isCamel, false)
+}
+
+// MarshalInput marshals graphql input object argument to gRPC request message,
+// the input must provide exactly one member if the message is @oneOf input which consists of a single oneof
+func MarshalInput(input, v interface{}, isCamel bool) error {
+	return marshalRequest(input, v, isCamel, true)
+}
+
+func marshalRequest(args, v interface{}, isCamel, input bool) error {
 	if args == nil {
 		return errors.New("Resolved params should be non-nil")
 	}
@@ -55,11 +219,91 @@ func MarshalRequest(args, v interface{}, isCamel bool) error {
 	if isCamel {
 		m = toLowerCaseKeys(m)
 	}
+	msg, isMessage := v.(proto.Message)
+	if isMessage {
+		if err := checkOneofs(msg.ProtoReflect().Descriptor(), m, protoFieldName, input); err != nil {
+			return err
+		}
+	}
+	if t := reflect.TypeOf(v); t != nil {
+		m = entriesToMaps(m, t).(map[string]interface{}) // nolint: errcheck
+	}
 	buf, err := json.Marshal(m)
 	if err != nil {
 		return err
 	}
-	return json.Unmarshal(buf, &v)
+	if err := json.Unmarshal(buf, &v); err != nil {
+		return err
+	}
+	if isMessage {
+		return setOneofMembers(msg.ProtoReflect(), m)
+	}
+	return nil
+}
+
+// entriesToMaps converts lists of key-value entries to objects where the field of type t is protobuf map,
+// because maps are exposed as lists of entry input objects in GraphQL.
+// Struct fields are looked up by the name of json tag which is the same as the argument name.
+func entriesToMaps(v interface{}, t reflect.Type) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		fields := jsonFieldTypes(t)
+		out := make(map[string]interface{}, len(m))
+		for k, vv := range m {
+			if ft, ok := fields[k]; ok {
+				vv = entriesToMaps(vv, ft)
+			}
+			out[k] = vv
+		}
+		return out
+	case reflect.Slice:
+		items, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		out := make([]interface{}, len(items))
+		for i, item := range items {
+			out[i] = entriesToMaps(item, t.Elem())
+		}
+		return out
+	case reflect.Map:
+		entries, ok := v.([]interface{})
+		if !ok {
+			return v
+		}
+		out := make(map[string]interface{}, len(entries))
+		for _, e := range entries {
+			entry, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			out[fmt.Sprint(entry["key"])] = entriesToMaps(entry["value"], t.Elem())
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+// jsonFieldTypes returns types of exported struct fields by the name of json tag
+func jsonFieldTypes(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = f.Type
+	}
+	return fields
 }
 
 // Convert to lower case keyname string
@@ -0,0 +1,72 @@
+//go:build go1.18
+// +build go1.18
+
+package runtime
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+// fuzzParseLimits are limits of fuzzing which are small enough to be hit by generated inputs
+var fuzzParseLimits = RequestParseLimits{
+	MaxJSONDepth: 8,
+	MaxVariables: 4,
+}
+
+func assertParsedRequest(t *testing.T, req *GraphqlRequest, err error) {
+	if err != nil {
+		var pe *RequestParseError
+		if !errors.As(err, &pe) || pe.Err == nil {
+			t.Fatalf("error must be RequestParseError with kind: %#v", err)
+		}
+		return
+	}
+	if req == nil {
+		t.Fatal("request must not be nil without error")
+	}
+	if len(req.Variables) > fuzzParseLimits.MaxVariables {
+		t.Fatalf("variables exceed limit: %d", len(req.Variables))
+	}
+}
+
+func FuzzParseGraphqlRequestBody(f *testing.F) {
+	for _, seed := range []string{
+		`{ hello }`,
+		`{"query":"{ hello }"}`,
+		`{"query":"query Get($id: ID) { user(id: $id) { id } }","operationName":"Get","variables":{"id":"1"}}`,
+		`{"query":"{ hello }","variables":"{\"id\":\"1\"}"}`,
+		`{"query":"{ hello }","variables":null}`,
+		`{"id":"persisted","extensions":{"persistedQuery":{"version":1}}}`,
+		`{"variables":{"a":{"b":{"c":[[[[[[1]]]]]]}}}}`,
+		``,
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, body string) {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+		req, err := ParseGraphqlRequest(r, fuzzParseLimits)
+		assertParsedRequest(t, req, err)
+	})
+}
+
+func FuzzParseGraphqlRequestQuery(f *testing.F) {
+	f.Add(`{ hello }`, "", "")
+	f.Add(`query Get($id: ID) { user(id: $id) { id } }`, "Get", `{"id":"1"}`)
+	f.Add(`{"query":"{ hello }"}`, "", `"{}"`)
+	f.Add(`{ hello }`, "", `[1]`)
+	f.Fuzz(func(t *testing.T, query, operationName, variables string) {
+		values := url.Values{}
+		values.Set("query", query)
+		values.Set("operationName", operationName)
+		values.Set("variables", variables)
+		r := httptest.NewRequest(http.MethodGet, "/graphql?"+values.Encode(), nil)
+		req, err := ParseGraphqlRequest(r, fuzzParseLimits)
+		assertParsedRequest(t, req, err)
+	})
+}
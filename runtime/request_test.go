@@ -1,8 +1,13 @@
 package runtime
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
+	"net/http"
+	"net/http/httptest"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -102,3 +107,136 @@ func TestMarshalRequestWithCamelCaseInput(t *testing.T) {
 	assert.NoError(t, err)
 	assertStruct(t, v)
 }
+
+type Library struct {
+	Shelves map[string]*Shelf `json:"shelves,omitempty"`
+}
+
+type Shelf struct {
+	Name  string       `json:"name,omitempty"`
+	Books map[int32]*C `json:"books,omitempty"`
+}
+
+func TestMarshalRequestWithMapEntries(t *testing.T) {
+	for _, isCamel := range []bool{false, true} {
+		data := map[string]interface{}{
+			"shelves": []interface{}{
+				map[string]interface{}{
+					"key": "sf",
+					"value": map[string]interface{}{
+						"name": "Sci-Fi",
+						"books": []interface{}{
+							map[string]interface{}{"key": 1, "value": map[string]interface{}{"string_value": "Dune"}},
+							map[string]interface{}{"key": 2, "value": map[string]interface{}{"string_value": "Dune Messiah"}},
+						},
+					},
+				},
+			},
+		}
+		var v *Library
+		err := MarshalRequest(data, &v, isCamel)
+		assert.NoError(t, err)
+		if assert.Contains(t, v.Shelves, "sf") {
+			assert.Equal(t, "Sci-Fi", v.Shelves["sf"].Name)
+			assert.Equal(t, map[int32]*C{
+				1: {StringValue: "Dune"},
+				2: {StringValue: "Dune Messiah"},
+			}, v.Shelves["sf"].Books)
+		}
+	}
+}
+
+func TestParseRequestLimits(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	}
+
+	t.Run("Raw query is not treated as JSON", func(t *testing.T) {
+		req, err := ParseGraphqlRequest(newRequest(`{ user { friends { id } } }`), RequestParseLimits{MaxJSONDepth: 1})
+		assert.NoError(t, err)
+		assert.Equal(t, `{ user { friends { id } } }`, req.Query)
+	})
+
+	t.Run("Reject deeply nested JSON", func(t *testing.T) {
+		body := `{"query":"{ hello }","variables":{"a":{"b":{"c":[1]}}}}`
+		_, err := ParseGraphqlRequest(newRequest(body), RequestParseLimits{MaxJSONDepth: 4})
+		assert.Error(t, err)
+		req, err := ParseGraphqlRequest(newRequest(body), RequestParseLimits{MaxJSONDepth: 5})
+		assert.NoError(t, err)
+		assert.Equal(t, "{ hello }", req.Query)
+	})
+
+	t.Run("Reject too many variables", func(t *testing.T) {
+		body := `{"query":"{ hello }","variables":{"a":1,"b":2,"c":3}}`
+		_, err := ParseGraphqlRequest(newRequest(body), RequestParseLimits{MaxVariables: 2})
+		assert.Error(t, err)
+		_, err = ParseGraphqlRequest(newRequest(body), RequestParseLimits{MaxVariables: 3})
+		assert.NoError(t, err)
+	})
+}
+
+func TestParseRequestVariables(t *testing.T) {
+	newRequest := func(body string) *http.Request {
+		return httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body))
+	}
+
+	for body, expected := range map[string]map[string]interface{}{
+		`{"query":"{ hello }","variables":{"id":"1"}}`:         {"id": "1"},
+		`{"query":"{ hello }","variables":"{\"id\":\"1\"}"}`:   {"id": "1"},
+		`{"query":"{ hello }","variables":" {\"id\":\"1\"} "}`: {"id": "1"},
+		`{"query":"{ hello }","variables":null}`:               nil,
+		`{"query":"{ hello }","variables":"null"}`:             nil,
+		`{"query":"{ hello }","variables":""}`:                 nil,
+		`{"query":"{ hello }"}`:                                nil,
+	} {
+		req, err := ParseGraphqlRequest(newRequest(body), RequestParseLimits{})
+		if assert.NoError(t, err, body) {
+			assert.Equal(t, "{ hello }", req.Query)
+			assert.Equal(t, expected, req.Variables, body)
+		}
+	}
+
+	for body, message := range map[string]string{
+		`{"query":"{ hello }","variables":[1]}`:        "variables must be a JSON object",
+		`{"query":"{ hello }","variables":"[1]"}`:      "variables must be a JSON object",
+		`{"query":"{ hello }","variables":"{\"id\":"}`: "malformed variables",
+		`{"query":"{ hello }","variables":1}`:          "variables must be a JSON object",
+	} {
+		_, err := ParseGraphqlRequest(newRequest(body), RequestParseLimits{})
+		if assert.Error(t, err, body) {
+			assert.Contains(t, err.Error(), message)
+		}
+	}
+
+	t.Run("Encoded variables are limited", func(t *testing.T) {
+		body := `{"query":"{ hello }","variables":"{\"a\":{\"b\":1}}"}`
+		_, err := ParseGraphqlRequest(newRequest(body), RequestParseLimits{MaxJSONDepth: 2})
+		assert.Error(t, err)
+		_, err = ParseGraphqlRequest(newRequest(body), RequestParseLimits{MaxJSONDepth: 3})
+		assert.NoError(t, err)
+	})
+
+	t.Run("Query parameters of GET request", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, `/graphql?query=query+Get($id:ID){hello}&operationName=Get&variables={"id":"1"}`, nil)
+		req, err := ParseGraphqlRequest(r, RequestParseLimits{})
+		if assert.NoError(t, err) {
+			assert.Equal(t, "query Get($id:ID){hello}", req.Query)
+			assert.Equal(t, "Get", req.OperationName)
+			assert.Equal(t, map[string]interface{}{"id": "1"}, req.Variables)
+		}
+	})
+}
+
+func TestParseGraphqlRequestErrors(t *testing.T) {
+	for expected, r := range map[error]*http.Request{
+		ErrRequestMethod:    httptest.NewRequest(http.MethodPut, "/graphql", nil),
+		ErrRequestDepth:     httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"variables":{"a":{"b":1}}}`)),
+		ErrRequestVariables: httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"variables":"{"}`)),
+		ErrTooManyVariables: httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{"variables":{"a":1,"b":2,"c":3}}`)),
+	} {
+		_, err := ParseGraphqlRequest(r, RequestParseLimits{MaxJSONDepth: 2, MaxVariables: 2})
+		if assert.IsType(t, &RequestParseError{}, err) {
+			assert.True(t, errors.Is(err, expected), err.Error())
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package runtime
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Generated object types are package level singletons which are shared between schemas,
+// so resolvers which are already wrapped by resolveField are detected by the code pointer in order not to wrap them repeatedly.
+// This keeps no state out of the types, so types of schemas which are swapped by DynamicSchema can be collected.
+var instrumentedResolver = reflect.ValueOf(resolveField(nil)).Pointer()
+
+func isInstrumented(fn graphql.FieldResolveFn) bool {
+	return fn != nil && reflect.ValueOf(fn).Pointer() == instrumentedResolver
+}
+
+// instrumentFields wraps root field resolvers.
+// Root fields are created whenever the schema is built so we can wrap them without any guards.
+func instrumentFields(fields graphql.Fields) {
+	for _, f := range fields {
+		f.Resolve = resolveField(f.Resolve)
+	}
+}
+
+// instrumentSchema wraps all field resolvers of object types in schema except root and introspection types
+func instrumentSchema(schema *graphql.Schema) {
+	roots := map[graphql.Type]struct{}{}
+	if q := schema.QueryType(); q != nil {
+		roots[q] = struct{}{}
+	}
+	if m := schema.MutationType(); m != nil {
+		roots[m] = struct{}{}
+	}
+
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+
+	for name, t := range schema.TypeMap() {
+		obj, ok := t.(*graphql.Object)
+		if !ok || strings.HasPrefix(name, "__") {
+			continue
+		}
+		if _, ok := roots[obj]; ok {
+			continue
+		}
+		for _, def := range obj.Fields() {
+			if !isInstrumented(def.Resolve) {
+				def.Resolve = resolveField(def.Resolve)
+			}
+		}
+	}
+}
+
+// resolveField wraps field resolver to run request scoped hooks which are stored in the context
+func resolveField(next graphql.FieldResolveFn) graphql.FieldResolveFn {
+	// Default resolver just picks a value from the source,
+	// so only custom resolvers which call upstream gRPC are measured with span and metrics
+	upstream := next != nil
+	if next == nil {
+		next = graphql.DefaultResolveFn
+	}
+	return func(p graphql.ResolveParams) (interface{}, error) {
+		p.Context = attachContext(p.Context)
+		p.Args = sanitizeArgs(p)
+		defer tracerFromContext(p.Context).resolver(p.Info)()
+
+		// Audit denied mutations as well
+		audited := auditMutation(p)
+		if err := authorizeField(p); err != nil {
+			audited(err)
+			return nil, err
+		}
+		resolve := withDirectives(p, next)
+		if !upstream {
+			value, err := resolve(p)
+			if err == nil {
+				value, err = resolveEnum(p, value)
+			}
+			if err == nil {
+				value, err = resolveBytes(p, value)
+			}
+			audited(err)
+			return value, err
+		}
+		defer metricsFromContext(p.Context).resolver(p.Context, p.Info)()
+
+		var end, finish func(error)
+		p.Context, end = startResolverSpan(p.Context, p.Info)
+		p.Context, finish = startAPMResolverSpan(p.Context, p.Info)
+		value, err := resolve(p)
+		if err == nil {
+			value, err = resolveEnum(p, value)
+		}
+		if err == nil {
+			value, err = resolveBytes(p, value)
+		}
+		finish(err)
+		end(err)
+		audited(err)
+		return value, err
+	}
+}
@@ -1,7 +1,9 @@
 package runtime
 
 import (
+	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/iancoleman/strcase"
@@ -129,6 +131,28 @@ func marshalMap(v reflect.Value) []mapValue {
 	return ret
 }
 
+// MapEntries converts protobuf map field value to a list of key-value entries which are sorted by the key.
+// Values are kept as they are, so that nested messages are resolved by the default resolver of the entry type.
+// Values which are not map, like already marshaled by MarshalResponse, are returned as they are.
+func MapEntries(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return v
+	}
+	entries := make([]mapValue, 0, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		entries = append(entries, mapValue{
+			Key:   iter.Key().Interface(),
+			Value: iter.Value().Interface(),
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return fmt.Sprint(entries[i].Key) < fmt.Sprint(entries[j].Key)
+	})
+	return entries
+}
+
 // Get primitive type value
 // Protobuf in Go only use a few scalar types.
 // See: https://developers.google.com/protocol-buffers/docs/proto3#scalar
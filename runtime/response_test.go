@@ -242,3 +242,19 @@ func TestMarshalMap(t *testing.T) {
 	assert.Contains(t, []string{"item01", "item02"}, aa[1].Key)
 	assert.Contains(t, []int64{1, 2}, aa[1].Value)
 }
+
+func TestMapEntries(t *testing.T) {
+	v := MapEntries(map[string]*exampleSubStruct{
+		"b": {SomeData: "B"},
+		"a": {SomeData: "A"},
+	})
+	assert.Equal(t, []mapValue{
+		{Key: "a", Value: &exampleSubStruct{SomeData: "A"}},
+		{Key: "b", Value: &exampleSubStruct{SomeData: "B"}},
+	}, v)
+
+	// Marshaled value is returned as it is
+	marshaled := MarshalResponse(map[string]int64{"item01": 1})
+	assert.Equal(t, marshaled, MapEntries(marshaled))
+	assert.Nil(t, MapEntries(nil))
+}
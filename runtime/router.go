@@ -0,0 +1,58 @@
+package runtime
+
+import (
+	"strings"
+
+	"net/http"
+)
+
+// Router hosts multiple ServeMux on URL paths like "/public/graphql" and "/admin/graphql".
+// Each ServeMux has isolated schema, and router middlewares run before the middlewares of each ServeMux.
+// To share gRPC connections between schemas, register handlers with the same *grpc.ClientConn to each ServeMux.
+type Router struct {
+	middlewares []MiddlewareFunc
+	routes      map[string]*ServeMux
+}
+
+// NewRouter creates Router pointer with shared middlewares
+func NewRouter(ms ...MiddlewareFunc) *Router {
+	return &Router{
+		middlewares: ms,
+		routes:      make(map[string]*ServeMux),
+	}
+}
+
+// Use adds more shared middlewares
+func (rt *Router) Use(ms ...MiddlewareFunc) *Router {
+	rt.middlewares = append(rt.middlewares, ms...)
+	return rt
+}
+
+// Mount mounts ServeMux on the path
+func (rt *Router) Mount(path string, mux *ServeMux) *Router {
+	rt.routes[normalizeRoutePath(path)] = mux
+	return rt
+}
+
+// ServeHTTP implements http.Handler
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	mux, ok := rt.routes[normalizeRoutePath(r.URL.Path)]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	middlewares := make([]MiddlewareFunc, 0, len(rt.middlewares)+len(mux.middlewares))
+	middlewares = append(middlewares, rt.middlewares...)
+	middlewares = append(middlewares, mux.middlewares...)
+	mux.serve(w, r, middlewares)
+}
+
+func normalizeRoutePath(path string) string {
+	if path != "/" {
+		path = strings.TrimSuffix(path, "/")
+	}
+	if !strings.HasPrefix(path, "/") {
+		path = "/" + path
+	}
+	return path
+}
@@ -0,0 +1,46 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRouter(t *testing.T) {
+	var called []string
+	trace := func(name string) MiddlewareFunc {
+		return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+			called = append(called, name)
+			return ctx, nil
+		}
+	}
+
+	public := NewServeMux(trace("public"))
+	assert.NoError(t, public.AddHandler(newTenantHandler("public")))
+	admin := NewServeMux(trace("admin"))
+	assert.NoError(t, admin.AddHandler(newTenantHandler("admin")))
+
+	router := NewRouter(trace("shared")).
+		Mount("/public/graphql", public).
+		Mount("/admin/graphql/", admin)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{ tenant }`)))
+		return w
+	}
+
+	assert.Contains(t, serve("/public/graphql").Body.String(), `"tenant":"public"`)
+	assert.Equal(t, []string{"shared", "public"}, called)
+
+	called = nil
+	assert.Contains(t, serve("/admin/graphql").Body.String(), `"tenant":"admin"`)
+	assert.Equal(t, []string{"shared", "admin"}, called)
+
+	assert.Equal(t, http.StatusNotFound, serve("/graphql").Code)
+}
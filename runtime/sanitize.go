@@ -0,0 +1,103 @@
+package runtime
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+	"golang.org/x/text/unicode/norm"
+)
+
+// StringSanitizer normalizes string argument value before it is copied into gRPC request message
+type StringSanitizer func(s string) string
+
+// TrimSpace removes leading and trailing white spaces
+func TrimSpace(s string) string {
+	return strings.TrimSpace(s)
+}
+
+// NormalizeNFC normalizes the string to Unicode Normalization Form C,
+// so that visually same strings are sent to upstreams in the same bytes
+func NormalizeNFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// StripControlCharacters removes control characters except tab and line breaks
+func StripControlCharacters(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// Sanitizers are built-in sanitizers by name which are enabled by "sanitize" of the config file
+var Sanitizers = map[string]StringSanitizer{
+	"trim_space":    TrimSpace,
+	"nfc":           NormalizeNFC,
+	"strip_control": StripControlCharacters,
+}
+
+// SanitizeConfig declares sanitizers of string arguments
+type SanitizeConfig struct {
+	// Sanitizers which are applied to string arguments of all fields
+	Global []StringSanitizer
+	// Sanitizers per field like "Mutation.createUser" which are applied after global ones
+	Fields map[string][]StringSanitizer
+}
+
+type sanitizeKey struct{}
+
+// WithArgumentSanitizer is middleware function to sanitize string arguments before resolving fields,
+// so that both generated handlers and DynamicHandler copy sanitized values into gRPC request messages.
+// Strings in input objects and lists are sanitized as well, and field authorizers also see sanitized arguments.
+func WithArgumentSanitizer(config SanitizeConfig) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, sanitizeKey{}, config), nil
+	}
+}
+
+// sanitizeArgs returns arguments of the field which are sanitized by the config in the context
+func sanitizeArgs(p graphql.ResolveParams) map[string]interface{} {
+	config, ok := p.Context.Value(sanitizeKey{}).(SanitizeConfig)
+	if !ok || len(p.Args) == 0 {
+		return p.Args
+	}
+	sanitizers := config.Global
+	if p.Info.ParentType != nil {
+		sanitizers = append(sanitizers[:len(sanitizers):len(sanitizers)], config.Fields[p.Info.ParentType.Name()+"."+p.Info.FieldName]...)
+	}
+	if len(sanitizers) == 0 {
+		return p.Args
+	}
+	return sanitizeValue(p.Args, sanitizers).(map[string]interface{})
+}
+
+// sanitizeValue returns copy of v whose strings are sanitized
+func sanitizeValue(v interface{}, sanitizers []StringSanitizer) interface{} {
+	switch t := v.(type) {
+	case string:
+		for _, sanitize := range sanitizers {
+			t = sanitize(t)
+		}
+		return t
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(t))
+		for k, vv := range t {
+			out[k] = sanitizeValue(vv, sanitizers)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(t))
+		for i, vv := range t {
+			out[i] = sanitizeValue(vv, sanitizers)
+		}
+		return out
+	default:
+		return v
+	}
+}
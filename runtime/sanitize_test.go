@@ -0,0 +1,57 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizers(t *testing.T) {
+	assert.Equal(t, "a b", TrimSpace(" a b\n"))
+	assert.Equal(t, "\u00e9", NormalizeNFC("e\u0301"))
+	assert.Equal(t, "ab\tc\n", StripControlCharacters("a\x00b\tc\x1b\n"))
+}
+
+func TestArgumentSanitizer(t *testing.T) {
+	t.Run("global", func(t *testing.T) {
+		mux := NewServeMux(WithArgumentSanitizer(SanitizeConfig{
+			Global: []StringSanitizer{StripControlCharacters, TrimSpace, NormalizeNFC},
+		}))
+		assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+		result := serveTestQuery(t, mux, `{ user(id: " cafe\u0301\u0007 ") { id } }`)
+		assert.Len(t, result.Errors, 0)
+		assert.Equal(t, map[string]interface{}{
+			"user": map[string]interface{}{"id": "caf\u00e9"},
+		}, result.Data)
+	})
+
+	t.Run("per field", func(t *testing.T) {
+		mux := NewServeMux(WithArgumentSanitizer(SanitizeConfig{
+			Global: []StringSanitizer{TrimSpace},
+			Fields: map[string][]StringSanitizer{
+				"Query.user": {strings.ToUpper},
+			},
+		}))
+		assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+		result := serveTestQuery(t, mux, `{ user(id: " abc ") { id } }`)
+		assert.Equal(t, map[string]interface{}{
+			"user": map[string]interface{}{"id": "ABC"},
+		}, result.Data)
+	})
+
+	t.Run("nested values", func(t *testing.T) {
+		v := sanitizeValue(map[string]interface{}{
+			"input": map[string]interface{}{
+				"names": []interface{}{" a ", 1},
+			},
+		}, []StringSanitizer{TrimSpace})
+		assert.Equal(t, map[string]interface{}{
+			"input": map[string]interface{}{
+				"names": []interface{}{"a", 1},
+			},
+		}, v)
+	})
+}
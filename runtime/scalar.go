@@ -0,0 +1,133 @@
+package runtime
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+var namePattern = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// customScalar is the scalar of graphql.field scalar option, whose coercion is replaced by RegisterScalar
+type customScalar struct {
+	mu           sync.RWMutex
+	scalar       *graphql.Scalar
+	serialize    graphql.SerializeFn
+	parseValue   graphql.ParseValueFn
+	parseLiteral graphql.ParseLiteralFn
+}
+
+// Custom scalars are shared by name in the process like generated types, because a schema must not contain
+// different types with the same name
+var (
+	customScalarsMu sync.Mutex
+	customScalars   = make(map[string]*customScalar)
+)
+
+// CustomScalar returns the scalar of the name which generated code and dynamic handlers use for fields
+// of graphql.field scalar option. Values pass through as they are until coercion is registered by ServeMux.RegisterScalar.
+func CustomScalar(name string) *graphql.Scalar {
+	return customScalarOf(name).scalar
+}
+
+func customScalarOf(name string) *customScalar {
+	customScalarsMu.Lock()
+	defer customScalarsMu.Unlock()
+	if c, ok := customScalars[name]; ok {
+		return c
+	}
+	c := &customScalar{
+		serialize:    passThroughValue,
+		parseValue:   passThroughValue,
+		parseLiteral: literalValue,
+	}
+	c.scalar = graphql.NewScalar(graphql.ScalarConfig{
+		Name: name,
+		Serialize: func(value interface{}) interface{} {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			return c.serialize(value)
+		},
+		ParseValue: func(value interface{}) interface{} {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			return c.parseValue(value)
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			c.mu.RLock()
+			defer c.mu.RUnlock()
+			return c.parseLiteral(valueAST)
+		},
+	})
+	customScalars[name] = c
+	return c
+}
+
+// RegisterScalar supplies coercion of the custom scalar which fields of graphql.field scalar option are exposed as.
+// serialize converts the field value to the response value, parseValue converts the variable value
+// and parseLiteral converts the literal in the query to the argument value, which is marshaled to the request field.
+// Nil function keeps passing values through. Custom scalars are shared by name in the process,
+// so the coercion applies to all ServeMux which serve the scalar.
+func (s *ServeMux) RegisterScalar(
+	name string,
+	serialize graphql.SerializeFn,
+	parseValue graphql.ParseValueFn,
+	parseLiteral graphql.ParseLiteralFn,
+) error {
+	if s.built {
+		return ErrServeMuxBuilt
+	}
+	if !namePattern.MatchString(name) {
+		return errors.New("invalid scalar name: '" + name + "'")
+	}
+	if _, ok := builtinScalars[name]; ok || name == Bytes.Name() {
+		return errors.New("builtin scalar " + name + " cannot be registered")
+	}
+
+	c := customScalarOf(name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.serialize = passThroughValue
+	if serialize != nil {
+		c.serialize = serialize
+	}
+	c.parseValue = passThroughValue
+	if parseValue != nil {
+		c.parseValue = parseValue
+	}
+	c.parseLiteral = literalValue
+	if parseLiteral != nil {
+		c.parseLiteral = parseLiteral
+	}
+	return nil
+}
+
+func passThroughValue(value interface{}) interface{} {
+	return value
+}
+
+// literalValue converts scalar literal to the value as it is, lists and objects are invalid
+func literalValue(valueAST ast.Value) interface{} {
+	switch v := valueAST.(type) {
+	case *ast.StringValue:
+		return v.Value
+	case *ast.BooleanValue:
+		return v.Value
+	case *ast.IntValue:
+		if i, err := strconv.ParseInt(v.Value, 10, 64); err == nil {
+			return i
+		}
+		return nil
+	case *ast.FloatValue:
+		if f, err := strconv.ParseFloat(v.Value, 64); err == nil {
+			return f
+		}
+		return nil
+	default:
+		return nil
+	}
+}
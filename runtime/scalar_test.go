@@ -0,0 +1,122 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/stretchr/testify/assert"
+)
+
+const testScalarFile = `
+name: "scalar.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "graphql.proto"
+message_type {
+  name: "Loan"
+  field { name: "id" number: 1 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "id" options { [graphql.field] { scalar: "TestUUID" } } }
+  field { name: "ids" number: 2 type: TYPE_STRING label: LABEL_REPEATED json_name: "ids" options { [graphql.field] { scalar: "TestUUID" } } }
+  field { name: "note" number: 3 type: TYPE_STRING label: LABEL_OPTIONAL json_name: "note" }
+}
+service {
+  name: "LoanService"
+  method { name: "GetLoan" input_type: ".library.v1.Loan" output_type: ".library.v1.Loan" }
+}
+`
+
+func TestDynamicCustomScalarFields(t *testing.T) {
+	file := testDynamicFile(t, "scalar.proto", testScalarFile)
+	handlers, err := NewDynamicHandlers(nil, file.Services().Get(0))
+	if !assert.NoError(t, err) {
+		return
+	}
+	query := handlers[0].GetQueries(nil)["getLoan"]
+
+	fields := query.Type.(*graphql.Object).Fields()
+	assert.Equal(t, CustomScalar("TestUUID"), fields["id"].Type)
+	assert.Equal(t, "[TestUUID]", fields["ids"].Type.String())
+	assert.Equal(t, graphql.String, fields["note"].Type)
+	assert.Equal(t, CustomScalar("TestUUID"), query.Args["id"].Type)
+}
+
+func TestServeMuxRegisterScalar(t *testing.T) {
+	schema, err := graphql.NewSchema(graphql.SchemaConfig{
+		Query: graphql.NewObject(graphql.ObjectConfig{
+			Name: "Query",
+			Fields: graphql.Fields{
+				"echo": &graphql.Field{
+					Type: CustomScalar("TestDecimal"),
+					Args: graphql.FieldConfigArgument{
+						"value": &graphql.ArgumentConfig{Type: CustomScalar("TestDecimal")},
+					},
+					Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+						return p.Args["value"], nil
+					},
+				},
+			},
+		}),
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+	execute := func(query string, variables map[string]interface{}) *graphql.Result {
+		return graphql.Do(graphql.Params{
+			Schema:         schema,
+			RequestString:  query,
+			VariableValues: variables,
+		})
+	}
+
+	// Values pass through until coercion is registered
+	result := execute(`{ echo(value: "1.50") }`, nil)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{"echo": "1.50"}, result.Data)
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.RegisterScalar("TestDecimal",
+		func(value interface{}) interface{} {
+			return "D" + value.(string)
+		},
+		func(value interface{}) interface{} {
+			s, ok := value.(string)
+			if !ok {
+				return nil
+			}
+			return strings.TrimPrefix(s, "D")
+		},
+		func(valueAST ast.Value) interface{} {
+			v, ok := valueAST.(*ast.StringValue)
+			if !ok {
+				return nil
+			}
+			return strings.TrimPrefix(v.Value, "D")
+		},
+	))
+
+	result = execute(`{ echo(value: "D1.50") }`, nil)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{"echo": "D1.50"}, result.Data)
+
+	result = execute(`query Echo($v: TestDecimal) { echo(value: $v) }`, map[string]interface{}{"v": "D2"})
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{"echo": "D2"}, result.Data)
+
+	result = execute(`{ echo(value: 1) }`, nil)
+	assert.Len(t, result.Errors, 1)
+
+	// Nil functions restore pass through
+	assert.NoError(t, mux.RegisterScalar("TestDecimal", nil, nil, nil))
+	result = execute(`{ echo(value: "1.50") }`, nil)
+	assert.Equal(t, map[string]interface{}{"echo": "1.50"}, result.Data)
+
+	assert.Error(t, mux.RegisterScalar("String", nil, nil, nil))
+	assert.Error(t, mux.RegisterScalar("Bytes", nil, nil, nil))
+	assert.Error(t, mux.RegisterScalar("Test-UUID", nil, nil, nil))
+
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	_, err = mux.Build()
+	assert.NoError(t, err)
+	assert.Equal(t, ErrServeMuxBuilt, mux.RegisterScalar("TestDecimal", nil, nil, nil))
+}
@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"strings"
+
+	"github.com/graphql-go/graphql"
+)
+
+// HandlerSchema is schema of the registered handler
+type HandlerSchema struct {
+	// Service is service name of the handler, or Go type name if the handler doesn't implement ServiceNamer
+	Service string
+	Schema  graphql.Schema
+}
+
+// Schema builds schema which is served for requests of the handlers registered via AddHandler,
+// including renamed, namespaced and grouped root fields.
+// Root fields are not bound to any connection, so the schema is used to describe and validate operations
+// like printing SDL or diffing schemas, not to execute them.
+func (s *ServeMux) Schema() (graphql.Schema, error) {
+	return s.staticSchemaOf(s.handlers)
+}
+
+// TenantSchema builds schema which is served for requests of the tenant, see Schema for details.
+// Returns the same schema as Schema if no handler is registered for the tenant.
+func (s *ServeMux) TenantSchema(tenant string) (graphql.Schema, error) {
+	return s.staticSchemaOf(s.tenantHandlersOf(strings.ToLower(tenant)))
+}
+
+// HandlerSchemas builds schema of each handler registered via AddHandler in registration order, see Schema for details.
+func (s *ServeMux) HandlerSchemas() ([]HandlerSchema, error) {
+	schemas := make([]HandlerSchema, 0, len(s.handlers))
+	for _, h := range s.handlers {
+		// Namespace objects of groups are not shared with served schema which merges groups of all handlers
+		schema, err := (&ServeMux{}).staticSchemaOf([]GraphqlHandler{h})
+		if err != nil {
+			return nil, err
+		}
+		schemas = append(schemas, HandlerSchema{
+			Service: handlerName(h),
+			Schema:  schema,
+		})
+	}
+	return schemas, nil
+}
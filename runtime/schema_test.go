@@ -0,0 +1,51 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeMuxSchema(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	assert.NoError(t, mux.AddHandler(newGroupedTestHandler("invoices", "Invoice", "billing")))
+	assert.NoError(t, mux.AddHandler(newGroupedTestHandler("payments", "Payment", "billing")))
+	assert.NoError(t, mux.AddTenantHandler("Tenant-A.example.com", &testHandler{
+		queries: graphql.Fields{
+			"tenant": &graphql.Field{Type: graphql.String},
+		},
+	}))
+
+	schema, err := mux.Schema()
+	assert.NoError(t, err)
+	fields := schema.QueryType().Fields()
+	assert.Contains(t, fields, "hello")
+	assert.NotContains(t, fields, "tenant")
+	billing := graphql.GetNamed(fields["billing"].Type).(*graphql.Object)
+	assert.Contains(t, billing.Fields(), "invoices")
+	assert.Contains(t, billing.Fields(), "payments")
+
+	schema, err = mux.TenantSchema("tenant-a.example.com")
+	assert.NoError(t, err)
+	assert.Contains(t, schema.QueryType().Fields(), "hello")
+	assert.Contains(t, schema.QueryType().Fields(), "tenant")
+
+	schemas, err := mux.HandlerSchemas()
+	assert.NoError(t, err)
+	if assert.Len(t, schemas, 3) {
+		assert.Equal(t, "*runtime.testHandler", schemas[0].Service)
+		assert.Contains(t, schemas[0].Schema.QueryType().Fields(), "hello")
+		assert.NotContains(t, schemas[0].Schema.QueryType().Fields(), "billing")
+
+		// Grouped fields of other handlers are not included
+		billing := graphql.GetNamed(schemas[2].Schema.QueryType().Fields()["billing"].Type).(*graphql.Object)
+		assert.NotContains(t, billing.Fields(), "invoices")
+		assert.Contains(t, billing.Fields(), "payments")
+	}
+
+	// Served schema is not affected by schemas of handlers
+	result := serveTestQuery(t, mux, `{ billing { invoices payments } }`)
+	assert.Len(t, result.Errors, 0)
+}
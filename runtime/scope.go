@@ -0,0 +1,177 @@
+package runtime
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"google.golang.org/grpc/metadata"
+)
+
+// requestScope holds request scoped state which is shared between ServeMux, middlewares and resolvers
+type requestScope struct {
+	mu             sync.Mutex
+	extensions     map[string]interface{}
+	request        *GraphqlRequest
+	operationName  string
+	upstreams      map[string]string
+	redactPatterns []string
+	headers        []*metadata.MD
+	trailers       []*metadata.MD
+	finishers      []func(*graphql.Result)
+}
+
+type requestScopeKey struct{}
+
+func withRequestScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, requestScopeKey{}, &requestScope{
+		extensions: make(map[string]interface{}),
+		upstreams:  make(map[string]string),
+	})
+}
+
+// scopeFromContext returns request scope in the context, or nil if the context is not derived from ServeMux request
+func scopeFromContext(ctx context.Context) *requestScope {
+	s, _ := ctx.Value(requestScopeKey{}).(*requestScope) // nolint: errcheck
+	return s
+}
+
+// onFinish registers function which is called with the result right before responding.
+// Registered functions are called in reverse order like defer statement.
+func onFinish(ctx context.Context, fn func(*graphql.Result)) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.finishers = append(s.finishers, fn)
+}
+
+// finishRequest calls all functions which are registered via onFinish
+func finishRequest(ctx context.Context, result *graphql.Result) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	finishers := s.finishers
+	s.finishers = nil
+	s.mu.Unlock()
+
+	for i := len(finishers) - 1; i >= 0; i-- {
+		finishers[i](result)
+	}
+}
+
+// setRequest stores parsed GraphQL request to the scope
+func setRequest(ctx context.Context, req *GraphqlRequest) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.request = req
+}
+
+// requestFromContext returns parsed GraphQL request, or nil if the request has not been parsed yet
+func requestFromContext(ctx context.Context) *GraphqlRequest {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.request
+}
+
+// Number of hex digits of the document hash in the label of anonymous operation
+const documentHashLength = 12
+
+// setDocumentOperation stores the name of the operation in the document if the request doesn't specify operation name
+// and the document has only one operation, so that the operation is named in metrics, logs and spans
+func setDocumentOperation(ctx context.Context, doc *ast.Document) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return
+	}
+	var ops []*ast.OperationDefinition
+	for _, d := range doc.Definitions {
+		if op, ok := d.(*ast.OperationDefinition); ok {
+			ops = append(ops, op)
+		}
+	}
+	if len(ops) != 1 || ops[0].Name == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.operationName = ops[0].Name.Value
+}
+
+// OperationNameFromContext returns GraphQL operation name of the request,
+// or the name of the only operation in the document if the request doesn't specify it.
+// Note that operation name is empty in middlewares because the request body is not parsed yet.
+func OperationNameFromContext(ctx context.Context) string {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return ""
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.request == nil {
+		return ""
+	}
+	if s.request.OperationName != "" {
+		return s.request.OperationName
+	}
+	return s.operationName
+}
+
+// OperationLabelFromContext returns the label of the operation which metrics, logs and spans are tagged with.
+// The label is operation name, or "anonymous:" and the head of SHA-256 hash of the document for anonymous operation,
+// so that traffic can be broken down per operation even if clients don't name operations.
+// Returns empty string if the request has not been parsed yet.
+func OperationLabelFromContext(ctx context.Context) string {
+	if name := OperationNameFromContext(ctx); name != "" {
+		return name
+	}
+	req := requestFromContext(ctx)
+	if req == nil || req.Query == "" {
+		return ""
+	}
+	return "anonymous:" + documentHash(req.Query)
+}
+
+// documentHash returns the head of hex encoded SHA-256 hash of the document
+func documentHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])[:documentHashLength]
+}
+
+// setUpstream stores gRPC target which serves the root field
+func setUpstream(ctx context.Context, field, target string) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.upstreams[field] = target
+}
+
+// upstreamFromContext returns gRPC target which serves the root field
+func upstreamFromContext(ctx context.Context, field string) (string, bool) {
+	s := scopeFromContext(ctx)
+	if s == nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	target, ok := s.upstreams[field]
+	return target, ok
+}
@@ -0,0 +1,173 @@
+package runtime
+
+import (
+	"context"
+	"reflect"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// handlerMiddlewares are middlewares which are scoped to the registered handler
+type handlerMiddlewares struct {
+	handler     GraphqlHandler
+	middlewares []MiddlewareFunc
+}
+
+// scopedMiddlewares is request scoped middlewares by root field, which run only if the field is selected
+type scopedMiddlewares struct {
+	w http.ResponseWriter
+	r *http.Request
+	// Middlewares of each handler in registration order
+	chains [][]MiddlewareFunc
+	// Index of the chain by root field name, or "<group>.<field>" for grouped fields
+	fields map[string]int
+}
+
+// AddHandlerWithMiddlewares registers graphql handler like AddHandler,
+// and middlewares which run only when the operation selects any root field of the handler, e.g. stricter authentication
+// only for fields of the admin service. Scoped middlewares run after the document is validated,
+// following global middlewares, and they must not write the response.
+func (s *ServeMux) AddHandlerWithMiddlewares(h GraphqlHandler, ms ...MiddlewareFunc) error {
+	if err := s.AddHandler(h); err != nil {
+		return err
+	}
+	if len(ms) > 0 {
+		// AddHandler may wrap the handler, scope middlewares to the registered one
+		s.scoped = append(s.scoped, handlerMiddlewares{
+			handler:     s.handlers[len(s.handlers)-1],
+			middlewares: ms,
+		})
+	}
+	return nil
+}
+
+// middlewaresOf returns scoped middlewares of the handler
+func (s *ServeMux) middlewaresOf(h GraphqlHandler) []MiddlewareFunc {
+	// Handlers whose type is not comparable can't be registered with middlewares
+	if !reflect.TypeOf(h).Comparable() {
+		return nil
+	}
+	for _, hm := range s.scoped {
+		if hm.handler == h {
+			return hm.middlewares
+		}
+	}
+	return nil
+}
+
+func newScopedMiddlewares(w http.ResponseWriter, r *http.Request) *scopedMiddlewares {
+	return &scopedMiddlewares{
+		w:      w,
+		r:      r,
+		fields: make(map[string]int),
+	}
+}
+
+// add scopes middlewares to the root fields of the handler, grouped fields are scoped under the namespace field
+func (sm *scopedMiddlewares) add(ms []MiddlewareFunc, fields []string, groups map[string]string) {
+	if len(ms) == 0 || len(fields) == 0 {
+		return
+	}
+	sm.chains = append(sm.chains, ms)
+	for _, f := range fields {
+		if group, ok := groups[f]; ok {
+			f = group + "." + f
+		}
+		sm.fields[f] = len(sm.chains) - 1
+	}
+}
+
+// run runs middlewares of handlers whose root fields are selected by the operation in the document.
+// Each chain runs once even if the operation selects multiple fields of the handler.
+func (sm *scopedMiddlewares) run(ctx context.Context, serveMux *ServeMux, doc *ast.Document, operationName string) (context.Context, []GraphqlError) {
+	if len(sm.chains) == 0 {
+		return ctx, nil
+	}
+	selected := make([]bool, len(sm.chains))
+	for _, f := range selectedRootFields(doc, operationName) {
+		if i, ok := sm.fields[f]; ok {
+			selected[i] = true
+		}
+	}
+	for i, chain := range sm.chains {
+		if !selected[i] {
+			continue
+		}
+		for _, m := range chain {
+			next, err := m(ctx, serveMux, sm.w, sm.r)
+			if err != nil {
+				return ctx, []GraphqlError{middlewareError(err)}
+			}
+			ctx = next
+		}
+	}
+	return ctx, nil
+}
+
+// selectedRootFields returns root field names of the operation, and "<root>.<field>" names of their sub fields
+// so that fields under namespace objects of groups are matched.
+// Fields of all operations are returned if the operation is not determined.
+func selectedRootFields(doc *ast.Document, operationName string) []string {
+	fragments := collectFragments(doc)
+	var ops []*ast.OperationDefinition
+	if op := selectOperation(doc, operationName); op != nil {
+		ops = append(ops, op)
+	} else {
+		for _, d := range doc.Definitions {
+			if op, ok := d.(*ast.OperationDefinition); ok {
+				ops = append(ops, op)
+			}
+		}
+	}
+
+	var names []string
+	for _, op := range ops {
+		for _, root := range selectionFields(op.SelectionSet, fragments, map[string]struct{}{}) {
+			names = append(names, root.Name.Value)
+			for _, f := range selectionFields(root.SelectionSet, fragments, map[string]struct{}{}) {
+				names = append(names, root.Name.Value+"."+f.Name.Value)
+			}
+		}
+	}
+	return names
+}
+
+// selectionFields returns fields on the same level of the selection set including fragments
+func selectionFields(
+	set *ast.SelectionSet,
+	fragments map[string]*ast.FragmentDefinition,
+	visited map[string]struct{},
+) []*ast.Field {
+
+	if set == nil {
+		return nil
+	}
+	var fields []*ast.Field
+	for _, s := range set.Selections {
+		switch v := s.(type) {
+		case *ast.Field:
+			if v.Name != nil {
+				fields = append(fields, v)
+			}
+		case *ast.InlineFragment:
+			fields = append(fields, selectionFields(v.SelectionSet, fragments, visited)...)
+		case *ast.FragmentSpread:
+			if v.Name == nil {
+				continue
+			}
+			f, ok := fragments[v.Name.Value]
+			if !ok {
+				continue
+			}
+			if _, ok := visited[v.Name.Value]; ok {
+				continue
+			}
+			visited[v.Name.Value] = struct{}{}
+			fields = append(fields, selectionFields(f.SelectionSet, fragments, visited)...)
+			delete(visited, v.Name.Value)
+		}
+	}
+	return fields
+}
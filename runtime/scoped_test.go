@@ -0,0 +1,93 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+type scopedTestKey struct{}
+
+func TestAddHandlerWithMiddlewares(t *testing.T) {
+	var calls int
+	admin := &testHandler{
+		queries: graphql.Fields{
+			"adminStats": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return p.Context.Value(scopedTestKey{}), nil
+				},
+			},
+			"adminUsers": &graphql.Field{
+				Type: graphql.Int,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return 1, nil
+				},
+			},
+		},
+	}
+	requireAdmin := func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		calls++
+		if r.Header.Get("X-Role") != "admin" {
+			return ctx, NewMiddlewareError("PERMISSION_DENIED", "admin role is required")
+		}
+		return context.WithValue(ctx, scopedTestKey{}, "admin"), nil
+	}
+
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	assert.NoError(t, mux.AddHandlerWithMiddlewares(admin, requireAdmin))
+
+	// Fields of other handlers don't run scoped middlewares
+	result := serveTestQuery(t, mux, `{ hello }`)
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, 0, calls)
+
+	for _, query := range []string{
+		`{ hello adminStats }`,
+		`{ ...Stats } fragment Stats on Query { adminStats }`,
+		`{"query":"query A { hello } query B { adminUsers }","operationName":"B"}`,
+	} {
+		result = serveTestQuery(t, mux, query)
+		assert.Nil(t, result.Data)
+		if assert.Len(t, result.Errors, 1, query) {
+			assert.Equal(t, "PERMISSION_DENIED", result.Errors[0].Extensions["code"])
+		}
+	}
+
+	// Middlewares run once per request, and the context is passed to resolvers
+	calls = 0
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ adminStats adminUsers }`))
+	r.Header.Set("X-Role", "admin")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	result = &graphql.Result{}
+	assert.NoError(t, json.NewDecoder(w.Body).Decode(result))
+	assert.Len(t, result.Errors, 0)
+	assert.Equal(t, map[string]interface{}{"adminStats": "admin", "adminUsers": float64(1)}, result.Data)
+	assert.Equal(t, 1, calls)
+}
+
+func TestAddHandlerWithMiddlewaresGrouped(t *testing.T) {
+	deny := func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return ctx, NewMiddlewareError("PERMISSION_DENIED", "denied")
+	}
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newGroupedTestHandler("invoices", "Invoice", "billing")))
+	assert.NoError(t, mux.AddHandlerWithMiddlewares(newGroupedTestHandler("payments", "Payment", "billing"), deny))
+
+	result := serveTestQuery(t, mux, `{ billing { invoices } }`)
+	assert.Len(t, result.Errors, 0)
+
+	result = serveTestQuery(t, mux, `{ billing { payments } }`)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "PERMISSION_DENIED", result.Errors[0].Extensions["code"])
+	}
+}
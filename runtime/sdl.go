@@ -0,0 +1,263 @@
+package runtime
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"encoding/json"
+
+	"github.com/graphql-go/graphql"
+)
+
+// builtinScalars are scalar types which are not printed in SDL
+var builtinScalars = map[string]struct{}{
+	"String":  {},
+	"Int":     {},
+	"Float":   {},
+	"Boolean": {},
+	"ID":      {},
+}
+
+// PrintSchema prints the schema in GraphQL SDL. Types are sorted by name so that the output is stable.
+func PrintSchema(schema *graphql.Schema) string {
+	typeMap := schema.TypeMap()
+	names := make([]string, 0, len(typeMap))
+	for name := range typeMap {
+		if strings.HasPrefix(name, "__") {
+			continue
+		}
+		if _, ok := builtinScalars[name]; ok {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	printSchemaDefinition(&b, schema)
+	for _, name := range names {
+		if b.Len() > 0 {
+			b.WriteString("\n")
+		}
+		printType(&b, typeMap[name])
+	}
+	return b.String()
+}
+
+// printSchemaDefinition prints schema definition only if root types are not named by convention
+func printSchemaDefinition(b *strings.Builder, schema *graphql.Schema) {
+	var roots []string
+	if q := schema.QueryType(); q != nil && q.Name() != "Query" {
+		roots = append(roots, "  query: "+q.Name())
+	}
+	if m := schema.MutationType(); m != nil && m.Name() != "Mutation" {
+		roots = append(roots, "  mutation: "+m.Name())
+	}
+	if len(roots) == 0 {
+		return
+	}
+	b.WriteString("schema {\n" + strings.Join(roots, "\n") + "\n}\n")
+}
+
+func printType(b *strings.Builder, t graphql.Type) {
+	printDescription(b, t.Description(), "")
+	switch v := t.(type) {
+	case *graphql.Scalar:
+		fmt.Fprintf(b, "scalar %s\n", v.Name())
+	case *graphql.Object:
+		fmt.Fprintf(b, "type %s", v.Name())
+		if ifaces := v.Interfaces(); len(ifaces) > 0 {
+			names := make([]string, len(ifaces))
+			for i, iface := range ifaces {
+				names[i] = iface.Name()
+			}
+			b.WriteString(" implements " + strings.Join(names, " & "))
+		}
+		printFields(b, v.Fields())
+	case *graphql.Interface:
+		fmt.Fprintf(b, "interface %s", v.Name())
+		printFields(b, v.Fields())
+	case *graphql.Union:
+		members := make([]string, len(v.Types()))
+		for i, m := range v.Types() {
+			members[i] = m.Name()
+		}
+		fmt.Fprintf(b, "union %s = %s\n", v.Name(), strings.Join(members, " | "))
+	case *graphql.Enum:
+		// Enum values are defined from map, sort them for stable output
+		values := append([]*graphql.EnumValueDefinition{}, v.Values()...)
+		sort.Slice(values, func(i, j int) bool {
+			return values[i].Name < values[j].Name
+		})
+		fmt.Fprintf(b, "enum %s {\n", v.Name())
+		for _, value := range values {
+			printDescription(b, value.Description, "  ")
+			b.WriteString("  " + value.Name + printDeprecated(value.DeprecationReason) + "\n")
+		}
+		b.WriteString("}\n")
+	case *graphql.InputObject:
+		fields := v.Fields()
+		names := make([]string, 0, len(fields))
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		fmt.Fprintf(b, "input %s", v.Name())
+		if isOneofInputName(v.Name()) {
+			b.WriteString(" @oneOf")
+		}
+		b.WriteString(" {\n")
+		for _, name := range names {
+			f := fields[name]
+			printDescription(b, f.Description(), "  ")
+			b.WriteString("  " + f.Name() + ": " + f.Type.String() + printDefaultValue(f.DefaultValue, f.Type) + "\n")
+		}
+		b.WriteString("}\n")
+	}
+}
+
+func printFields(b *strings.Builder, fields graphql.FieldDefinitionMap) {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	b.WriteString(" {\n")
+	for _, name := range names {
+		f := fields[name]
+		printDescription(b, f.Description, "  ")
+		b.WriteString("  " + f.Name + printArgs(f.Args) + ": " + f.Type.String() + printDeprecated(f.DeprecationReason) + "\n")
+	}
+	b.WriteString("}\n")
+}
+
+// printArgs prints arguments in single line, or one argument per line if any of them has description
+func printArgs(args []*graphql.Argument) string {
+	if len(args) == 0 {
+		return ""
+	}
+	// Arguments are defined from map, sort them for stable output
+	args = append([]*graphql.Argument{}, args...)
+	sort.Slice(args, func(i, j int) bool {
+		return args[i].Name() < args[j].Name()
+	})
+
+	var multiline bool
+	for _, arg := range args {
+		if arg.Description() != "" {
+			multiline = true
+		}
+	}
+
+	printed := make([]string, len(args))
+	for i, arg := range args {
+		printed[i] = arg.Name() + ": " + arg.Type.String() + printDefaultValue(arg.DefaultValue, arg.Type)
+	}
+	if !multiline {
+		return "(" + strings.Join(printed, ", ") + ")"
+	}
+
+	var b strings.Builder
+	b.WriteString("(\n")
+	for i, arg := range args {
+		printDescription(&b, arg.Description(), "    ")
+		b.WriteString("    " + printed[i] + "\n")
+	}
+	b.WriteString("  )")
+	return b.String()
+}
+
+func printDescription(b *strings.Builder, description, indent string) {
+	if description == "" {
+		return
+	}
+	description = strings.ReplaceAll(description, `"""`, `\"""`)
+	// Quote at the end is joined to the closing quotes in single line
+	if !strings.Contains(description, "\n") && !strings.HasSuffix(description, `"`) {
+		b.WriteString(indent + `"""` + description + `"""` + "\n")
+		return
+	}
+	b.WriteString(indent + `"""` + "\n")
+	for _, line := range strings.Split(description, "\n") {
+		// Keep blank lines of paragraphs and code blocks without trailing spaces
+		if strings.TrimSpace(line) == "" {
+			b.WriteString("\n")
+			continue
+		}
+		b.WriteString(indent + line + "\n")
+	}
+	b.WriteString(indent + `"""` + "\n")
+}
+
+func printDeprecated(reason string) string {
+	if reason == "" {
+		return ""
+	}
+	if reason == graphql.DefaultDeprecationReason {
+		return " @deprecated"
+	}
+	return " @deprecated(reason: " + printString(reason) + ")"
+}
+
+func printDefaultValue(value interface{}, t graphql.Input) string {
+	if value == nil {
+		return ""
+	}
+	return " = " + printValue(value, t)
+}
+
+// printValue prints Go value of the input type as GraphQL literal
+func printValue(value interface{}, t graphql.Input) string {
+	if value == nil {
+		return "null"
+	}
+	switch v := t.(type) {
+	case *graphql.NonNull:
+		return printValue(value, v.OfType)
+	case *graphql.List:
+		rv := reflect.ValueOf(value)
+		if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+			return printValue(value, v.OfType)
+		}
+		items := make([]string, rv.Len())
+		for i := range items {
+			items[i] = printValue(rv.Index(i).Interface(), v.OfType)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case *graphql.Enum:
+		for _, ev := range v.Values() {
+			if reflect.DeepEqual(ev.Value, value) {
+				return ev.Name
+			}
+		}
+	case *graphql.InputObject:
+		if m, ok := value.(map[string]interface{}); ok {
+			fields := v.Fields()
+			keys := make([]string, 0, len(m))
+			for k := range m {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			items := make([]string, 0, len(keys))
+			for _, k := range keys {
+				if f, ok := fields[k]; ok {
+					items = append(items, k+": "+printValue(m[k], f.Type))
+				}
+			}
+			return "{" + strings.Join(items, ", ") + "}"
+		}
+	}
+	if s, ok := value.(string); ok {
+		return printString(s)
+	}
+	return fmt.Sprint(value)
+}
+
+// printString prints GraphQL string literal, whose escape sequences are compatible with JSON
+func printString(s string) string {
+	b, _ := json.Marshal(s) // nolint: errcheck
+	return string(b)
+}
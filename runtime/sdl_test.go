@@ -0,0 +1,97 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintSchema(t *testing.T) {
+	color := graphql.NewEnum(graphql.EnumConfig{
+		Name: "Color",
+		Values: graphql.EnumValueConfigMap{
+			"RED":  &graphql.EnumValueConfig{Value: 0},
+			"BLUE": &graphql.EnumValueConfig{Value: 1, DeprecationReason: "use RED"},
+		},
+	})
+	filter := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "Filter",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"color": &graphql.InputObjectFieldConfig{Type: color, DefaultValue: 0},
+			"tags":  &graphql.InputObjectFieldConfig{Type: graphql.NewList(graphql.String)},
+		},
+	})
+	schema, err := newSchema(graphql.Fields{
+		"user": &graphql.Field{
+			Type:        testUserType,
+			Description: "Get user by id",
+			Args: graphql.FieldConfigArgument{
+				"id": &graphql.ArgumentConfig{Type: graphql.NewNonNull(graphql.String)},
+			},
+		},
+		"search": &graphql.Field{
+			Type:              graphql.NewList(graphql.String),
+			DeprecationReason: graphql.DefaultDeprecationReason,
+			Args: graphql.FieldConfigArgument{
+				"filter": &graphql.ArgumentConfig{
+					Type:         filter,
+					DefaultValue: map[string]interface{}{"color": 1, "tags": []interface{}{"a"}},
+					Description:  "Search filter\nwith tags",
+				},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, `enum Color {
+  BLUE @deprecated(reason: "use RED")
+  RED
+}
+
+input Filter {
+  color: Color = RED
+  tags: [String]
+}
+
+type Query {
+  search(
+    """
+    Search filter
+    with tags
+    """
+    filter: Filter = {color: BLUE, tags: ["a"]}
+  ): [String] @deprecated
+  """Get user by id"""
+  user(id: String!): Test_Type_User
+}
+
+type Test_Type_User {
+  email: String
+  id: String
+}
+`, PrintSchema(&schema))
+}
+
+func TestPrintDescription(t *testing.T) {
+	tests := []struct {
+		description string
+		expect      string
+	}{
+		{description: "Get user", expect: "  \"\"\"Get user\"\"\"\n"},
+		{description: `Say "hello"`, expect: "  \"\"\"\n  Say \"hello\"\n  \"\"\"\n"},
+		{description: `Contains """ quotes`, expect: "  \"\"\"Contains \\\"\"\" quotes\"\"\"\n"},
+		{
+			description: "Get user.\n\nExample:\n\n    user(id: \"1\")",
+			expect:      "  \"\"\"\n  Get user.\n\n  Example:\n\n      user(id: \"1\")\n  \"\"\"\n",
+		},
+	}
+	for _, tt := range tests {
+		var b strings.Builder
+		printDescription(&b, tt.description, "  ")
+		assert.Equal(t, tt.expect, b.String())
+	}
+}
@@ -0,0 +1,45 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	serve := func(config SecurityHeadersConfig) http.Header {
+		mux := NewServeMux(SecurityHeaders(config))
+		assert.NoError(t, mux.AddHandler(newTestHandler()))
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ hello }`))
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Header()
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		header := serve(SecurityHeadersConfig{})
+		assert.Equal(t, "nosniff", header.Get("X-Content-Type-Options"))
+		assert.Equal(t, DefaultContentSecurityPolicy, header.Get("Content-Security-Policy"))
+		assert.Equal(t, "no-referrer", header.Get("Referrer-Policy"))
+		assert.Equal(t, "DENY", header.Get("X-Frame-Options"))
+		assert.Empty(t, header.Get("Strict-Transport-Security"))
+	})
+
+	t.Run("configured", func(t *testing.T) {
+		header := serve(SecurityHeadersConfig{
+			ContentSecurityPolicy: "default-src 'self'",
+			ReferrerPolicy:        "same-origin",
+			FrameOptions:          "-",
+			HSTSMaxAge:            31536000,
+			HSTSIncludeSubdomains: true,
+		})
+		assert.Equal(t, "default-src 'self'", header.Get("Content-Security-Policy"))
+		assert.Equal(t, "same-origin", header.Get("Referrer-Policy"))
+		assert.NotContains(t, header, "X-Frame-Options")
+		assert.Equal(t, "max-age=31536000; includeSubDomains", header.Get("Strict-Transport-Security"))
+	})
+}
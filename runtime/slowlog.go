@@ -0,0 +1,94 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Number of the slowest resolvers which are logged as resolver breakdown
+const slowQueryResolverLimit = 10
+
+// SlowQuery is a log entry of the operation which exceeds the threshold
+type SlowQuery struct {
+	// Operation name, or the document hash for anonymous operation, see OperationLabelFromContext
+	OperationName string                 `json:"operationName"`
+	Duration      string                 `json:"duration"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	Upstreams     []string               `json:"upstreams,omitempty"`
+	Resolvers     []SlowQueryResolver    `json:"resolvers,omitempty"`
+}
+
+// SlowQueryResolver is a resolver timing in the slow query log
+type SlowQueryResolver struct {
+	Path     string `json:"path"`
+	Duration string `json:"duration"`
+}
+
+// WithSlowQueryLog is middleware function to log operations which take longer than threshold via ServeMux logger.
+// Logged variables are masked with redaction patterns.
+func WithSlowQueryLog(threshold time.Duration) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		start := time.Now()
+		ctx, t := ensureTracer(ctx)
+
+		onFinish(ctx, func(result *graphql.Result) {
+			d := time.Since(start)
+			if d < threshold {
+				return
+			}
+			entry := newSlowQuery(ctx, t, d)
+			out, _ := json.Marshal(entry) // nolint: errcheck
+			serveMux.logger().Printf("slow GraphQL operation %q took %s: %s", entry.OperationName, d, string(out))
+		})
+		return ctx, nil
+	}
+}
+
+func newSlowQuery(ctx context.Context, t *tracer, d time.Duration) SlowQuery {
+	entry := SlowQuery{
+		Duration: d.String(),
+	}
+	entry.OperationName = OperationLabelFromContext(ctx)
+	if req := requestFromContext(ctx); req != nil {
+		if req.Variables != nil {
+			entry.Variables = redactValue(req.Variables, redactPatterns(ctx)).(map[string]interface{})
+		}
+	}
+
+	resolvers := t.resolvers()
+	sort.SliceStable(resolvers, func(i, j int) bool {
+		return resolvers[i].Duration > resolvers[j].Duration
+	})
+
+	upstreams := map[string]struct{}{}
+	for i, r := range resolvers {
+		if len(r.Path) == 1 {
+			if target, ok := upstreamFromContext(ctx, r.FieldName); ok {
+				upstreams[target] = struct{}{}
+			}
+		}
+		if i < slowQueryResolverLimit {
+			path := make([]string, len(r.Path))
+			for j, p := range r.Path {
+				path[j] = fmt.Sprint(p)
+			}
+			entry.Resolvers = append(entry.Resolvers, SlowQueryResolver{
+				Path:     strings.Join(path, "."),
+				Duration: time.Duration(r.Duration).String(),
+			})
+		}
+	}
+	for target := range upstreams {
+		entry.Upstreams = append(entry.Upstreams, target)
+	}
+	sort.Strings(entry.Upstreams)
+	return entry
+}
@@ -0,0 +1,62 @@
+package runtime
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+type testLogger struct {
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestWithSlowQueryLog(t *testing.T) {
+	h := newTestHandler()
+	h.queries["slow"] = &graphql.Field{
+		Type: graphql.String,
+		Args: graphql.FieldConfigArgument{
+			"token": &graphql.ArgumentConfig{
+				Type: graphql.String,
+			},
+		},
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			time.Sleep(20 * time.Millisecond)
+			return "done", nil
+		},
+	}
+	logger := &testLogger{}
+	mux := NewServeMux(WithSlowQueryLog(10 * time.Millisecond))
+	mux.Logger = logger
+	assert.NoError(t, mux.AddHandler(h))
+
+	serveTestQuery(t, mux, `{ hello }`)
+	assert.Len(t, logger.lines, 0)
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(
+		`{"query":"query Slow($token: String) { slow(token: $token) }","operationName":"Slow","variables":{"token":"s3cr3t","page":1}}`,
+	))
+	r.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+
+	if assert.Len(t, logger.lines, 1) {
+		line := logger.lines[0]
+		assert.Contains(t, line, `slow GraphQL operation "Slow"`)
+		assert.Contains(t, line, `"variables":{"page":1,"token":"[REDACTED]"}`)
+		assert.Contains(t, line, `"path":"slow"`)
+		assert.NotContains(t, line, "s3cr3t")
+	}
+	// Tracing extension is not responded by slow query log
+	assert.NotContains(t, w.Body.String(), `"tracing"`)
+}
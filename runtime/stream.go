@@ -0,0 +1,128 @@
+package runtime
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultStreamMaxItems is the maximum number of messages which are collected from server stream
+// when WithStreamLimits is not used
+const DefaultStreamMaxItems = 1000
+
+// StreamLimits bounds collecting messages of server-streaming RPC which backs query field
+type StreamLimits struct {
+	// Maximum number of collected messages, DefaultStreamMaxItems if zero.
+	// The stream is cancelled when the limit is reached and collected messages are responded.
+	MaxItems int
+	// Maximum duration of collecting, unlimited if zero.
+	// The stream is cancelled when the duration is elapsed and collected messages are responded.
+	Timeout time.Duration
+}
+
+type streamLimitsKey struct{}
+
+// WithStreamLimits is middleware function to set limits of collecting server stream into list field
+func WithStreamLimits(limits StreamLimits) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, streamLimitsKey{}, limits), nil
+	}
+}
+
+// streamLimits returns limits which are stored in the context with defaults
+func streamLimits(ctx context.Context) StreamLimits {
+	limits, _ := ctx.Value(streamLimitsKey{}).(StreamLimits) // nolint: errcheck
+	if limits.MaxItems <= 0 {
+		limits.MaxItems = DefaultStreamMaxItems
+	}
+	return limits
+}
+
+// serverStreamDesc describes server-streaming RPC for grpc.ClientConn.NewStream
+var serverStreamDesc = &grpc.StreamDesc{ServerStreams: true}
+
+// collectServerStream calls server-streaming RPC and drains the stream into messages which are created by newMessage.
+// Collecting stops at the end of the stream, or when limits in the context are reached.
+// Error is returned only if the RPC fails or the context of the request is done.
+func collectServerStream(
+	ctx context.Context,
+	conn *grpc.ClientConn,
+	method string,
+	req proto.Message,
+	newMessage func() proto.Message,
+	opts ...grpc.CallOption,
+) ([]proto.Message, error) {
+	limits := streamLimits(ctx)
+	var streamCtx context.Context
+	var cancel context.CancelFunc
+	if limits.Timeout > 0 {
+		streamCtx, cancel = context.WithTimeout(ctx, limits.Timeout)
+	} else {
+		streamCtx, cancel = context.WithCancel(ctx)
+	}
+	// Cancelling stops the upstream when collecting is stopped before the end of the stream
+	defer cancel()
+
+	// Header and trailer call options write metadata asynchronously when the stream is cancelled,
+	// so they are filled from the stream after collecting instead
+	var header, trailer []*metadata.MD
+	callOpts := make([]grpc.CallOption, 0, len(opts))
+	for _, opt := range opts {
+		switch o := opt.(type) {
+		case grpc.HeaderCallOption:
+			header = append(header, o.HeaderAddr)
+		case grpc.TrailerCallOption:
+			trailer = append(trailer, o.TrailerAddr)
+		default:
+			callOpts = append(callOpts, opt)
+		}
+	}
+
+	stream, err := conn.NewStream(streamCtx, serverStreamDesc, method, callOpts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(req); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+
+	messages := []proto.Message{}
+	var eof bool
+	for len(messages) < limits.MaxItems {
+		msg := newMessage()
+		if err := stream.RecvMsg(msg); err != nil {
+			if err == io.EOF {
+				eof = true
+				break
+			}
+			// Timeout of collecting responds messages so far, but timeout of the request is error
+			if ctx.Err() == nil && streamCtx.Err() == context.DeadlineExceeded {
+				break
+			}
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+
+	if md, err := stream.Header(); err == nil {
+		for _, addr := range header {
+			*addr = md
+		}
+	}
+	// Trailer is available only when the stream is completed
+	if eof {
+		for _, addr := range trailer {
+			*addr = stream.Trailer()
+		}
+	}
+	return messages, nil
+}
@@ -0,0 +1,138 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+const testStreamFile = `
+name: "stream.proto"
+package: "library.v1"
+syntax: "proto3"
+dependency: "book.proto"
+dependency: "graphql.proto"
+service {
+  name: "StreamService"
+  method { name: "ListNewBooks" input_type: ".library.v1.GetBookRequest" output_type: ".library.v1.Book" server_streaming: true }
+  method {
+    name: "TailBooks" input_type: ".library.v1.GetBookRequest" output_type: ".library.v1.Book" server_streaming: true
+    options { [graphql.schema] { type: QUERY name: "tailTitles" response { required: true pluck: "title" } } }
+  }
+  method {
+    name: "WatchBooks" input_type: ".library.v1.GetBookRequest" output_type: ".library.v1.Book" server_streaming: true
+    options { [graphql.schema] { type: MUTATION name: "watchBooks" } }
+  }
+  method { name: "UploadBooks" input_type: ".library.v1.Book" output_type: ".library.v1.Book" client_streaming: true }
+}
+`
+
+func testStreamService(t *testing.T) protoreflect.ServiceDescriptor {
+	t.Helper()
+	return testDynamicFile(t, "stream.proto", testBookFile, testStreamFile).Services().Get(0)
+}
+
+// newTestStreamConn serves ListNewBooks which streams 5 books, and TailBooks which streams books until cancelled
+func newTestStreamConn(t *testing.T, service protoreflect.ServiceDescriptor) (*grpc.ClientConn, func()) {
+	book := service.ParentFile().Imports().Get(0).Messages().ByName("Book")
+	conn, stop, err := NewInProcessConn(
+		func(*grpc.Server) {},
+		grpc.CustomCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(srv interface{}, stream grpc.ServerStream) error {
+			method, _ := grpc.MethodFromServerStream(stream) // nolint: errcheck
+			req := &rawFrame{}
+			if err := stream.RecvMsg(req); err != nil {
+				return err
+			}
+			in := dynamicpb.NewMessage(service.Methods().ByName("TailBooks").Input())
+			if err := proto.Unmarshal(req.data, in); err != nil {
+				return err
+			}
+			title := in.Get(in.Descriptor().Fields().ByName("title")).String()
+
+			for i := 1; method == "/library.v1.StreamService/TailBooks" || i <= 5; i++ {
+				out := dynamicpb.NewMessage(book)
+				out.Set(book.Fields().ByName("title"), protoreflect.ValueOfString(fmt.Sprintf("%s %d", title, i)))
+				data, _ := proto.Marshal(out) // nolint: errcheck
+				if err := stream.SendMsg(&rawFrame{data: data}); err != nil {
+					return err
+				}
+				if i > 5 {
+					time.Sleep(10 * time.Millisecond)
+				}
+			}
+			return nil
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return conn, stop
+}
+
+func TestDynamicHandlerServerStream(t *testing.T) {
+	service := testStreamService(t)
+	conn, stop := newTestStreamConn(t, service)
+	defer stop()
+
+	handlers, err := NewDynamicHandlers(conn, service)
+	if !assert.NoError(t, err) {
+		return
+	}
+	h := handlers[0]
+
+	queries := h.GetQueries(conn)
+	assert.Len(t, queries, 2)
+	assert.Equal(t, "[LibraryV1_Type_Book!]", queries["listNewBooks"].Type.String())
+	assert.Equal(t, "[String!]!", queries["tailTitles"].Type.String())
+	assert.Len(t, h.GetMutations(conn), 0)
+
+	mappings := h.FieldMappings()
+	assert.True(t, mappings[0].Stream)
+
+	t.Run("drain stream", func(t *testing.T) {
+		mux := NewServeMux()
+		assert.NoError(t, mux.AddHandler(h))
+		result := serveTestQuery(t, mux, `{"query":"{ listNewBooks(title: \"Dune\") { title } }"}`)
+		assert.Len(t, result.Errors, 0)
+		books := result.Data.(map[string]interface{})["listNewBooks"].([]interface{})
+		assert.Len(t, books, 5)
+		assert.Equal(t, map[string]interface{}{"title": "Dune 5"}, books[4])
+	})
+
+	t.Run("max items", func(t *testing.T) {
+		mux := NewServeMux()
+		mux.Use(WithStreamLimits(StreamLimits{MaxItems: 3}))
+		assert.NoError(t, mux.AddHandler(h))
+		result := serveTestQuery(t, mux, `{"query":"{ tailTitles(title: \"Dune\") }"}`)
+		assert.Len(t, result.Errors, 0)
+		assert.Equal(t, []interface{}{"Dune 1", "Dune 2", "Dune 3"}, result.Data.(map[string]interface{})["tailTitles"])
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		mux := NewServeMux()
+		mux.Use(WithStreamLimits(StreamLimits{Timeout: 100 * time.Millisecond}))
+		assert.NoError(t, mux.AddHandler(h))
+		result := serveTestQuery(t, mux, `{"query":"{ tailTitles(title: \"Dune\") }"}`)
+		assert.Len(t, result.Errors, 0)
+		titles := result.Data.(map[string]interface{})["tailTitles"].([]interface{})
+		assert.True(t, len(titles) > 5 && len(titles) < DefaultStreamMaxItems)
+	})
+
+	t.Run("request cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		md := service.Methods().ByName("TailBooks")
+		_, err := collectServerStream(ctx, conn, "/library.v1.StreamService/TailBooks", dynamicpb.NewMessage(md.Input()), func() proto.Message {
+			return dynamicpb.NewMessage(md.Output())
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,60 @@
+package runtime
+
+import (
+	"net"
+	"strings"
+
+	"net/http"
+)
+
+// TenantSelector returns tenant key of the request which is used to select handler group
+type TenantSelector func(r *http.Request) string
+
+// TenantByHost selects tenant by lower cased request host without port
+func TenantByHost(r *http.Request) string {
+	host := r.Host
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.ToLower(host)
+}
+
+// AddTenantHandler registers graphql handler which is served only for the tenant.
+// Tenant is selected via ServeMux.TenantSelector, default is TenantByHost, so tenant key is like "tenant-a.example.com".
+// Handlers which are registered via AddHandler are served for all tenants.
+// The handler is namespaced and checked for conflicts like AddHandler, against handlers which serve the tenant.
+func (s *ServeMux) AddTenantHandler(tenant string, h GraphqlHandler) error {
+	tenant = strings.ToLower(tenant)
+	h, err := s.prepareHandler(h, s.tenantHandlersOf(tenant))
+	if err != nil {
+		return err
+	}
+	if s.tenantHandlers == nil {
+		s.tenantHandlers = make(map[string][]GraphqlHandler)
+	}
+	s.tenantHandlers[tenant] = append(s.tenantHandlers[tenant], h)
+	return nil
+}
+
+// handlersFor returns handlers which serve the request, and the tenant of them which is empty if no tenant handler serves the request
+func (s *ServeMux) handlersFor(r *http.Request) (string, []GraphqlHandler) {
+	if len(s.tenantHandlers) == 0 {
+		return "", s.handlers
+	}
+	selector := s.TenantSelector
+	if selector == nil {
+		selector = TenantByHost
+	}
+	tenant := strings.ToLower(selector(r))
+	if _, ok := s.tenantHandlers[tenant]; !ok {
+		return "", s.handlers
+	}
+	return tenant, s.tenantHandlersOf(tenant)
+}
+
+// tenantHandlersOf returns handlers which serve requests of the tenant
+func (s *ServeMux) tenantHandlersOf(tenant string) []GraphqlHandler {
+	handlers := make([]GraphqlHandler, 0, len(s.handlers)+len(s.tenantHandlers[tenant]))
+	handlers = append(handlers, s.handlers...)
+	return append(handlers, s.tenantHandlers[tenant]...)
+}
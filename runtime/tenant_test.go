@@ -0,0 +1,90 @@
+package runtime
+
+import (
+	"strings"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/graphql-go/graphql"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTenantHandler(name string) *testHandler {
+	return &testHandler{
+		queries: graphql.Fields{
+			"tenant": &graphql.Field{
+				Type: graphql.String,
+				Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+					return name, nil
+				},
+			},
+		},
+	}
+}
+
+func TestTenantByHost(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	r.Host = "Tenant-A.example.com:8080"
+	assert.Equal(t, "tenant-a.example.com", TenantByHost(r))
+}
+
+func TestAddTenantHandler(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+	assert.NoError(t, mux.AddTenantHandler("tenant-a.example.com", newTenantHandler("a")))
+	assert.NoError(t, mux.AddTenantHandler("tenant-b.example.com", newTenantHandler("b")))
+
+	serve := func(host, query string) *graphql.Result {
+		r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(query))
+		r.Host = host
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		var result graphql.Result
+		assert.NoError(t, json.NewDecoder(w.Body).Decode(&result))
+		return &result
+	}
+
+	result := serve("tenant-a.example.com", `{ tenant hello }`)
+	assert.Equal(t, map[string]interface{}{"tenant": "a", "hello": "world"}, result.Data)
+
+	result = serve("tenant-b.example.com", `{ tenant }`)
+	assert.Equal(t, map[string]interface{}{"tenant": "b"}, result.Data)
+
+	// Unknown tenant is served only common handlers
+	result = serve("example.com", `{ tenant }`)
+	assert.Len(t, result.Errors, 1)
+
+	// Custom selector
+	mux.TenantSelector = func(r *http.Request) string {
+		return r.Header.Get("X-Tenant")
+	}
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ tenant }`))
+	r.Header.Set("X-Tenant", "tenant-b.example.com")
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Contains(t, w.Body.String(), `"tenant":"b"`)
+}
+
+func TestAddTenantHandlerLikeAddHandler(t *testing.T) {
+	// Groups of tenant handlers conflict with root fields of common handlers
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testHandler{
+		queries: graphql.Fields{
+			"billing": &graphql.Field{Type: graphql.String},
+		},
+	}))
+	assert.Error(t, mux.AddTenantHandler("tenant-a.example.com", newGroupedTestHandler("invoices", "Invoice", "billing")))
+
+	// Tenant handlers are namespaced by service
+	mux = NewServeMux()
+	mux.NamespaceByService = true
+	assert.NoError(t, mux.AddTenantHandler("tenant-a.example.com", &namedTestHandler{newTenantHandler("a"), "tenants.TenantService"}))
+	r := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(`{ tenantService { tenant } }`))
+	r.Host = "tenant-a.example.com"
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, r)
+	assert.Contains(t, w.Body.String(), `"tenantService":{"tenant":"a"}`)
+}
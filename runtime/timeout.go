@@ -0,0 +1,100 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql/gqlerrors"
+	"google.golang.org/grpc/codes"
+)
+
+// OperationTimeouts maps GraphQL operation name to timeout duration
+type OperationTimeouts map[string]time.Duration
+
+type timeoutConfig struct {
+	timeout   time.Duration
+	overrides OperationTimeouts
+}
+
+type timeoutKey struct{}
+
+// Timeout is middleware function to bound total execution time of the GraphQL operation.
+// Timeout could be overridden per operation name. When execution exceeds the timeout,
+// outstanding gRPC calls are cancelled and the result contains DEADLINE_EXCEEDED error.
+func Timeout(d time.Duration, overrides ...OperationTimeouts) MiddlewareFunc {
+	config := timeoutConfig{
+		timeout:   d,
+		overrides: OperationTimeouts{},
+	}
+	for _, o := range overrides {
+		for name, t := range o {
+			config.overrides[name] = t
+		}
+	}
+
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return context.WithValue(ctx, timeoutKey{}, config), nil
+	}
+}
+
+// operationTimeout returns timeout duration for the operation which is stored in the context
+func operationTimeout(ctx context.Context, operationName string) (time.Duration, bool) {
+	config, ok := ctx.Value(timeoutKey{}).(timeoutConfig)
+	if !ok {
+		return 0, false
+	}
+	if t, ok := config.overrides[operationName]; ok {
+		return t, t > 0
+	}
+	return config.timeout, config.timeout > 0
+}
+
+// markDeadlineExceeded replaces errors which are caused by exceeding timeout with DEADLINE_EXCEEDED code
+func markDeadlineExceeded(errs []GraphqlError, timeout time.Duration) {
+	for i := 0; i < len(errs); i++ {
+		e := errs[i]
+		if !isDeadlineExceeded(e) {
+			continue
+		}
+		e.Message = "operation exceeded timeout of " + timeout.String()
+		if e.Extensions == nil {
+			e.Extensions = make(map[string]interface{})
+		}
+		e.Extensions["code"] = "DEADLINE_EXCEEDED"
+		errs[i] = e
+	}
+}
+
+// isDeadlineExceeded reports whether the error is DeadlineExceeded status of upstream call or the context error
+func isDeadlineExceeded(e GraphqlError) bool {
+	if st, ok := upstreamStatus(e); ok {
+		return st.Code() == codes.DeadlineExceeded
+	}
+	err := e.OriginalError()
+	if ge, ok := err.(*gqlerrors.Error); ok {
+		err = ge.OriginalError
+	}
+	return err != nil && errors.Is(err, context.DeadlineExceeded)
+}
+
+// detachedContext hides cancellation of the context from graphql.Execute.
+// graphql.Execute returns as soon as the context is done while resolvers are still running, and then both of them write to the same result.
+// In order to avoid that, resolveField restores original context so resolvers still observe the cancellation and finish early.
+type detachedContext struct {
+	context.Context
+}
+
+func (detachedContext) Deadline() (time.Time, bool) { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}       { return nil }
+func (detachedContext) Err() error                  { return nil }
+
+// attachContext returns original context if ctx is detached
+func attachContext(ctx context.Context) context.Context {
+	if d, ok := ctx.(detachedContext); ok {
+		return d.Context
+	}
+	return ctx
+}
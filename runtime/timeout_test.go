@@ -0,0 +1,86 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/graphql-go/graphql"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestOperationTimeout(t *testing.T) {
+	ctx := context.Background()
+	_, ok := operationTimeout(ctx, "")
+	assert.False(t, ok)
+
+	ctx, err := Timeout(time.Second, OperationTimeouts{"Report": time.Minute, "Unlimited": 0})(ctx, nil, nil, nil)
+	assert.NoError(t, err)
+
+	d, ok := operationTimeout(ctx, "")
+	assert.True(t, ok)
+	assert.Equal(t, time.Second, d)
+
+	d, ok = operationTimeout(ctx, "Report")
+	assert.True(t, ok)
+	assert.Equal(t, time.Minute, d)
+
+	_, ok = operationTimeout(ctx, "Unlimited")
+	assert.False(t, ok)
+}
+
+func TestTimeoutMiddleware(t *testing.T) {
+	h := newTestHandler()
+	h.queries["slow"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			select {
+			case <-p.Context.Done():
+				return nil, p.Context.Err()
+			case <-time.After(time.Second):
+				return "done", nil
+			}
+		},
+	}
+
+	// Errors are detected by the status of upstream call, not by messages
+	h.queries["upstream"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			<-p.Context.Done()
+			return nil, errors.Wrap(status.Error(codes.DeadlineExceeded, "upstream"), "Failed to call RPC")
+		},
+	}
+	h.queries["message"] = &graphql.Field{
+		Type: graphql.String,
+		Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			<-p.Context.Done()
+			return nil, errors.New("context deadline exceeded in message")
+		},
+	}
+
+	mux := NewServeMux(Timeout(10 * time.Millisecond))
+	assert.NoError(t, mux.AddHandler(h))
+
+	result := serveTestQuery(t, mux, `{ slow }`)
+	if assert.Len(t, result.Errors, 1) {
+		assert.Equal(t, "DEADLINE_EXCEEDED", result.Errors[0].Extensions["code"])
+	}
+	result = serveTestQuery(t, mux, `{ upstream message }`)
+	if assert.Len(t, result.Errors, 2) {
+		got := map[string]interface{}{}
+		for _, e := range result.Errors {
+			got[e.Message] = e.Extensions["code"]
+		}
+		assert.Equal(t, map[string]interface{}{
+			"operation exceeded timeout of 10ms":   "DEADLINE_EXCEEDED",
+			"context deadline exceeded in message": nil,
+		}, got)
+	}
+
+	result = serveTestQuery(t, mux, `{ hello }`)
+	assert.Len(t, result.Errors, 0)
+}
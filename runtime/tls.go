@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"errors"
+	"fmt"
+
+	"crypto/tls"
+	"crypto/x509"
+	"io/ioutil"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// UpstreamTLSConfig is TLS configuration for gRPC upstream connections
+type UpstreamTLSConfig struct {
+	// PEM encoded CA bundle file to verify the server certificate, system roots are used if empty
+	CAFile string
+	// PEM encoded client certificate and key files for mutual TLS, both or neither must be set
+	CertFile string
+	KeyFile  string
+	// Server name to verify the certificate and send as SNI, default is the host of dial target
+	ServerName string
+	// If true, server certificate is not verified. Never use this except for development.
+	InsecureSkipVerify bool
+}
+
+// TLSConfig loads certificates and creates tls.Config
+func (c UpstreamTLSConfig) TLSConfig() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify, // nolint: gosec
+	}
+	if c.CAFile != "" {
+		pem, err := ioutil.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, errors.New("no certificate is found in CA file " + c.CAFile)
+		}
+		config.RootCAs = pool
+	}
+	if c.CertFile != "" || c.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+	return config, nil
+}
+
+// UpstreamTLS creates gRPC dial option of transport credentials from the config.
+// Generated handlers use this for tls service option, and it also can be passed to WithDialOptions middleware
+// or grpc.Dial of the connection which is passed to Register*GraphqlHandler.
+func UpstreamTLS(config UpstreamTLSConfig) (grpc.DialOption, error) {
+	c, err := config.TLSConfig()
+	if err != nil {
+		return nil, err
+	}
+	return grpc.WithTransportCredentials(credentials.NewTLS(c)), nil
+}
@@ -0,0 +1,135 @@
+package runtime
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
+	pb "google.golang.org/grpc/reflection/grpc_testing"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// writeTestCertificate writes self-signed certificate of the host which is also used as CA
+func writeTestCertificate(t *testing.T, dir, host string) (certFile, keyFile string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		DNSNames:              []string{host},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certFile, keyFile = filepath.Join(dir, host+".pem"), filepath.Join(dir, host+".key")
+	if err := ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return certFile, keyFile
+}
+
+func TestUpstreamTLS(t *testing.T) {
+	dir := t.TempDir()
+	serverCert, serverKey := writeTestCertificate(t, dir, "upstream.test")
+	clientCert, clientKey := writeTestCertificate(t, dir, "gateway.test")
+
+	// Upstream requires client certificate which is signed by gateway CA
+	serverConfig, err := UpstreamTLSConfig{CAFile: clientCert, CertFile: serverCert, KeyFile: serverKey}.TLSConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	serverConfig.ClientCAs = serverConfig.RootCAs
+	serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+	lis := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer(grpc.Creds(credentials.NewTLS(serverConfig)))
+	pb.RegisterSearchServiceServer(server, &testSearchServer{})
+	reflection.Register(server)
+	go server.Serve(lis) // nolint: errcheck
+	t.Cleanup(server.Stop)
+
+	reflect := func(config UpstreamTLSConfig) error {
+		creds, err := UpstreamTLS(config)
+		if err != nil {
+			return err
+		}
+		conn, err := grpc.Dial("bufnet", creds, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.Dial()
+		}))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_, err = NewReflectionHandlers(ctx, conn)
+		return err
+	}
+
+	t.Run("mutual TLS", func(t *testing.T) {
+		assert.NoError(t, reflect(UpstreamTLSConfig{
+			CAFile:     serverCert,
+			CertFile:   clientCert,
+			KeyFile:    clientKey,
+			ServerName: "upstream.test",
+		}))
+	})
+	t.Run("without client certificate", func(t *testing.T) {
+		assert.Error(t, reflect(UpstreamTLSConfig{
+			CAFile:     serverCert,
+			ServerName: "upstream.test",
+		}))
+	})
+	t.Run("server name mismatch", func(t *testing.T) {
+		assert.Error(t, reflect(UpstreamTLSConfig{
+			CAFile:     serverCert,
+			CertFile:   clientCert,
+			KeyFile:    clientKey,
+			ServerName: "other.test",
+		}))
+	})
+	t.Run("insecure skip verify", func(t *testing.T) {
+		assert.NoError(t, reflect(UpstreamTLSConfig{
+			CertFile:           clientCert,
+			KeyFile:            clientKey,
+			InsecureSkipVerify: true,
+		}))
+	})
+	t.Run("invalid files", func(t *testing.T) {
+		_, err := UpstreamTLS(UpstreamTLSConfig{CAFile: serverKey})
+		assert.Error(t, err)
+		_, err = UpstreamTLS(UpstreamTLSConfig{CertFile: clientCert})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,161 @@
+package runtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"net/http"
+
+	"github.com/graphql-go/graphql"
+)
+
+// TracingResolver is a resolver timing in Apollo Tracing format
+type TracingResolver struct {
+	Path        []interface{} `json:"path"`
+	ParentType  string        `json:"parentType"`
+	FieldName   string        `json:"fieldName"`
+	ReturnType  string        `json:"returnType"`
+	StartOffset int64         `json:"startOffset"`
+	Duration    int64         `json:"duration"`
+}
+
+// TracingPhase is a timing of parsing or validation phase in Apollo Tracing format
+type TracingPhase struct {
+	StartOffset int64 `json:"startOffset"`
+	Duration    int64 `json:"duration"`
+}
+
+// TracingExecution holds resolver timings in Apollo Tracing format
+type TracingExecution struct {
+	Resolvers []TracingResolver `json:"resolvers"`
+}
+
+// Tracing is "tracing" extension structure which is compatible with Apollo Tracing format.
+// All offsets and durations are nanoseconds.
+// See: https://github.com/apollographql/apollo-tracing
+type Tracing struct {
+	Version    int              `json:"version"`
+	StartTime  time.Time        `json:"startTime"`
+	EndTime    time.Time        `json:"endTime"`
+	Duration   int64            `json:"duration"`
+	Parsing    TracingPhase     `json:"parsing"`
+	Validation TracingPhase     `json:"validation"`
+	Execution  TracingExecution `json:"execution"`
+}
+
+// tracer records timings of a request
+type tracer struct {
+	mu      sync.Mutex
+	tracing Tracing
+	// Whether responds tracing as extension. Tracer is also used internally like slow query log.
+	respond bool
+}
+
+type tracerKey struct{}
+
+// WithTracing is middleware function to respond resolver timings as "tracing" extension
+// which is compatible with Apollo Tracing format.
+func WithTracing() MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		ctx, t := ensureTracer(ctx)
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.respond = true
+		return ctx, nil
+	}
+}
+
+// ensureTracer returns tracer in the context, or creates new tracer and stores it to the context
+func ensureTracer(ctx context.Context) (context.Context, *tracer) {
+	if t := tracerFromContext(ctx); t != nil {
+		return ctx, t
+	}
+	t := &tracer{
+		tracing: Tracing{
+			Version:   1,
+			StartTime: time.Now(),
+			Execution: TracingExecution{
+				Resolvers: []TracingResolver{},
+			},
+		},
+	}
+	return context.WithValue(ctx, tracerKey{}, t), t
+}
+
+// tracerFromContext returns tracer in the context.
+// Returned tracer may be nil, and then all tracer methods do nothing.
+func tracerFromContext(ctx context.Context) *tracer {
+	t, _ := ctx.Value(tracerKey{}).(*tracer) // nolint: errcheck
+	return t
+}
+
+func (t *tracer) offset(now time.Time) int64 {
+	return now.Sub(t.tracing.StartTime).Nanoseconds()
+}
+
+// phase records phase timing and returns function to finish it
+func (t *tracer) phase(p *TracingPhase) func() {
+	start := time.Now()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		p.StartOffset = t.offset(start)
+		p.Duration = time.Since(start).Nanoseconds()
+	}
+}
+
+func (t *tracer) parsing() func() {
+	if t == nil {
+		return func() {}
+	}
+	return t.phase(&t.tracing.Parsing)
+}
+
+func (t *tracer) validation() func() {
+	if t == nil {
+		return func() {}
+	}
+	return t.phase(&t.tracing.Validation)
+}
+
+// resolver records resolver timing and returns function to finish it
+func (t *tracer) resolver(info graphql.ResolveInfo) func() {
+	if t == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.tracing.Execution.Resolvers = append(t.tracing.Execution.Resolvers, TracingResolver{
+			Path:        info.Path.AsArray(),
+			ParentType:  info.ParentType.Name(),
+			FieldName:   info.FieldName,
+			ReturnType:  info.ReturnType.String(),
+			StartOffset: t.offset(start),
+			Duration:    time.Since(start).Nanoseconds(),
+		})
+	}
+}
+
+// finish completes tracing and adds it to response extensions
+func (t *tracer) finish(ctx context.Context) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.tracing.EndTime = time.Now()
+	t.tracing.Duration = t.offset(t.tracing.EndTime)
+	if t.respond {
+		AddExtension(ctx, "tracing", t.tracing)
+	}
+}
+
+// resolvers returns copy of recorded resolver timings
+func (t *tracer) resolvers() []TracingResolver {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]TracingResolver{}, t.tracing.Execution.Resolvers...)
+}
@@ -0,0 +1,49 @@
+package runtime
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTracingExtension(t *testing.T) {
+	mux := NewServeMux(WithTracing())
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ user(id: "1") { id } }`)
+	assert.Len(t, result.Errors, 0)
+
+	tracing, ok := result.Extensions["tracing"].(map[string]interface{})
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	assert.Equal(t, float64(1), tracing["version"])
+	assert.NotEmpty(t, tracing["startTime"])
+	assert.NotEmpty(t, tracing["endTime"])
+	assert.Contains(t, tracing, "parsing")
+	assert.Contains(t, tracing, "validation")
+
+	execution, ok := tracing["execution"].(map[string]interface{})
+	if !assert.True(t, ok) {
+		t.FailNow()
+	}
+	resolvers, ok := execution["resolvers"].([]interface{})
+	if assert.True(t, ok) && assert.Len(t, resolvers, 2) {
+		paths := []interface{}{}
+		for _, r := range resolvers {
+			paths = append(paths, r.(map[string]interface{})["path"])
+		}
+		assert.ElementsMatch(t, []interface{}{
+			[]interface{}{"user"},
+			[]interface{}{"user", "id"},
+		}, paths)
+	}
+}
+
+func TestTracingIsDisabledByDefault(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(newTestHandler()))
+
+	result := serveTestQuery(t, mux, `{ hello }`)
+	assert.NotContains(t, result.Extensions, "tracing")
+}
@@ -0,0 +1,112 @@
+package runtime
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultTwirpPrefix is default route prefix of Twirp servers
+const DefaultTwirpPrefix = "/twirp"
+
+// TwirpConfig is upstream of Twirp protocol (https://twitchtv.github.io/twirp/docs/spec_v7.html)
+type TwirpConfig struct {
+	// Base URL of the server like "http://users.internal:8080"
+	BaseURL string
+	// Route prefix of the server, default is DefaultTwirpPrefix.
+	// RPCs are sent to "<BaseURL><Prefix>/<package.Service>/<Method>"
+	Prefix string
+	// HTTP client to send requests, default is http.DefaultClient
+	Client *http.Client
+}
+
+// twirpCodes are Twirp error codes which are not gRPC code names
+var twirpCodes = map[string]codes.Code{
+	"malformed": codes.InvalidArgument,
+	"bad_route": codes.Unimplemented,
+	"dataloss":  codes.DataLoss,
+}
+
+// NewTwirpConn returns gRPC connection which calls RPCs of Twirp server in binary protobuf,
+// so that handlers which are generated from the same protos serve Twirp services behind the gateway.
+// Pass the connection to Register*GraphqlHandler or NewDynamicHandlers like gRPC connection.
+//
+// Metadata is sent as HTTP headers, response headers are received as header metadata,
+// and Twirp errors are converted to gRPC status errors. Twirp doesn't have streaming RPCs.
+func NewTwirpConn(config TwirpConfig) (*grpc.ClientConn, error) {
+	if config.BaseURL == "" {
+		return nil, errors.New("base URL of Twirp upstream is not specified")
+	}
+	client := config.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	prefix := config.Prefix
+	if prefix == "" {
+		prefix = DefaultTwirpPrefix
+	}
+	baseURL := strings.TrimSuffix(config.BaseURL, "/") + "/" + strings.Trim(prefix, "/")
+
+	return newUnaryProxyConn(func(ctx context.Context, method string, md metadata.MD, body []byte) (metadata.MD, metadata.MD, []byte, error) {
+		req, err := http.NewRequest(http.MethodPost, baseURL+method, bytes.NewReader(body))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		req = req.WithContext(ctx)
+		proxyHeaders(req.Header, md)
+		req.Header.Set("Content-Type", "application/protobuf")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, nil, nil, proxyTransportError(ctx, err)
+		}
+		defer resp.Body.Close()
+		out, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, nil, nil, proxyTransportError(ctx, err)
+		}
+
+		header, _ := proxyMetadata(resp.Header, "")
+		if resp.StatusCode != http.StatusOK {
+			return header, nil, nil, twirpError(resp.StatusCode, out)
+		}
+		return header, nil, out, nil
+	})
+}
+
+// twirpError converts error response of Twirp protocol
+func twirpError(statusCode int, body []byte) error {
+	var e struct {
+		Code string `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &e); err != nil || e.Code == "" {
+		return proxyError("", fmt.Sprintf("Twirp upstream responds HTTP %d", statusCode), twirpHTTPCode(statusCode))
+	}
+	if c, ok := twirpCodes[e.Code]; ok {
+		return proxyError("", e.Msg, c)
+	}
+	return proxyError(e.Code, e.Msg, codes.Unknown)
+}
+
+// twirpHTTPCode maps HTTP status which is not responded by Twirp server, e.g. from proxies
+func twirpHTTPCode(statusCode int) codes.Code {
+	switch {
+	case statusCode >= 300 && statusCode < 400:
+		return codes.Internal
+	case statusCode == http.StatusNotFound:
+		return codes.Unimplemented
+	}
+	// Other statuses are same as Connect
+	return connectHTTPCode(statusCode)
+}
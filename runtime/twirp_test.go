@@ -0,0 +1,101 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func newTestTwirpServer(t *testing.T, prefix string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case prefix + "/echo.Echo/Echo":
+			assert.Equal(t, "application/protobuf", r.Header.Get("Content-Type"))
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+
+			body, _ := ioutil.ReadAll(r.Body) // nolint: errcheck
+			in := &wrapperspb.StringValue{}
+			assert.NoError(t, proto.Unmarshal(body, in))
+			out, _ := proto.Marshal(&wrapperspb.StringValue{Value: "echo " + in.Value}) // nolint: errcheck
+			w.Header().Set("Content-Type", "application/protobuf")
+			w.Header().Set("X-Request-Id", "abc")
+			w.Write(out) // nolint: errcheck
+		case prefix + "/echo.Echo/Invalid":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"code":"malformed","msg":"bad request body"}`)) // nolint: errcheck
+		case prefix + "/echo.Echo/Fail":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"code":"permission_denied","msg":"not yours","meta":{"owner":"someone"}}`)) // nolint: errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func TestTwirpConn(t *testing.T) {
+	server := newTestTwirpServer(t, "/twirp")
+	defer server.Close()
+
+	conn, err := NewTwirpConn(TwirpConfig{BaseURL: server.URL})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+
+	t.Run("unary", func(t *testing.T) {
+		var header metadata.MD
+		out := &wrapperspb.StringValue{}
+		err := conn.Invoke(ctx, "/echo.Echo/Echo", &wrapperspb.StringValue{Value: "hello"}, out, grpc.Header(&header))
+		assert.NoError(t, err)
+		assert.Equal(t, "echo hello", out.Value)
+		assert.Equal(t, []string{"abc"}, header.Get("x-request-id"))
+	})
+
+	t.Run("twirp errors", func(t *testing.T) {
+		err := conn.Invoke(ctx, "/echo.Echo/Invalid", &wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Equal(t, "bad request body", status.Convert(err).Message())
+
+		err = conn.Invoke(ctx, "/echo.Echo/Fail", &wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("http error", func(t *testing.T) {
+		err := conn.Invoke(ctx, "/echo.Echo/Unknown", &wrapperspb.StringValue{}, &wrapperspb.StringValue{})
+		assert.Equal(t, codes.Unimplemented, status.Code(err))
+	})
+}
+
+func TestConfigDialTwirp(t *testing.T) {
+	server := newTestTwirpServer(t, "/rpc")
+	defer server.Close()
+
+	c, err := parseConfig([]byte("upstream:\n  address: " + server.URL + "/rpc\n  protocol: twirp\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := c.Dial(context.Background(), "echo.Echo")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer token")
+	out := &wrapperspb.StringValue{}
+	assert.NoError(t, conn.Invoke(ctx, "/echo.Echo/Echo", &wrapperspb.StringValue{Value: "twirp"}, out))
+	assert.Equal(t, "echo twirp", out.Value)
+}
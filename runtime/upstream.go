@@ -0,0 +1,133 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"net/http"
+
+	"google.golang.org/grpc"
+)
+
+// UpstreamHostEnvPrefix is prefix of environment variables which override upstream host of the service.
+// The variable name is the prefix and upper cased full service name whose non-alphanumerics are replaced with underscore,
+// e.g. GRAPHQL_UPSTREAM_GREETER_GREETER for "greeter.Greeter".
+const UpstreamHostEnvPrefix = "GRAPHQL_UPSTREAM_"
+
+// unixAuthority is :authority of unix domain socket connections, which is also verified as TLS server name by default
+const unixAuthority = "localhost"
+
+type upstreamHostsKey struct{}
+
+// WithUpstreamHosts is middleware function to override upstream hosts of generated handlers,
+// keyed by full service name like "greeter.Greeter", so that the same generated code runs in any environment.
+// Handlers which are registered with connection are not affected.
+func WithUpstreamHosts(hosts map[string]string) MiddlewareFunc {
+	return func(ctx context.Context, serveMux *ServeMux, w http.ResponseWriter, r *http.Request) (context.Context, error) {
+		return ContextWithUpstreamHosts(ctx, hosts), nil
+	}
+}
+
+// ContextWithUpstreamHosts returns context which overrides upstream hosts like WithUpstreamHosts,
+// which is useful to resolve upstreams outside of requests, e.g. command line flags on startup
+func ContextWithUpstreamHosts(ctx context.Context, hosts map[string]string) context.Context {
+	merged := make(map[string]string)
+	if v, ok := ctx.Value(upstreamHostsKey{}).(map[string]string); ok {
+		for service, host := range v {
+			merged[service] = host
+		}
+	}
+	for service, host := range hosts {
+		merged[service] = host
+	}
+	return context.WithValue(ctx, upstreamHostsKey{}, merged)
+}
+
+// UpstreamHost returns upstream host of the service which is overridden by WithUpstreamHosts middleware
+// or environment variable of UpstreamHostEnvPrefix in this order, otherwise returns defaultHost of proto option.
+func UpstreamHost(ctx context.Context, service, defaultHost string) string {
+	if host, ok := upstreamHostOverride(ctx, service); ok {
+		return host
+	}
+	return defaultHost
+}
+
+func upstreamHostOverride(ctx context.Context, service string) (string, bool) {
+	if hosts, ok := ctx.Value(upstreamHostsKey{}).(map[string]string); ok {
+		if host, ok := hosts[service]; ok {
+			return host, true
+		}
+	}
+	if host := os.Getenv(upstreamHostEnv(service)); host != "" {
+		return host, true
+	}
+	return "", false
+}
+
+// upstreamHostEnv returns environment variable name which overrides upstream host of the service
+func upstreamHostEnv(service string) string {
+	return UpstreamHostEnvPrefix + strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, service)
+}
+
+// DialUpstream creates automatic connection of generated handler to the upstream of the service.
+// The host is resolved via UpstreamHost, or Discovery of WithDiscovery middleware if the host is not overridden.
+// Dial options of WithDialOptions middleware are added to opts.
+func DialUpstream(ctx context.Context, service, host string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	opts = append(opts[:len(opts):len(opts)], DialOptions(ctx)...)
+	if override, ok := upstreamHostOverride(ctx, service); ok {
+		return Dial(ctx, override, opts...)
+	}
+	if d, ok := ctx.Value(discoveryKey{}).(Discovery); ok {
+		endpoint, err := d.Resolve(ctx, service)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover upstream of %s: %w", service, err)
+		}
+		if endpoint != nil {
+			if endpoint.Credentials != nil {
+				opts = append(opts, grpc.WithTransportCredentials(endpoint.Credentials))
+			}
+			return Dial(ctx, endpoint.Target, opts...)
+		}
+	}
+	return Dial(ctx, host, opts...)
+}
+
+// Dial creates client connection to the upstream target.
+// In addition to targets which gRPC resolves, unix domain socket targets like "unix:///var/run/app.sock"
+// or "unix:relative.sock" are dialed via the socket with "localhost" authority, for sidecar deployments.
+func Dial(ctx context.Context, target string, opts ...grpc.DialOption) (*grpc.ClientConn, error) {
+	if path, ok := unixSocketPath(target); ok {
+		target = "passthrough:///" + unixAuthority
+		opts = append([]grpc.DialOption{
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			}),
+		}, opts...)
+	}
+	return grpc.DialContext(ctx, target, opts...)
+}
+
+// unixSocketPath returns socket path of unix domain socket target
+func unixSocketPath(target string) (string, bool) {
+	switch {
+	case strings.HasPrefix(target, "unix://"):
+		return strings.TrimPrefix(target, "unix://"), true
+	case strings.HasPrefix(target, "unix:"):
+		return strings.TrimPrefix(target, "unix:"), true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,81 @@
+package runtime
+
+import (
+	"context"
+	"net"
+	"os"
+	"testing"
+	"time"
+
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+	pb "google.golang.org/grpc/reflection/grpc_testing"
+)
+
+func TestDialUnixSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "upstream.sock")
+	lis, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := grpc.NewServer()
+	pb.RegisterSearchServiceServer(server, &testSearchServer{})
+	reflection.Register(server)
+	go server.Serve(lis) // nolint: errcheck
+	t.Cleanup(server.Stop)
+
+	for _, target := range []string{"unix://" + path, "unix:" + path} {
+		t.Run(target, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			conn, err := Dial(ctx, target, grpc.WithInsecure(), grpc.WithBlock())
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer conn.Close()
+			assert.Equal(t, "passthrough:///localhost", conn.Target())
+
+			handlers, err := NewReflectionHandlers(ctx, conn)
+			assert.NoError(t, err)
+			assert.Len(t, handlers, 1)
+		})
+	}
+}
+
+// setTestEnv sets the environment variable during the test, and restores it on cleanup
+func setTestEnv(t *testing.T, key, value string) {
+	t.Helper()
+	prev, ok := os.LookupEnv(key)
+	if err := os.Setenv(key, value); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if ok {
+			os.Setenv(key, prev) // nolint: errcheck
+		} else {
+			os.Unsetenv(key) // nolint: errcheck
+		}
+	})
+}
+
+func TestUpstreamHost(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "localhost:50051", UpstreamHost(ctx, "greeter.Greeter", "localhost:50051"))
+
+	setTestEnv(t, "GRAPHQL_UPSTREAM_GREETER_GREETER", "greeter.staging:50051")
+	assert.Equal(t, "greeter.staging:50051", UpstreamHost(ctx, "greeter.Greeter", "localhost:50051"))
+	assert.Equal(t, "localhost:50052", UpstreamHost(ctx, "greeter.v2.Greeter", "localhost:50052"))
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", nil)
+	ctx, err := WithUpstreamHosts(map[string]string{"greeter.Greeter": "unix:///var/run/greeter.sock"})(ctx, nil, nil, r)
+	assert.NoError(t, err)
+	ctx, err = WithUpstreamHosts(map[string]string{"greeter.v2.Greeter": "greeter-v2:50051"})(ctx, nil, nil, r)
+	assert.NoError(t, err)
+	assert.Equal(t, "unix:///var/run/greeter.sock", UpstreamHost(ctx, "greeter.Greeter", "localhost:50051"))
+	assert.Equal(t, "greeter-v2:50051", UpstreamHost(ctx, "greeter.v2.Greeter", "localhost:50052"))
+}
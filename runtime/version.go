@@ -0,0 +1,143 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	goruntime "runtime"
+	"runtime/debug"
+	"strings"
+
+	"encoding/json"
+	"net/http"
+)
+
+const gatewayModulePath = "github.com/ysugimoto/grpc-graphql-gateway"
+
+// BuildInfo describes the binary which serves the gateway
+type BuildInfo struct {
+	// Go version which the binary is built with
+	GoVersion string `json:"goVersion"`
+	// Main module path and version of the binary
+	Path    string `json:"path,omitempty"`
+	Version string `json:"version,omitempty"`
+	// Version of grpc-graphql-gateway module
+	GatewayVersion string `json:"gatewayVersion,omitempty"`
+	// VCS revision and commit time which are stamped by go build
+	Revision   string `json:"revision,omitempty"`
+	CommitTime string `json:"commitTime,omitempty"`
+	Modified   bool   `json:"modified,omitempty"`
+}
+
+// UpstreamReport is upstream gRPC target of the service
+type UpstreamReport struct {
+	Service string `json:"service"`
+	Target  string `json:"target"`
+}
+
+// SchemaReport describes what is actually deployed, the schema version, build info and upstream targets
+type SchemaReport struct {
+	// SchemaVersion is hash of the schema SDL, which is the default version of PublishSchema
+	SchemaVersion string           `json:"schemaVersion"`
+	Build         BuildInfo        `json:"build"`
+	Upstreams     []UpstreamReport `json:"upstreams"`
+}
+
+// SchemaReport builds report of the registered handlers.
+// Upstream targets are resolved via CreateConnection of each handler, so that upstream overrides and discovery are applied.
+// Note that handlers which are added via AddTenantHandler are not included.
+func (s *ServeMux) SchemaReport(ctx context.Context) (*SchemaReport, error) {
+	schema, err := s.Schema()
+	if err != nil {
+		return nil, err
+	}
+	report := &SchemaReport{
+		SchemaVersion: sdlVersion(PrintSchema(&schema)),
+		Build:         readBuildInfo(),
+		Upstreams:     []UpstreamReport{},
+	}
+	for _, h := range s.handlers {
+		if v, ok := h.(schemaVersioner); ok {
+			h, _ = v.snapshot()
+		}
+		conn, closer, err := h.CreateConnection(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create connection of %s: %w", handlerName(h), err)
+		}
+		if conn != nil {
+			report.Upstreams = append(report.Upstreams, UpstreamReport{
+				Service: handlerName(h),
+				Target:  conn.Target(),
+			})
+		}
+		closer()
+	}
+	return report, nil
+}
+
+// LogSchemaReport logs the schema report via Logger, call this on startup after all handlers are added
+func (s *ServeMux) LogSchemaReport(ctx context.Context) error {
+	report, err := s.SchemaReport(ctx)
+	if err != nil {
+		return err
+	}
+	upstreams := make([]string, len(report.Upstreams))
+	for i, u := range report.Upstreams {
+		upstreams[i] = u.Service + "=" + u.Target
+	}
+	b := report.Build
+	s.logger().Printf(
+		"GraphQL schema version %s is served by %s %s (revision %s, gateway %s, %s), upstreams: %s",
+		report.SchemaVersion, b.Path, b.Version, b.Revision, b.GatewayVersion, b.GoVersion, strings.Join(upstreams, ", "),
+	)
+	return nil
+}
+
+// VersionHandler returns http.Handler which responds the schema report in JSON, mount it on like "/graphql/version".
+//
+//	mux := runtime.NewServeMux()
+//	http.Handle("/graphql", mux)
+//	http.Handle("/graphql/version", mux.VersionHandler())
+func (s *ServeMux) VersionHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report, err := s.SchemaReport(r.Context())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		out, _ := json.Marshal(report) // nolint: errcheck
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(out) // nolint: errcheck
+	})
+}
+
+// readBuildInfo reads build info which is embedded in the binary
+func readBuildInfo() BuildInfo {
+	info := BuildInfo{
+		GoVersion: goruntime.Version(),
+	}
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.Path = bi.Main.Path
+	info.Version = bi.Main.Version
+	if bi.Main.Path == gatewayModulePath {
+		info.GatewayVersion = bi.Main.Version
+	}
+	for _, dep := range bi.Deps {
+		if dep.Path == gatewayModulePath {
+			info.GatewayVersion = dep.Version
+		}
+	}
+	for _, setting := range bi.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.CommitTime = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
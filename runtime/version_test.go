@@ -0,0 +1,64 @@
+package runtime
+
+import (
+	"context"
+	"testing"
+
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+)
+
+// testUpstreamHandler is testHandler which connects to the upstream target
+type testUpstreamHandler struct {
+	*testHandler
+	target string
+}
+
+func (h *testUpstreamHandler) CreateConnection(ctx context.Context) (*grpc.ClientConn, func(), error) {
+	conn, err := grpc.Dial(h.target, grpc.WithInsecure())
+	if err != nil {
+		return nil, nil, err
+	}
+	return conn, func() { conn.Close() }, nil // nolint: errcheck
+}
+
+func (h *testUpstreamHandler) ServiceName() string {
+	return "test.v1.TestService"
+}
+
+func TestVersionHandler(t *testing.T) {
+	mux := NewServeMux()
+	assert.NoError(t, mux.AddHandler(&testUpstreamHandler{
+		testHandler: newTestHandler(),
+		target:      "dns:///users:50051",
+	}))
+
+	w := httptest.NewRecorder()
+	mux.VersionHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/graphql/version", nil))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+	var report SchemaReport
+	if err := json.NewDecoder(w.Body).Decode(&report); err != nil {
+		t.Fatal(err)
+	}
+	schema, err := mux.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, sdlVersion(PrintSchema(&schema)), report.SchemaVersion)
+	assert.NotEmpty(t, report.Build.GoVersion)
+	assert.Equal(t, []UpstreamReport{
+		{Service: "test.v1.TestService", Target: "dns:///users:50051"},
+	}, report.Upstreams)
+
+	logger := &testLogger{}
+	mux.Logger = logger
+	assert.NoError(t, mux.LogSchemaReport(context.Background()))
+	assert.Len(t, logger.lines, 1)
+	assert.Contains(t, logger.lines[0], "GraphQL schema version "+report.SchemaVersion)
+	assert.Contains(t, logger.lines[0], "upstreams: test.v1.TestService=dns:///users:50051")
+}